@@ -2,76 +2,289 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/local/aws-local-dashboard/internal/alerts"
+	"github.com/local/aws-local-dashboard/internal/audit"
 	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/backup"
 	"github.com/local/aws-local-dashboard/internal/cache"
 	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/config"
+	"github.com/local/aws-local-dashboard/internal/currency"
+	"github.com/local/aws-local-dashboard/internal/events"
+	"github.com/local/aws-local-dashboard/internal/history"
 	"github.com/local/aws-local-dashboard/internal/httpserver"
+	"github.com/local/aws-local-dashboard/internal/jobs"
 	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/tui"
 	"github.com/local/aws-local-dashboard/internal/types"
 )
 
 func main() {
 	ctx := context.Background()
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	staticDir := os.Getenv("STATIC_DIR")
-	if staticDir == "" {
-		staticDir = "./static"
-	}
-
-	cacheTTLSeconds := 60
-	if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
-		if parsed, err := time.ParseDuration(v + "s"); err == nil {
-			cacheTTLSeconds = int(parsed.Seconds())
-		}
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
 	}
 
-	cacheTTL := time.Duration(cacheTTLSeconds) * time.Second
-
 	// Profile manager handles system vs custom AWS credentials without
 	// mutating the user's ~/.aws configuration.
-	profileManager := profiles.NewManager(ctx)
+	profileManager := profiles.NewManager(ctx, profiles.Options{
+		Offline: cfg.DemoMode == "replay",
+	})
 
-	executor := awscli.NewCLIExecutor(profileManager)
+	var executor awscli.Executor = awscli.NewCLIExecutor(profileManager)
+	switch cfg.DemoMode {
+	case "record":
+		executor, err = awscli.NewRecordingExecutor(executor, cfg.FixtureDir)
+		if err != nil {
+			log.Fatalf("failed to enable fixture recording: %v", err)
+		}
+		log.Printf("Recording sanitized AWS CLI responses to %s", cfg.FixtureDir)
+	case "replay":
+		executor, err = awscli.NewReplayExecutor(cfg.FixtureDir)
+		if err != nil {
+			log.Fatalf("failed to enable fixture replay: %v", err)
+		}
+		log.Printf("Replaying AWS CLI responses from %s (no AWS calls will be made)", cfg.FixtureDir)
+	}
 
-	cmdManager, err := commands.LoadManager(executor, os.Getenv("COMMAND_CONFIG_PATH"))
+	cmdManager, err := commands.LoadManager(executor, cfg.CommandConfigPath)
 	if err != nil {
 		log.Printf("warning: failed to load command config: %v", err)
 	}
 
-	costCache := cache.New[awscli.CachedCost](cacheTTL)
-	costService := awscli.NewCostService(executor, costCache, profileManager)
+	var costCache cache.Cache[awscli.CachedCost]
+	var resourceCache cache.Cache[types.ServiceResources]
+	switch cfg.CacheBackend {
+	case "redis":
+		redisClient, err := cache.NewRedisClient(cfg.CacheRedisURL)
+		if err != nil {
+			log.Fatalf("invalid cache configuration: %v", err)
+		}
+		if err := redisClient.Ping(); err != nil {
+			log.Printf("warning: Redis cache unavailable, fetching from AWS until it is: %v", err)
+		}
+		prefix := cfg.CacheRedisPrefix + ":"
+		costCache = cache.NewRedis[awscli.CachedCost](redisClient, prefix+"costs:", cfg.CacheTTL, cfg.CacheMaxStale)
+		resourceCache = cache.NewRedis[types.ServiceResources](redisClient, prefix+"resources:", cfg.CacheTTL, cfg.CacheMaxStale)
+	default:
+		costs := cache.New[awscli.CachedCost](cfg.CacheTTL)
+		resources := cache.New[types.ServiceResources](cfg.CacheTTL)
+		if cfg.CacheDir != "" {
+			if err := os.MkdirAll(cfg.CacheDir, 0o700); err != nil {
+				log.Fatalf("failed to create cache dir: %v", err)
+			}
+			if err := costs.Persist(filepath.Join(cfg.CacheDir, "costs.json"), cfg.CacheMaxStale); err != nil {
+				log.Printf("warning: failed to load saved costs: %v", err)
+			}
+			if err := resources.Persist(filepath.Join(cfg.CacheDir, "resources.json"), cfg.CacheMaxStale); err != nil {
+				log.Printf("warning: failed to load saved resources: %v", err)
+			}
+		}
+		costCache, resourceCache = costs, resources
+	}
+
+	var converter *currency.Converter
+	if cfg.DisplayCurrency != "" {
+		converter = currency.NewConverter(cfg.DisplayCurrency, cfg.ExchangeRates, cfg.ExchangeRatesURL)
+	}
+	var costHistory *history.Store
+	if cfg.CostHistoryPath != "" {
+		costHistory, err = history.Open(cfg.CostHistoryPath)
+		if err != nil {
+			log.Fatalf("failed to open cost history: %v", err)
+		}
+	}
+	costService := awscli.NewCostService(executor, costCache, profileManager, converter, costHistory)
 
-	resourceCLI := awscli.NewResourceService(executor)
-	resourceCache := cache.New[types.ServiceResources](cacheTTL)
+	resourceCLI := awscli.NewResourceService(executor, profileManager)
 	resourceService := awscli.NewCachedResourceService(resourceCLI, resourceCache, profileManager)
 
-	clearCaches := func() {
+	if cfg.TUI {
+		app := tui.New(costService, resourceService, profileManager)
+		if err := app.Run(ctx, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("tui error: %v", err)
+		}
+		return
+	}
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	bus.Subscribe(events.CacheCleared, func(events.Event) {
 		costCache.Clear()
 		resourceCache.Clear()
+	})
+	forgetProfile := func(e events.Event) {
+		id, _ := e.Data["id"].(string)
+		awscli.ForgetProfileCosts(costCache, id)
+		awscli.ForgetProfileResources(resourceCache, id)
 	}
+	bus.Subscribe(events.ProfileDeleted, forgetProfile)
+	bus.Subscribe(events.ProfileUpdated, forgetProfile)
 
-	handler := httpserver.NewServer(costService, resourceService, profileManager, cmdManager, staticDir, clearCaches)
+	var eventSocket http.Handler
+	for _, sink := range cfg.EventSinks {
+		switch sink {
+		case "log":
+			bus.AddSink(events.LogSink{})
+		case "webhook":
+			bus.AddSink(events.NewWebhookSink(cfg.EventWebhookURL))
+		case "websocket":
+			ws := events.NewWebSocketSink()
+			bus.AddSink(ws)
+			eventSocket = ws
+		}
+	}
 
+	alertManager, err := alerts.NewManager(cfg.AlertStorePath)
+	if err != nil {
+		log.Fatalf("failed to load alert rules: %v", err)
+	}
+	favorites, err := commands.LoadFavorites(cfg.FavoritesStorePath)
+	if err != nil {
+		log.Fatalf("failed to load favorite commands: %v", err)
+	}
+
+	// Everything a user sets up through the dashboard is registered here so
+	// /api/admin/backup can carry it to another host.
+	backups := backup.NewManager()
+	backups.Register(backup.Component{
+		Name:      "profiles",
+		Sensitive: true,
+		Export:    profileManager.ExportState,
+		Import:    profileManager.ImportState,
+	})
+	backups.Register(backup.Component{
+		Name:   "alerts",
+		Export: alertManager.ExportState,
+		Import: alertManager.ImportState,
+	})
+	if cmdManager != nil {
+		backups.Register(backup.Component{
+			Name:   "commands",
+			Export: cmdManager.RawConfig,
+			// Restored commands run like the configured ones, without a
+			// confirmation step, so they must pass the safety filter. The
+			// restore checks that before importing anything; Import itself
+			// doesn't, so rolling back to the current config always works.
+			Validate: func(data []byte) error {
+				return cmdManager.CheckConfig(data, httpserver.CheckSafeAWSArgs)
+			},
+			Import: func(data []byte) error {
+				return cmdManager.ReplaceConfig(data, nil)
+			},
+		})
+	}
+	backups.Register(backup.Component{
+		Name:   "favorites",
+		Export: favorites.ExportState,
+		Import: favorites.ImportState,
+	})
+
+	apiToken, generated, err := cfg.APIToken()
+	if err != nil {
+		log.Fatalf("failed to set up API auth: %v", err)
+	}
+	switch {
+	case generated:
+		log.Printf("API auth enabled; generated token saved to %s. Open the dashboard at /#token=%s", cfg.AuthTokenPath, apiToken)
+	case apiToken != "" && cfg.AuthToken == "":
+		log.Printf("API auth enabled; token read from %s", cfg.AuthTokenPath)
+	case apiToken != "":
+		log.Printf("API auth enabled with the configured token")
+	}
+
+	var auditLog *audit.Log
+	if cfg.AuditLogPath != "" {
+		auditLog, err = audit.Open(cfg.AuditLogPath)
+		if err != nil {
+			log.Fatalf("failed to open audit log: %v", err)
+		}
+		defer auditLog.Close()
+	}
+
+	handler := httpserver.NewServer(httpserver.Options{
+		CostService:            costService,
+		ResourceService:        resourceService,
+		ProfileManager:         profileManager,
+		CommandManager:         cmdManager,
+		CommandIAMCheck:        cfg.CommandIAMCheck,
+		CommandPermissionCheck: cfg.CommandPermissionCheck,
+		MutatingCommands:       cfg.MutatingCommands,
+		RawMaxPages:            cfg.RawMaxPages,
+		Favorites:              favorites,
+		Backups:                backups,
+		Alerts:                 alertManager,
+		Jobs:                   jobs.NewManager(cfg.JobWorkers),
+		JobTimeout:             cfg.JobTimeout,
+		History:                costHistory,
+		StaticDir:              cfg.StaticDir,
+		Events:                 bus,
+		EventSocket:            eventSocket,
+		DefaultLocale:          cfg.Locale,
+		AuthToken:              apiToken,
+		TrustedOrigins:         cfg.TrustedOrigins,
+		Loopback:               cfg.SocketPath == "" && !cfg.Exposed(),
+		Audit:                  auditLog,
+		RequestTimeout:         cfg.RequestTimeout,
+		ScanTimeout:            cfg.ScanTimeout,
+		GraphQL:                cfg.GraphQL,
+		Runtime: httpserver.RuntimeInfo{
+			Listen:          cfg.Describe(),
+			TLS:             cfg.TLSCert != "",
+			CacheTTL:        cfg.CacheTTL,
+			CacheBackend:    cfg.CacheBackend,
+			CacheDir:        cfg.CacheDir,
+			CacheMaxStale:   cfg.CacheMaxStale,
+			DemoMode:        cfg.DemoMode,
+			DisplayCurrency: cfg.DisplayCurrency,
+			EventSinks:      cfg.EventSinks,
+		},
+	})
+
+	// Leave room past the route timeouts to write their 504s; with either
+	// disabled, responses may take as long as the AWS calls do.
+	writeTimeout := time.Duration(0)
+	if cfg.RequestTimeout > 0 && cfg.ScanTimeout > 0 {
+		writeTimeout = max(cfg.RequestTimeout, cfg.ScanTimeout) + 10*time.Second
+	}
 	server := &http.Server{
-		Addr:         ":" + port,
 		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: writeTimeout,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("Starting server on :%s (static dir: %s)", port, staticDir)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	tlsConfig, generatedCert, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("failed to set up TLS: %v", err)
+	}
+	if generatedCert {
+		log.Printf("Generated a self-signed TLS certificate at %s; browsers will warn until it is trusted", cfg.TLSCert)
+	}
+
+	ln, err := cfg.Listen()
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", cfg.Describe(), err)
+	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	if cfg.Exposed() && apiToken == "" {
+		log.Printf("warning: listening on %s without API auth; anyone who can reach it can use your AWS credentials (see -auth)", cfg.Describe())
+	}
+	log.Printf("Starting server on %s (static dir: %s)", cfg.Describe(), cfg.StaticDir)
+	if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("server error: %v", err)
 	}
 
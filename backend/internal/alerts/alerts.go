@@ -0,0 +1,403 @@
+// Package alerts stores cost threshold rules and evaluates them against the
+// cost service whenever cost data is refreshed.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Metric names the value a rule compares against its threshold.
+type Metric string
+
+const (
+	// MTDTotal is month-to-date usage cost before credits.
+	MTDTotal Metric = "mtd_total"
+	// MTDNet is month-to-date net cost after credits and refunds.
+	MTDNet Metric = "mtd_net"
+	// ServiceMTD is one service's month-to-date cost; Rule.Service is required.
+	ServiceMTD Metric = "service_mtd"
+	// DailyCost is the cost of the last complete day.
+	DailyCost Metric = "daily_cost"
+	// DailyDeltaPct is the percent increase of the last complete day over the
+	// day before it.
+	DailyDeltaPct Metric = "daily_delta_pct"
+)
+
+// Rule is a configured threshold. An alert fires when the metric's value is
+// greater than Threshold.
+type Rule struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Metric    Metric    `json:"metric"`
+	Threshold float64   `json:"threshold"`
+	Service   string    `json:"service,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Alert is a rule whose threshold was exceeded at the last evaluation.
+type Alert struct {
+	RuleID    string    `json:"ruleId"`
+	RuleName  string    `json:"ruleName"`
+	Metric    Metric    `json:"metric"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Currency  string    `json:"currency,omitempty"`
+	Profile   string    `json:"profile"`
+	Period    string    `json:"period"`
+	Message   string    `json:"message"`
+	FiredAt   time.Time `json:"firedAt"`
+}
+
+type storeState struct {
+	NextID    int64   `json:"nextId"`
+	Rules     []Rule  `json:"rules"`
+	Triggered []Alert `json:"triggered,omitempty"`
+}
+
+// Manager owns the alert rules and the currently triggered alerts.
+type Manager struct {
+	mu        sync.Mutex
+	storePath string
+	nextID    int64
+	rules     []Rule
+	triggered []Alert
+
+	// evaluating guards against overlapping evaluations when several cost
+	// requests complete at once.
+	evaluating sync.Mutex
+}
+
+// NewManager loads rules from storePath, if it exists. An empty storePath
+// keeps rules in memory only.
+func NewManager(storePath string) (*Manager, error) {
+	m := &Manager{storePath: storePath, nextID: 1}
+	if storePath == "" {
+		return m, nil
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+
+	var state storeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid alert store %s: %w", storePath, err)
+	}
+	m.applyStateLocked(state)
+	return m, nil
+}
+
+// Rules returns the configured rules, oldest first.
+func (m *Manager) Rules() []Rule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Rule{}, m.rules...)
+}
+
+// Triggered returns the alerts that fired at the last evaluation.
+func (m *Manager) Triggered() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Alert{}, m.triggered...)
+}
+
+// AddRule validates r, assigns it an ID and persists it.
+func (m *Manager) AddRule(r Rule) (Rule, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Service = strings.TrimSpace(r.Service)
+	switch r.Metric {
+	case MTDTotal, MTDNet, DailyCost, DailyDeltaPct:
+	case ServiceMTD:
+		if r.Service == "" {
+			return Rule{}, fmt.Errorf("metric %s requires a service", r.Metric)
+		}
+	default:
+		return Rule{}, fmt.Errorf("unknown metric %q (expected %s, %s, %s, %s or %s)", r.Metric, MTDTotal, MTDNet, ServiceMTD, DailyCost, DailyDeltaPct)
+	}
+	if r.Threshold < 0 {
+		return Rule{}, fmt.Errorf("threshold must not be negative")
+	}
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("%s > %g", r.Metric, r.Threshold)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r.ID = strconv.FormatInt(m.nextID, 10)
+	m.nextID++
+	r.CreatedAt = time.Now().UTC()
+	m.rules = append(m.rules, r)
+	m.saveLocked()
+	return r, nil
+}
+
+// DeleteRule removes a rule and any alert it triggered.
+func (m *Manager) DeleteRule(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := -1
+	for i, r := range m.rules {
+		if r.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("alert rule %q not found", id)
+	}
+	m.rules = append(m.rules[:idx], m.rules[idx+1:]...)
+
+	var kept []Alert
+	for _, a := range m.triggered {
+		if a.RuleID != id {
+			kept = append(kept, a)
+		}
+	}
+	m.triggered = kept
+	m.saveLocked()
+	return nil
+}
+
+// Evaluate checks every rule against current cost data for profile and
+// returns the alerts that newly fired: a rule fires once per period (month or
+// day) and profile, however often costs are refreshed.
+func (m *Manager) Evaluate(ctx context.Context, costs services.CostService, profile string, now time.Time) ([]Alert, error) {
+	m.evaluating.Lock()
+	defer m.evaluating.Unlock()
+
+	rules := m.Rules()
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	snap, err := takeSnapshot(ctx, costs, rules, now)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := make(map[string]Alert, len(m.triggered))
+	for _, a := range m.triggered {
+		previous[a.RuleID+"|"+a.Profile+"|"+a.Period] = a
+	}
+
+	// Alerts for other profiles stay until that profile is evaluated again.
+	var triggered, fired []Alert
+	for _, a := range m.triggered {
+		if a.Profile != profile {
+			triggered = append(triggered, a)
+		}
+	}
+	for _, r := range rules {
+		value, period, ok := snap.value(r)
+		if !ok || value <= r.Threshold {
+			continue
+		}
+
+		key := r.ID + "|" + profile + "|" + period
+		if prev, ok := previous[key]; ok {
+			prev.Value = value
+			triggered = append(triggered, prev)
+			continue
+		}
+
+		a := Alert{
+			RuleID:    r.ID,
+			RuleName:  r.Name,
+			Metric:    r.Metric,
+			Value:     value,
+			Threshold: r.Threshold,
+			Profile:   profile,
+			Period:    period,
+			FiredAt:   now.UTC(),
+		}
+		if r.Metric != DailyDeltaPct {
+			a.Currency = snap.currency
+		}
+		a.Message = alertMessage(r, a)
+		triggered = append(triggered, a)
+		fired = append(fired, a)
+	}
+
+	sort.Slice(triggered, func(i, j int) bool {
+		return triggered[i].FiredAt.After(triggered[j].FiredAt)
+	})
+	m.triggered = triggered
+	m.saveLocked()
+	return fired, nil
+}
+
+func alertMessage(r Rule, a Alert) string {
+	switch r.Metric {
+	case DailyDeltaPct:
+		return fmt.Sprintf("%s: daily cost up %.1f%% (threshold %g%%)", r.Name, a.Value, r.Threshold)
+	case ServiceMTD:
+		return fmt.Sprintf("%s: %s month-to-date cost %.2f %s exceeds %g", r.Name, r.Service, a.Value, a.Currency, r.Threshold)
+	default:
+		return fmt.Sprintf("%s: %s %.2f %s exceeds %g", r.Name, r.Metric, a.Value, a.Currency, r.Threshold)
+	}
+}
+
+// snapshot holds the cost figures the rules are evaluated against.
+type snapshot struct {
+	month    string
+	day      string
+	currency string
+	overview types.CostOverview
+	services []types.ServiceCost
+	// daily holds the last two complete days, oldest first, when available.
+	daily []types.CostPoint
+}
+
+func takeSnapshot(ctx context.Context, costs services.CostService, rules []Rule, now time.Time) (snapshot, error) {
+	now = now.UTC()
+	yesterday := now.AddDate(0, 0, -1)
+	snap := snapshot{
+		month: now.Format("2006-01"),
+		day:   yesterday.Format("2006-01-02"),
+	}
+
+	// Empty start/end is the current month, which shares the cost cache with
+	// the dashboard's default view.
+	overview, err := costs.GetCostOverview(ctx, "", "", types.CostFilter{})
+	if err != nil {
+		return snapshot{}, err
+	}
+	snap.overview = overview
+	snap.currency = overview.Currency
+
+	var needServices, needDaily bool
+	for _, r := range rules {
+		switch r.Metric {
+		case ServiceMTD:
+			needServices = true
+		case DailyCost, DailyDeltaPct:
+			needDaily = true
+		}
+	}
+
+	if needServices {
+		snap.services, err = costs.GetServiceCosts(ctx, "", "", types.CostFilter{})
+		if err != nil {
+			return snapshot{}, err
+		}
+	}
+	if needDaily {
+		start := now.AddDate(0, 0, -2).Format("2006-01-02")
+		series, err := costs.GetCostTimeSeries(ctx, start, snap.day, "DAILY", types.CostFilter{})
+		if err != nil {
+			return snapshot{}, err
+		}
+		snap.daily = series.Points
+	}
+	return snap, nil
+}
+
+// value returns the rule's metric and the period it applies to. ok is false
+// when the data needed is missing.
+func (s snapshot) value(r Rule) (float64, string, bool) {
+	switch r.Metric {
+	case MTDTotal:
+		return s.overview.Total, s.month, true
+	case MTDNet:
+		return s.overview.NetTotal, s.month, true
+	case ServiceMTD:
+		var total float64
+		found := false
+		for _, c := range s.services {
+			if strings.EqualFold(c.Service, r.Service) || strings.EqualFold(c.DrilldownKey, r.Service) {
+				total += c.Cost
+				found = true
+			}
+		}
+		return total, s.month, found
+	case DailyCost:
+		if len(s.daily) == 0 {
+			return 0, "", false
+		}
+		return s.daily[len(s.daily)-1].Cost, s.day, true
+	case DailyDeltaPct:
+		if len(s.daily) < 2 {
+			return 0, "", false
+		}
+		prev := s.daily[len(s.daily)-2].Cost
+		last := s.daily[len(s.daily)-1].Cost
+		if prev <= 0 {
+			return 0, "", false
+		}
+		return (last - prev) / prev * 100, s.day, true
+	}
+	return 0, "", false
+}
+
+// ExportState returns the rules as JSON, for backups. Triggered alerts are
+// transient and not included.
+func (m *Manager) ExportState() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return json.MarshalIndent(storeState{NextID: m.nextID, Rules: m.rules}, "", "  ")
+}
+
+// ImportState replaces all rules with those in data (as produced by
+// ExportState) and persists the result.
+func (m *Manager) ImportState(data []byte) error {
+	var state storeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid alert state: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state.Triggered = nil
+	m.applyStateLocked(state)
+	m.saveLocked()
+	return nil
+}
+
+// applyStateLocked replaces the in-memory state. Caller must hold m.mu.
+func (m *Manager) applyStateLocked(state storeState) {
+	if state.NextID > 0 {
+		m.nextID = state.NextID
+	}
+	m.rules = state.Rules
+	m.triggered = state.Triggered
+}
+
+// saveLocked persists rules and triggered alerts. Caller must hold m.mu.
+func (m *Manager) saveLocked() {
+	if m.storePath == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(storeState{
+		NextID:    m.nextID,
+		Rules:     m.rules,
+		Triggered: m.triggered,
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(m.storePath, data, 0o600)
+}
@@ -0,0 +1,160 @@
+// Package audit keeps an append-only record of actions taken through the
+// API, such as profile changes and command executions, so it is possible to
+// tell afterwards what was run against which account.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Results of an audited action.
+const (
+	ResultOK     = "ok"
+	ResultDenied = "denied"
+	ResultFailed = "failed"
+)
+
+// Entry is one audited action.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId,omitempty"`
+	// Remote is the client address and UserAgent its user agent; with API
+	// authentication every client shares one token, so these are what
+	// tells callers apart.
+	Remote    string `json:"remote"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Action    string `json:"action"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	// Profile is the profile the request used: the active one when the action
+	// started, or a per-request override.
+	Profile string `json:"profile,omitempty"`
+	// Args are the action's parameters, such as a profile ID or the aws CLI
+	// arguments of a command. Credentials are never recorded.
+	Args       []string `json:"args,omitempty"`
+	Status     int      `json:"status"`
+	Result     string   `json:"result"`
+	DurationMs int64    `json:"durationMs"`
+}
+
+// Query selects entries. Zero fields match everything.
+type Query struct {
+	// Action matches the action or, with a trailing ".", every action with
+	// that prefix (e.g. "profile.").
+	Action  string
+	Profile string
+	Result  string
+	Since   time.Time
+	Until   time.Time
+	// Limit caps the number of entries returned, newest first.
+	Limit int
+}
+
+func (q Query) matches(e Entry) bool {
+	switch {
+	case q.Action != "" && !(e.Action == q.Action || strings.HasSuffix(q.Action, ".") && strings.HasPrefix(e.Action, q.Action)):
+		return false
+	case q.Profile != "" && e.Profile != q.Profile:
+		return false
+	case q.Result != "" && e.Result != q.Result:
+		return false
+	case !q.Since.IsZero() && e.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !e.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// Log is an append-only JSON-lines audit log. Entries are only ever
+// appended; nothing in the server rewrites or truncates the file.
+type Log struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open opens the audit log at path for appending, creating it (readable only
+// by the owner) if needed.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{path: path, file: f}, nil
+}
+
+// Record appends e to the log. It is safe to call on a nil Log, which makes
+// auditing optional for callers.
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(line)
+	return err
+}
+
+// Entries returns the entries matching q, newest first.
+func (l *Log) Entries(q Query) ([]Entry, error) {
+	l.mu.Lock()
+	data, err := os.ReadFile(l.path)
+	l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e Entry
+		// A torn final line (e.g. after a crash) is skipped, not fatal.
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		if q.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	slices.Reverse(entries)
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return entries, nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,81 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// ACM
+
+type acmListCertificatesOutput struct {
+	CertificateSummaryList []struct {
+		CertificateArn string `json:"CertificateArn"`
+		DomainName     string `json:"DomainName"`
+		Status         string `json:"Status"`
+		Type           string `json:"Type"`
+		KeyAlgorithm   string `json:"KeyAlgorithm"`
+		InUse          bool   `json:"InUse"`
+		NotAfter       string `json:"NotAfter"`
+	} `json:"CertificateSummaryList"`
+}
+
+// acmKeyTypes overrides list-certificates' default filter, which only returns
+// RSA_1024 and RSA_2048 certificates.
+const acmKeyTypes = "keyTypes=RSA_1024,RSA_2048,RSA_3072,RSA_4096,EC_prime256v1,EC_secp384r1,EC_secp521r1"
+
+// certExpiryWarningDays is how close to NotAfter a certificate must be before
+// it is flagged as expiring soon.
+const certExpiryWarningDays = 30
+
+func (s *resourceService) getCertificates(ctx context.Context, region string) (types.ServiceResources, error) {
+	certs, msg, err := forRegions(ctx, s, region, s.getCertificatesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:      "acm",
+		Certificates: certs,
+		Message:      msg,
+	}, nil
+}
+
+func (s *resourceService) getCertificatesSingleRegion(ctx context.Context, region string) ([]types.Certificate, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"acm", "list-certificates", "--includes", acmKeyTypes}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp acmListCertificatesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-certificates output: %w", err)
+	}
+
+	now := time.Now()
+	var certs []types.Certificate
+	for _, c := range resp.CertificateSummaryList {
+		cert := types.Certificate{
+			ARN:          c.CertificateArn,
+			DomainName:   c.DomainName,
+			Status:       c.Status,
+			Type:         c.Type,
+			KeyAlgorithm: c.KeyAlgorithm,
+			InUse:        c.InUse,
+			NotAfter:     c.NotAfter,
+			Region:       region,
+		}
+		// Certificates pending validation have no NotAfter yet.
+		if t, err := time.Parse(time.RFC3339, c.NotAfter); err == nil {
+			days := int(math.Floor(t.Sub(now).Hours() / 24))
+			cert.DaysUntilExpiry = &days
+			cert.ExpiringSoon = days <= certExpiryWarningDays
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,83 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// AMIs
+
+type ec2DescribeImagesOutput struct {
+	Images []struct {
+		ImageID             string `json:"ImageId"`
+		Name                string `json:"Name"`
+		Description         string `json:"Description"`
+		CreationDate        string `json:"CreationDate"`
+		State               string `json:"State"`
+		Architecture        string `json:"Architecture"`
+		Public              bool   `json:"Public"`
+		BlockDeviceMappings []struct {
+			Ebs *struct {
+				SnapshotID string `json:"SnapshotId"`
+				VolumeSize int    `json:"VolumeSize"`
+			} `json:"Ebs"`
+		} `json:"BlockDeviceMappings"`
+	} `json:"Images"`
+}
+
+func (s *resourceService) getAMIs(ctx context.Context, region string) (types.ServiceResources, error) {
+	images, msg, err := forRegions(ctx, s, region, s.getAMIsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service: "ami",
+		AMIs:    images,
+		Message: msg,
+	}, nil
+}
+
+func (s *resourceService) getAMIsSingleRegion(ctx context.Context, region string) ([]types.AMI, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-images", "--owners", "self"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeImagesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-images output: %w", err)
+	}
+
+	var images []types.AMI
+	for _, img := range resp.Images {
+		var snapshots []string
+		size := 0
+		for _, bdm := range img.BlockDeviceMappings {
+			if bdm.Ebs == nil {
+				continue
+			}
+			if bdm.Ebs.SnapshotID != "" {
+				snapshots = append(snapshots, bdm.Ebs.SnapshotID)
+			}
+			size += bdm.Ebs.VolumeSize
+		}
+
+		images = append(images, types.AMI{
+			ImageID:      img.ImageID,
+			Name:         img.Name,
+			Description:  img.Description,
+			CreationDate: img.CreationDate,
+			State:        img.State,
+			Architecture: img.Architecture,
+			Public:       img.Public,
+			SnapshotIDs:  snapshots,
+			TotalSizeGiB: size,
+			Region:       region,
+		})
+	}
+	return images, nil
+}
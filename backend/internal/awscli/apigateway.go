@@ -0,0 +1,166 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// API Gateway (REST via apigateway, HTTP/WebSocket via apigatewayv2)
+
+type apigatewayGetRestApisOutput struct {
+	Items []struct {
+		ID                    string `json:"id"`
+		Name                  string `json:"name"`
+		Description           string `json:"description"`
+		CreatedDate           string `json:"createdDate"`
+		EndpointConfiguration struct {
+			Types []string `json:"types"`
+		} `json:"endpointConfiguration"`
+	} `json:"items"`
+}
+
+type apigatewayGetStagesOutput struct {
+	Item []struct {
+		StageName string `json:"stageName"`
+	} `json:"item"`
+}
+
+type apigatewayv2GetApisOutput struct {
+	Items []struct {
+		APIID        string `json:"ApiId"`
+		Name         string `json:"Name"`
+		Description  string `json:"Description"`
+		ProtocolType string `json:"ProtocolType"`
+		APIEndpoint  string `json:"ApiEndpoint"`
+		CreatedDate  string `json:"CreatedDate"`
+	} `json:"Items"`
+}
+
+type apigatewayv2GetStagesOutput struct {
+	Items []struct {
+		StageName string `json:"StageName"`
+	} `json:"Items"`
+}
+
+// maxConcurrentAPIs bounds the per-API get-stages calls within a region.
+const maxConcurrentAPIs = 4
+
+func (s *resourceService) getAPIGateways(ctx context.Context, region string) (types.ServiceResources, error) {
+	apis, msg, err := forRegions(ctx, s, region, s.getAPIGatewaysSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service: "apigateway",
+		APIs:    apis,
+		Message: msg,
+	}, nil
+}
+
+func (s *resourceService) getAPIGatewaysSingleRegion(ctx context.Context, region string) ([]types.APIGatewayAPI, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"apigateway", "get-rest-apis"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rest apigatewayGetRestApisOutput
+	if err := json.Unmarshal(out, &rest); err != nil {
+		return nil, fmt.Errorf("failed to parse get-rest-apis output: %w", err)
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"apigatewayv2", "get-apis"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var v2 apigatewayv2GetApisOutput
+	if err := json.Unmarshal(out, &v2); err != nil {
+		return nil, fmt.Errorf("failed to parse get-apis output: %w", err)
+	}
+
+	var apis []types.APIGatewayAPI
+	for _, a := range rest.Items {
+		// get-rest-apis does not return the invoke URL; derive it when the
+		// region is known.
+		endpoint := ""
+		if region != "" {
+			endpoint = fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com", a.ID, region)
+		}
+
+		apis = append(apis, types.APIGatewayAPI{
+			ID:           a.ID,
+			Name:         a.Name,
+			Description:  a.Description,
+			Protocol:     "REST",
+			EndpointType: strings.Join(a.EndpointConfiguration.Types, ","),
+			Endpoint:     endpoint,
+			CreatedDate:  a.CreatedDate,
+			Region:       region,
+		})
+	}
+	for _, a := range v2.Items {
+		apis = append(apis, types.APIGatewayAPI{
+			ID:           a.APIID,
+			Name:         a.Name,
+			Description:  a.Description,
+			Protocol:     a.ProtocolType,
+			EndpointType: "REGIONAL",
+			Endpoint:     a.APIEndpoint,
+			CreatedDate:  a.CreatedDate,
+			Region:       region,
+		})
+	}
+
+	errs := make([]error, len(apis))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentAPIs)
+
+	for i := range apis {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			apis[i].StageCount, errs[i] = s.countAPIStages(ctx, region, apis[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return apis, nil
+}
+
+func (s *resourceService) countAPIStages(ctx context.Context, region string, api types.APIGatewayAPI) (int, error) {
+	if api.Protocol == "REST" {
+		out, err := s.exec.RunJSON(ctx, regionArgs([]string{"apigateway", "get-stages", "--rest-api-id", api.ID}, region)...)
+		if err != nil {
+			return 0, err
+		}
+		var resp apigatewayGetStagesOutput
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return 0, fmt.Errorf("failed to parse get-stages output: %w", err)
+		}
+		return len(resp.Item), nil
+	}
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"apigatewayv2", "get-stages", "--api-id", api.ID}, region)...)
+	if err != nil {
+		return 0, err
+	}
+	var resp apigatewayv2GetStagesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return 0, fmt.Errorf("failed to parse get-stages output: %w", err)
+	}
+	return len(resp.Items), nil
+}
@@ -0,0 +1,168 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Athena
+
+type athenaListWorkGroupsOutput struct {
+	WorkGroups []struct {
+		Name         string `json:"Name"`
+		State        string `json:"State"`
+		Description  string `json:"Description"`
+		CreationTime string `json:"CreationTime"`
+	} `json:"WorkGroups"`
+}
+
+type athenaGetWorkGroupOutput struct {
+	WorkGroup struct {
+		Configuration struct {
+			BytesScannedCutoffPerQuery      *int64 `json:"BytesScannedCutoffPerQuery"`
+			EnforceWorkGroupConfiguration   bool   `json:"EnforceWorkGroupConfiguration"`
+			PublishCloudWatchMetricsEnabled bool   `json:"PublishCloudWatchMetricsEnabled"`
+			ResultConfiguration             struct {
+				OutputLocation string `json:"OutputLocation"`
+			} `json:"ResultConfiguration"`
+			EngineVersion struct {
+				EffectiveEngineVersion string `json:"EffectiveEngineVersion"`
+			} `json:"EngineVersion"`
+		} `json:"Configuration"`
+	} `json:"WorkGroup"`
+}
+
+type athenaListNamedQueriesOutput struct {
+	NamedQueryIDs []string `json:"NamedQueryIds"`
+}
+
+type athenaBatchGetNamedQueryOutput struct {
+	NamedQueries []struct {
+		NamedQueryID string `json:"NamedQueryId"`
+		Name         string `json:"Name"`
+		Description  string `json:"Description"`
+		Database     string `json:"Database"`
+		WorkGroup    string `json:"WorkGroup"`
+	} `json:"NamedQueries"`
+}
+
+// athenaBatchSize is the maximum number of IDs batch-get-named-query accepts.
+const athenaBatchSize = 50
+
+type athenaInventory struct {
+	workGroups []types.AthenaWorkGroup
+	queries    []types.AthenaNamedQuery
+}
+
+func (s *resourceService) getAthena(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getAthenaSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "athena", Message: msg}
+	for _, inv := range inventories {
+		res.AthenaWorkGroups = append(res.AthenaWorkGroups, inv.workGroups...)
+		res.AthenaNamedQueries = append(res.AthenaNamedQueries, inv.queries...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getAthenaSingleRegion(ctx context.Context, region string) ([]athenaInventory, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"athena", "list-work-groups"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp athenaListWorkGroupsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-work-groups output: %w", err)
+	}
+
+	var inv athenaInventory
+	for _, wg := range resp.WorkGroups {
+		out, err := s.exec.RunJSON(ctx, regionArgs([]string{"athena", "get-work-group", "--work-group", wg.Name}, region)...)
+		if err != nil {
+			return nil, err
+		}
+
+		var detail athenaGetWorkGroupOutput
+		if err := json.Unmarshal(out, &detail); err != nil {
+			return nil, fmt.Errorf("failed to parse get-work-group output: %w", err)
+		}
+		cfg := detail.WorkGroup.Configuration
+
+		queries, err := s.getAthenaNamedQueries(ctx, region, wg.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var cutoff int64
+		if cfg.BytesScannedCutoffPerQuery != nil {
+			cutoff = *cfg.BytesScannedCutoffPerQuery
+		}
+
+		inv.workGroups = append(inv.workGroups, types.AthenaWorkGroup{
+			Name:                       wg.Name,
+			State:                      wg.State,
+			Description:                wg.Description,
+			EngineVersion:              cfg.EngineVersion.EffectiveEngineVersion,
+			BytesScannedCutoffPerQuery: cutoff,
+			EnforceConfiguration:       cfg.EnforceWorkGroupConfiguration,
+			CloudWatchMetricsEnabled:   cfg.PublishCloudWatchMetricsEnabled,
+			OutputLocation:             cfg.ResultConfiguration.OutputLocation,
+			NamedQueryCount:            len(queries),
+			CreationTime:               wg.CreationTime,
+			Region:                     region,
+		})
+		inv.queries = append(inv.queries, queries...)
+	}
+
+	return []athenaInventory{inv}, nil
+}
+
+func (s *resourceService) getAthenaNamedQueries(ctx context.Context, region, workGroup string) ([]types.AthenaNamedQuery, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"athena", "list-named-queries", "--work-group", workGroup}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list athenaListNamedQueriesOutput
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse list-named-queries output: %w", err)
+	}
+
+	var queries []types.AthenaNamedQuery
+	for start := 0; start < len(list.NamedQueryIDs); start += athenaBatchSize {
+		end := start + athenaBatchSize
+		if end > len(list.NamedQueryIDs) {
+			end = len(list.NamedQueryIDs)
+		}
+
+		args := append([]string{"athena", "batch-get-named-query", "--named-query-ids"}, list.NamedQueryIDs[start:end]...)
+		out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch athenaBatchGetNamedQueryOutput
+		if err := json.Unmarshal(out, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse batch-get-named-query output: %w", err)
+		}
+
+		for _, q := range batch.NamedQueries {
+			queries = append(queries, types.AthenaNamedQuery{
+				ID:          q.NamedQueryID,
+				Name:        q.Name,
+				Description: q.Description,
+				Database:    q.Database,
+				WorkGroup:   q.WorkGroup,
+				Region:      region,
+			})
+		}
+	}
+	return queries, nil
+}
@@ -0,0 +1,128 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// AWS Backup
+
+type backupListBackupVaultsOutput struct {
+	BackupVaultList []struct {
+		BackupVaultName        string `json:"BackupVaultName"`
+		BackupVaultArn         string `json:"BackupVaultArn"`
+		NumberOfRecoveryPoints int64  `json:"NumberOfRecoveryPoints"`
+		EncryptionKeyArn       string `json:"EncryptionKeyArn"`
+		Locked                 bool   `json:"Locked"`
+		CreationDate           string `json:"CreationDate"`
+	} `json:"BackupVaultList"`
+}
+
+type backupListBackupPlansOutput struct {
+	BackupPlansList []struct {
+		BackupPlanID      string `json:"BackupPlanId"`
+		BackupPlanName    string `json:"BackupPlanName"`
+		CreationDate      string `json:"CreationDate"`
+		LastExecutionDate string `json:"LastExecutionDate"`
+	} `json:"BackupPlansList"`
+}
+
+type backupGetBackupPlanOutput struct {
+	BackupPlan struct {
+		Rules []struct {
+			RuleName              string `json:"RuleName"`
+			ScheduleExpression    string `json:"ScheduleExpression"`
+			TargetBackupVaultName string `json:"TargetBackupVaultName"`
+			Lifecycle             struct {
+				DeleteAfterDays int `json:"DeleteAfterDays"`
+			} `json:"Lifecycle"`
+		} `json:"Rules"`
+	} `json:"BackupPlan"`
+}
+
+type backupInventory struct {
+	vaults []types.BackupVault
+	plans  []types.BackupPlan
+}
+
+func (s *resourceService) getBackupInventory(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getBackupInventorySingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "backup", Message: msg}
+	for _, inv := range inventories {
+		res.BackupVaults = append(res.BackupVaults, inv.vaults...)
+		res.BackupPlans = append(res.BackupPlans, inv.plans...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getBackupInventorySingleRegion(ctx context.Context, region string) ([]backupInventory, error) {
+	var inv backupInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"backup", "list-backup-vaults"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var vaults backupListBackupVaultsOutput
+	if err := json.Unmarshal(out, &vaults); err != nil {
+		return nil, fmt.Errorf("failed to parse list-backup-vaults output: %w", err)
+	}
+	for _, v := range vaults.BackupVaultList {
+		inv.vaults = append(inv.vaults, types.BackupVault{
+			Name:           v.BackupVaultName,
+			ARN:            v.BackupVaultArn,
+			RecoveryPoints: v.NumberOfRecoveryPoints,
+			EncryptionKey:  v.EncryptionKeyArn,
+			Locked:         v.Locked,
+			CreationDate:   v.CreationDate,
+			Region:         region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"backup", "list-backup-plans"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var plans backupListBackupPlansOutput
+	if err := json.Unmarshal(out, &plans); err != nil {
+		return nil, fmt.Errorf("failed to parse list-backup-plans output: %w", err)
+	}
+	for _, p := range plans.BackupPlansList {
+		// Schedules live on the plan's rules, which list-backup-plans omits.
+		out, err := s.exec.RunJSON(ctx, regionArgs([]string{"backup", "get-backup-plan", "--backup-plan-id", p.BackupPlanID}, region)...)
+		if err != nil {
+			return nil, err
+		}
+		var detail backupGetBackupPlanOutput
+		if err := json.Unmarshal(out, &detail); err != nil {
+			return nil, fmt.Errorf("failed to parse get-backup-plan output: %w", err)
+		}
+
+		var rules []types.BackupRule
+		for _, r := range detail.BackupPlan.Rules {
+			rules = append(rules, types.BackupRule{
+				Name:            r.RuleName,
+				Schedule:        r.ScheduleExpression,
+				TargetVault:     r.TargetBackupVaultName,
+				DeleteAfterDays: r.Lifecycle.DeleteAfterDays,
+			})
+		}
+
+		inv.plans = append(inv.plans, types.BackupPlan{
+			ID:                p.BackupPlanID,
+			Name:              p.BackupPlanName,
+			Rules:             rules,
+			CreationDate:      p.CreationDate,
+			LastExecutionDate: p.LastExecutionDate,
+			Region:            region,
+		})
+	}
+
+	return []backupInventory{inv}, nil
+}
@@ -0,0 +1,74 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// CloudFormation
+
+type cfnDescribeStacksOutput struct {
+	Stacks []struct {
+		StackName         string `json:"StackName"`
+		StackID           string `json:"StackId"`
+		StackStatus       string `json:"StackStatus"`
+		StackStatusReason string `json:"StackStatusReason"`
+		Description       string `json:"Description"`
+		CreationTime      string `json:"CreationTime"`
+		LastUpdatedTime   string `json:"LastUpdatedTime"`
+		ParentID          string `json:"ParentId"`
+		DriftInformation  struct {
+			StackDriftStatus   string `json:"StackDriftStatus"`
+			LastCheckTimestamp string `json:"LastCheckTimestamp"`
+		} `json:"DriftInformation"`
+	} `json:"Stacks"`
+}
+
+func (s *resourceService) getCloudFormationStacks(ctx context.Context, region string) (types.ServiceResources, error) {
+	stacks, msg, err := forRegions(ctx, s, region, s.getCloudFormationStacksSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:              "cloudformation",
+		CloudFormationStacks: stacks,
+		Message:              msg,
+	}, nil
+}
+
+// getCloudFormationStacksSingleRegion reports the drift status from the last
+// detection run. Drilldowns are read-only, so detection itself is never
+// triggered here.
+func (s *resourceService) getCloudFormationStacksSingleRegion(ctx context.Context, region string) ([]types.CloudFormationStack, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"cloudformation", "describe-stacks"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp cfnDescribeStacksOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-stacks output: %w", err)
+	}
+
+	var stacks []types.CloudFormationStack
+	for _, st := range resp.Stacks {
+		stacks = append(stacks, types.CloudFormationStack{
+			Name:           st.StackName,
+			StackID:        st.StackID,
+			Status:         st.StackStatus,
+			StatusReason:   st.StackStatusReason,
+			Description:    st.Description,
+			CreationTime:   st.CreationTime,
+			LastUpdated:    st.LastUpdatedTime,
+			Nested:         st.ParentID != "",
+			DriftStatus:    st.DriftInformation.StackDriftStatus,
+			DriftCheckedAt: st.DriftInformation.LastCheckTimestamp,
+			Region:         region,
+		})
+	}
+	return stacks, nil
+}
@@ -0,0 +1,111 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// CloudWatch alarms
+
+type cloudwatchDescribeAlarmsOutput struct {
+	MetricAlarms []struct {
+		AlarmName             string   `json:"AlarmName"`
+		AlarmArn              string   `json:"AlarmArn"`
+		StateValue            string   `json:"StateValue"`
+		StateUpdatedTimestamp string   `json:"StateUpdatedTimestamp"`
+		Namespace             string   `json:"Namespace"`
+		MetricName            string   `json:"MetricName"`
+		Statistic             string   `json:"Statistic"`
+		ComparisonOperator    string   `json:"ComparisonOperator"`
+		Threshold             *float64 `json:"Threshold"`
+		ActionsEnabled        bool     `json:"ActionsEnabled"`
+	} `json:"MetricAlarms"`
+	CompositeAlarms []struct {
+		AlarmName             string `json:"AlarmName"`
+		AlarmArn              string `json:"AlarmArn"`
+		StateValue            string `json:"StateValue"`
+		StateUpdatedTimestamp string `json:"StateUpdatedTimestamp"`
+		ActionsEnabled        bool   `json:"ActionsEnabled"`
+	} `json:"CompositeAlarms"`
+}
+
+// alarmStateOrder puts firing alarms first when grouping by state.
+var alarmStateOrder = map[string]int{
+	"ALARM":             0,
+	"INSUFFICIENT_DATA": 1,
+	"OK":                2,
+}
+
+func (s *resourceService) getCloudWatchAlarms(ctx context.Context, region string) (types.ServiceResources, error) {
+	alarms, msg, err := forRegions(ctx, s, region, s.getCloudWatchAlarmsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	sort.SliceStable(alarms, func(i, j int) bool {
+		return alarmStateOrder[alarms[i].State] < alarmStateOrder[alarms[j].State]
+	})
+
+	counts := map[string]int{}
+	for _, a := range alarms {
+		counts[a.State]++
+	}
+	note := fmt.Sprintf("ALARM: %d, INSUFFICIENT_DATA: %d, OK: %d",
+		counts["ALARM"], counts["INSUFFICIENT_DATA"], counts["OK"])
+	if msg != "" {
+		note += ". " + msg
+	}
+
+	return types.ServiceResources{
+		Service:          "cloudwatch-alarms",
+		CloudWatchAlarms: alarms,
+		Message:          note,
+	}, nil
+}
+
+func (s *resourceService) getCloudWatchAlarmsSingleRegion(ctx context.Context, region string) ([]types.CloudWatchAlarm, error) {
+	args := []string{"cloudwatch", "describe-alarms", "--alarm-types", "MetricAlarm", "CompositeAlarm"}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp cloudwatchDescribeAlarmsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-alarms output: %w", err)
+	}
+
+	var alarms []types.CloudWatchAlarm
+	for _, a := range resp.MetricAlarms {
+		alarms = append(alarms, types.CloudWatchAlarm{
+			Name:               a.AlarmName,
+			ARN:                a.AlarmArn,
+			Type:               "metric",
+			State:              a.StateValue,
+			StateUpdated:       a.StateUpdatedTimestamp,
+			Namespace:          a.Namespace,
+			MetricName:         a.MetricName,
+			Statistic:          a.Statistic,
+			ComparisonOperator: a.ComparisonOperator,
+			Threshold:          a.Threshold,
+			ActionsEnabled:     a.ActionsEnabled,
+			Region:             region,
+		})
+	}
+	for _, a := range resp.CompositeAlarms {
+		alarms = append(alarms, types.CloudWatchAlarm{
+			Name:           a.AlarmName,
+			ARN:            a.AlarmArn,
+			Type:           "composite",
+			State:          a.StateValue,
+			StateUpdated:   a.StateUpdatedTimestamp,
+			ActionsEnabled: a.ActionsEnabled,
+			Region:         region,
+		})
+	}
+	return alarms, nil
+}
@@ -0,0 +1,113 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Cognito
+
+type cognitoListUserPoolsOutput struct {
+	UserPools []struct {
+		ID               string `json:"Id"`
+		Name             string `json:"Name"`
+		Status           string `json:"Status"`
+		CreationDate     string `json:"CreationDate"`
+		LastModifiedDate string `json:"LastModifiedDate"`
+	} `json:"UserPools"`
+}
+
+type cognitoDescribeUserPoolOutput struct {
+	UserPool struct {
+		EstimatedNumberOfUsers int64  `json:"EstimatedNumberOfUsers"`
+		MfaConfiguration       string `json:"MfaConfiguration"`
+		UserPoolTier           string `json:"UserPoolTier"`
+	} `json:"UserPool"`
+}
+
+type cognitoListIdentityPoolsOutput struct {
+	IdentityPools []struct {
+		IdentityPoolID   string `json:"IdentityPoolId"`
+		IdentityPoolName string `json:"IdentityPoolName"`
+	} `json:"IdentityPools"`
+}
+
+// cognitoPageSize is passed as --max-results, which both list calls require.
+const cognitoPageSize = "60"
+
+type cognitoInventory struct {
+	userPools     []types.CognitoUserPool
+	identityPools []types.CognitoIdentityPool
+}
+
+func (s *resourceService) getCognito(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getCognitoSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "cognito", Message: msg}
+	for _, inv := range inventories {
+		res.CognitoUserPools = append(res.CognitoUserPools, inv.userPools...)
+		res.CognitoIdentityPools = append(res.CognitoIdentityPools, inv.identityPools...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getCognitoSingleRegion(ctx context.Context, region string) ([]cognitoInventory, error) {
+	var inv cognitoInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"cognito-idp", "list-user-pools", "--max-results", cognitoPageSize}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var pools cognitoListUserPoolsOutput
+	if err := json.Unmarshal(out, &pools); err != nil {
+		return nil, fmt.Errorf("failed to parse list-user-pools output: %w", err)
+	}
+
+	for _, p := range pools.UserPools {
+		// The user estimate is only available from describe-user-pool.
+		out, err := s.exec.RunJSON(ctx, regionArgs([]string{"cognito-idp", "describe-user-pool", "--user-pool-id", p.ID}, region)...)
+		if err != nil {
+			return nil, err
+		}
+		var detail cognitoDescribeUserPoolOutput
+		if err := json.Unmarshal(out, &detail); err != nil {
+			return nil, fmt.Errorf("failed to parse describe-user-pool output: %w", err)
+		}
+
+		inv.userPools = append(inv.userPools, types.CognitoUserPool{
+			ID:             p.ID,
+			Name:           p.Name,
+			Status:         p.Status,
+			Tier:           detail.UserPool.UserPoolTier,
+			EstimatedUsers: detail.UserPool.EstimatedNumberOfUsers,
+			MFA:            detail.UserPool.MfaConfiguration,
+			CreationDate:   p.CreationDate,
+			LastModified:   p.LastModifiedDate,
+			Region:         region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"cognito-identity", "list-identity-pools", "--max-results", cognitoPageSize}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var identityPools cognitoListIdentityPoolsOutput
+	if err := json.Unmarshal(out, &identityPools); err != nil {
+		return nil, fmt.Errorf("failed to parse list-identity-pools output: %w", err)
+	}
+	for _, p := range identityPools.IdentityPools {
+		inv.identityPools = append(inv.identityPools, types.CognitoIdentityPool{
+			ID:     p.IdentityPoolID,
+			Name:   p.IdentityPoolName,
+			Region: region,
+		})
+	}
+
+	return []cognitoInventory{inv}, nil
+}
@@ -0,0 +1,189 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Per-service usage-type breakdown
+
+func (s *costService) GetServiceBreakdown(ctx context.Context, service, start, end string) (types.ServiceCostBreakdown, error) {
+	service = strings.TrimSpace(service)
+	if service == "" {
+		return types.ServiceCostBreakdown{}, fmt.Errorf("service is required")
+	}
+
+	// Resolve drilldown keys against the cached service list for the same
+	// period, so "ec2" covers both "Amazon Elastic Compute Cloud - Compute"
+	// and "EC2 - Other".
+	cached, err := s.getOrFetch(ctx, start, end, types.CostFilter{})
+	if err != nil {
+		return types.ServiceCostBreakdown{}, err
+	}
+	ceServices := resolveCostServices(service, cached.Services)
+
+	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(start, end)
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.ServiceCostBreakdown{}, err
+	}
+	cacheKey := fmt.Sprintf("usage-breakdown:%s:%s:%s:%s", profile, strings.Join(ceServices, "|"), ceStart, ceEnd)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.Breakdown, nil
+	}
+
+	breakdown, err := s.fetchServiceBreakdown(ctx, ceServices, ceStart, ceEnd)
+	if err != nil {
+		return types.ServiceCostBreakdown{}, err
+	}
+	breakdown.Service = service
+	breakdown.Start = displayStart
+	breakdown.End = displayEnd
+
+	s.cache.Set(cacheKey, CachedCost{Breakdown: breakdown})
+	return breakdown, nil
+}
+
+// resolveCostServices returns the Cost Explorer SERVICE values to filter on.
+// An exact service name wins; otherwise every service sharing the drilldown
+// key is used. Unknown names are passed through unchanged.
+func resolveCostServices(service string, costs []types.ServiceCost) []string {
+	for _, c := range costs {
+		if strings.EqualFold(c.Service, service) {
+			return []string{c.Service}
+		}
+	}
+
+	key := strings.ToLower(service)
+	var names []string
+	for _, c := range costs {
+		// Zero-cost entries include the synthetic ones added for drilldown,
+		// which are not real Cost Explorer services.
+		if c.DrilldownKey == key && c.Cost != 0 {
+			names = append(names, c.Service)
+		}
+	}
+	if len(names) == 0 {
+		return []string{service}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *costService) fetchServiceBreakdown(ctx context.Context, ceServices []string, ceStart, ceEnd string) (types.ServiceCostBreakdown, error) {
+	filter, err := json.Marshal(map[string]any{
+		"Dimensions": map[string]any{
+			"Key":    "SERVICE",
+			"Values": ceServices,
+		},
+	})
+	if err != nil {
+		return types.ServiceCostBreakdown{}, err
+	}
+
+	args := []string{
+		"ce", "get-cost-and-usage",
+		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
+		"--granularity", "MONTHLY",
+		"--metrics", "UnblendedCost", "UsageQuantity",
+		"--filter", string(filter),
+		"--group-by", "Type=DIMENSION,Key=USAGE_TYPE",
+	}
+
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return types.ServiceCostBreakdown{}, err
+	}
+
+	var resp ceResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.ServiceCostBreakdown{}, fmt.Errorf("failed to parse cost explorer USAGE_TYPE response: %w", err)
+	}
+
+	// Ranges spanning several months come back as one result per month, so
+	// sum each usage type across all of them.
+	byType := make(map[string]*types.UsageTypeCost)
+	breakdown := types.ServiceCostBreakdown{
+		Services: ceServices,
+		Currency: "USD",
+	}
+	for _, r := range resp.ResultsByTime {
+		for _, g := range r.Groups {
+			if len(g.Keys) == 0 {
+				continue
+			}
+			cost, ok := g.Metrics["UnblendedCost"]
+			if !ok {
+				continue
+			}
+			amount, err := strconv.ParseFloat(cost.Amount, 64)
+			if err != nil {
+				continue
+			}
+
+			usageType := g.Keys[0]
+			ut, ok := byType[usageType]
+			if !ok {
+				ut = &types.UsageTypeCost{
+					UsageType: usageType,
+					Category:  usageTypeCategory(usageType),
+					Currency:  cost.Unit,
+				}
+				byType[usageType] = ut
+			}
+			ut.Cost += amount
+			if q, ok := g.Metrics["UsageQuantity"]; ok {
+				if v, err := strconv.ParseFloat(q.Amount, 64); err == nil {
+					ut.Usage += v
+					ut.UsageUnit = q.Unit
+				}
+			}
+
+			breakdown.Total += amount
+			breakdown.Currency = cost.Unit
+		}
+	}
+
+	for _, ut := range byType {
+		breakdown.UsageTypes = append(breakdown.UsageTypes, *ut)
+	}
+	sort.Slice(breakdown.UsageTypes, func(i, j int) bool {
+		return breakdown.UsageTypes[i].Cost > breakdown.UsageTypes[j].Cost
+	})
+	return breakdown, nil
+}
+
+// usageTypeCategory maps a usage type such as "USE1-EBS:VolumeUsage.gp3" to a
+// coarse component name. Usage types carry a region prefix, so match on
+// substrings rather than prefixes.
+func usageTypeCategory(usageType string) string {
+	switch {
+	case strings.Contains(usageType, "BoxUsage"), strings.Contains(usageType, "SpotUsage"),
+		strings.Contains(usageType, "DedicatedUsage"), strings.Contains(usageType, "HostUsage"),
+		strings.Contains(usageType, "InstanceUsage"), strings.Contains(usageType, "Lambda-GB-Second"):
+		return "Compute"
+	case strings.Contains(usageType, "EBS:"), strings.Contains(usageType, "EBSOptimized"):
+		return "EBS"
+	case strings.Contains(usageType, "DataTransfer"), strings.Contains(usageType, "-Bytes"),
+		strings.Contains(usageType, "CloudFront-In"), strings.Contains(usageType, "CloudFront-Out"):
+		return "Data Transfer"
+	case strings.Contains(usageType, "NatGateway"):
+		return "NAT Gateway"
+	case strings.Contains(usageType, "ElasticIP"), strings.Contains(usageType, "PublicIPv4"):
+		return "Public IP"
+	case strings.Contains(usageType, "LoadBalancer"), strings.Contains(usageType, "LCUUsage"):
+		return "Load Balancer"
+	case strings.Contains(usageType, "TimedStorage"), strings.Contains(usageType, "StorageUsage"):
+		return "Storage"
+	case strings.Contains(usageType, "Requests"), strings.Contains(usageType, "Request"):
+		return "Requests"
+	default:
+		return "Other"
+	}
+}
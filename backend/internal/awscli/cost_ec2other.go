@@ -0,0 +1,94 @@
+package awscli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// EC2-Other decomposition
+
+// ec2OtherService is the Cost Explorer SERVICE value for EC2 charges that are
+// not instance hours.
+const ec2OtherService = "EC2 - Other"
+
+func (s *costService) GetEC2OtherCosts(ctx context.Context, start, end string) (types.EC2OtherBreakdown, error) {
+	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(start, end)
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.EC2OtherBreakdown{}, err
+	}
+	cacheKey := fmt.Sprintf("ec2-other:%s:%s:%s", profile, ceStart, ceEnd)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.EC2Other, nil
+	}
+
+	usage, err := s.fetchServiceBreakdown(ctx, []string{ec2OtherService}, ceStart, ceEnd)
+	if err != nil {
+		return types.EC2OtherBreakdown{}, err
+	}
+
+	breakdown := types.EC2OtherBreakdown{
+		Total:    usage.Total,
+		Currency: usage.Currency,
+		Start:    displayStart,
+		End:      displayEnd,
+	}
+	buckets := make(map[string]*types.EC2OtherBucket)
+	for _, ut := range usage.UsageTypes {
+		name := ec2OtherBucket(ut.UsageType)
+		b, ok := buckets[name]
+		if !ok {
+			b = &types.EC2OtherBucket{Name: name}
+			buckets[name] = b
+		}
+		b.Cost += ut.Cost
+		// Usage types arrive sorted by cost, so each bucket's are too.
+		b.UsageTypes = append(b.UsageTypes, ut)
+	}
+	for _, b := range buckets {
+		b.Percent = percentOf(b.Cost, breakdown.Total)
+		breakdown.Buckets = append(breakdown.Buckets, *b)
+	}
+	sort.Slice(breakdown.Buckets, func(i, j int) bool {
+		return breakdown.Buckets[i].Cost > breakdown.Buckets[j].Cost
+	})
+
+	s.cache.Set(cacheKey, CachedCost{EC2Other: breakdown})
+	return breakdown, nil
+}
+
+// ec2OtherBucket names the component an "EC2 - Other" usage type belongs to.
+// It is finer grained than usageTypeCategory, which has a single bucket each
+// for EBS and data transfer.
+func ec2OtherBucket(usageType string) string {
+	switch {
+	case strings.Contains(usageType, "NatGateway-Hours"):
+		return "NAT Gateway hours"
+	case strings.Contains(usageType, "NatGateway-Bytes"):
+		return "NAT Gateway data processed"
+	case strings.Contains(usageType, "EBS:Snapshot"), strings.Contains(usageType, "EBS:FastSnapshotRestore"):
+		return "EBS snapshots"
+	case strings.Contains(usageType, "EBS:VolumeP-IOPS"), strings.Contains(usageType, "EBS:VolumeP-Throughput"),
+		strings.Contains(usageType, "EBS:VolumeIOUsage"):
+		return "EBS IOPS and throughput"
+	case strings.Contains(usageType, "EBS:"):
+		return "EBS volumes"
+	case strings.Contains(usageType, "ElasticIP:IdleAddress"), strings.Contains(usageType, "ElasticIP:AdditionalAddress"),
+		strings.Contains(usageType, "PublicIPv4:IdleAddress"):
+		return "Idle Elastic IPs"
+	case strings.Contains(usageType, "PublicIPv4:InUseAddress"), strings.Contains(usageType, "ElasticIP:"):
+		return "Public IPv4 addresses"
+	case strings.Contains(usageType, "DataTransfer-Regional-Bytes"):
+		return "Inter-AZ data transfer"
+	case strings.Contains(usageType, "DataTransfer-Out-Bytes"), strings.Contains(usageType, "AWS-Out-Bytes"):
+		return "Data transfer out"
+	case strings.Contains(usageType, "DataTransfer"), strings.Contains(usageType, "-Bytes"):
+		return "Other data transfer"
+	default:
+		return "Other"
+	}
+}
@@ -0,0 +1,82 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Cost Explorer filters
+
+// costFilterArgs translates filter into a --filter argument for
+// get-cost-and-usage, along with a stable key for caching. Both are empty for
+// a zero filter. Service drilldown keys are resolved against the unfiltered
+// service list for the same period.
+func (s *costService) costFilterArgs(ctx context.Context, start, end string, filter types.CostFilter) ([]string, string, error) {
+	services := cleanFilterValues(filter.Services)
+	regions := cleanFilterValues(filter.Regions)
+	usageTypes := cleanFilterValues(filter.UsageTypes)
+
+	if len(services) > 0 {
+		cached, err := s.getOrFetch(ctx, start, end, types.CostFilter{})
+		if err != nil {
+			return nil, "", err
+		}
+		var resolved []string
+		for _, svc := range services {
+			resolved = append(resolved, resolveCostServices(svc, cached.Services)...)
+		}
+		services = cleanFilterValues(resolved)
+	}
+
+	var exprs []map[string]any
+	var keyParts []string
+	add := func(dimension string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		exprs = append(exprs, map[string]any{
+			"Dimensions": map[string]any{"Key": dimension, "Values": values},
+		})
+		keyParts = append(keyParts, dimension+"="+strings.Join(values, ","))
+	}
+	add("SERVICE", services)
+	add("REGION", regions)
+	add("USAGE_TYPE", usageTypes)
+
+	var expr any
+	switch len(exprs) {
+	case 0:
+		return nil, "", nil
+	case 1:
+		expr = exprs[0]
+	default:
+		expr = map[string]any{"And": exprs}
+	}
+
+	data, err := json.Marshal(expr)
+	if err != nil {
+		return nil, "", err
+	}
+	return []string{"--filter", string(data)}, strings.Join(keyParts, ";"), nil
+}
+
+// cleanFilterValues trims, de-duplicates and sorts values so equivalent
+// filters share a cache entry.
+func cleanFilterValues(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
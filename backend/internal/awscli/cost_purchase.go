@@ -0,0 +1,123 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Purchase option breakdown
+
+func (s *costService) GetPurchaseTypeCosts(ctx context.Context, start, end string, filter types.CostFilter) (types.PurchaseTypeReport, error) {
+	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(start, end)
+
+	filterArgs, filterKey, err := s.costFilterArgs(ctx, start, end, filter)
+	if err != nil {
+		return types.PurchaseTypeReport{}, err
+	}
+
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.PurchaseTypeReport{}, err
+	}
+	cacheKey := fmt.Sprintf("purchase-types:%s:%s:%s:%s", profile, ceStart, ceEnd, filterKey)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.Purchases, nil
+	}
+
+	report, err := s.fetchPurchaseTypeCosts(ctx, ceStart, ceEnd, filterArgs)
+	if err != nil {
+		return types.PurchaseTypeReport{}, err
+	}
+	report.Start = displayStart
+	report.End = displayEnd
+
+	s.cache.Set(cacheKey, CachedCost{Purchases: report})
+	return report, nil
+}
+
+// fetchPurchaseTypeCosts uses AmortizedCost so Reserved Instance and Savings
+// Plans upfront fees are spread over the usage they cover; with
+// UnblendedCost that usage would show up as nearly free.
+func (s *costService) fetchPurchaseTypeCosts(ctx context.Context, ceStart, ceEnd string, filterArgs []string) (types.PurchaseTypeReport, error) {
+	args := []string{
+		"ce", "get-cost-and-usage",
+		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
+		"--granularity", "MONTHLY",
+		"--metrics", "AmortizedCost",
+		"--group-by", "Type=DIMENSION,Key=PURCHASE_TYPE",
+	}
+	args = append(args, filterArgs...)
+
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return types.PurchaseTypeReport{}, err
+	}
+
+	var resp ceResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.PurchaseTypeReport{}, fmt.Errorf("failed to parse cost explorer PURCHASE_TYPE response: %w", err)
+	}
+
+	report := types.PurchaseTypeReport{Currency: "USD"}
+	byType := make(map[string]float64)
+	for _, r := range resp.ResultsByTime {
+		for _, g := range r.Groups {
+			if len(g.Keys) == 0 {
+				continue
+			}
+			metric, ok := g.Metrics["AmortizedCost"]
+			if !ok {
+				continue
+			}
+			amount, err := strconv.ParseFloat(metric.Amount, 64)
+			if err != nil {
+				continue
+			}
+			report.Currency = metric.Unit
+			byType[g.Keys[0]] += amount
+		}
+	}
+
+	for purchaseType, cost := range byType {
+		discounted := discountedPurchaseType(purchaseType)
+		report.PurchaseTypes = append(report.PurchaseTypes, types.PurchaseTypeCost{
+			PurchaseType: purchaseType,
+			Cost:         cost,
+			Discounted:   discounted,
+		})
+		// Credits and refunds have no purchase type and can be negative;
+		// leave them out of the share calculation.
+		if cost > 0 {
+			report.Total += cost
+			if discounted {
+				report.Discounted += cost
+			}
+		}
+	}
+	for i := range report.PurchaseTypes {
+		if c := report.PurchaseTypes[i].Cost; c > 0 {
+			report.PurchaseTypes[i].Percent = percentOf(c, report.Total)
+		}
+	}
+	sort.Slice(report.PurchaseTypes, func(i, j int) bool {
+		return report.PurchaseTypes[i].Cost > report.PurchaseTypes[j].Cost
+	})
+
+	report.DiscountedPercent = percentOf(report.Discounted, report.Total)
+	return report, nil
+}
+
+// discountedPurchaseType reports whether a PURCHASE_TYPE value is priced
+// below On Demand.
+func discountedPurchaseType(purchaseType string) bool {
+	lower := strings.ToLower(purchaseType)
+	return strings.Contains(lower, "spot") ||
+		strings.Contains(lower, "reserved") ||
+		strings.Contains(lower, "savings plan")
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/local/aws-local-dashboard/internal/cache"
+	"github.com/local/aws-local-dashboard/internal/currency"
+	"github.com/local/aws-local-dashboard/internal/history"
 	"github.com/local/aws-local-dashboard/internal/profiles"
 	"github.com/local/aws-local-dashboard/internal/services"
 	"github.com/local/aws-local-dashboard/internal/types"
@@ -17,56 +20,198 @@ import (
 
 // CachedCost is used by the cost cache.
 type CachedCost struct {
-	Overview types.CostOverview
-	Services []types.ServiceCost
+	Overview   types.CostOverview
+	Services   []types.ServiceCost
+	Breakdown  types.ServiceCostBreakdown
+	TimeSeries types.CostTimeSeries
+	Untagged   types.UntaggedCostReport
+	Purchases  types.PurchaseTypeReport
+	Sparklines types.ServiceSparklines
+	EC2Other   types.EC2OtherBreakdown
+	// Raw is a Cost Explorer response, cached by runCE under the full set of
+	// query parameters.
+	Raw []byte
 }
 
 type costService struct {
 	exec           Executor
-	cache          *cache.Cache[CachedCost]
+	cache          cache.Cache[CachedCost]
 	profileManager *profiles.Manager
+	converter      *currency.Converter
+	history        *history.Store
+	status         ceStatus
+	inflight       cache.Group[[]byte]
 }
 
 // NewCostService creates a CostService implementation backed by the AWS CLI.
-func NewCostService(exec Executor, cache *cache.Cache[CachedCost], profileManager *profiles.Manager) services.CostService {
+// converter may be nil when no display currency is configured, and history
+// nil to disable the on-disk cost history.
+func NewCostService(exec Executor, cache cache.Cache[CachedCost], profileManager *profiles.Manager, converter *currency.Converter, history *history.Store) services.CostService {
 	return &costService{
 		exec:           exec,
 		cache:          cache,
 		profileManager: profileManager,
+		converter:      converter,
+		history:        history,
+		status:         ceStatus{sessionStart: time.Now().UTC()},
 	}
 }
 
-func (s *costService) GetCostOverview(ctx context.Context, start, end string) (types.CostOverview, error) {
-	cached, err := s.getOrFetch(ctx, start, end)
-	return cached.Overview, err
+func (s *costService) GetCostOverview(ctx context.Context, start, end string, filter types.CostFilter) (types.CostOverview, error) {
+	cached, err := s.getOrFetch(ctx, start, end, filter)
+	if err != nil {
+		return types.CostOverview{}, err
+	}
+	return s.convertOverview(ctx, cached.Overview), nil
+}
+
+func (s *costService) GetServiceCosts(ctx context.Context, start, end string, filter types.CostFilter) ([]types.ServiceCost, error) {
+	cached, err := s.getOrFetch(ctx, start, end, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertServiceCosts(ctx, cached.Services), nil
+}
+
+// convertOverview adds display-currency amounts to o. Conversion is applied
+// after the cache so changing rates never requires refetching costs.
+func (s *costService) convertOverview(ctx context.Context, o types.CostOverview) types.CostOverview {
+	if s.converter == nil {
+		return o
+	}
+	rate, err := s.converter.Rate(ctx, o.Currency)
+	if err != nil {
+		log.Printf("cost: %v", err)
+		return o
+	}
+	o.Converted = &types.ConvertedCostOverview{
+		Currency:            s.converter.Target(),
+		Rate:                rate,
+		Total:               o.Total * rate,
+		NetTotal:            o.NetTotal * rate,
+		CreditsApplied:      o.CreditsApplied * rate,
+		Tax:                 o.Tax * rate,
+		Refunds:             o.Refunds * rate,
+		Support:             o.Support * rate,
+		Fees:                o.Fees * rate,
+		OtherCharges:        o.OtherCharges * rate,
+		PreviousPeriodTotal: o.PreviousPeriodTotal * rate,
+	}
+	return o
+}
+
+// convertServiceCosts returns a copy of costs with ConvertedCost filled in,
+// leaving the cached slice untouched.
+func (s *costService) convertServiceCosts(ctx context.Context, costs []types.ServiceCost) []types.ServiceCost {
+	if s.converter == nil {
+		return costs
+	}
+	out := make([]types.ServiceCost, len(costs))
+	for i, c := range costs {
+		if rate, err := s.converter.Rate(ctx, c.Currency); err == nil {
+			converted := c.Cost * rate
+			c.ConvertedCost = &converted
+			c.DisplayCurrency = s.converter.Target()
+		}
+		out[i] = c
+	}
+	return out
 }
 
-func (s *costService) GetServiceCosts(ctx context.Context, start, end string) ([]types.ServiceCost, error) {
-	cached, err := s.getOrFetch(ctx, start, end)
-	return cached.Services, err
+// profileKey scopes cache keys to the profile ctx queries: the active one
+// unless a multi-account request chose another (see profiles.WithProfile),
+// or its account and principal for a shared cache (see cacheScope).
+func (s *costService) profileKey(ctx context.Context) (string, error) {
+	return cacheScope(ctx, s.profileManager, s.cache.Shared())
 }
 
-func (s *costService) getOrFetch(ctx context.Context, userStart, userEnd string) (CachedCost, error) {
-	activeKey := "system"
+// localProfile is the ID of the profile ctx queries, or "system".
+func (s *costService) localProfile(ctx context.Context) string {
 	if s.profileManager != nil {
-		if id := s.profileManager.ActiveID(); id != "" {
-			activeKey = id
+		if id := s.profileManager.ProfileID(ctx); id != "" {
+			return id
 		}
 	}
+	return "system"
+}
+
+// ForgetProfileCosts removes the cached cost results of profile id. Every
+// cost cache key has the form "<kind>:<profile>:...". Entries of a shared
+// cache are kept: they are keyed by account, not by this dashboard's
+// profiles, and other dashboards may still use them.
+func ForgetProfileCosts(c cache.Cache[CachedCost], id string) {
+	if c.Shared() {
+		return
+	}
+	c.DeleteFunc(func(key string) bool {
+		_, rest, _ := strings.Cut(key, ":")
+		return strings.HasPrefix(rest, id+":")
+	})
+}
+
+func (s *costService) getOrFetch(ctx context.Context, userStart, userEnd string, filter types.CostFilter) (CachedCost, error) {
+	profile := s.localProfile(ctx)
 	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(userStart, userEnd)
-	cacheKey := fmt.Sprintf("cost-and-services:%s:%s:%s", activeKey, ceStart, ceEnd)
+
+	filterArgs, filterKey, err := s.costFilterArgs(ctx, userStart, userEnd, filter)
+	if err != nil {
+		return CachedCost{}, err
+	}
+
+	scope, err := s.profileKey(ctx)
+	if err != nil {
+		return CachedCost{}, err
+	}
+	cacheKey := fmt.Sprintf("cost-and-services:%s:%s:%s:%s", scope, ceStart, ceEnd, filterKey)
 	if val, ok := s.cache.Get(cacheKey); ok {
 		return val, nil
 	}
 
-	fetched, err := s.fetchFromAWS(ctx, ceStart, ceEnd, displayStart, displayEnd)
+	// The history only holds unfiltered results.
+	useHistory := s.history != nil && filterKey == ""
+	if useHistory {
+		if e, ok := s.history.Lookup(profile, ceStart, ceEnd); ok && settled(ceEnd, e.FetchedAt) {
+			val := CachedCost{Overview: e.Overview, Services: e.Services}
+			s.cache.Set(cacheKey, val)
+			return val, nil
+		}
+	}
+
+	fetched, err := s.fetchFromAWS(ctx, ceStart, ceEnd, displayStart, displayEnd, filterArgs)
 	if err != nil {
 		return CachedCost{}, err
 	}
 	s.cache.Set(cacheKey, fetched)
+
+	if useHistory {
+		err := s.history.Record(history.Entry{
+			Profile:  profile,
+			Start:    ceStart,
+			End:      ceEnd,
+			Overview: fetched.Overview,
+			Services: fetched.Services,
+		})
+		if err != nil {
+			log.Printf("cost: failed to record cost history: %v", err)
+		}
+	}
 	return fetched, nil
 }
 
+// costSettleTime is how long after a period ends Cost Explorer may still
+// revise its figures. Data fetched later than this is treated as final.
+const costSettleTime = 72 * time.Hour
+
+// settled reports whether data for a range ending at ceEnd (exclusive) that
+// was fetched at fetchedAt can be reused instead of querying again.
+func settled(ceEnd string, fetchedAt time.Time) bool {
+	end, err := time.Parse("2006-01-02", ceEnd)
+	if err != nil {
+		return false
+	}
+	return fetchedAt.After(end.Add(costSettleTime))
+}
+
 type ceResponse struct {
 	ResultsByTime []struct {
 		TimePeriod struct {
@@ -87,7 +232,7 @@ type ceResponse struct {
 	} `json:"ResultsByTime"`
 }
 
-func (s *costService) fetchFromAWS(ctx context.Context, ceStart, ceEnd, displayStart, displayEnd string) (CachedCost, error) {
+func (s *costService) fetchFromAWS(ctx context.Context, ceStart, ceEnd, displayStart, displayEnd string, filterArgs []string) (CachedCost, error) {
 	args := []string{
 		"ce", "get-cost-and-usage",
 		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
@@ -95,14 +240,10 @@ func (s *costService) fetchFromAWS(ctx context.Context, ceStart, ceEnd, displayS
 		"--metrics", "UnblendedCost",
 		"--group-by", "Type=DIMENSION,Key=SERVICE",
 	}
+	args = append(args, filterArgs...)
 
-	out, err := s.exec.RunJSON(ctx, args...)
+	out, err := s.runCE(ctx, args...)
 	if err != nil {
-		// If Cost Explorer is disabled, surface a friendlier error.
-		lower := strings.ToLower(err.Error())
-		if strings.Contains(lower, "cost explorer") && strings.Contains(lower, "enable") {
-			return CachedCost{}, services.ErrCostExplorerDisabled
-		}
 		return CachedCost{}, err
 	}
 
@@ -166,32 +307,37 @@ func (s *costService) fetchFromAWS(ctx context.Context, ceStart, ceEnd, displayS
 	// Derive totals and credits using a second query grouped by RECORD_TYPE, so that
 	// we can show "usage before credits", "credits applied", and "net" similar to
 	// the AWS console.
-	usageTotal, creditsApplied, currencyForTotals, err := s.fetchRecordTypeTotals(ctx, ceStart, ceEnd)
+	totals, err := s.fetchRecordTypeTotals(ctx, ceStart, ceEnd, filterArgs)
 	if err != nil {
 		// Fallback to the overall UnblendedCost total if the secondary query fails.
+		totals = recordTypeTotals{Currency: currency}
 		if t, ok := r.Total["UnblendedCost"]; ok {
 			if v, parseErr := strconv.ParseFloat(t.Amount, 64); parseErr == nil {
-				usageTotal = v
-				currency = t.Unit
+				totals.Usage = v
+				totals.Currency = t.Unit
 			}
 		}
-	} else {
-		currency = currencyForTotals
 	}
 
-	netTotal := usageTotal - creditsApplied
+	netTotal := totals.net()
 	if math.Abs(netTotal) < 0.0000001 {
 		netTotal = 0
 	}
 
 	overview := types.CostOverview{
-		Total:          usageTotal,
+		Total:          totals.Usage,
 		NetTotal:       netTotal,
-		CreditsApplied: creditsApplied,
-		Currency:       currency,
+		CreditsApplied: totals.Credits,
+		Tax:            totals.Tax,
+		Refunds:        totals.Refunds,
+		Support:        totals.Support,
+		Fees:           totals.Fees,
+		OtherCharges:   totals.Other,
+		Currency:       totals.Currency,
 		Start:          displayStart,
 		End:            displayEnd,
 	}
+	s.addPreviousPeriod(ctx, &overview, ceStart, ceEnd, filterArgs)
 
 	return CachedCost{
 		Overview: overview,
@@ -199,6 +345,61 @@ func (s *costService) fetchFromAWS(ctx context.Context, ceStart, ceEnd, displayS
 	}, nil
 }
 
+// addPreviousPeriod fills in the month-over-month comparison fields of o. The
+// comparison is best-effort: if the prior period cannot be fetched the fields
+// are left empty rather than failing the whole overview.
+func (s *costService) addPreviousPeriod(ctx context.Context, o *types.CostOverview, ceStart, ceEnd string, filterArgs []string) {
+	prevStart, prevEnd, ok := previousPeriod(ceStart, ceEnd)
+	if !ok {
+		return
+	}
+
+	prev, err := s.fetchRecordTypeTotals(ctx, prevStart, prevEnd, filterArgs)
+	if err != nil {
+		return
+	}
+	prevTotal := prev.Usage
+
+	o.PreviousPeriodTotal = prevTotal
+	o.PreviousStart = prevStart
+	if t, err := time.Parse("2006-01-02", prevEnd); err == nil {
+		o.PreviousEnd = t.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if prevTotal > 0 {
+		pct := (o.Total - prevTotal) / prevTotal * 100
+		o.PercentChange = &pct
+	}
+}
+
+// previousPeriod returns the Cost Explorer range (end exclusive) to compare
+// [ceStart, ceEnd) against. Ranges starting on the 1st are shifted back one
+// calendar month, so October 1-17 compares with September 1-17; any other
+// range is compared with the same number of days immediately before it.
+func previousPeriod(ceStart, ceEnd string) (string, string, bool) {
+	const layout = "2006-01-02"
+
+	start, err1 := time.Parse(layout, ceStart)
+	end, err2 := time.Parse(layout, ceEnd)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		return "", "", false
+	}
+
+	var prevStart, prevEnd time.Time
+	if start.Day() == 1 {
+		prevStart = start.AddDate(0, -1, 0)
+		prevEnd = end.AddDate(0, -1, 0)
+		// AddDate normalises e.g. March 31 to March 3, and a month-aligned
+		// range may be longer than the month before it; never overlap.
+		if prevEnd.After(start) || !prevEnd.After(prevStart) {
+			prevEnd = start
+		}
+	} else {
+		prevEnd = start
+		prevStart = start.Add(-end.Sub(start))
+	}
+	return prevStart.Format(layout), prevEnd.Format(layout), true
+}
+
 // currentMonthRange returns the start and end dates (YYYY-MM-DD) for the current month in UTC.
 func currentMonthRange() (string, string) {
 	now := time.Now().UTC()
@@ -264,14 +465,65 @@ func normalizeServiceName(name string) (displayName string, drilldownKey string)
 		return "Amazon S3", "s3"
 	case strings.Contains(lower, "relational database service"):
 		return "RDS", "rds"
+	case strings.Contains(lower, "lambda"):
+		return "Lambda", "lambda"
+	case strings.Contains(lower, "route 53"):
+		return "Route 53", "route53"
+	case strings.Contains(lower, "certificate manager"):
+		return "Certificate Manager", "acm"
+	case strings.Contains(lower, "key management service"):
+		return "KMS", "kms"
+	case strings.Contains(lower, "simple queue service"):
+		return "SQS", "sqs"
+	case strings.Contains(lower, "elastic beanstalk"):
+		return "Elastic Beanstalk", "elasticbeanstalk"
+	case strings.Contains(lower, "api gateway"):
+		return "API Gateway", "apigateway"
+	case lower == "aws glue":
+		return "Glue", "glue"
+	case strings.Contains(lower, "athena"):
+		return "Athena", "athena"
+	case strings.Contains(lower, "lightsail"):
+		return "Lightsail", "lightsail"
+	case lower == "aws backup":
+		return "AWS Backup", "backup"
+	case strings.Contains(lower, "database migration service"):
+		return "DMS", "dms"
+	case lower == "amazon mq":
+		return "Amazon MQ", "mq"
+	case strings.Contains(lower, "cognito"):
+		return "Cognito", "cognito"
+	case strings.Contains(lower, "eventbridge"):
+		return "EventBridge", "eventbridge"
+	case strings.Contains(lower, "workspaces"):
+		return "WorkSpaces", "workspaces"
 	default:
 		return name, ""
 	}
 }
 
+// recordTypeTotals is the period's cost split by Cost Explorer RECORD_TYPE.
+// Credits and Refunds are reported as positive amounts.
+type recordTypeTotals struct {
+	Usage    float64
+	Credits  float64
+	Tax      float64
+	Refunds  float64
+	Support  float64
+	Fees     float64
+	Other    float64
+	Currency string
+}
+
+// net returns what the account actually pays: usage plus tax, support, fees
+// and any other charges, less credits and refunds.
+func (t recordTypeTotals) net() float64 {
+	return t.Usage + t.Tax + t.Support + t.Fees + t.Other - t.Credits - t.Refunds
+}
+
 // fetchRecordTypeTotals queries Cost Explorer grouped by RECORD_TYPE so we can
-// distinguish usage from credits and compute net totals.
-func (s *costService) fetchRecordTypeTotals(ctx context.Context, start, end string) (usageTotal float64, creditsApplied float64, currency string, err error) {
+// distinguish usage from credits, tax, refunds, support and fees.
+func (s *costService) fetchRecordTypeTotals(ctx context.Context, start, end string, filterArgs []string) (recordTypeTotals, error) {
 	args := []string{
 		"ce", "get-cost-and-usage",
 		"--time-period", fmt.Sprintf("Start=%s,End=%s", start, end),
@@ -279,64 +531,60 @@ func (s *costService) fetchRecordTypeTotals(ctx context.Context, start, end stri
 		"--metrics", "UnblendedCost",
 		"--group-by", "Type=DIMENSION,Key=RECORD_TYPE",
 	}
+	args = append(args, filterArgs...)
 
-	out, execErr := s.exec.RunJSON(ctx, args...)
-	if execErr != nil {
-		return 0, 0, "", execErr
-	}
-
-	var resp struct {
-		ResultsByTime []struct {
-			Groups []struct {
-				Keys    []string `json:"Keys"`
-				Metrics map[string]struct {
-					Amount string `json:"Amount"`
-					Unit   string `json:"Unit"`
-				} `json:"Metrics"`
-			} `json:"Groups"`
-		} `json:"ResultsByTime"`
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return recordTypeTotals{}, err
 	}
 
+	var resp ceResponse
 	if err := json.Unmarshal(out, &resp); err != nil {
-		return 0, 0, "", fmt.Errorf("failed to parse cost explorer RECORD_TYPE response: %w", err)
+		return recordTypeTotals{}, fmt.Errorf("failed to parse cost explorer RECORD_TYPE response: %w", err)
 	}
 
 	if len(resp.ResultsByTime) == 0 {
-		return 0, 0, "", fmt.Errorf("no cost data returned from cost explorer for RECORD_TYPE breakdown")
+		return recordTypeTotals{}, fmt.Errorf("no cost data returned from cost explorer for RECORD_TYPE breakdown")
 	}
 
-	r := resp.ResultsByTime[0]
-
-	currency = "USD"
-	var usage, credits float64
+	totals := recordTypeTotals{Currency: "USD"}
 
-	for _, g := range r.Groups {
-		if len(g.Keys) == 0 {
-			continue
-		}
-		recordType := strings.ToLower(g.Keys[0])
-		metric, ok := g.Metrics["UnblendedCost"]
-		if !ok {
-			continue
-		}
-		amount, parseErr := strconv.ParseFloat(metric.Amount, 64)
-		if parseErr != nil {
-			continue
-		}
-		currency = metric.Unit
-
-		switch recordType {
-		case "usage":
-			usage += amount
-		case "credit":
-			// Credits are represented as negative amounts in Cost Explorer.
-			if amount < 0 {
-				credits += -amount
-			} else {
-				credits += amount
+	// Ranges spanning several months come back as one result per month.
+	for _, r := range resp.ResultsByTime {
+		for _, g := range r.Groups {
+			if len(g.Keys) == 0 {
+				continue
+			}
+			metric, ok := g.Metrics["UnblendedCost"]
+			if !ok {
+				continue
+			}
+			amount, err := strconv.ParseFloat(metric.Amount, 64)
+			if err != nil {
+				continue
+			}
+			totals.Currency = metric.Unit
+
+			switch strings.ToLower(g.Keys[0]) {
+			case "usage":
+				totals.Usage += amount
+			case "credit":
+				// Credits and refunds are negative amounts in Cost Explorer.
+				totals.Credits += math.Abs(amount)
+			case "refund":
+				totals.Refunds += math.Abs(amount)
+			case "tax":
+				totals.Tax += amount
+			case "support":
+				totals.Support += amount
+			case "fee", "rifee", "savingsplanrecurringfee", "savingsplanupfrontfee":
+				totals.Fees += amount
+			default:
+				// Discounts and covered usage keep their sign.
+				totals.Other += amount
 			}
 		}
 	}
 
-	return usage, credits, currency, nil
+	return totals, nil
 }
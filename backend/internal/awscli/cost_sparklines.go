@@ -0,0 +1,125 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Per-service sparklines
+
+// sparklineMonths is how many months of history the sparklines cover.
+const sparklineMonths = 12
+
+func (s *costService) GetServiceSparklines(ctx context.Context) (types.ServiceSparklines, error) {
+	now := time.Now().UTC()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(sparklineMonths - 1), 0)
+	ceStart := first.Format("2006-01-02")
+	ceEnd := now.AddDate(0, 0, 1).Format("2006-01-02")
+
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.ServiceSparklines{}, err
+	}
+	cacheKey := fmt.Sprintf("sparklines:%s:%s:%s", profile, ceStart, ceEnd)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.Sparklines, nil
+	}
+
+	months := make([]string, sparklineMonths)
+	for i := range months {
+		months[i] = first.AddDate(0, i, 0).Format("2006-01")
+	}
+
+	sparklines, err := s.fetchSparklines(ctx, ceStart, ceEnd, months)
+	if err != nil {
+		return types.ServiceSparklines{}, err
+	}
+
+	s.cache.Set(cacheKey, CachedCost{Sparklines: sparklines})
+	return sparklines, nil
+}
+
+// fetchSparklines makes a single MONTHLY query grouped by SERVICE; Cost
+// Explorer returns one ResultsByTime entry per month.
+func (s *costService) fetchSparklines(ctx context.Context, ceStart, ceEnd string, months []string) (types.ServiceSparklines, error) {
+	args := []string{
+		"ce", "get-cost-and-usage",
+		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
+		"--granularity", "MONTHLY",
+		"--metrics", "UnblendedCost",
+		"--group-by", "Type=DIMENSION,Key=SERVICE",
+	}
+
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return types.ServiceSparklines{}, err
+	}
+
+	var resp ceResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.ServiceSparklines{}, fmt.Errorf("failed to parse cost explorer monthly response: %w", err)
+	}
+
+	monthIndex := make(map[string]int, len(months))
+	for i, m := range months {
+		monthIndex[m] = i
+	}
+
+	result := types.ServiceSparklines{
+		Months:   months,
+		Currency: "USD",
+	}
+	byService := make(map[string]*types.ServiceSparkline)
+	for _, r := range resp.ResultsByTime {
+		if len(r.TimePeriod.Start) < 7 {
+			continue
+		}
+		idx, ok := monthIndex[r.TimePeriod.Start[:7]]
+		if !ok {
+			continue
+		}
+		for _, g := range r.Groups {
+			if len(g.Keys) == 0 {
+				continue
+			}
+			metric, ok := g.Metrics["UnblendedCost"]
+			if !ok {
+				continue
+			}
+			amount, err := strconv.ParseFloat(metric.Amount, 64)
+			if err != nil {
+				continue
+			}
+			result.Currency = metric.Unit
+
+			name := g.Keys[0]
+			line, ok := byService[name]
+			if !ok {
+				displayName, drillKey := normalizeServiceName(name)
+				line = &types.ServiceSparkline{
+					Service:      name,
+					DisplayName:  displayName,
+					DrilldownKey: drillKey,
+					Monthly:      make([]float64, len(months)),
+				}
+				byService[name] = line
+			}
+			line.Monthly[idx] += amount
+			line.Total += amount
+		}
+	}
+
+	for _, line := range byService {
+		result.Services = append(result.Services, *line)
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		return result.Services[i].Total > result.Services[j].Total
+	})
+	return result, nil
+}
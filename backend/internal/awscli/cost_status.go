@@ -0,0 +1,192 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Cost Explorer status
+
+// ceRequestCost is what AWS bills per Cost Explorer API request, in USD.
+const ceRequestCost = 0.01
+
+// ceStatus tracks the outcome of Cost Explorer requests made by this process.
+type ceStatus struct {
+	mu            sync.Mutex
+	sessionStart  time.Time
+	calls         int
+	state         string
+	lastSuccessAt time.Time
+	dataEstimated bool
+	lastErrorAt   time.Time
+	lastErrorCode string
+	lastError     string
+}
+
+// ceCallTimeout bounds a Cost Explorer call shared by concurrent requests,
+// which runs on after the request that started it is cancelled.
+const ceCallTimeout = 2 * time.Minute
+
+// runCE runs a Cost Explorer command. Since every request is billed,
+// responses are cached under the profile and the full argument list (metrics,
+// granularity, grouping and filter), and concurrent identical requests share
+// a single CLI call. Callers must not modify the returned bytes.
+func (s *costService) runCE(ctx context.Context, args ...string) ([]byte, error) {
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := "ce:" + profile + ":" + strings.Join(args, "\x00")
+	if val, ok := s.cache.Get(key); ok {
+		return val.Raw, nil
+	}
+
+	// The shared call isn't tied to the request that started it, so one
+	// caller giving up doesn't fail the others; each stops waiting when its
+	// own context is done.
+	out, err, _ := s.inflight.DoContext(ctx, key, func() ([]byte, error) {
+		callCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ceCallTimeout)
+		defer cancel()
+		out, err := s.callCE(callCtx, args...)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(key, CachedCost{Raw: out})
+		return out, nil
+	})
+	if err != nil && unreachable(err) {
+		// Fall back to the response saved by an earlier run, if any, while
+		// AWS can't be reached; the failure still shows in GetStatus.
+		if saved, at, ok := s.cache.Stale(key); ok {
+			log.Printf("cost: serving %s from %s: %v", strings.Join(args[:2], " "), at.Format(time.RFC3339), err)
+			return saved.Raw, nil
+		}
+	}
+	return out, err
+}
+
+// callCE runs a Cost Explorer command, classifying failures by AWS error code
+// and recording the outcome for GetStatus.
+func (s *costService) callCE(ctx context.Context, args ...string) ([]byte, error) {
+	out, err := s.exec.RunJSON(ctx, args...)
+
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+
+	// Every attempt is counted, erring on the side of overestimating cost.
+	s.status.calls++
+
+	if err != nil {
+		classified := costExplorerError(err)
+		s.status.lastErrorAt = time.Now().UTC()
+		s.status.lastError = err.Error()
+		s.status.lastErrorCode = ""
+		var cliErr *CLIError
+		if errors.As(err, &cliErr) {
+			s.status.lastErrorCode = cliErr.Code
+		}
+		switch {
+		case errors.Is(classified, services.ErrCostExplorerDisabled):
+			s.status.state = "disabled"
+		case errors.Is(classified, services.ErrCostExplorerAccessDenied):
+			s.status.state = "access_denied"
+		case errors.Is(classified, services.ErrCostDataUnavailable), errors.Is(classified, services.ErrCostExplorerThrottled):
+			// Cost Explorer answered, so it is enabled.
+			s.status.state = "enabled"
+		default:
+			s.status.state = "error"
+		}
+		return nil, classified
+	}
+
+	var resp struct {
+		ResultsByTime []struct {
+			Estimated bool `json:"Estimated"`
+		} `json:"ResultsByTime"`
+	}
+	estimated := false
+	if json.Unmarshal(out, &resp) == nil {
+		for _, r := range resp.ResultsByTime {
+			estimated = estimated || r.Estimated
+		}
+	}
+
+	s.status.state = "enabled"
+	s.status.lastSuccessAt = time.Now().UTC()
+	s.status.dataEstimated = estimated
+	return out, nil
+}
+
+// costExplorerError classifies a Cost Explorer CLI failure by its AWS error
+// code into the services sentinel errors, so handlers can use errors.Is.
+func costExplorerError(err error) error {
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code == "" {
+		return err
+	}
+
+	switch cliErr.Code {
+	case "AccessDeniedException":
+		// AWS uses this code both for "User not enabled for cost explorer
+		// access" and for missing ce:* permissions; only the message differs.
+		if strings.Contains(strings.ToLower(cliErr.Message), "not enabled") {
+			return services.ErrCostExplorerDisabled
+		}
+		return fmt.Errorf("%w: %s", services.ErrCostExplorerAccessDenied, cliErr.Message)
+	case "DataUnavailableException", "BillExpirationException":
+		return fmt.Errorf("%w: %s", services.ErrCostDataUnavailable, cliErr.Message)
+	case "LimitExceededException", "ThrottlingException":
+		return fmt.Errorf("%w: %s", services.ErrCostExplorerThrottled, cliErr.Message)
+	}
+	return err
+}
+
+func (s *costService) GetStatus(ctx context.Context, probe bool) (types.CostExplorerStatus, error) {
+	s.status.mu.Lock()
+	unknown := s.status.state == ""
+	s.status.mu.Unlock()
+
+	if probe && unknown {
+		// The smallest useful query: yesterday's total, ungrouped. Its error,
+		// if any, is captured in the status.
+		now := time.Now().UTC()
+		_, _ = s.runCE(ctx,
+			"ce", "get-cost-and-usage",
+			"--time-period", fmt.Sprintf("Start=%s,End=%s", now.AddDate(0, 0, -1).Format("2006-01-02"), now.Format("2006-01-02")),
+			"--granularity", "DAILY",
+			"--metrics", "UnblendedCost",
+		)
+	}
+
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+
+	st := types.CostExplorerStatus{
+		State:            s.status.state,
+		DataEstimated:    s.status.dataEstimated,
+		LastErrorCode:    s.status.lastErrorCode,
+		LastError:        s.status.lastError,
+		APICalls:         s.status.calls,
+		EstimatedAPICost: float64(s.status.calls) * ceRequestCost,
+		SessionStart:     s.status.sessionStart.Format(time.RFC3339),
+	}
+	if st.State == "" {
+		st.State = "unknown"
+	}
+	if !s.status.lastSuccessAt.IsZero() {
+		st.LastSuccessAt = s.status.lastSuccessAt.Format(time.RFC3339)
+	}
+	if !s.status.lastErrorAt.IsZero() {
+		st.LastErrorAt = s.status.lastErrorAt.Format(time.RFC3339)
+	}
+	return st, nil
+}
@@ -0,0 +1,92 @@
+package awscli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/cache"
+)
+
+// failingExecutor is an Executor whose commands all return err.
+type failingExecutor struct{ err error }
+
+func (f failingExecutor) RunJSON(context.Context, ...string) ([]byte, error) {
+	return nil, f.err
+}
+
+func TestRunCEFallsBackOnlyWhenUnreachable(t *testing.T) {
+	args := []string{"ce", "get-cost-and-usage", "--granularity", "MONTHLY"}
+	for _, tt := range fallbackCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := staleCache[CachedCost](t)
+			s := NewCostService(failingExecutor{tt.err}, c, nil, nil, nil).(*costService)
+			c.Set("ce:system:"+strings.Join(args, "\x00"), CachedCost{Raw: []byte(`{}`)})
+
+			out, err := s.runCE(context.Background(), args...)
+			if tt.fallback {
+				if err != nil || string(out) != `{}` {
+					t.Errorf("got %s, %v; want the saved response", out, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("got the saved response %s; want the error %v", out, tt.err)
+			}
+		})
+	}
+}
+
+// blockingExecutor counts its commands and answers them once released, or
+// fails them when their context ends first.
+type blockingExecutor struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingExecutor) RunJSON(ctx context.Context, _ ...string) ([]byte, error) {
+	e.calls.Add(1)
+	e.started <- struct{}{}
+	select {
+	case <-e.release:
+		return []byte(`{"ResultsByTime":[]}`), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestRunCESurvivesFirstCallerCancelling(t *testing.T) {
+	exec := &blockingExecutor{started: make(chan struct{}, 2), release: make(chan struct{})}
+	s := NewCostService(exec, cache.New[CachedCost](time.Minute), nil, nil, nil).(*costService)
+	args := []string{"ce", "get-cost-and-usage"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := s.runCE(ctx, args...)
+		first <- err
+	}()
+	<-exec.started
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	// The call goes on, so a caller arriving now shares it or its result.
+	second := make(chan error, 1)
+	go func() {
+		_, err := s.runCE(context.Background(), args...)
+		second <- err
+	}()
+	close(exec.release)
+	if err := <-second; err != nil {
+		t.Fatalf("waiting caller got %v", err)
+	}
+	if n := exec.calls.Load(); n != 1 {
+		t.Errorf("ran %d Cost Explorer calls, want 1 shared call", n)
+	}
+}
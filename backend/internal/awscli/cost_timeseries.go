@@ -0,0 +1,119 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Cost time series
+
+// maxHourlyDays is the longest range Cost Explorer serves at HOURLY
+// granularity; hourly data is also only retained for the last 14 days.
+const maxHourlyDays = 14
+
+func (s *costService) GetCostTimeSeries(ctx context.Context, start, end, granularity string, filter types.CostFilter) (types.CostTimeSeries, error) {
+	granularity = strings.ToUpper(strings.TrimSpace(granularity))
+	if granularity == "" {
+		granularity = "DAILY"
+	}
+
+	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(start, end)
+
+	switch granularity {
+	case "DAILY", "MONTHLY":
+	case "HOURLY":
+		if days := rangeDays(ceStart, ceEnd); days > maxHourlyDays {
+			return types.CostTimeSeries{}, fmt.Errorf("%w: HOURLY supports at most %d days, got %d", services.ErrInvalidGranularity, maxHourlyDays, days)
+		}
+	default:
+		return types.CostTimeSeries{}, fmt.Errorf("%w: %q (use DAILY, MONTHLY or HOURLY)", services.ErrInvalidGranularity, granularity)
+	}
+
+	filterArgs, filterKey, err := s.costFilterArgs(ctx, start, end, filter)
+	if err != nil {
+		return types.CostTimeSeries{}, err
+	}
+
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.CostTimeSeries{}, err
+	}
+	cacheKey := fmt.Sprintf("timeseries:%s:%s:%s:%s:%s", profile, granularity, ceStart, ceEnd, filterKey)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.TimeSeries, nil
+	}
+
+	series, err := s.fetchTimeSeries(ctx, ceStart, ceEnd, granularity, filterArgs)
+	if err != nil {
+		return types.CostTimeSeries{}, err
+	}
+	series.Start = displayStart
+	series.End = displayEnd
+
+	s.cache.Set(cacheKey, CachedCost{TimeSeries: series})
+	return series, nil
+}
+
+func (s *costService) fetchTimeSeries(ctx context.Context, ceStart, ceEnd, granularity string, filterArgs []string) (types.CostTimeSeries, error) {
+	// HOURLY requires full timestamps in the time period.
+	if granularity == "HOURLY" {
+		ceStart += "T00:00:00Z"
+		ceEnd += "T00:00:00Z"
+	}
+
+	args := []string{
+		"ce", "get-cost-and-usage",
+		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
+		"--granularity", granularity,
+		"--metrics", "UnblendedCost",
+	}
+	args = append(args, filterArgs...)
+
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return types.CostTimeSeries{}, err
+	}
+
+	var resp ceResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.CostTimeSeries{}, fmt.Errorf("failed to parse cost explorer time series response: %w", err)
+	}
+
+	series := types.CostTimeSeries{
+		Granularity: granularity,
+		Currency:    "USD",
+	}
+	for _, r := range resp.ResultsByTime {
+		point := types.CostPoint{
+			Start: r.TimePeriod.Start,
+			End:   r.TimePeriod.End,
+		}
+		if t, ok := r.Total["UnblendedCost"]; ok {
+			if v, err := strconv.ParseFloat(t.Amount, 64); err == nil {
+				point.Cost = v
+				series.Currency = t.Unit
+			}
+		}
+		series.Points = append(series.Points, point)
+	}
+	return series, nil
+}
+
+// rangeDays returns the number of days in [ceStart, ceEnd).
+func rangeDays(ceStart, ceEnd string) int {
+	const layout = "2006-01-02"
+
+	start, err1 := time.Parse(layout, ceStart)
+	end, err2 := time.Parse(layout, ceEnd)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
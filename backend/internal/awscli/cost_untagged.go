@@ -0,0 +1,150 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Untagged cost report
+
+func (s *costService) GetUntaggedCosts(ctx context.Context, tagKey, start, end string) (types.UntaggedCostReport, error) {
+	tagKey = strings.TrimSpace(tagKey)
+	if tagKey == "" {
+		return types.UntaggedCostReport{}, fmt.Errorf("tag key is required")
+	}
+
+	ceStart, ceEnd, displayStart, displayEnd := normalizeDateRange(start, end)
+	profile, err := s.profileKey(ctx)
+	if err != nil {
+		return types.UntaggedCostReport{}, err
+	}
+	cacheKey := fmt.Sprintf("untagged:%s:%s:%s:%s", profile, tagKey, ceStart, ceEnd)
+	if val, ok := s.cache.Get(cacheKey); ok {
+		return val.Untagged, nil
+	}
+
+	report, err := s.fetchUntaggedCosts(ctx, tagKey, ceStart, ceEnd)
+	if err != nil {
+		return types.UntaggedCostReport{}, err
+	}
+	report.Start = displayStart
+	report.End = displayEnd
+
+	s.cache.Set(cacheKey, CachedCost{Untagged: report})
+	return report, nil
+}
+
+// fetchUntaggedCosts groups usage by SERVICE and by the tag, so one Cost
+// Explorer query yields both each service's total and its untagged part.
+// Cost Explorer reports untagged spend under the value "<key>$".
+func (s *costService) fetchUntaggedCosts(ctx context.Context, tagKey, ceStart, ceEnd string) (types.UntaggedCostReport, error) {
+	filter, err := json.Marshal(map[string]any{
+		"Dimensions": map[string]any{
+			"Key":    "RECORD_TYPE",
+			"Values": []string{"Usage"},
+		},
+	})
+	if err != nil {
+		return types.UntaggedCostReport{}, err
+	}
+
+	args := []string{
+		"ce", "get-cost-and-usage",
+		"--time-period", fmt.Sprintf("Start=%s,End=%s", ceStart, ceEnd),
+		"--granularity", "MONTHLY",
+		"--metrics", "UnblendedCost",
+		"--filter", string(filter),
+		"--group-by", "Type=DIMENSION,Key=SERVICE", "Type=TAG,Key=" + tagKey,
+	}
+
+	out, err := s.runCE(ctx, args...)
+	if err != nil {
+		return types.UntaggedCostReport{}, err
+	}
+
+	var resp ceResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.UntaggedCostReport{}, fmt.Errorf("failed to parse cost explorer TAG response: %w", err)
+	}
+
+	report := types.UntaggedCostReport{
+		TagKey:   tagKey,
+		Currency: "USD",
+	}
+	byService := make(map[string]*types.UntaggedServiceCost)
+	byValue := make(map[string]float64)
+
+	for _, r := range resp.ResultsByTime {
+		for _, g := range r.Groups {
+			if len(g.Keys) < 2 {
+				continue
+			}
+			metric, ok := g.Metrics["UnblendedCost"]
+			if !ok {
+				continue
+			}
+			amount, err := strconv.ParseFloat(metric.Amount, 64)
+			if err != nil {
+				continue
+			}
+			report.Currency = metric.Unit
+
+			name := g.Keys[0]
+			svc, ok := byService[name]
+			if !ok {
+				displayName, drillKey := normalizeServiceName(name)
+				svc = &types.UntaggedServiceCost{
+					Service:      name,
+					DisplayName:  displayName,
+					DrilldownKey: drillKey,
+				}
+				byService[name] = svc
+			}
+			svc.TotalCost += amount
+			report.Total += amount
+
+			// Tag group keys look like "team$backend"; "team$" is untagged.
+			value := strings.TrimPrefix(g.Keys[1], tagKey+"$")
+			if value == "" {
+				svc.UntaggedCost += amount
+				report.Untagged += amount
+				continue
+			}
+			byValue[value] += amount
+		}
+	}
+
+	for _, svc := range byService {
+		if svc.UntaggedCost == 0 {
+			continue
+		}
+		svc.UntaggedPercent = percentOf(svc.UntaggedCost, svc.TotalCost)
+		report.Services = append(report.Services, *svc)
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		return report.Services[i].UntaggedCost > report.Services[j].UntaggedCost
+	})
+
+	for value, cost := range byValue {
+		report.TagValues = append(report.TagValues, types.TagValueCost{Value: value, Cost: cost})
+	}
+	sort.Slice(report.TagValues, func(i, j int) bool {
+		return report.TagValues[i].Cost > report.TagValues[j].Cost
+	})
+
+	report.UntaggedPercent = percentOf(report.Untagged, report.Total)
+	return report, nil
+}
+
+func percentOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total * 100
+}
@@ -0,0 +1,118 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// DMS
+
+type dmsDescribeReplicationInstancesOutput struct {
+	ReplicationInstances []struct {
+		ReplicationInstanceIdentifier string `json:"ReplicationInstanceIdentifier"`
+		ReplicationInstanceArn        string `json:"ReplicationInstanceArn"`
+		ReplicationInstanceClass      string `json:"ReplicationInstanceClass"`
+		ReplicationInstanceStatus     string `json:"ReplicationInstanceStatus"`
+		AllocatedStorage              int    `json:"AllocatedStorage"`
+		EngineVersion                 string `json:"EngineVersion"`
+		MultiAZ                       bool   `json:"MultiAZ"`
+		PubliclyAccessible            bool   `json:"PubliclyAccessible"`
+		InstanceCreateTime            string `json:"InstanceCreateTime"`
+	} `json:"ReplicationInstances"`
+}
+
+type dmsDescribeReplicationTasksOutput struct {
+	ReplicationTasks []struct {
+		ReplicationTaskIdentifier string `json:"ReplicationTaskIdentifier"`
+		ReplicationTaskArn        string `json:"ReplicationTaskArn"`
+		ReplicationInstanceArn    string `json:"ReplicationInstanceArn"`
+		MigrationType             string `json:"MigrationType"`
+		Status                    string `json:"Status"`
+		StopReason                string `json:"StopReason"`
+		LastFailureMessage        string `json:"LastFailureMessage"`
+		ReplicationTaskStats      struct {
+			FullLoadProgressPercent int `json:"FullLoadProgressPercent"`
+		} `json:"ReplicationTaskStats"`
+	} `json:"ReplicationTasks"`
+}
+
+type dmsInventory struct {
+	instances []types.DMSInstance
+	tasks     []types.DMSTask
+}
+
+func (s *resourceService) getDMS(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getDMSSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "dms", Message: msg}
+	for _, inv := range inventories {
+		res.DMSInstances = append(res.DMSInstances, inv.instances...)
+		res.DMSTasks = append(res.DMSTasks, inv.tasks...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getDMSSingleRegion(ctx context.Context, region string) ([]dmsInventory, error) {
+	var inv dmsInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"dms", "describe-replication-instances"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var instances dmsDescribeReplicationInstancesOutput
+	if err := json.Unmarshal(out, &instances); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-replication-instances output: %w", err)
+	}
+
+	names := make(map[string]string, len(instances.ReplicationInstances))
+	for _, ri := range instances.ReplicationInstances {
+		names[ri.ReplicationInstanceArn] = ri.ReplicationInstanceIdentifier
+		inv.instances = append(inv.instances, types.DMSInstance{
+			Identifier:         ri.ReplicationInstanceIdentifier,
+			ARN:                ri.ReplicationInstanceArn,
+			Class:              ri.ReplicationInstanceClass,
+			AllocatedStorageGB: ri.AllocatedStorage,
+			Status:             ri.ReplicationInstanceStatus,
+			EngineVersion:      ri.EngineVersion,
+			MultiAZ:            ri.MultiAZ,
+			PubliclyAccessible: ri.PubliclyAccessible,
+			CreateTime:         ri.InstanceCreateTime,
+			Region:             region,
+		})
+	}
+
+	// --without-settings drops the large per-task settings document.
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"dms", "describe-replication-tasks", "--without-settings"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var tasks dmsDescribeReplicationTasksOutput
+	if err := json.Unmarshal(out, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-replication-tasks output: %w", err)
+	}
+	for _, t := range tasks.ReplicationTasks {
+		reason := t.StopReason
+		if t.LastFailureMessage != "" {
+			reason = t.LastFailureMessage
+		}
+
+		inv.tasks = append(inv.tasks, types.DMSTask{
+			Identifier:          t.ReplicationTaskIdentifier,
+			ARN:                 t.ReplicationTaskArn,
+			ReplicationInstance: names[t.ReplicationInstanceArn],
+			MigrationType:       t.MigrationType,
+			Status:              t.Status,
+			StatusReason:        reason,
+			FullLoadPercent:     t.ReplicationTaskStats.FullLoadProgressPercent,
+			Region:              region,
+		})
+	}
+
+	return []dmsInventory{inv}, nil
+}
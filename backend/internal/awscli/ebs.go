@@ -0,0 +1,181 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// EBS volumes
+
+type ec2DescribeVolumesOutput struct {
+	Volumes []struct {
+		VolumeID         string `json:"VolumeId"`
+		Size             int    `json:"Size"`
+		VolumeType       string `json:"VolumeType"`
+		Iops             int    `json:"Iops"`
+		Throughput       int    `json:"Throughput"`
+		Encrypted        bool   `json:"Encrypted"`
+		State            string `json:"State"`
+		AvailabilityZone string `json:"AvailabilityZone"`
+		CreateTime       string `json:"CreateTime"`
+		SnapshotID       string `json:"SnapshotId"`
+		Attachments      []struct {
+			InstanceID string `json:"InstanceId"`
+			Device     string `json:"Device"`
+			State      string `json:"State"`
+		} `json:"Attachments"`
+		Tags []awsTag `json:"Tags"`
+	} `json:"Volumes"`
+}
+
+func (s *resourceService) getEBSVolumes(ctx context.Context, region string) (types.ServiceResources, error) {
+	vols, msg, err := forRegions(ctx, s, region, s.getEBSVolumesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:    "ebs",
+		EBSVolumes: vols,
+		Message:    msg,
+	}, nil
+}
+
+func (s *resourceService) getEBSVolumesSingleRegion(ctx context.Context, region string) ([]types.EBSVolume, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-volumes"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeVolumesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-volumes output: %w", err)
+	}
+
+	var vols []types.EBSVolume
+	for _, v := range resp.Volumes {
+		var instances []string
+		for _, a := range v.Attachments {
+			if a.InstanceID != "" {
+				instances = append(instances, a.InstanceID)
+			}
+		}
+
+		volRegion := region
+		if volRegion == "" {
+			volRegion = regionFromAZ(v.AvailabilityZone)
+		}
+
+		// "available" is the EC2 state for a volume with no attachment; it
+		// keeps billing for provisioned storage while doing nothing.
+		vols = append(vols, types.EBSVolume{
+			VolumeID:         v.VolumeID,
+			Name:             tagValue(v.Tags, "Name"),
+			SizeGiB:          v.Size,
+			VolumeType:       v.VolumeType,
+			Iops:             v.Iops,
+			Throughput:       v.Throughput,
+			Encrypted:        v.Encrypted,
+			State:            v.State,
+			AttachedTo:       instances,
+			Unattached:       v.State == "available",
+			SnapshotID:       v.SnapshotID,
+			AvailabilityZone: v.AvailabilityZone,
+			CreateTime:       v.CreateTime,
+			Region:           volRegion,
+			Tags:             tagMap(v.Tags),
+		})
+	}
+	return vols, nil
+}
+
+// EBS snapshots
+
+type ec2DescribeSnapshotsOutput struct {
+	Snapshots []struct {
+		SnapshotID  string   `json:"SnapshotId"`
+		VolumeID    string   `json:"VolumeId"`
+		VolumeSize  int      `json:"VolumeSize"`
+		StartTime   string   `json:"StartTime"`
+		State       string   `json:"State"`
+		Description string   `json:"Description"`
+		Encrypted   bool     `json:"Encrypted"`
+		StorageTier string   `json:"StorageTier"`
+		Tags        []awsTag `json:"Tags"`
+	} `json:"Snapshots"`
+}
+
+// unknownSourceVolume is the placeholder volume id EC2 reports for snapshots
+// that were copied or imported rather than taken from a volume.
+const unknownSourceVolume = "vol-ffffffff"
+
+func (s *resourceService) getEBSSnapshots(ctx context.Context, region string) (types.ServiceResources, error) {
+	snaps, msg, err := forRegions(ctx, s, region, s.getEBSSnapshotsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:      "snapshots",
+		EBSSnapshots: snaps,
+		Message:      msg,
+	}, nil
+}
+
+func (s *resourceService) getEBSSnapshotsSingleRegion(ctx context.Context, region string) ([]types.EBSSnapshot, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-snapshots", "--owner-ids", "self"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeSnapshotsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-snapshots output: %w", err)
+	}
+	if len(resp.Snapshots) == 0 {
+		return nil, nil
+	}
+
+	// Snapshots only reference volumes in their own region, so one
+	// describe-volumes call per region is enough to find orphans.
+	vols, err := s.getEBSVolumesSingleRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(vols))
+	for _, v := range vols {
+		existing[v.VolumeID] = true
+	}
+
+	now := time.Now()
+	var snaps []types.EBSSnapshot
+	for _, sn := range resp.Snapshots {
+		ageDays := 0
+		if t, err := time.Parse(time.RFC3339, sn.StartTime); err == nil {
+			ageDays = int(now.Sub(t).Hours() / 24)
+		}
+
+		volumeExists := existing[sn.VolumeID]
+		snaps = append(snaps, types.EBSSnapshot{
+			SnapshotID:   sn.SnapshotID,
+			Name:         tagValue(sn.Tags, "Name"),
+			Description:  sn.Description,
+			VolumeID:     sn.VolumeID,
+			SizeGiB:      sn.VolumeSize,
+			State:        sn.State,
+			Encrypted:    sn.Encrypted,
+			StorageTier:  sn.StorageTier,
+			StartTime:    sn.StartTime,
+			AgeDays:      ageDays,
+			VolumeExists: volumeExists,
+			Orphaned:     !volumeExists && sn.VolumeID != unknownSourceVolume,
+			Region:       region,
+			Tags:         tagMap(sn.Tags),
+		})
+	}
+	return snaps, nil
+}
@@ -0,0 +1,132 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Elastic Beanstalk
+
+type ebDescribeApplicationsOutput struct {
+	Applications []struct {
+		ApplicationName string   `json:"ApplicationName"`
+		Description     string   `json:"Description"`
+		DateCreated     string   `json:"DateCreated"`
+		Versions        []string `json:"Versions"`
+	} `json:"Applications"`
+}
+
+type ebDescribeEnvironmentsOutput struct {
+	Environments []struct {
+		EnvironmentName   string `json:"EnvironmentName"`
+		EnvironmentID     string `json:"EnvironmentId"`
+		ApplicationName   string `json:"ApplicationName"`
+		VersionLabel      string `json:"VersionLabel"`
+		SolutionStackName string `json:"SolutionStackName"`
+		PlatformArn       string `json:"PlatformArn"`
+		CNAME             string `json:"CNAME"`
+		Status            string `json:"Status"`
+		Health            string `json:"Health"`
+		HealthStatus      string `json:"HealthStatus"`
+		DateUpdated       string `json:"DateUpdated"`
+		Tier              struct {
+			Name string `json:"Name"`
+		} `json:"Tier"`
+	} `json:"Environments"`
+}
+
+func (s *resourceService) getBeanstalk(ctx context.Context, region string) (types.ServiceResources, error) {
+	apps, msg, err := forRegions(ctx, s, region, s.getBeanstalkApplicationsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	envs, envMsg, err := forRegions(ctx, s, region, s.getBeanstalkEnvironmentsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	if msg == "" {
+		msg = envMsg
+	}
+
+	// Attach environment counts so applications without any running
+	// environment stand out.
+	type appKey struct{ region, name string }
+	envCount := map[appKey]int{}
+	for _, e := range envs {
+		envCount[appKey{e.Region, e.ApplicationName}]++
+	}
+	for i := range apps {
+		apps[i].EnvironmentCount = envCount[appKey{apps[i].Region, apps[i].Name}]
+	}
+
+	return types.ServiceResources{
+		Service:               "elasticbeanstalk",
+		BeanstalkApplications: apps,
+		BeanstalkEnvironments: envs,
+		Message:               msg,
+	}, nil
+}
+
+func (s *resourceService) getBeanstalkApplicationsSingleRegion(ctx context.Context, region string) ([]types.BeanstalkApplication, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"elasticbeanstalk", "describe-applications"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ebDescribeApplicationsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-applications output: %w", err)
+	}
+
+	var apps []types.BeanstalkApplication
+	for _, a := range resp.Applications {
+		apps = append(apps, types.BeanstalkApplication{
+			Name:         a.ApplicationName,
+			Description:  a.Description,
+			DateCreated:  a.DateCreated,
+			VersionCount: len(a.Versions),
+			Region:       region,
+		})
+	}
+	return apps, nil
+}
+
+func (s *resourceService) getBeanstalkEnvironmentsSingleRegion(ctx context.Context, region string) ([]types.BeanstalkEnvironment, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"elasticbeanstalk", "describe-environments"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ebDescribeEnvironmentsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-environments output: %w", err)
+	}
+
+	var envs []types.BeanstalkEnvironment
+	for _, e := range resp.Environments {
+		platform := e.SolutionStackName
+		if platform == "" {
+			platform = e.PlatformArn
+		}
+
+		envs = append(envs, types.BeanstalkEnvironment{
+			Name:            e.EnvironmentName,
+			EnvironmentID:   e.EnvironmentID,
+			ApplicationName: e.ApplicationName,
+			VersionLabel:    e.VersionLabel,
+			Platform:        platform,
+			Tier:            e.Tier.Name,
+			CNAME:           e.CNAME,
+			Status:          e.Status,
+			Health:          e.Health,
+			HealthStatus:    e.HealthStatus,
+			DateUpdated:     e.DateUpdated,
+			Region:          region,
+		})
+	}
+	return envs, nil
+}
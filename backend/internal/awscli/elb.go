@@ -0,0 +1,236 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Load balancers (ALB/NLB/GWLB via elbv2, classic via elb)
+
+type elbv2DescribeLoadBalancersOutput struct {
+	LoadBalancers []struct {
+		LoadBalancerName string `json:"LoadBalancerName"`
+		LoadBalancerArn  string `json:"LoadBalancerArn"`
+		DNSName          string `json:"DNSName"`
+		Scheme           string `json:"Scheme"`
+		Type             string `json:"Type"`
+		VpcID            string `json:"VpcId"`
+		CreatedTime      string `json:"CreatedTime"`
+		State            struct {
+			Code string `json:"Code"`
+		} `json:"State"`
+		AvailabilityZones []struct {
+			ZoneName string `json:"ZoneName"`
+		} `json:"AvailabilityZones"`
+	} `json:"LoadBalancers"`
+}
+
+type elbDescribeLoadBalancersOutput struct {
+	LoadBalancerDescriptions []struct {
+		LoadBalancerName  string   `json:"LoadBalancerName"`
+		DNSName           string   `json:"DNSName"`
+		Scheme            string   `json:"Scheme"`
+		VPCID             string   `json:"VPCId"`
+		CreatedTime       string   `json:"CreatedTime"`
+		AvailabilityZones []string `json:"AvailabilityZones"`
+		Instances         []struct {
+			InstanceID string `json:"InstanceId"`
+		} `json:"Instances"`
+	} `json:"LoadBalancerDescriptions"`
+}
+
+func (s *resourceService) getLoadBalancers(ctx context.Context, region string) (types.ServiceResources, error) {
+	lbs, msg, err := forRegions(ctx, s, region, s.getLoadBalancersSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:       "elb",
+		LoadBalancers: lbs,
+		Message:       msg,
+	}, nil
+}
+
+func (s *resourceService) getLoadBalancersSingleRegion(ctx context.Context, region string) ([]types.LoadBalancer, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"elbv2", "describe-load-balancers"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var v2 elbv2DescribeLoadBalancersOutput
+	if err := json.Unmarshal(out, &v2); err != nil {
+		return nil, fmt.Errorf("failed to parse elbv2 describe-load-balancers output: %w", err)
+	}
+
+	var lbs []types.LoadBalancer
+	for _, lb := range v2.LoadBalancers {
+		azs := make([]string, 0, len(lb.AvailabilityZones))
+		for _, az := range lb.AvailabilityZones {
+			azs = append(azs, az.ZoneName)
+		}
+		sort.Strings(azs)
+
+		lbs = append(lbs, types.LoadBalancer{
+			Name:              lb.LoadBalancerName,
+			ARN:               lb.LoadBalancerArn,
+			Type:              lb.Type,
+			Scheme:            lb.Scheme,
+			DNSName:           lb.DNSName,
+			State:             lb.State.Code,
+			VpcID:             lb.VpcID,
+			AvailabilityZones: azs,
+			CreatedTime:       lb.CreatedTime,
+			Region:            region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"elb", "describe-load-balancers"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var classic elbDescribeLoadBalancersOutput
+	if err := json.Unmarshal(out, &classic); err != nil {
+		return nil, fmt.Errorf("failed to parse elb describe-load-balancers output: %w", err)
+	}
+
+	for _, lb := range classic.LoadBalancerDescriptions {
+		azs := append([]string(nil), lb.AvailabilityZones...)
+		sort.Strings(azs)
+
+		// Classic load balancers have no provisioning state; report them as
+		// active so the column stays comparable with elbv2 results.
+		lbs = append(lbs, types.LoadBalancer{
+			Name:              lb.LoadBalancerName,
+			Type:              "classic",
+			Scheme:            lb.Scheme,
+			DNSName:           lb.DNSName,
+			State:             "active",
+			VpcID:             lb.VPCID,
+			AvailabilityZones: azs,
+			CreatedTime:       lb.CreatedTime,
+			Region:            region,
+		})
+	}
+
+	return lbs, nil
+}
+
+// Target groups
+
+type elbv2DescribeTargetGroupsOutput struct {
+	TargetGroups []struct {
+		TargetGroupName  string   `json:"TargetGroupName"`
+		TargetGroupArn   string   `json:"TargetGroupArn"`
+		Protocol         string   `json:"Protocol"`
+		Port             int      `json:"Port"`
+		TargetType       string   `json:"TargetType"`
+		VpcID            string   `json:"VpcId"`
+		HealthCheckPath  string   `json:"HealthCheckPath"`
+		LoadBalancerArns []string `json:"LoadBalancerArns"`
+	} `json:"TargetGroups"`
+}
+
+type elbv2DescribeTargetHealthOutput struct {
+	TargetHealthDescriptions []struct {
+		TargetHealth struct {
+			State string `json:"State"`
+		} `json:"TargetHealth"`
+	} `json:"TargetHealthDescriptions"`
+}
+
+// maxConcurrentTargetHealth bounds the per-group describe-target-health calls
+// issued within a single region.
+const maxConcurrentTargetHealth = 4
+
+func (s *resourceService) getTargetGroups(ctx context.Context, region string) (types.ServiceResources, error) {
+	groups, msg, err := forRegions(ctx, s, region, s.getTargetGroupsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:      "target-group",
+		TargetGroups: groups,
+		Message:      msg,
+	}, nil
+}
+
+func (s *resourceService) getTargetGroupsSingleRegion(ctx context.Context, region string) ([]types.TargetGroup, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"elbv2", "describe-target-groups"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp elbv2DescribeTargetGroupsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-target-groups output: %w", err)
+	}
+
+	groups := make([]types.TargetGroup, len(resp.TargetGroups))
+	errs := make([]error, len(resp.TargetGroups))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentTargetHealth)
+
+	for i, tg := range resp.TargetGroups {
+		groups[i] = types.TargetGroup{
+			Name:             tg.TargetGroupName,
+			ARN:              tg.TargetGroupArn,
+			Protocol:         tg.Protocol,
+			Port:             tg.Port,
+			TargetType:       tg.TargetType,
+			VpcID:            tg.VpcID,
+			HealthCheckPath:  tg.HealthCheckPath,
+			LoadBalancerArns: tg.LoadBalancerArns,
+			Region:           region,
+		}
+
+		wg.Add(1)
+		go func(i int, arn string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = s.fillTargetHealth(ctx, region, arn, &groups[i])
+		}(i, tg.TargetGroupArn)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// fillTargetHealth counts the targets of a group by health state.
+func (s *resourceService) fillTargetHealth(ctx context.Context, region, arn string, tg *types.TargetGroup) error {
+	args := []string{"elbv2", "describe-target-health", "--target-group-arn", arn}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return err
+	}
+
+	var resp elbv2DescribeTargetHealthOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return fmt.Errorf("failed to parse describe-target-health output: %w", err)
+	}
+
+	for _, d := range resp.TargetHealthDescriptions {
+		tg.TotalTargets++
+		switch d.TargetHealth.State {
+		case "healthy":
+			tg.HealthyTargets++
+		case "unhealthy":
+			tg.UnhealthyTargets++
+		}
+	}
+	return nil
+}
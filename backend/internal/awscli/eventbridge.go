@@ -0,0 +1,168 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// EventBridge rules and EventBridge Scheduler schedules
+
+type eventsListEventBusesOutput struct {
+	EventBuses []struct {
+		Name string `json:"Name"`
+	} `json:"EventBuses"`
+}
+
+type eventsListRulesOutput struct {
+	Rules []struct {
+		Name               string `json:"Name"`
+		Arn                string `json:"Arn"`
+		State              string `json:"State"`
+		Description        string `json:"Description"`
+		ScheduleExpression string `json:"ScheduleExpression"`
+		EventPattern       string `json:"EventPattern"`
+		EventBusName       string `json:"EventBusName"`
+		ManagedBy          string `json:"ManagedBy"`
+	} `json:"Rules"`
+}
+
+type eventsListTargetsByRuleOutput struct {
+	Targets []struct {
+		ID string `json:"Id"`
+	} `json:"Targets"`
+}
+
+type schedulerListSchedulesOutput struct {
+	Schedules []struct {
+		Name         string `json:"Name"`
+		Arn          string `json:"Arn"`
+		GroupName    string `json:"GroupName"`
+		State        string `json:"State"`
+		CreationDate string `json:"CreationDate"`
+		Target       struct {
+			Arn string `json:"Arn"`
+		} `json:"Target"`
+	} `json:"Schedules"`
+}
+
+// maxConcurrentRules bounds the per-rule list-targets-by-rule calls.
+const maxConcurrentRules = 4
+
+type eventBridgeInventory struct {
+	rules     []types.EventBridgeRule
+	schedules []types.EventBridgeSchedule
+}
+
+func (s *resourceService) getEventBridge(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getEventBridgeSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "eventbridge", Message: msg}
+	for _, inv := range inventories {
+		res.EventBridgeRules = append(res.EventBridgeRules, inv.rules...)
+		res.EventBridgeSchedules = append(res.EventBridgeSchedules, inv.schedules...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getEventBridgeSingleRegion(ctx context.Context, region string) ([]eventBridgeInventory, error) {
+	var inv eventBridgeInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"events", "list-event-buses"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var buses eventsListEventBusesOutput
+	if err := json.Unmarshal(out, &buses); err != nil {
+		return nil, fmt.Errorf("failed to parse list-event-buses output: %w", err)
+	}
+
+	for _, bus := range buses.EventBuses {
+		out, err := s.exec.RunJSON(ctx, regionArgs([]string{"events", "list-rules", "--event-bus-name", bus.Name}, region)...)
+		if err != nil {
+			return nil, err
+		}
+		var rules eventsListRulesOutput
+		if err := json.Unmarshal(out, &rules); err != nil {
+			return nil, fmt.Errorf("failed to parse list-rules output: %w", err)
+		}
+
+		for _, r := range rules.Rules {
+			inv.rules = append(inv.rules, types.EventBridgeRule{
+				Name:               r.Name,
+				ARN:                r.Arn,
+				EventBus:           bus.Name,
+				State:              r.State,
+				Description:        r.Description,
+				ScheduleExpression: r.ScheduleExpression,
+				EventPattern:       r.EventPattern,
+				ManagedBy:          r.ManagedBy,
+				Region:             region,
+			})
+		}
+	}
+
+	errs := make([]error, len(inv.rules))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRules)
+
+	for i := range inv.rules {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			inv.rules[i].TargetCount, errs[i] = s.countRuleTargets(ctx, region, inv.rules[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"scheduler", "list-schedules"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var schedules schedulerListSchedulesOutput
+	if err := json.Unmarshal(out, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse list-schedules output: %w", err)
+	}
+	for _, sc := range schedules.Schedules {
+		inv.schedules = append(inv.schedules, types.EventBridgeSchedule{
+			Name:         sc.Name,
+			ARN:          sc.Arn,
+			Group:        sc.GroupName,
+			State:        sc.State,
+			TargetARN:    sc.Target.Arn,
+			CreationDate: sc.CreationDate,
+			Region:       region,
+		})
+	}
+
+	return []eventBridgeInventory{inv}, nil
+}
+
+func (s *resourceService) countRuleTargets(ctx context.Context, region string, rule types.EventBridgeRule) (int, error) {
+	args := []string{"events", "list-targets-by-rule", "--rule", rule.Name, "--event-bus-name", rule.EventBus}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp eventsListTargetsByRuleOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return 0, fmt.Errorf("failed to parse list-targets-by-rule output: %w", err)
+	}
+	return len(resp.Targets), nil
+}
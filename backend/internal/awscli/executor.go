@@ -3,12 +3,15 @@ package awscli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/services"
 )
 
 // Executor abstracts running AWS CLI commands.
@@ -16,6 +19,114 @@ type Executor interface {
 	RunJSON(ctx context.Context, args ...string) ([]byte, error)
 }
 
+// Output formats of the aws CLI, as accepted by --output.
+const (
+	FormatJSON  = "json"
+	FormatTable = "table"
+	FormatText  = "text"
+	FormatYAML  = "yaml"
+)
+
+// Formats lists the output formats commands can be run with.
+var Formats = []string{FormatJSON, FormatTable, FormatText, FormatYAML}
+
+// ValidFormat reports whether format is one of Formats.
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrFormatUnsupported is returned when an executor can't produce an output
+// format other than JSON, e.g. when replaying recorded fixtures.
+var ErrFormatUnsupported = errors.New("output format not supported")
+
+// FormatExecutor is an Executor that can also return the CLI's other output
+// formats.
+type FormatExecutor interface {
+	Executor
+	Run(ctx context.Context, format string, args ...string) ([]byte, error)
+}
+
+// RunFormat runs args through exec with the given output format; an empty
+// format means JSON.
+func RunFormat(ctx context.Context, exec Executor, format string, args ...string) ([]byte, error) {
+	if format == "" || format == FormatJSON {
+		return exec.RunJSON(ctx, args...)
+	}
+	if fe, ok := exec.(FormatExecutor); ok {
+		return fe.Run(ctx, format, args...)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFormatUnsupported, format)
+}
+
+// Invocation describes how an aws CLI command would run: its full argv, the
+// names (never the values) of the environment variables set on top of the
+// server's own, and the profile and region it would use.
+type Invocation struct {
+	Argv    []string `json:"argv"`
+	Env     []string `json:"env"`
+	Profile string   `json:"profile,omitempty"`
+	// Region is the --region argument or, failing that, the profile's
+	// default region; empty means the CLI's own configuration decides.
+	Region string `json:"region,omitempty"`
+}
+
+// Planner is an Executor that can describe a command without running it.
+type Planner interface {
+	Plan(ctx context.Context, format string, args ...string) (Invocation, error)
+}
+
+// ErrDryRunUnsupported is returned when an executor can't describe commands
+// without running them, e.g. when replaying recorded fixtures.
+var ErrDryRunUnsupported = errors.New("dry run not supported")
+
+// PlanFormat describes how exec would run args with the given output format;
+// an empty format means JSON.
+func PlanFormat(ctx context.Context, exec Executor, format string, args ...string) (Invocation, error) {
+	if format == "" {
+		format = FormatJSON
+	}
+	if p, ok := exec.(Planner); ok {
+		return p.Plan(ctx, format, args...)
+	}
+	return Invocation{}, ErrDryRunUnsupported
+}
+
+// CLIError is returned when the aws CLI exits with an error. Code and
+// Operation are parsed from the CLI's standard "An error occurred (Code) when
+// calling the Operation operation: message" line and are empty for other
+// failures, such as a missing CLI or bad arguments.
+type CLIError struct {
+	Code      string
+	Operation string
+	Message   string
+	// ExitCode is the CLI's exit status, or -1 if it did not run.
+	ExitCode int
+	// Output is the CLI's full error output.
+	Output string
+}
+
+func (e *CLIError) Error() string {
+	return fmt.Sprintf("aws cli error: %s", e.Output)
+}
+
+var cliErrorPattern = regexp.MustCompile(`An error occurred \(([^)]+)\) when calling the (\w+) operation(?: \([^)]*\))?: (.*)`)
+
+// parseCLIError builds a CLIError from the CLI's error output.
+func parseCLIError(output string, exitCode int) *CLIError {
+	e := &CLIError{Output: output, Message: output, ExitCode: exitCode}
+	if m := cliErrorPattern.FindStringSubmatch(output); m != nil {
+		e.Code = m[1]
+		e.Operation = m[2]
+		e.Message = strings.TrimSpace(m[3])
+	}
+	return e
+}
+
 type CLIExecutor struct {
 	profileManager *profiles.Manager
 }
@@ -29,14 +140,31 @@ func NewCLIExecutor(profileManager *profiles.Manager) *CLIExecutor {
 
 // RunJSON runs an aws CLI command and returns the JSON output.
 func (e *CLIExecutor) RunJSON(ctx context.Context, args ...string) ([]byte, error) {
-	// Ensure we always request JSON
-	args = append(args, "--output", "json")
+	return e.Run(ctx, FormatJSON, args...)
+}
+
+// Run runs an aws CLI command and returns its output in the given format.
+// Any --output in args is replaced, so the caller gets what it asked for.
+func (e *CLIExecutor) Run(ctx context.Context, format string, args ...string) ([]byte, error) {
+	if !ValidFormat(format) {
+		return nil, fmt.Errorf("%w: %s", ErrFormatUnsupported, format)
+	}
+	args = cliArgs(ctx, format, args)
+	if err := e.checkRegion(ctx, args); err != nil {
+		return nil, err
+	}
 
 	cmd := exec.CommandContext(ctx, "aws", args...)
 
 	// Apply active profile environment, without mutating system configuration.
+	var profileID string
 	if e.profileManager != nil {
-		if envOverrides := e.profileManager.ActiveEnv(); len(envOverrides) > 0 {
+		profileID = e.profileManager.ProfileID(ctx)
+		envOverrides, err := e.profileManager.ActiveEnv(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(envOverrides) > 0 {
 			cmd.Env = append(os.Environ(), envOverrides...)
 		}
 	}
@@ -50,10 +178,179 @@ func (e *CLIExecutor) RunJSON(ctx context.Context, args ...string) ([]byte, erro
 		if errMsg == "" {
 			errMsg = err.Error()
 		}
-		return nil, fmt.Errorf("aws cli error: %s", errMsg)
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+		cliErr := parseCLIError(errMsg, exitCode)
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %w", services.ErrCLINotFound, cliErr)
+		}
+		if cliErr.expiredCredentials() {
+			if e.profileManager != nil {
+				e.profileManager.MarkExpired(profileID)
+			}
+			return nil, fmt.Errorf("%w: %w", services.ErrCredentialsExpired, cliErr)
+		}
+		return nil, cliErr
 	}
 
 	return stdout.Bytes(), nil
 }
 
+// Plan describes how Run would run args, without running anything or
+// fetching credentials.
+func (e *CLIExecutor) Plan(ctx context.Context, format string, args ...string) (Invocation, error) {
+	if !ValidFormat(format) {
+		return Invocation{}, fmt.Errorf("%w: %s", ErrFormatUnsupported, format)
+	}
+	args = cliArgs(ctx, format, args)
+	if err := e.checkRegion(ctx, args); err != nil {
+		return Invocation{}, err
+	}
 
+	inv := Invocation{Argv: append([]string{"aws"}, args...), Env: []string{}, Region: argRegion(args)}
+	if e.profileManager != nil {
+		inv.Profile = e.profileManager.ProfileID(ctx)
+		if names := e.profileManager.EnvNames(inv.Profile); names != nil {
+			inv.Env = names
+		}
+		if inv.Region == "" {
+			inv.Region = e.profileManager.DefaultRegion(inv.Profile)
+		}
+	}
+	return inv, nil
+}
+
+// cliArgs returns the arguments Run passes to the aws CLI: args with the
+// requested output format and the context's region, unless args set one.
+func cliArgs(ctx context.Context, format string, args []string) []string {
+	args = append(WithoutOutput(args), "--output", format)
+	if region := profiles.ContextRegion(ctx); region != "" && argRegion(args) == "" {
+		args = append(args, "--region", region)
+	}
+	return args
+}
+
+// checkRegion refuses args whose --region the profile may not use.
+func (e *CLIExecutor) checkRegion(ctx context.Context, args []string) error {
+	if e.profileManager == nil {
+		return nil
+	}
+	if region := argRegion(args); region != "" && !e.profileManager.RegionAllowed(ctx, region) {
+		return fmt.Errorf("%w: %s", profiles.ErrRegionNotAllowed, region)
+	}
+	return nil
+}
+
+// argRegion returns the value of a --region argument, or "".
+func argRegion(args []string) string {
+	for i, a := range args {
+		if a == "--region" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(a, "--region="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// WithoutOutput returns args without any --output option, which Run sets.
+func WithoutOutput(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--output" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(args[i], "--output=") {
+			continue
+		}
+		out = append(out, args[i])
+	}
+	return out
+}
+
+// expiredCredentials reports whether the CLI failed because session
+// credentials (including an SSO session) have expired.
+func (e *CLIError) expiredCredentials() bool {
+	switch e.Code {
+	case "ExpiredToken", "ExpiredTokenException":
+		return true
+	}
+	return strings.Contains(e.Output, "Token has expired") || strings.Contains(e.Output, "session associated with this profile has expired")
+}
+
+// unreachableMessages are how the CLI reports that it couldn't reach an AWS
+// endpoint at all.
+var unreachableMessages = []string{
+	"Could not connect to the endpoint URL",
+	"Connect timeout on endpoint URL",
+	"Read timeout on endpoint URL",
+	"Connection was closed before we received a valid response",
+	"Failed to connect to proxy URL",
+}
+
+// unreachable reports whether err means AWS couldn't be reached, such as
+// during a network outage, as opposed to AWS refusing the request. Only
+// then may callers fall back to data saved earlier; expired credentials or
+// revoked access must still fail.
+func unreachable(err error) bool {
+	if errors.Is(err, services.ErrCredentialsExpired) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != "" {
+		return false
+	}
+	for _, msg := range unreachableMessages {
+		if strings.Contains(cliErr.Output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classes of CLIError, as reported by Class.
+const (
+	ClassAuthFailed   = "aws_auth_failed"
+	ClassAccessDenied = "access_denied"
+	ClassThrottled    = "throttled"
+	ClassNotFound     = "aws_not_found"
+	ClassInvalid      = "aws_invalid_request"
+	// ClassAWS is any other error AWS returned, ClassCLI a failure of the
+	// CLI itself such as bad arguments.
+	ClassAWS = "aws_error"
+	ClassCLI = "cli_error"
+)
+
+// Class groups the error by what a caller can do about it: fix credentials,
+// request permissions, retry later or fix the request.
+func (e *CLIError) Class() string {
+	switch e.Code {
+	case "AuthFailure", "UnrecognizedClientException", "InvalidClientTokenId", "SignatureDoesNotMatch",
+		"InvalidSignatureException", "IncompleteSignature", "MissingAuthenticationToken":
+		return ClassAuthFailed
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation", "AuthorizationError",
+		"Forbidden":
+		return ClassAccessDenied
+	case "Throttling", "ThrottlingException", "ThrottledException", "RequestLimitExceeded",
+		"TooManyRequestsException", "RequestThrottled", "SlowDown", "LimitExceededException":
+		return ClassThrottled
+	case "ValidationException", "ValidationError", "InvalidParameterValue", "InvalidParameterException",
+		"InvalidParameterCombination", "MissingParameter", "InvalidRequestException":
+		return ClassInvalid
+	case "":
+		if strings.Contains(e.Output, "Unable to locate credentials") {
+			return ClassAuthFailed
+		}
+		return ClassCLI
+	}
+	if strings.HasSuffix(e.Code, "NotFound") || strings.HasSuffix(e.Code, "NotFoundException") ||
+		strings.HasPrefix(e.Code, "NoSuch") {
+		return ClassNotFound
+	}
+	return ClassAWS
+}
@@ -0,0 +1,163 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Glue
+
+type glueGetDatabasesOutput struct {
+	DatabaseList []struct {
+		Name        string `json:"Name"`
+		Description string `json:"Description"`
+		LocationURI string `json:"LocationUri"`
+		CreateTime  string `json:"CreateTime"`
+	} `json:"DatabaseList"`
+}
+
+type glueGetCrawlersOutput struct {
+	Crawlers []struct {
+		Name         string `json:"Name"`
+		State        string `json:"State"`
+		DatabaseName string `json:"DatabaseName"`
+		Schedule     struct {
+			ScheduleExpression string `json:"ScheduleExpression"`
+			State              string `json:"State"`
+		} `json:"Schedule"`
+		LastCrawl struct {
+			Status       string `json:"Status"`
+			StartTime    string `json:"StartTime"`
+			ErrorMessage string `json:"ErrorMessage"`
+		} `json:"LastCrawl"`
+	} `json:"Crawlers"`
+}
+
+type glueGetJobsOutput struct {
+	Jobs []struct {
+		Name            string   `json:"Name"`
+		Role            string   `json:"Role"`
+		GlueVersion     string   `json:"GlueVersion"`
+		WorkerType      string   `json:"WorkerType"`
+		NumberOfWorkers int      `json:"NumberOfWorkers"`
+		MaxCapacity     *float64 `json:"MaxCapacity"`
+		LastModifiedOn  string   `json:"LastModifiedOn"`
+		Command         struct {
+			Name string `json:"Name"`
+		} `json:"Command"`
+	} `json:"Jobs"`
+}
+
+// glueWorkerDPU maps Glue worker types to the DPUs each worker consumes.
+var glueWorkerDPU = map[string]float64{
+	"G.025X":   0.25,
+	"Standard": 1,
+	"G.1X":     1,
+	"G.2X":     2,
+	"G.4X":     4,
+	"G.8X":     8,
+	"Z.2X":     2,
+}
+
+// glueInventory bundles the per-region Glue listings so a single fan-out
+// covers all three resource kinds.
+type glueInventory struct {
+	databases []types.GlueDatabase
+	crawlers  []types.GlueCrawler
+	jobs      []types.GlueJob
+}
+
+func (s *resourceService) getGlue(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getGlueSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "glue", Message: msg}
+	for _, inv := range inventories {
+		res.GlueDatabases = append(res.GlueDatabases, inv.databases...)
+		res.GlueCrawlers = append(res.GlueCrawlers, inv.crawlers...)
+		res.GlueJobs = append(res.GlueJobs, inv.jobs...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getGlueSingleRegion(ctx context.Context, region string) ([]glueInventory, error) {
+	var inv glueInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"glue", "get-databases"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var dbs glueGetDatabasesOutput
+	if err := json.Unmarshal(out, &dbs); err != nil {
+		return nil, fmt.Errorf("failed to parse get-databases output: %w", err)
+	}
+	for _, d := range dbs.DatabaseList {
+		inv.databases = append(inv.databases, types.GlueDatabase{
+			Name:        d.Name,
+			Description: d.Description,
+			LocationURI: d.LocationURI,
+			CreateTime:  d.CreateTime,
+			Region:      region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"glue", "get-crawlers"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var crawlers glueGetCrawlersOutput
+	if err := json.Unmarshal(out, &crawlers); err != nil {
+		return nil, fmt.Errorf("failed to parse get-crawlers output: %w", err)
+	}
+	for _, c := range crawlers.Crawlers {
+		inv.crawlers = append(inv.crawlers, types.GlueCrawler{
+			Name:          c.Name,
+			State:         c.State,
+			DatabaseName:  c.DatabaseName,
+			Schedule:      c.Schedule.ScheduleExpression,
+			ScheduleState: c.Schedule.State,
+			LastRunStatus: c.LastCrawl.Status,
+			LastRunTime:   c.LastCrawl.StartTime,
+			LastRunError:  c.LastCrawl.ErrorMessage,
+			Region:        region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"glue", "get-jobs"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var jobs glueGetJobsOutput
+	if err := json.Unmarshal(out, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse get-jobs output: %w", err)
+	}
+	for _, j := range jobs.Jobs {
+		// Jobs configured with a worker type report capacity as workers;
+		// older jobs only carry MaxCapacity in DPUs.
+		dpu := 0.0
+		if j.WorkerType != "" {
+			dpu = glueWorkerDPU[j.WorkerType] * float64(j.NumberOfWorkers)
+		} else if j.MaxCapacity != nil {
+			dpu = *j.MaxCapacity
+		}
+
+		inv.jobs = append(inv.jobs, types.GlueJob{
+			Name:            j.Name,
+			Type:            j.Command.Name,
+			GlueVersion:     j.GlueVersion,
+			WorkerType:      j.WorkerType,
+			NumberOfWorkers: j.NumberOfWorkers,
+			DPU:             dpu,
+			Role:            j.Role,
+			LastModifiedOn:  j.LastModifiedOn,
+			Region:          region,
+		})
+	}
+
+	return []glueInventory{inv}, nil
+}
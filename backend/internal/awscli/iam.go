@@ -0,0 +1,139 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// IAM (global)
+
+type iamListUsersOutput struct {
+	Users []struct {
+		UserName         string `json:"UserName"`
+		UserID           string `json:"UserId"`
+		Arn              string `json:"Arn"`
+		CreateDate       string `json:"CreateDate"`
+		PasswordLastUsed string `json:"PasswordLastUsed"`
+	} `json:"Users"`
+}
+
+// iamRoleDetailsOutput is the Role-filtered get-account-authorization-details
+// response. Unlike list-roles it includes RoleLastUsed for every role.
+type iamRoleDetailsOutput struct {
+	RoleDetailList []struct {
+		RoleName     string `json:"RoleName"`
+		RoleID       string `json:"RoleId"`
+		Arn          string `json:"Arn"`
+		Path         string `json:"Path"`
+		CreateDate   string `json:"CreateDate"`
+		RoleLastUsed struct {
+			LastUsedDate string `json:"LastUsedDate"`
+			Region       string `json:"Region"`
+		} `json:"RoleLastUsed"`
+	} `json:"RoleDetailList"`
+}
+
+type iamListGroupsOutput struct {
+	Groups []struct {
+		GroupName  string `json:"GroupName"`
+		GroupID    string `json:"GroupId"`
+		Arn        string `json:"Arn"`
+		CreateDate string `json:"CreateDate"`
+	} `json:"Groups"`
+}
+
+type iamListPoliciesOutput struct {
+	Policies []struct {
+		PolicyName      string `json:"PolicyName"`
+		PolicyID        string `json:"PolicyId"`
+		Arn             string `json:"Arn"`
+		AttachmentCount int    `json:"AttachmentCount"`
+		CreateDate      string `json:"CreateDate"`
+		UpdateDate      string `json:"UpdateDate"`
+	} `json:"Policies"`
+}
+
+func (s *resourceService) getIAMInventory(ctx context.Context) (types.ServiceResources, error) {
+	res := types.ServiceResources{Service: "iam"}
+
+	out, err := s.exec.RunJSON(ctx, "iam", "list-users")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	var users iamListUsersOutput
+	if err := json.Unmarshal(out, &users); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse list-users output: %w", err)
+	}
+	for _, u := range users.Users {
+		res.IAMUsers = append(res.IAMUsers, types.IAMUser{
+			UserName:         u.UserName,
+			UserID:           u.UserID,
+			ARN:              u.Arn,
+			CreateDate:       u.CreateDate,
+			PasswordLastUsed: u.PasswordLastUsed,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, "iam", "get-account-authorization-details", "--filter", "Role")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	var roles iamRoleDetailsOutput
+	if err := json.Unmarshal(out, &roles); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse get-account-authorization-details output: %w", err)
+	}
+	for _, r := range roles.RoleDetailList {
+		res.IAMRoles = append(res.IAMRoles, types.IAMRole{
+			RoleName:       r.RoleName,
+			RoleID:         r.RoleID,
+			ARN:            r.Arn,
+			Path:           r.Path,
+			CreateDate:     r.CreateDate,
+			LastUsedDate:   r.RoleLastUsed.LastUsedDate,
+			LastUsedRegion: r.RoleLastUsed.Region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, "iam", "list-groups")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	var groups iamListGroupsOutput
+	if err := json.Unmarshal(out, &groups); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse list-groups output: %w", err)
+	}
+	for _, g := range groups.Groups {
+		res.IAMGroups = append(res.IAMGroups, types.IAMGroup{
+			GroupName:  g.GroupName,
+			GroupID:    g.GroupID,
+			ARN:        g.Arn,
+			CreateDate: g.CreateDate,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, "iam", "list-policies", "--scope", "Local")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	var policies iamListPoliciesOutput
+	if err := json.Unmarshal(out, &policies); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse list-policies output: %w", err)
+	}
+	for _, p := range policies.Policies {
+		res.IAMPolicies = append(res.IAMPolicies, types.IAMPolicy{
+			PolicyName:      p.PolicyName,
+			PolicyID:        p.PolicyID,
+			ARN:             p.Arn,
+			AttachmentCount: p.AttachmentCount,
+			CreateDate:      p.CreateDate,
+			UpdateDate:      p.UpdateDate,
+		})
+	}
+
+	res.Message = fmt.Sprintf("%d users, %d roles, %d groups, %d customer-managed policies",
+		len(res.IAMUsers), len(res.IAMRoles), len(res.IAMGroups), len(res.IAMPolicies))
+	return res, nil
+}
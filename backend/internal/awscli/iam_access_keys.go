@@ -0,0 +1,127 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// IAM access keys
+
+type iamListAccessKeysOutput struct {
+	AccessKeyMetadata []struct {
+		UserName    string `json:"UserName"`
+		AccessKeyID string `json:"AccessKeyId"`
+		Status      string `json:"Status"`
+		CreateDate  string `json:"CreateDate"`
+	} `json:"AccessKeyMetadata"`
+}
+
+type iamGetAccessKeyLastUsedOutput struct {
+	AccessKeyLastUsed struct {
+		LastUsedDate string `json:"LastUsedDate"`
+		ServiceName  string `json:"ServiceName"`
+		Region       string `json:"Region"`
+	} `json:"AccessKeyLastUsed"`
+}
+
+// staleAccessKeyDays is the key age after which an access key is flagged as
+// stale, matching the common 90-day rotation policy.
+const staleAccessKeyDays = 90
+
+// maxConcurrentIAMUsers bounds the per-user key lookups.
+const maxConcurrentIAMUsers = 4
+
+func (s *resourceService) getAccessKeys(ctx context.Context) (types.ServiceResources, error) {
+	out, err := s.exec.RunJSON(ctx, "iam", "list-users")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	var users iamListUsersOutput
+	if err := json.Unmarshal(out, &users); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse list-users output: %w", err)
+	}
+
+	perUser := make([][]types.AccessKey, len(users.Users))
+	errs := make([]error, len(users.Users))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentIAMUsers)
+
+	for i, u := range users.Users {
+		wg.Add(1)
+		go func(i int, userName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			perUser[i], errs[i] = s.getUserAccessKeys(ctx, userName)
+		}(i, u.UserName)
+	}
+	wg.Wait()
+
+	var keys []types.AccessKey
+	for i := range perUser {
+		if errs[i] != nil {
+			return types.ServiceResources{}, errs[i]
+		}
+		keys = append(keys, perUser[i]...)
+	}
+
+	// Oldest keys first so the ones most in need of rotation lead the table.
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].AgeDays > keys[j].AgeDays })
+
+	return types.ServiceResources{
+		Service:    "access-keys",
+		AccessKeys: keys,
+	}, nil
+}
+
+func (s *resourceService) getUserAccessKeys(ctx context.Context, userName string) ([]types.AccessKey, error) {
+	out, err := s.exec.RunJSON(ctx, "iam", "list-access-keys", "--user-name", userName)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp iamListAccessKeysOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-access-keys output: %w", err)
+	}
+
+	now := time.Now()
+	var keys []types.AccessKey
+	for _, k := range resp.AccessKeyMetadata {
+		out, err := s.exec.RunJSON(ctx, "iam", "get-access-key-last-used", "--access-key-id", k.AccessKeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastUsed iamGetAccessKeyLastUsedOutput
+		if err := json.Unmarshal(out, &lastUsed); err != nil {
+			return nil, fmt.Errorf("failed to parse get-access-key-last-used output: %w", err)
+		}
+
+		ageDays := 0
+		if t, err := time.Parse(time.RFC3339, k.CreateDate); err == nil {
+			ageDays = int(now.Sub(t).Hours() / 24)
+		}
+
+		keys = append(keys, types.AccessKey{
+			UserName:        userName,
+			AccessKeyID:     k.AccessKeyID,
+			Status:          k.Status,
+			CreateDate:      k.CreateDate,
+			AgeDays:         ageDays,
+			LastUsedDate:    lastUsed.AccessKeyLastUsed.LastUsedDate,
+			LastUsedService: lastUsed.AccessKeyLastUsed.ServiceName,
+			LastUsedRegion:  lastUsed.AccessKeyLastUsed.Region,
+			Stale:           ageDays > staleAccessKeyDays,
+		})
+	}
+	return keys, nil
+}
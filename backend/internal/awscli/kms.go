@@ -0,0 +1,174 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// KMS
+
+type kmsListKeysOutput struct {
+	Keys []struct {
+		KeyID  string `json:"KeyId"`
+		KeyArn string `json:"KeyArn"`
+	} `json:"Keys"`
+}
+
+type kmsListAliasesOutput struct {
+	Aliases []struct {
+		AliasName   string `json:"AliasName"`
+		TargetKeyID string `json:"TargetKeyId"`
+	} `json:"Aliases"`
+}
+
+type kmsDescribeKeyOutput struct {
+	KeyMetadata struct {
+		KeyID        string `json:"KeyId"`
+		Arn          string `json:"Arn"`
+		Description  string `json:"Description"`
+		KeyManager   string `json:"KeyManager"`
+		KeyState     string `json:"KeyState"`
+		KeySpec      string `json:"KeySpec"`
+		KeyUsage     string `json:"KeyUsage"`
+		Origin       string `json:"Origin"`
+		MultiRegion  bool   `json:"MultiRegion"`
+		CreationDate string `json:"CreationDate"`
+	} `json:"KeyMetadata"`
+}
+
+type kmsGetKeyRotationStatusOutput struct {
+	KeyRotationEnabled bool `json:"KeyRotationEnabled"`
+}
+
+// maxConcurrentKMSKeys bounds the per-key describe calls within a region.
+const maxConcurrentKMSKeys = 4
+
+func (s *resourceService) getKMSKeys(ctx context.Context, region string) (types.ServiceResources, error) {
+	keys, msg, err := forRegions(ctx, s, region, s.getKMSKeysSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service: "kms",
+		KMSKeys: keys,
+		Message: msg,
+	}, nil
+}
+
+func (s *resourceService) getKMSKeysSingleRegion(ctx context.Context, region string) ([]types.KMSKey, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"kms", "list-keys"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list kmsListKeysOutput
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse list-keys output: %w", err)
+	}
+	if len(list.Keys) == 0 {
+		return nil, nil
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"kms", "list-aliases"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases kmsListAliasesOutput
+	if err := json.Unmarshal(out, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse list-aliases output: %w", err)
+	}
+	aliasByKey := make(map[string]string, len(aliases.Aliases))
+	for _, a := range aliases.Aliases {
+		if a.TargetKeyID != "" && aliasByKey[a.TargetKeyID] == "" {
+			aliasByKey[a.TargetKeyID] = a.AliasName
+		}
+	}
+
+	described := make([]*types.KMSKey, len(list.Keys))
+	errs := make([]error, len(list.Keys))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentKMSKeys)
+
+	for i, k := range list.Keys {
+		wg.Add(1)
+		go func(i int, keyID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			described[i], errs[i] = s.describeKMSKey(ctx, region, keyID)
+		}(i, k.KeyID)
+	}
+	wg.Wait()
+
+	var keys []types.KMSKey
+	for i, k := range described {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if k == nil {
+			continue
+		}
+		k.Alias = aliasByKey[k.KeyID]
+		keys = append(keys, *k)
+	}
+	return keys, nil
+}
+
+// describeKMSKey returns nil for AWS managed keys, which are free and not
+// under the account's control.
+func (s *resourceService) describeKMSKey(ctx context.Context, region, keyID string) (*types.KMSKey, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"kms", "describe-key", "--key-id", keyID}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp kmsDescribeKeyOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-key output: %w", err)
+	}
+
+	md := resp.KeyMetadata
+	if md.KeyManager != "CUSTOMER" {
+		return nil, nil
+	}
+
+	key := &types.KMSKey{
+		KeyID:        md.KeyID,
+		ARN:          md.Arn,
+		Description:  md.Description,
+		KeyState:     md.KeyState,
+		KeySpec:      md.KeySpec,
+		KeyUsage:     md.KeyUsage,
+		Origin:       md.Origin,
+		MultiRegion:  md.MultiRegion,
+		CreationDate: md.CreationDate,
+		Region:       region,
+	}
+
+	// Automatic rotation only applies to symmetric keys with AWS-generated
+	// material, and the status call fails for keys pending deletion or import.
+	rotatable := md.KeySpec == "SYMMETRIC_DEFAULT" && md.Origin == "AWS_KMS" &&
+		(md.KeyState == "Enabled" || md.KeyState == "Disabled")
+	if !rotatable {
+		return key, nil
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"kms", "get-key-rotation-status", "--key-id", keyID}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotation kmsGetKeyRotationStatusOutput
+	if err := json.Unmarshal(out, &rotation); err != nil {
+		return nil, fmt.Errorf("failed to parse get-key-rotation-status output: %w", err)
+	}
+	key.RotationEnabled = rotation.KeyRotationEnabled
+	return key, nil
+}
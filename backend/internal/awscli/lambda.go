@@ -0,0 +1,78 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Lambda
+
+type lambdaListFunctionsOutput struct {
+	Functions []struct {
+		FunctionName  string   `json:"FunctionName"`
+		FunctionArn   string   `json:"FunctionArn"`
+		Runtime       string   `json:"Runtime"`
+		Handler       string   `json:"Handler"`
+		PackageType   string   `json:"PackageType"`
+		MemorySize    int      `json:"MemorySize"`
+		Timeout       int      `json:"Timeout"`
+		CodeSize      int64    `json:"CodeSize"`
+		LastModified  string   `json:"LastModified"`
+		Architectures []string `json:"Architectures"`
+	} `json:"Functions"`
+}
+
+func (s *resourceService) getLambdaFunctions(ctx context.Context, region string) (types.ServiceResources, error) {
+	fns, msg, err := forRegions(ctx, s, region, s.getLambdaFunctionsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:         "lambda",
+		LambdaFunctions: fns,
+		Message:         msg,
+	}, nil
+}
+
+func (s *resourceService) getLambdaFunctionsSingleRegion(ctx context.Context, region string) ([]types.LambdaFunction, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"lambda", "list-functions"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp lambdaListFunctionsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-functions output: %w", err)
+	}
+
+	var fns []types.LambdaFunction
+	for _, f := range resp.Functions {
+		runtime := f.Runtime
+		if runtime == "" && f.PackageType == "Image" {
+			// Container-image functions have no managed runtime.
+			runtime = "container-image"
+		}
+		arch := ""
+		if len(f.Architectures) > 0 {
+			arch = f.Architectures[0]
+		}
+
+		fns = append(fns, types.LambdaFunction{
+			FunctionName: f.FunctionName,
+			FunctionArn:  f.FunctionArn,
+			Runtime:      runtime,
+			Handler:      f.Handler,
+			Architecture: arch,
+			MemorySize:   f.MemorySize,
+			Timeout:      f.Timeout,
+			CodeSize:     f.CodeSize,
+			LastModified: f.LastModified,
+			Region:       region,
+		})
+	}
+	return fns, nil
+}
@@ -0,0 +1,129 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Launch templates and legacy launch configurations
+
+type ec2DescribeLaunchTemplatesOutput struct {
+	LaunchTemplates []struct {
+		LaunchTemplateID     string `json:"LaunchTemplateId"`
+		LaunchTemplateName   string `json:"LaunchTemplateName"`
+		DefaultVersionNumber int64  `json:"DefaultVersionNumber"`
+		LatestVersionNumber  int64  `json:"LatestVersionNumber"`
+		CreateTime           string `json:"CreateTime"`
+	} `json:"LaunchTemplates"`
+}
+
+type ec2DescribeLaunchTemplateVersionsOutput struct {
+	LaunchTemplateVersions []struct {
+		LaunchTemplateID   string `json:"LaunchTemplateId"`
+		LaunchTemplateData struct {
+			ImageID      string `json:"ImageId"`
+			InstanceType string `json:"InstanceType"`
+		} `json:"LaunchTemplateData"`
+	} `json:"LaunchTemplateVersions"`
+}
+
+type autoscalingDescribeLaunchConfigurationsOutput struct {
+	LaunchConfigurations []struct {
+		LaunchConfigurationName string `json:"LaunchConfigurationName"`
+		LaunchConfigurationARN  string `json:"LaunchConfigurationARN"`
+		ImageID                 string `json:"ImageId"`
+		InstanceType            string `json:"InstanceType"`
+		CreatedTime             string `json:"CreatedTime"`
+	} `json:"LaunchConfigurations"`
+}
+
+func (s *resourceService) getLaunchTemplates(ctx context.Context, region string) (types.ServiceResources, error) {
+	templates, msg, err := forRegions(ctx, s, region, s.getLaunchTemplatesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:         "launch-template",
+		LaunchTemplates: templates,
+		Message:         msg,
+	}, nil
+}
+
+func (s *resourceService) getLaunchTemplatesSingleRegion(ctx context.Context, region string) ([]types.LaunchTemplate, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-launch-templates"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeLaunchTemplatesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-launch-templates output: %w", err)
+	}
+
+	var templates []types.LaunchTemplate
+	if len(resp.LaunchTemplates) > 0 {
+		// Without a template ID, "$Default" returns the default version of
+		// every template in the region in a single call.
+		out, err = s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-launch-template-versions", "--versions", "$Default"}, region)...)
+		if err != nil {
+			return nil, err
+		}
+
+		var versions ec2DescribeLaunchTemplateVersionsOutput
+		if err := json.Unmarshal(out, &versions); err != nil {
+			return nil, fmt.Errorf("failed to parse describe-launch-template-versions output: %w", err)
+		}
+
+		type templateData struct{ imageID, instanceType string }
+		data := make(map[string]templateData, len(versions.LaunchTemplateVersions))
+		for _, v := range versions.LaunchTemplateVersions {
+			data[v.LaunchTemplateID] = templateData{
+				imageID:      v.LaunchTemplateData.ImageID,
+				instanceType: v.LaunchTemplateData.InstanceType,
+			}
+		}
+
+		for _, lt := range resp.LaunchTemplates {
+			d := data[lt.LaunchTemplateID]
+			templates = append(templates, types.LaunchTemplate{
+				ID:             lt.LaunchTemplateID,
+				Name:           lt.LaunchTemplateName,
+				Kind:           "launch-template",
+				DefaultVersion: lt.DefaultVersionNumber,
+				LatestVersion:  lt.LatestVersionNumber,
+				ImageID:        d.imageID,
+				InstanceType:   d.instanceType,
+				CreatedTime:    lt.CreateTime,
+				Region:         region,
+			})
+		}
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"autoscaling", "describe-launch-configurations"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var lcs autoscalingDescribeLaunchConfigurationsOutput
+	if err := json.Unmarshal(out, &lcs); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-launch-configurations output: %w", err)
+	}
+
+	for _, lc := range lcs.LaunchConfigurations {
+		templates = append(templates, types.LaunchTemplate{
+			ID:           lc.LaunchConfigurationARN,
+			Name:         lc.LaunchConfigurationName,
+			Kind:         "launch-configuration",
+			ImageID:      lc.ImageID,
+			InstanceType: lc.InstanceType,
+			CreatedTime:  lc.CreatedTime,
+			Region:       region,
+		})
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,178 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Lightsail
+
+type lightsailGetInstancesOutput struct {
+	Instances []struct {
+		Name            string `json:"name"`
+		Arn             string `json:"arn"`
+		BundleID        string `json:"bundleId"`
+		BlueprintName   string `json:"blueprintName"`
+		PublicIPAddress string `json:"publicIpAddress"`
+		IsStaticIP      bool   `json:"isStaticIp"`
+		CreatedAt       string `json:"createdAt"`
+		State           struct {
+			Name string `json:"name"`
+		} `json:"state"`
+		Location struct {
+			AvailabilityZone string `json:"availabilityZone"`
+		} `json:"location"`
+	} `json:"instances"`
+}
+
+type lightsailGetBundlesOutput struct {
+	Bundles []struct {
+		BundleID    string  `json:"bundleId"`
+		Name        string  `json:"name"`
+		Price       float64 `json:"price"`
+		CPUCount    int     `json:"cpuCount"`
+		RAMSizeInGb float64 `json:"ramSizeInGb"`
+		DiskSizeGb  int     `json:"diskSizeInGb"`
+	} `json:"bundles"`
+}
+
+type lightsailGetStaticIPsOutput struct {
+	StaticIPs []struct {
+		Name       string `json:"name"`
+		IPAddress  string `json:"ipAddress"`
+		AttachedTo string `json:"attachedTo"`
+		IsAttached bool   `json:"isAttached"`
+	} `json:"staticIps"`
+}
+
+type lightsailGetRelationalDatabasesOutput struct {
+	RelationalDatabases []struct {
+		Name          string `json:"name"`
+		BundleID      string `json:"relationalDatabaseBundleId"`
+		Engine        string `json:"engine"`
+		EngineVersion string `json:"engineVersion"`
+		State         string `json:"state"`
+		CreatedAt     string `json:"createdAt"`
+	} `json:"relationalDatabases"`
+}
+
+type lightsailInventory struct {
+	instances []types.LightsailInstance
+	staticIPs []types.LightsailStaticIP
+	databases []types.LightsailDatabase
+}
+
+func (s *resourceService) getLightsail(ctx context.Context, region string) (types.ServiceResources, error) {
+	inventories, msg, err := forRegions(ctx, s, region, s.getLightsailSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	res := types.ServiceResources{Service: "lightsail", Message: msg}
+	for _, inv := range inventories {
+		res.LightsailInstances = append(res.LightsailInstances, inv.instances...)
+		res.LightsailStaticIPs = append(res.LightsailStaticIPs, inv.staticIPs...)
+		res.LightsailDatabases = append(res.LightsailDatabases, inv.databases...)
+	}
+	return res, nil
+}
+
+func (s *resourceService) getLightsailSingleRegion(ctx context.Context, region string) ([]lightsailInventory, error) {
+	var inv lightsailInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"lightsail", "get-instances"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var instances lightsailGetInstancesOutput
+	if err := json.Unmarshal(out, &instances); err != nil {
+		return nil, fmt.Errorf("failed to parse get-instances output: %w", err)
+	}
+
+	if len(instances.Instances) > 0 {
+		out, err = s.exec.RunJSON(ctx, regionArgs([]string{"lightsail", "get-bundles"}, region)...)
+		if err != nil {
+			return nil, err
+		}
+		var bundles lightsailGetBundlesOutput
+		if err := json.Unmarshal(out, &bundles); err != nil {
+			return nil, fmt.Errorf("failed to parse get-bundles output: %w", err)
+		}
+
+		type bundleInfo struct {
+			name    string
+			price   float64
+			cpus    int
+			ramGiB  float64
+			diskGiB int
+		}
+		byID := make(map[string]bundleInfo, len(bundles.Bundles))
+		for _, b := range bundles.Bundles {
+			byID[b.BundleID] = bundleInfo{b.Name, b.Price, b.CPUCount, b.RAMSizeInGb, b.DiskSizeGb}
+		}
+
+		for _, i := range instances.Instances {
+			b := byID[i.BundleID]
+			inv.instances = append(inv.instances, types.LightsailInstance{
+				Name:             i.Name,
+				ARN:              i.Arn,
+				State:            i.State.Name,
+				Blueprint:        i.BlueprintName,
+				BundleID:         i.BundleID,
+				BundleName:       b.name,
+				MonthlyPrice:     b.price,
+				CPUCount:         b.cpus,
+				RAMGiB:           b.ramGiB,
+				DiskGiB:          b.diskGiB,
+				PublicIP:         i.PublicIPAddress,
+				StaticIP:         i.IsStaticIP,
+				AvailabilityZone: i.Location.AvailabilityZone,
+				CreatedAt:        i.CreatedAt,
+				Region:           region,
+			})
+		}
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"lightsail", "get-static-ips"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var ips lightsailGetStaticIPsOutput
+	if err := json.Unmarshal(out, &ips); err != nil {
+		return nil, fmt.Errorf("failed to parse get-static-ips output: %w", err)
+	}
+	for _, ip := range ips.StaticIPs {
+		inv.staticIPs = append(inv.staticIPs, types.LightsailStaticIP{
+			Name:       ip.Name,
+			IPAddress:  ip.IPAddress,
+			AttachedTo: ip.AttachedTo,
+			Attached:   ip.IsAttached,
+			Region:     region,
+		})
+	}
+
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"lightsail", "get-relational-databases"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+	var dbs lightsailGetRelationalDatabasesOutput
+	if err := json.Unmarshal(out, &dbs); err != nil {
+		return nil, fmt.Errorf("failed to parse get-relational-databases output: %w", err)
+	}
+	for _, d := range dbs.RelationalDatabases {
+		inv.databases = append(inv.databases, types.LightsailDatabase{
+			Name:          d.Name,
+			BundleID:      d.BundleID,
+			Engine:        d.Engine,
+			EngineVersion: d.EngineVersion,
+			State:         d.State,
+			CreatedAt:     d.CreatedAt,
+			Region:        region,
+		})
+	}
+
+	return []lightsailInventory{inv}, nil
+}
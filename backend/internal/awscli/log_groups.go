@@ -0,0 +1,77 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// CloudWatch Logs
+
+type logsDescribeLogGroupsOutput struct {
+	LogGroups []struct {
+		LogGroupName    string `json:"logGroupName"`
+		Arn             string `json:"arn"`
+		CreationTime    int64  `json:"creationTime"` // epoch milliseconds
+		RetentionInDays *int   `json:"retentionInDays"`
+		StoredBytes     int64  `json:"storedBytes"`
+		LogGroupClass   string `json:"logGroupClass"`
+	} `json:"logGroups"`
+}
+
+func (s *resourceService) getLogGroups(ctx context.Context, region string) (types.ServiceResources, error) {
+	groups, msg, err := forRegions(ctx, s, region, s.getLogGroupsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	// Largest groups first; they dominate storage cost.
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].StoredBytes > groups[j].StoredBytes })
+
+	return types.ServiceResources{
+		Service:   "log-groups",
+		LogGroups: groups,
+		Message:   msg,
+	}, nil
+}
+
+func (s *resourceService) getLogGroupsSingleRegion(ctx context.Context, region string) ([]types.LogGroup, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"logs", "describe-log-groups"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp logsDescribeLogGroupsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-log-groups output: %w", err)
+	}
+
+	var groups []types.LogGroup
+	for _, g := range resp.LogGroups {
+		created := ""
+		if g.CreationTime > 0 {
+			created = time.UnixMilli(g.CreationTime).UTC().Format(time.RFC3339)
+		}
+
+		retention := 0
+		if g.RetentionInDays != nil {
+			retention = *g.RetentionInDays
+		}
+
+		groups = append(groups, types.LogGroup{
+			Name:            g.LogGroupName,
+			ARN:             g.Arn,
+			Class:           g.LogGroupClass,
+			CreationTime:    created,
+			RetentionInDays: retention,
+			NeverExpire:     g.RetentionInDays == nil,
+			StoredBytes:     g.StoredBytes,
+			Region:          region,
+		})
+	}
+	return groups, nil
+}
@@ -0,0 +1,65 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Amazon MQ
+
+type mqListBrokersOutput struct {
+	BrokerSummaries []struct {
+		BrokerID         string `json:"BrokerId"`
+		BrokerName       string `json:"BrokerName"`
+		BrokerArn        string `json:"BrokerArn"`
+		BrokerState      string `json:"BrokerState"`
+		DeploymentMode   string `json:"DeploymentMode"`
+		EngineType       string `json:"EngineType"`
+		HostInstanceType string `json:"HostInstanceType"`
+		Created          string `json:"Created"`
+	} `json:"BrokerSummaries"`
+}
+
+func (s *resourceService) getMQBrokers(ctx context.Context, region string) (types.ServiceResources, error) {
+	brokers, msg, err := forRegions(ctx, s, region, s.getMQBrokersSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:   "mq",
+		MQBrokers: brokers,
+		Message:   msg,
+	}, nil
+}
+
+func (s *resourceService) getMQBrokersSingleRegion(ctx context.Context, region string) ([]types.MQBroker, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"mq", "list-brokers"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp mqListBrokersOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-brokers output: %w", err)
+	}
+
+	var brokers []types.MQBroker
+	for _, b := range resp.BrokerSummaries {
+		brokers = append(brokers, types.MQBroker{
+			BrokerID:       b.BrokerID,
+			Name:           b.BrokerName,
+			ARN:            b.BrokerArn,
+			EngineType:     b.EngineType,
+			InstanceType:   b.HostInstanceType,
+			DeploymentMode: b.DeploymentMode,
+			State:          b.BrokerState,
+			Created:        b.Created,
+			Region:         region,
+		})
+	}
+	return brokers, nil
+}
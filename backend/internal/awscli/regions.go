@@ -0,0 +1,126 @@
+package awscli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxConcurrentRegions limits parallel per-region calls to avoid hammering AWS
+// or exhausting local resources.
+const maxConcurrentRegions = 5
+
+// forRegions runs fetch for a single region, or for every enabled region when
+// region is "all". Regions failing with auth/endpoint errors are skipped during
+// an all-regions scan and listed in the returned message.
+func forRegions[T any](ctx context.Context, s *resourceService, region string, fetch func(ctx context.Context, region string) ([]T, error)) ([]T, string, error) {
+	if !strings.EqualFold(region, "all") {
+		items, err := fetch(ctx, region)
+		return items, "", err
+	}
+
+	regions, err := s.listRegions(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	type result struct {
+		region string
+		items  []T
+		err    error
+	}
+
+	resultsCh := make(chan result, len(regions))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRegions)
+
+	for _, rgn := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			items, err := fetch(ctx, region)
+			resultsCh <- result{region: region, items: items, err: err}
+		}(rgn)
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	var all []T
+	var skipped []string
+	var firstErr error
+
+	for r := range resultsCh {
+		if r.err != nil {
+			if isAuthError(r.err) {
+				skipped = append(skipped, r.region)
+				continue
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		all = append(all, r.items...)
+	}
+	if firstErr != nil {
+		return nil, "", firstErr
+	}
+
+	msg := ""
+	if len(skipped) > 0 {
+		msg = fmt.Sprintf("Skipped regions due to authentication errors: %s", strings.Join(skipped, ", "))
+	}
+	return all, msg, nil
+}
+
+// regionArgs appends --region to args when region is set.
+func regionArgs(args []string, region string) []string {
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+	return args
+}
+
+// awsTag is the Key/Value tag shape shared by most AWS CLI outputs.
+type awsTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+// tagValue returns the value of the tag named key, or "".
+func tagValue(tags []awsTag, key string) string {
+	for _, t := range tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// tagMap converts AWS tags to a map, or nil when there are none.
+func tagMap(tags []awsTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[t.Key] = t.Value
+	}
+	return m
+}
+
+// regionFromAZ derives the region from an availability zone name
+// (e.g. us-east-1a -> us-east-1).
+func regionFromAZ(az string) string {
+	if len(az) > 1 {
+		return az[:len(az)-1]
+	}
+	return ""
+}
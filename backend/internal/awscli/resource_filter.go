@@ -0,0 +1,172 @@
+package awscli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// tagFieldPrefix marks a condition on a resource tag rather than a field.
+const tagFieldPrefix = "tag:"
+
+// filterableFields caches, per resource type, the index of each field that
+// can be filtered on (scalars and lists of scalars), keyed by lowercased JSON
+// name.
+var filterableFields sync.Map // reflect.Type -> map[string]int
+
+func fieldIndex(t reflect.Type) map[string]int {
+	if idx, ok := filterableFields.Load(t); ok {
+		return idx.(map[string]int)
+	}
+	idx := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		kind := f.Type.Kind()
+		if kind == reflect.Slice {
+			kind = f.Type.Elem().Kind()
+		}
+		if isScalar(kind) || f.Type == reflect.TypeOf(map[string]string(nil)) {
+			idx[strings.ToLower(name)] = i
+		}
+	}
+	filterableFields.Store(t, idx)
+	return idx
+}
+
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// conditionField returns the field a condition reads: the tags map for tag
+// conditions, otherwise the named field.
+func conditionField(c types.ResourceCondition) string {
+	if strings.HasPrefix(strings.ToLower(c.Field), tagFieldPrefix) {
+		return "tags"
+	}
+	return strings.ToLower(c.Field)
+}
+
+// validateResourceFilter rejects operators it doesn't know and fields that no
+// resource type has, so a typo fails loudly instead of emptying every list.
+func validateResourceFilter(filter types.ResourceFilter) error {
+	lists := reflect.TypeOf(types.ServiceResources{})
+	for _, c := range filter {
+		switch c.Op {
+		case "=", "!=", "~=":
+		default:
+			return fmt.Errorf("%w: unknown operator %q", services.ErrInvalidResourceFilter, c.Op)
+		}
+		if len(c.Values) == 0 {
+			return fmt.Errorf("%w: %s needs a value", services.ErrInvalidResourceFilter, c.Field)
+		}
+		if strings.HasPrefix(strings.ToLower(c.Field), tagFieldPrefix) && len(c.Field) == len(tagFieldPrefix) {
+			return fmt.Errorf("%w: tag key is required", services.ErrInvalidResourceFilter)
+		}
+
+		field, known := conditionField(c), false
+		for i := 0; i < lists.NumField() && !known; i++ {
+			if t := lists.Field(i).Type; t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct {
+				_, known = fieldIndex(t.Elem())[field]
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: no resource has a %q field", services.ErrInvalidResourceFilter, c.Field)
+		}
+	}
+	return nil
+}
+
+// filterResources narrows each resource list in res to the items matching
+// every condition. Lists whose type lacks a filtered field are emptied. New
+// slices are built, so cached listings are left as they were.
+func filterResources(res types.ServiceResources, filter types.ResourceFilter) types.ServiceResources {
+	if len(filter) == 0 {
+		return res
+	}
+
+	v := reflect.ValueOf(&res).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		list := v.Field(i)
+		if list.Kind() != reflect.Slice || list.Type().Elem().Kind() != reflect.Struct || list.Len() == 0 {
+			continue
+		}
+		idx := fieldIndex(list.Type().Elem())
+
+		kept := reflect.MakeSlice(list.Type(), 0, list.Len())
+		for j := 0; j < list.Len(); j++ {
+			if matchesFilter(list.Index(j), idx, filter) {
+				kept = reflect.Append(kept, list.Index(j))
+			}
+		}
+		if kept.Len() == 0 {
+			kept = reflect.Zero(list.Type())
+		}
+		list.Set(kept)
+	}
+	return res
+}
+
+func matchesFilter(item reflect.Value, idx map[string]int, filter types.ResourceFilter) bool {
+	for _, c := range filter {
+		fi, ok := idx[conditionField(c)]
+		if !ok {
+			return false
+		}
+
+		var candidates []string
+		field := item.Field(fi)
+		switch {
+		case field.Kind() == reflect.Map:
+			key := c.Field[len(tagFieldPrefix):]
+			iter := field.MapRange()
+			for iter.Next() {
+				if strings.EqualFold(iter.Key().String(), key) {
+					candidates = append(candidates, iter.Value().String())
+				}
+			}
+		case field.Kind() == reflect.Slice:
+			for k := 0; k < field.Len(); k++ {
+				candidates = append(candidates, fmt.Sprint(field.Index(k).Interface()))
+			}
+		default:
+			candidates = []string{fmt.Sprint(field.Interface())}
+		}
+
+		if matchesCondition(c, candidates) == (c.Op == "!=") {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesCondition reports whether any candidate matches any of c's values;
+// "!=" is treated as "=" and negated by the caller.
+func matchesCondition(c types.ResourceCondition, candidates []string) bool {
+	for _, have := range candidates {
+		for _, want := range c.Values {
+			if c.Op == "~=" {
+				if strings.Contains(strings.ToLower(have), strings.ToLower(want)) {
+					return true
+				}
+			} else if strings.EqualFold(have, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
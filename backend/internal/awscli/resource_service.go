@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/local/aws-local-dashboard/internal/cache"
 	"github.com/local/aws-local-dashboard/internal/profiles"
@@ -14,25 +16,31 @@ import (
 )
 
 type resourceService struct {
-	exec Executor
+	exec           Executor
+	profileManager *profiles.Manager
 }
 
-// NewResourceService creates a ResourceService implementation backed by the AWS CLI.
-func NewResourceService(exec Executor) services.ResourceService {
+// NewResourceService creates a ResourceService implementation backed by the
+// AWS CLI. All-regions scans are limited to the active profile's allowed
+// regions.
+func NewResourceService(exec Executor, profileManager *profiles.Manager) services.ResourceService {
 	return &resourceService{
-		exec: exec,
+		exec:           exec,
+		profileManager: profileManager,
 	}
 }
 
-// NewCachedResourceService wraps a ResourceService with an in-memory cache so
-// repeated calls within a short TTL don't re-hit the AWS CLI.
+// NewCachedResourceService wraps a ResourceService with a cache so repeated
+// calls within a short TTL don't re-hit the AWS CLI. When the cache outlives
+// the server (see cache.Cache.Stale), a failed fetch returns the saved
+// listing, marked CachedAt.
 type cachedResourceService struct {
 	inner          services.ResourceService
-	cache          *cache.Cache[types.ServiceResources]
+	cache          cache.Cache[types.ServiceResources]
 	profileManager *profiles.Manager
 }
 
-func NewCachedResourceService(inner services.ResourceService, c *cache.Cache[types.ServiceResources], pm *profiles.Manager) services.ResourceService {
+func NewCachedResourceService(inner services.ResourceService, c cache.Cache[types.ServiceResources], pm *profiles.Manager) services.ResourceService {
 	return &cachedResourceService{
 		inner:          inner,
 		cache:          c,
@@ -40,30 +48,79 @@ func NewCachedResourceService(inner services.ResourceService, c *cache.Cache[typ
 	}
 }
 
-func (c *cachedResourceService) GetResources(ctx context.Context, service, region string) (types.ServiceResources, error) {
-	activeProfile := "system"
-	if c.profileManager != nil {
-		if id := c.profileManager.ActiveID(); id != "" {
-			activeProfile = id
-		}
+// ForgetProfileResources removes the cached resource listings of profile id.
+// As with ForgetProfileCosts, entries of a shared cache are kept.
+func ForgetProfileResources(c cache.Cache[types.ServiceResources], id string) {
+	if c.Shared() {
+		return
+	}
+	c.DeleteFunc(func(key string) bool {
+		return strings.HasPrefix(key, id+"|")
+	})
+}
+
+// cacheScope returns whose data cache keys for calls made with ctx hold:
+// the profile ID, or for a cache shared with other dashboards, whose
+// profile IDs may stand for other credentials, the account and principal
+// behind it (see profiles.Manager.CacheScope).
+func cacheScope(ctx context.Context, pm *profiles.Manager, shared bool) (string, error) {
+	if pm == nil {
+		return "system", nil
+	}
+	if shared {
+		return pm.CacheScope(ctx)
+	}
+	if id := pm.ProfileID(ctx); id != "" {
+		return id, nil
+	}
+	return "system", nil
+}
+
+func (c *cachedResourceService) GetResources(ctx context.Context, service, region string, filter types.ResourceFilter) (types.ServiceResources, error) {
+	// Check the filter before fetching anything; listings are cached
+	// unfiltered so every filter can share them.
+	if err := validateResourceFilter(filter); err != nil {
+		return types.ServiceResources{}, err
 	}
 
-	key := fmt.Sprintf("%s|%s|%s", activeProfile, strings.ToLower(service), strings.ToLower(region))
+	profile, err := cacheScope(ctx, c.profileManager, c.cache.Shared())
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	key := fmt.Sprintf("%s|%s|%s", profile, strings.ToLower(service), strings.ToLower(region))
 
 	if cached, ok := c.cache.Get(key); ok {
-		return cached, nil
+		return filterResources(cached, filter), nil
 	}
 
-	res, err := c.inner.GetResources(ctx, service, region)
+	res, err := c.inner.GetResources(ctx, service, region, nil)
 	if err != nil {
+		// Fall back to the listing saved by an earlier run, if any, while
+		// AWS can't be reached.
+		if saved, at, ok := c.cache.Stale(key); ok && unreachable(err) {
+			log.Printf("resources: serving %s listing from %s: %v", key, at.Format(time.RFC3339), err)
+			saved.CachedAt = at.UTC().Format(time.RFC3339)
+			return filterResources(saved, filter), nil
+		}
 		return types.ServiceResources{}, err
 	}
 
 	c.cache.Set(key, res)
-	return res, nil
+	return filterResources(res, filter), nil
+}
+
+func (s *resourceService) GetResources(ctx context.Context, service, region string, filter types.ResourceFilter) (types.ServiceResources, error) {
+	if err := validateResourceFilter(filter); err != nil {
+		return types.ServiceResources{}, err
+	}
+	res, err := s.getResources(ctx, service, region)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+	return filterResources(res, filter), nil
 }
 
-func (s *resourceService) GetResources(ctx context.Context, service, region string) (types.ServiceResources, error) {
+func (s *resourceService) getResources(ctx context.Context, service, region string) (types.ServiceResources, error) {
 	key := strings.ToLower(service)
 
 	switch key {
@@ -79,6 +136,72 @@ func (s *resourceService) GetResources(ctx context.Context, service, region stri
 		return s.getRekognitionCollections(ctx, region)
 	case "rds":
 		return s.getRDSInstances(ctx, region)
+	case "lambda":
+		return s.getLambdaFunctions(ctx, region)
+	case "ebs", "volumes":
+		return s.getEBSVolumes(ctx, region)
+	case "snapshots", "ebs-snapshots":
+		return s.getEBSSnapshots(ctx, region)
+	case "ami", "amis", "images":
+		return s.getAMIs(ctx, region)
+	case "sg", "security-groups":
+		return s.getSecurityGroups(ctx, region)
+	case "subnet", "subnets":
+		return s.getSubnets(ctx, region)
+	case "igw", "internet-gateways":
+		return s.getInternetGateways(ctx, region)
+	case "routetable", "route-tables":
+		return s.getRouteTables(ctx, region)
+	case "vpc-peering", "peering":
+		return s.getVPCPeeringConnections(ctx, region)
+	case "elb", "alb", "nlb", "load-balancers":
+		return s.getLoadBalancers(ctx, region)
+	case "target-group", "target-groups", "tg":
+		return s.getTargetGroups(ctx, region)
+	case "launch-template", "launch-templates", "launch-configurations":
+		return s.getLaunchTemplates(ctx, region)
+	case "route53", "hosted-zones":
+		return s.getHostedZones(ctx)
+	case "acm", "certificates":
+		return s.getCertificates(ctx, region)
+	case "iam":
+		return s.getIAMInventory(ctx)
+	case "access-keys", "iam-access-keys":
+		return s.getAccessKeys(ctx)
+	case "kms", "kms-keys":
+		return s.getKMSKeys(ctx, region)
+	case "ssm", "parameters":
+		return s.getSSMParameters(ctx, region)
+	case "sqs", "queues":
+		return s.getSQSQueues(ctx, region)
+	case "cloudwatch-alarms", "alarms":
+		return s.getCloudWatchAlarms(ctx, region)
+	case "log-groups", "logs":
+		return s.getLogGroups(ctx, region)
+	case "elasticbeanstalk", "beanstalk":
+		return s.getBeanstalk(ctx, region)
+	case "apigateway", "api-gateway":
+		return s.getAPIGateways(ctx, region)
+	case "glue":
+		return s.getGlue(ctx, region)
+	case "athena":
+		return s.getAthena(ctx, region)
+	case "cloudformation", "stacks":
+		return s.getCloudFormationStacks(ctx, region)
+	case "lightsail":
+		return s.getLightsail(ctx, region)
+	case "backup", "backup-vaults":
+		return s.getBackupInventory(ctx, region)
+	case "dms":
+		return s.getDMS(ctx, region)
+	case "mq", "amazonmq":
+		return s.getMQBrokers(ctx, region)
+	case "cognito":
+		return s.getCognito(ctx, region)
+	case "eventbridge", "events":
+		return s.getEventBridge(ctx, region)
+	case "workspaces":
+		return s.getWorkSpaces(ctx, region)
 	default:
 		return types.ServiceResources{
 			Service: service,
@@ -96,16 +219,14 @@ type ec2DescribeInstancesOutput struct {
 			InstanceType string `json:"InstanceType"`
 			PrivateIP    string `json:"PrivateIpAddress,omitempty"`
 			PublicIP     string `json:"PublicIpAddress,omitempty"`
+			LaunchTime   string `json:"LaunchTime"`
 			State        struct {
 				Name string `json:"Name"`
 			} `json:"State"`
 			Placement struct {
 				AvailabilityZone string `json:"AvailabilityZone"`
 			} `json:"Placement"`
-			Tags []struct {
-				Key   string `json:"Key"`
-				Value string `json:"Value"`
-			} `json:"Tags"`
+			Tags []awsTag `json:"Tags"`
 		} `json:"Instances"`
 	} `json:"Reservations"`
 }
@@ -162,7 +283,9 @@ func (s *resourceService) getEC2InstancesSingleRegion(ctx context.Context, regio
 				AvailabilityZone: inst.Placement.AvailabilityZone,
 				PrivateIP:        inst.PrivateIP,
 				PublicIP:         inst.PublicIP,
+				LaunchTime:       inst.LaunchTime,
 				Region:           instRegion,
+				Tags:             tagMap(inst.Tags),
 			})
 		}
 	}
@@ -243,14 +366,11 @@ func (s *resourceService) getEC2InstancesAllRegions(ctx context.Context) (types.
 
 type ec2DescribeVpcsOutput struct {
 	VPCs []struct {
-		VpcID     string `json:"VpcId"`
-		CIDRBlock string `json:"CidrBlock"`
-		IsDefault bool   `json:"IsDefault"`
-		State     string `json:"State"`
-		Tags      []struct {
-			Key   string `json:"Key"`
-			Value string `json:"Value"`
-		} `json:"Tags"`
+		VpcID     string   `json:"VpcId"`
+		CIDRBlock string   `json:"CidrBlock"`
+		IsDefault bool     `json:"IsDefault"`
+		State     string   `json:"State"`
+		Tags      []awsTag `json:"Tags"`
 	} `json:"Vpcs"`
 }
 
@@ -296,6 +416,7 @@ func (s *resourceService) getVPCsSingleRegion(ctx context.Context, region string
 			State:     v.State,
 			IsDefault: v.IsDefault,
 			Region:    vpcRegion,
+			Tags:      tagMap(v.Tags),
 		})
 	}
 
@@ -501,8 +622,8 @@ type s3ListBucketsOutput struct {
 }
 
 // getS3Buckets ignores the region parameter because S3 is global; we
-// return all buckets visible to the account and include their region if
-// it can be determined cheaply.
+// return all buckets visible to the account, then resolve each bucket's
+// region, size and object count concurrently.
 func (s *resourceService) getS3Buckets(ctx context.Context) (types.ServiceResources, error) {
 	out, err := s.exec.RunJSON(ctx, "s3api", "list-buckets")
 	if err != nil {
@@ -519,13 +640,20 @@ func (s *resourceService) getS3Buckets(ctx context.Context) (types.ServiceResour
 		buckets = append(buckets, types.S3Bucket{
 			Name:         b.Name,
 			CreationDate: b.CreationDate,
-			Region:       "", // Region resolution per bucket can be added later if needed.
 		})
 	}
 
+	failed := s.resolveBucketDetails(ctx, buckets)
+
+	msg := ""
+	if len(failed) > 0 {
+		msg = fmt.Sprintf("Could not resolve region or metrics for buckets: %s", strings.Join(failed, ", "))
+	}
+
 	return types.ServiceResources{
 		Service:   "s3",
 		S3Buckets: buckets,
+		Message:   msg,
 	}, nil
 }
 
@@ -660,32 +788,85 @@ type rdsDescribeDBInstancesOutput struct {
 	} `json:"DBInstances"`
 }
 
+type rdsDescribeDBClustersOutput struct {
+	DBClusters []struct {
+		DBClusterIdentifier string `json:"DBClusterIdentifier"`
+		Engine              string `json:"Engine"`
+		EngineVersion       string `json:"EngineVersion"`
+		EngineMode          string `json:"EngineMode"`
+		Status              string `json:"Status"`
+		Endpoint            string `json:"Endpoint"`
+		MultiAZ             bool   `json:"MultiAZ"`
+		DBClusterMembers    []struct {
+			DBInstanceIdentifier string `json:"DBInstanceIdentifier"`
+		} `json:"DBClusterMembers"`
+		ServerlessV2ScalingConfiguration *struct {
+			MinCapacity float64 `json:"MinCapacity"`
+			MaxCapacity float64 `json:"MaxCapacity"`
+		} `json:"ServerlessV2ScalingConfiguration"`
+	} `json:"DBClusters"`
+}
+
+type rdsDescribeDBSnapshotsOutput struct {
+	DBSnapshots []struct {
+		DBSnapshotIdentifier string `json:"DBSnapshotIdentifier"`
+		DBInstanceIdentifier string `json:"DBInstanceIdentifier"`
+		Engine               string `json:"Engine"`
+		Status               string `json:"Status"`
+		AllocatedStorage     int    `json:"AllocatedStorage"`
+		SnapshotCreateTime   string `json:"SnapshotCreateTime"`
+	} `json:"DBSnapshots"`
+}
+
+type rdsDescribeDBClusterSnapshotsOutput struct {
+	DBClusterSnapshots []struct {
+		DBClusterSnapshotIdentifier string `json:"DBClusterSnapshotIdentifier"`
+		DBClusterIdentifier         string `json:"DBClusterIdentifier"`
+		Engine                      string `json:"Engine"`
+		Status                      string `json:"Status"`
+		AllocatedStorage            int    `json:"AllocatedStorage"`
+		SnapshotCreateTime          string `json:"SnapshotCreateTime"`
+	} `json:"DBClusterSnapshots"`
+}
+
+// rdsInventory bundles instances, clusters and manual snapshots for one
+// region so they share a single region fan-out.
+type rdsInventory struct {
+	instances []types.RDSInstance
+	clusters  []types.RDSCluster
+	snapshots []types.RDSSnapshot
+}
+
 func (s *resourceService) getRDSInstances(ctx context.Context, region string) (types.ServiceResources, error) {
-	if strings.ToLower(region) == "all" {
-		return s.getRDSInstancesAllRegions(ctx)
+	inventories, msg, err := forRegions(ctx, s, region, s.getRDSSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
 	}
-	return s.getRDSInstancesSingleRegion(ctx, region)
-}
 
-func (s *resourceService) getRDSInstancesSingleRegion(ctx context.Context, region string) (types.ServiceResources, error) {
-	args := []string{"rds", "describe-db-instances"}
-	if region != "" {
-		args = append(args, "--region", region)
+	res := types.ServiceResources{Service: "rds", Message: msg}
+	for _, inv := range inventories {
+		res.RDSInstances = append(res.RDSInstances, inv.instances...)
+		res.RDSClusters = append(res.RDSClusters, inv.clusters...)
+		res.RDSSnapshots = append(res.RDSSnapshots, inv.snapshots...)
 	}
+	return res, nil
+}
 
-	out, err := s.exec.RunJSON(ctx, args...)
+func (s *resourceService) getRDSSingleRegion(ctx context.Context, region string) ([]rdsInventory, error) {
+	var inv rdsInventory
+
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"rds", "describe-db-instances"}, region)...)
 	if err != nil {
-		return types.ServiceResources{}, err
+		return nil, err
 	}
 
 	var resp rdsDescribeDBInstancesOutput
 	if err := json.Unmarshal(out, &resp); err != nil {
-		return types.ServiceResources{}, fmt.Errorf("failed to parse describe-db-instances output: %w", err)
+		return nil, fmt.Errorf("failed to parse describe-db-instances output: %w", err)
 	}
 
-	var dbs []types.RDSInstance
 	for _, db := range resp.DBInstances {
-		dbs = append(dbs, types.RDSInstance{
+		inv.instances = append(inv.instances, types.RDSInstance{
 			DBInstanceIdentifier: db.DBInstanceIdentifier,
 			Engine:               db.Engine,
 			Status:               db.DBInstanceStatus,
@@ -696,75 +877,85 @@ func (s *resourceService) getRDSInstancesSingleRegion(ctx context.Context, regio
 		})
 	}
 
-	return types.ServiceResources{
-		Service:      "rds",
-		RDSInstances: dbs,
-	}, nil
-}
-
-func (s *resourceService) getRDSInstancesAllRegions(ctx context.Context) (types.ServiceResources, error) {
-	regions, err := s.listRegions(ctx)
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"rds", "describe-db-clusters"}, region)...)
 	if err != nil {
-		return types.ServiceResources{}, err
+		return nil, err
 	}
 
-	type result struct {
-		region string
-		dbs    []types.RDSInstance
-		err    error
+	var clusters rdsDescribeDBClustersOutput
+	if err := json.Unmarshal(out, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-db-clusters output: %w", err)
 	}
 
-	resultsCh := make(chan result, len(regions))
-	var wg sync.WaitGroup
-
-	const maxConcurrent = 5
-	sem := make(chan struct{}, maxConcurrent)
+	for _, c := range clusters.DBClusters {
+		cluster := types.RDSCluster{
+			DBClusterIdentifier: c.DBClusterIdentifier,
+			Engine:              c.Engine,
+			EngineVersion:       c.EngineVersion,
+			EngineMode:          c.EngineMode,
+			Status:              c.Status,
+			Endpoint:            c.Endpoint,
+			MultiAZ:             c.MultiAZ,
+			MemberCount:         len(c.DBClusterMembers),
+			Region:              region,
+		}
+		if sc := c.ServerlessV2ScalingConfiguration; sc != nil {
+			cluster.ServerlessV2 = true
+			cluster.MinCapacityACU = sc.MinCapacity
+			cluster.MaxCapacityACU = sc.MaxCapacity
+		}
+		inv.clusters = append(inv.clusters, cluster)
+	}
 
-	for _, rgn := range regions {
-		wg.Add(1)
-		go func(region string) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
+	// Automated snapshots are covered by the free backup allowance; only
+	// manual snapshots accrue separate storage cost.
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"rds", "describe-db-snapshots", "--snapshot-type", "manual"}, region)...)
+	if err != nil {
+		return nil, err
+	}
 
-			res, err := s.getRDSInstancesSingleRegion(ctx, region)
-			if err != nil {
-				resultsCh <- result{region: region, err: err}
-				return
-			}
-			resultsCh <- result{region: region, dbs: res.RDSInstances}
-		}(rgn)
+	var snaps rdsDescribeDBSnapshotsOutput
+	if err := json.Unmarshal(out, &snaps); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-db-snapshots output: %w", err)
 	}
 
-	go func() {
-		wg.Wait()
-		close(resultsCh)
-	}()
+	for _, sn := range snaps.DBSnapshots {
+		inv.snapshots = append(inv.snapshots, types.RDSSnapshot{
+			SnapshotIdentifier: sn.DBSnapshotIdentifier,
+			Source:             sn.DBInstanceIdentifier,
+			SourceType:         "instance",
+			Engine:             sn.Engine,
+			Status:             sn.Status,
+			AllocatedStorageGB: sn.AllocatedStorage,
+			CreateTime:         sn.SnapshotCreateTime,
+			Region:             region,
+		})
+	}
 
-	var all []types.RDSInstance
-	var skipped []string
+	out, err = s.exec.RunJSON(ctx, regionArgs([]string{"rds", "describe-db-cluster-snapshots", "--snapshot-type", "manual"}, region)...)
+	if err != nil {
+		return nil, err
+	}
 
-	for r := range resultsCh {
-		if r.err != nil {
-			if isAuthError(r.err) {
-				skipped = append(skipped, r.region)
-				continue
-			}
-			return types.ServiceResources{}, r.err
-		}
-		all = append(all, r.dbs...)
+	var clusterSnaps rdsDescribeDBClusterSnapshotsOutput
+	if err := json.Unmarshal(out, &clusterSnaps); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-db-cluster-snapshots output: %w", err)
 	}
 
-	msg := ""
-	if len(skipped) > 0 {
-		msg = fmt.Sprintf("Skipped regions due to authentication errors: %s", strings.Join(skipped, ", "))
+	for _, sn := range clusterSnaps.DBClusterSnapshots {
+		inv.snapshots = append(inv.snapshots, types.RDSSnapshot{
+			SnapshotIdentifier: sn.DBClusterSnapshotIdentifier,
+			Source:             sn.DBClusterIdentifier,
+			SourceType:         "cluster",
+			Engine:             sn.Engine,
+			Status:             sn.Status,
+			AllocatedStorageGB: sn.AllocatedStorage,
+			CreateTime:         sn.SnapshotCreateTime,
+			Region:             region,
+		})
 	}
 
-	return types.ServiceResources{
-		Service:      "rds",
-		RDSInstances: all,
-		Message:      msg,
-	}, nil
+	return []rdsInventory{inv}, nil
 }
 
 // listRegions returns the list of region names for the account.
@@ -793,6 +984,10 @@ func (s *resourceService) listRegions(ctx context.Context) ([]string, error) {
 		if strings.EqualFold(r.OptInStatus, "not-opted-in") {
 			continue
 		}
+		// All-regions scans stay within the active profile's allowed regions.
+		if s.profileManager != nil && !s.profileManager.RegionAllowed(ctx, r.RegionName) {
+			continue
+		}
 		regions = append(regions, r.RegionName)
 	}
 	return regions, nil
@@ -0,0 +1,219 @@
+package awscli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/cache"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// failingResources is a ResourceService that always returns err.
+type failingResources struct{ err error }
+
+func (f failingResources) GetResources(context.Context, string, string, types.ResourceFilter) (types.ServiceResources, error) {
+	return types.ServiceResources{}, f.err
+}
+
+// staleCache returns a persisted cache whose entries expire at once but are
+// still served by Stale.
+func staleCache[V any](t *testing.T) *cache.Memory[V] {
+	t.Helper()
+	c := cache.New[V](time.Nanosecond)
+	if err := c.Persist(filepath.Join(t.TempDir(), "cache.json"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// fallbackCases are failures and whether saved data may be served instead.
+var fallbackCases = []struct {
+	name     string
+	err      error
+	fallback bool
+}{
+	{"endpoint unreachable", parseCLIError(`Could not connect to the endpoint URL: "https://ec2.us-east-1.amazonaws.com/"`, 255), true},
+	{"read timeout", parseCLIError(`Read timeout on endpoint URL: "https://ce.us-east-1.amazonaws.com/"`, 255), true},
+	{"expired credentials", fmt.Errorf("%w: %w", services.ErrCredentialsExpired, parseCLIError("An error occurred (ExpiredToken) when calling the DescribeInstances operation: The security token included in the request is expired", 254)), false},
+	{"access denied", parseCLIError("An error occurred (AccessDeniedException) when calling the GetCostAndUsage operation: User is not authorized", 254), false},
+	{"region not allowed", fmt.Errorf("%w: eu-west-1", profiles.ErrRegionNotAllowed), false},
+	{"cancelled", context.Canceled, false},
+}
+
+func TestCachedResourcesFallBackOnlyWhenUnreachable(t *testing.T) {
+	for _, tt := range fallbackCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := staleCache[types.ServiceResources](t)
+			c.Set("system|ec2|us-east-1", types.ServiceResources{Service: "ec2"})
+			svc := NewCachedResourceService(failingResources{tt.err}, c, nil)
+
+			res, err := svc.GetResources(context.Background(), "ec2", "us-east-1", nil)
+			if tt.fallback {
+				if err != nil || res.CachedAt == "" {
+					t.Errorf("got %+v, %v; want the saved listing", res, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// fakeRedis is a Redis server keeping GET, SET, SCAN and DEL data in memory.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// startFakeRedis serves a fakeRedis until the test ends and returns its URL.
+func startFakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	r := &fakeRedis{data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return "redis://" + ln.Addr().String()
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			br.ReadString('\n')
+			arg, _ := br.ReadString('\n')
+			args[i] = strings.TrimSuffix(arg, "\r\n")
+		}
+		conn.Write([]byte(r.do(args)))
+	}
+}
+
+func (r *fakeRedis) do(args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bulk := func(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		if v, ok := r.data[args[1]]; ok {
+			return bulk(v)
+		}
+		return "$-1\r\n"
+	case "SET":
+		r.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL":
+		for _, k := range args[1:] {
+			delete(r.data, k)
+		}
+		return ":1\r\n"
+	case "SCAN":
+		reply := fmt.Sprintf("*2\r\n%s*%d\r\n", bulk("0"), len(r.data))
+		for k := range r.data {
+			reply += bulk(k)
+		}
+		return reply
+	}
+	return "+PONG\r\n"
+}
+
+// fakeAWS puts an aws CLI on PATH whose sts get-caller-identity reports the
+// account in the access key ID, e.g. AKIA111111111111 is in 111111111111.
+func fakeAWS(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake aws CLI is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+account=$(echo "$AWS_ACCESS_KEY_ID" | cut -c5-16)
+echo "{\"UserId\":\"AIDA$account\",\"Account\":\"$account\",\"Arn\":\"arn:aws:iam::$account:user/dashboard\"}"
+`
+	if err := os.WriteFile(filepath.Join(dir, "aws"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// fixedResources is a ResourceService listing a single bucket.
+type fixedResources struct{ bucket string }
+
+func (f fixedResources) GetResources(context.Context, string, string, types.ResourceFilter) (types.ServiceResources, error) {
+	return types.ServiceResources{Service: "s3", S3Buckets: []types.S3Bucket{{Name: f.bucket}}}, nil
+}
+
+func TestSharedCacheKeysByAccount(t *testing.T) {
+	fakeAWS(t)
+	url := startFakeRedis(t)
+
+	// replica returns a dashboard whose profile "1" holds accessKey and
+	// lists bucket, sharing one Redis with the other replicas.
+	replica := func(accessKey, bucket string) (services.ResourceService, cache.Cache[types.ServiceResources]) {
+		t.Setenv("PROFILE_STORE_PATH", filepath.Join(t.TempDir(), "profiles.json"))
+		pm := profiles.NewManager(context.Background(), profiles.Options{Offline: true})
+		if p, err := pm.AddAndActivateProfile(context.Background(), "prod", accessKey, "secret", "", "us-east-1", ""); err != nil || p.ID != "1" {
+			t.Fatalf("AddAndActivateProfile = %+v, %v", p, err)
+		}
+		client, err := cache.NewRedisClient(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := cache.NewRedis[types.ServiceResources](client, "test:resources:", time.Hour, time.Hour)
+		return NewCachedResourceService(fixedResources{bucket}, c, pm), c
+	}
+	first, _ := replica("AKIA111111111111", "first")
+	second, secondCache := replica("AKIA222222222222", "second")
+	sameAccount, _ := replica("AKIA111111111111", "fetched")
+
+	get := func(svc services.ResourceService) string {
+		t.Helper()
+		res, err := svc.GetResources(context.Background(), "s3", "us-east-1", nil)
+		if err != nil || len(res.S3Buckets) != 1 {
+			t.Fatalf("GetResources = %+v, %v", res, err)
+		}
+		return res.S3Buckets[0].Name
+	}
+	if got := get(first); got != "first" {
+		t.Errorf("first replica got %q", got)
+	}
+	if got := get(second); got != "second" {
+		t.Errorf("replica in another account under the same profile ID got %q", got)
+	}
+
+	// Forgetting profile "1" on one replica leaves the others' entries.
+	ForgetProfileResources(secondCache, "1")
+	if got := get(sameAccount); got != "first" {
+		t.Errorf("replica in the first account got %q, want the shared listing", got)
+	}
+}
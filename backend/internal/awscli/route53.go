@@ -0,0 +1,62 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Route 53
+
+type route53ListHostedZonesOutput struct {
+	HostedZones []struct {
+		ID     string `json:"Id"`
+		Name   string `json:"Name"`
+		Config struct {
+			Comment     string `json:"Comment"`
+			PrivateZone bool   `json:"PrivateZone"`
+		} `json:"Config"`
+		ResourceRecordSetCount int `json:"ResourceRecordSetCount"`
+	} `json:"HostedZones"`
+}
+
+// defaultZoneRecordSets is the number of record sets (SOA and NS) Route 53
+// creates in every new hosted zone.
+const defaultZoneRecordSets = 2
+
+func (s *resourceService) getHostedZones(ctx context.Context) (types.ServiceResources, error) {
+	out, err := s.exec.RunJSON(ctx, "route53", "list-hosted-zones")
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	var resp route53ListHostedZonesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.ServiceResources{}, fmt.Errorf("failed to parse list-hosted-zones output: %w", err)
+	}
+
+	var zones []types.HostedZone
+	for _, z := range resp.HostedZones {
+		visibility := "public"
+		if z.Config.PrivateZone {
+			visibility = "private"
+		}
+
+		zones = append(zones, types.HostedZone{
+			ID:               strings.TrimPrefix(z.ID, "/hostedzone/"),
+			Name:             z.Name,
+			Visibility:       visibility,
+			RecordSetCount:   z.ResourceRecordSetCount,
+			Comment:          z.Config.Comment,
+			CleanupCandidate: z.ResourceRecordSetCount <= defaultZoneRecordSets,
+		})
+	}
+
+	return types.ServiceResources{
+		Service:     "route53",
+		HostedZones: zones,
+	}, nil
+}
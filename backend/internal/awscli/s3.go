@@ -0,0 +1,188 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// S3 per-bucket details
+
+type s3GetBucketLocationOutput struct {
+	LocationConstraint *string `json:"LocationConstraint"`
+}
+
+type cloudwatchGetMetricStatisticsOutput struct {
+	Datapoints []struct {
+		Timestamp string  `json:"Timestamp"`
+		Average   float64 `json:"Average"`
+	} `json:"Datapoints"`
+}
+
+type cloudwatchGetMetricDataOutput struct {
+	MetricDataResults []struct {
+		Timestamps []string  `json:"Timestamps"`
+		Values     []float64 `json:"Values"`
+	} `json:"MetricDataResults"`
+}
+
+// s3DetailWorkers is the size of the worker pool resolving bucket details.
+// Each bucket costs three CLI invocations, so keep this modest.
+const s3DetailWorkers = 8
+
+// resolveBucketDetails fills in Region, SizeBytes and ObjectCount for each
+// bucket in place. Failures are per bucket and do not abort the listing; the
+// names of buckets that could not be resolved are returned.
+func (s *resourceService) resolveBucketDetails(ctx context.Context, buckets []types.S3Bucket) []string {
+	jobs := make(chan int)
+	errs := make([]error, len(buckets))
+	var wg sync.WaitGroup
+
+	workers := s3DetailWorkers
+	if len(buckets) < workers {
+		workers = len(buckets)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = s.resolveBucket(ctx, &buckets[i])
+			}
+		}()
+	}
+
+	for i := range buckets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, buckets[i].Name)
+		}
+	}
+	return failed
+}
+
+func (s *resourceService) resolveBucket(ctx context.Context, b *types.S3Bucket) error {
+	out, err := s.exec.RunJSON(ctx, "s3api", "get-bucket-location", "--bucket", b.Name)
+	if err != nil {
+		return err
+	}
+
+	var loc s3GetBucketLocationOutput
+	if err := json.Unmarshal(out, &loc); err != nil {
+		return fmt.Errorf("failed to parse get-bucket-location output: %w", err)
+	}
+	b.Region = bucketRegion(loc.LocationConstraint)
+
+	// S3 storage metrics are published once a day, in the bucket's region.
+	size, err := s.bucketSize(ctx, b.Name, b.Region)
+	if err != nil {
+		return err
+	}
+	count, err := s.latestS3Metric(ctx, b.Name, b.Region, "NumberOfObjects", "AllStorageTypes")
+	if err != nil {
+		return err
+	}
+
+	b.SizeBytes = int64(size)
+	b.ObjectCount = int64(count)
+	return nil
+}
+
+// bucketRegion maps a LocationConstraint to a region name. Buckets in
+// us-east-1 report a null constraint and very old EU buckets report "EU".
+func bucketRegion(constraint *string) string {
+	if constraint == nil || *constraint == "" {
+		return "us-east-1"
+	}
+	if *constraint == "EU" {
+		return "eu-west-1"
+	}
+	return *constraint
+}
+
+// bucketSize returns the latest BucketSizeBytes of bucket summed over every
+// storage class. S3 publishes one metric per StorageType (StandardStorage,
+// StandardIAStorage, GlacierStorage, the Intelligent-Tiering tiers, ...), so
+// a SEARCH expression finds them all and SUM adds them up in one call.
+func (s *resourceService) bucketSize(ctx context.Context, bucket, region string) (float64, error) {
+	end := time.Now().UTC()
+	start := end.Add(-72 * time.Hour)
+
+	search := fmt.Sprintf(`SUM(SEARCH('{AWS/S3,BucketName,StorageType} MetricName="BucketSizeBytes" BucketName="%s"', 'Average', 86400))`, bucket)
+	queries, err := json.Marshal([]map[string]any{{"Id": "size", "Expression": search}})
+	if err != nil {
+		return 0, err
+	}
+	args := []string{
+		"cloudwatch", "get-metric-data",
+		"--metric-data-queries", string(queries),
+		"--start-time", start.Format(time.RFC3339),
+		"--end-time", end.Format(time.RFC3339),
+	}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp cloudwatchGetMetricDataOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return 0, fmt.Errorf("failed to parse get-metric-data output: %w", err)
+	}
+	if len(resp.MetricDataResults) == 0 {
+		return 0, nil
+	}
+	r := resp.MetricDataResults[0]
+	latest, value := "", 0.0
+	for i, ts := range r.Timestamps {
+		if i < len(r.Values) && ts > latest {
+			latest, value = ts, r.Values[i]
+		}
+	}
+	return value, nil
+}
+
+func (s *resourceService) latestS3Metric(ctx context.Context, bucket, region, metric, storageType string) (float64, error) {
+	end := time.Now().UTC()
+	start := end.Add(-72 * time.Hour)
+
+	args := []string{
+		"cloudwatch", "get-metric-statistics",
+		"--namespace", "AWS/S3",
+		"--metric-name", metric,
+		"--dimensions",
+		"Name=BucketName,Value=" + bucket,
+		"Name=StorageType,Value=" + storageType,
+		"--start-time", start.Format(time.RFC3339),
+		"--end-time", end.Format(time.RFC3339),
+		"--period", "86400",
+		"--statistics", "Average",
+	}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp cloudwatchGetMetricStatisticsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return 0, fmt.Errorf("failed to parse get-metric-statistics output: %w", err)
+	}
+	if len(resp.Datapoints) == 0 {
+		return 0, nil
+	}
+
+	sort.Slice(resp.Datapoints, func(i, j int) bool {
+		return resp.Datapoints[i].Timestamp > resp.Datapoints[j].Timestamp
+	})
+	return resp.Datapoints[0].Average, nil
+}
@@ -0,0 +1,42 @@
+package awscli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// recordingExecutor returns out for every command and remembers the last one.
+type recordingExecutor struct {
+	out  string
+	args []string
+}
+
+func (e *recordingExecutor) RunJSON(_ context.Context, args ...string) ([]byte, error) {
+	e.args = args
+	return []byte(e.out), nil
+}
+
+func TestBucketSizeSumsStorageClasses(t *testing.T) {
+	exec := &recordingExecutor{out: `{"MetricDataResults": [{
+		"Id": "size",
+		"Timestamps": ["2026-10-15T00:00:00+00:00", "2026-10-16T00:00:00+00:00"],
+		"Values": [100, 250]
+	}]}`}
+	s := &resourceService{exec: exec}
+
+	size, err := s.bucketSize(context.Background(), "logs", "eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 250 {
+		t.Errorf("size = %v, want the latest datapoint, 250", size)
+	}
+	cmd := strings.Join(exec.args, " ")
+	if !strings.Contains(cmd, `SUM(SEARCH('{AWS/S3,BucketName,StorageType} MetricName=\"BucketSizeBytes\" BucketName=\"logs\"'`) {
+		t.Errorf("query doesn't sum every storage type: %s", cmd)
+	}
+	if !strings.Contains(cmd, "--region eu-west-1") {
+		t.Errorf("query doesn't run in the bucket's region: %s", cmd)
+	}
+}
@@ -0,0 +1,130 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Security groups
+
+type ec2IPPermission struct {
+	IPProtocol string `json:"IpProtocol"`
+	FromPort   *int   `json:"FromPort"`
+	ToPort     *int   `json:"ToPort"`
+	IPRanges   []struct {
+		CidrIP string `json:"CidrIp"`
+	} `json:"IpRanges"`
+	IPv6Ranges []struct {
+		CidrIPv6 string `json:"CidrIpv6"`
+	} `json:"Ipv6Ranges"`
+	UserIDGroupPairs []struct {
+		GroupID string `json:"GroupId"`
+	} `json:"UserIdGroupPairs"`
+	PrefixListIDs []struct {
+		PrefixListID string `json:"PrefixListId"`
+	} `json:"PrefixListIds"`
+}
+
+type ec2DescribeSecurityGroupsOutput struct {
+	SecurityGroups []struct {
+		GroupID             string            `json:"GroupId"`
+		GroupName           string            `json:"GroupName"`
+		Description         string            `json:"Description"`
+		VpcID               string            `json:"VpcId"`
+		IPPermissions       []ec2IPPermission `json:"IpPermissions"`
+		IPPermissionsEgress []ec2IPPermission `json:"IpPermissionsEgress"`
+		Tags                []awsTag          `json:"Tags"`
+	} `json:"SecurityGroups"`
+}
+
+func (s *resourceService) getSecurityGroups(ctx context.Context, region string) (types.ServiceResources, error) {
+	groups, msg, err := forRegions(ctx, s, region, s.getSecurityGroupsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:        "sg",
+		SecurityGroups: groups,
+		Message:        msg,
+	}, nil
+}
+
+func (s *resourceService) getSecurityGroupsSingleRegion(ctx context.Context, region string) ([]types.SecurityGroup, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-security-groups"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeSecurityGroupsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-security-groups output: %w", err)
+	}
+
+	var groups []types.SecurityGroup
+	for _, g := range resp.SecurityGroups {
+		var openPorts []string
+		for _, p := range g.IPPermissions {
+			if permissionOpenToWorld(p) {
+				openPorts = append(openPorts, describePortRange(p))
+			}
+		}
+
+		groups = append(groups, types.SecurityGroup{
+			GroupID:          g.GroupID,
+			GroupName:        g.GroupName,
+			Name:             tagValue(g.Tags, "Name"),
+			Description:      g.Description,
+			VpcID:            g.VpcID,
+			IngressRuleCount: countRules(g.IPPermissions),
+			EgressRuleCount:  countRules(g.IPPermissionsEgress),
+			OpenToWorld:      len(openPorts) > 0,
+			OpenIngressPorts: openPorts,
+			Region:           region,
+			Tags:             tagMap(g.Tags),
+		})
+	}
+	return groups, nil
+}
+
+// countRules counts individual rules the way the console does: one per
+// source (CIDR, security group or prefix list) within each permission.
+func countRules(perms []ec2IPPermission) int {
+	n := 0
+	for _, p := range perms {
+		n += len(p.IPRanges) + len(p.IPv6Ranges) + len(p.UserIDGroupPairs) + len(p.PrefixListIDs)
+	}
+	return n
+}
+
+func permissionOpenToWorld(p ec2IPPermission) bool {
+	for _, r := range p.IPRanges {
+		if r.CidrIP == "0.0.0.0/0" {
+			return true
+		}
+	}
+	for _, r := range p.IPv6Ranges {
+		if r.CidrIPv6 == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+// describePortRange renders a permission as "tcp/22", "tcp/8000-8080" or "all".
+func describePortRange(p ec2IPPermission) string {
+	if p.IPProtocol == "-1" {
+		return "all"
+	}
+	if p.FromPort == nil || p.ToPort == nil || (*p.FromPort == -1 && *p.ToPort == -1) {
+		return p.IPProtocol
+	}
+	if *p.FromPort == *p.ToPort {
+		return p.IPProtocol + "/" + strconv.Itoa(*p.FromPort)
+	}
+	return p.IPProtocol + "/" + strconv.Itoa(*p.FromPort) + "-" + strconv.Itoa(*p.ToPort)
+}
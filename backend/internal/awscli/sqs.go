@@ -0,0 +1,117 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// SQS
+
+type sqsListQueuesOutput struct {
+	QueueUrls []string `json:"QueueUrls"`
+}
+
+type sqsGetQueueAttributesOutput struct {
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// maxConcurrentQueues bounds the per-queue attribute calls within a region.
+const maxConcurrentQueues = 4
+
+func (s *resourceService) getSQSQueues(ctx context.Context, region string) (types.ServiceResources, error) {
+	queues, msg, err := forRegions(ctx, s, region, s.getSQSQueuesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:   "sqs",
+		SQSQueues: queues,
+		Message:   msg,
+	}, nil
+}
+
+func (s *resourceService) getSQSQueuesSingleRegion(ctx context.Context, region string) ([]types.SQSQueue, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"sqs", "list-queues"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// list-queues prints nothing at all when the region has no queues.
+	if len(strings.TrimSpace(string(out))) == 0 {
+		return nil, nil
+	}
+
+	var resp sqsListQueuesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse list-queues output: %w", err)
+	}
+
+	queues := make([]types.SQSQueue, len(resp.QueueUrls))
+	errs := make([]error, len(resp.QueueUrls))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentQueues)
+
+	for i, url := range resp.QueueUrls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			queues[i], errs[i] = s.describeSQSQueue(ctx, region, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return queues, nil
+}
+
+func (s *resourceService) describeSQSQueue(ctx context.Context, region, url string) (types.SQSQueue, error) {
+	args := []string{
+		"sqs", "get-queue-attributes",
+		"--queue-url", url,
+		"--attribute-names",
+		"ApproximateNumberOfMessages",
+		"ApproximateNumberOfMessagesNotVisible",
+		"ApproximateNumberOfMessagesDelayed",
+		"FifoQueue",
+		"RedrivePolicy",
+	}
+	out, err := s.exec.RunJSON(ctx, regionArgs(args, region)...)
+	if err != nil {
+		return types.SQSQueue{}, err
+	}
+
+	var resp sqsGetQueueAttributesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return types.SQSQueue{}, fmt.Errorf("failed to parse get-queue-attributes output: %w", err)
+	}
+
+	attr := func(name string) int64 {
+		n, _ := strconv.ParseInt(resp.Attributes[name], 10, 64)
+		return n
+	}
+
+	return types.SQSQueue{
+		Name:                url[strings.LastIndex(url, "/")+1:],
+		URL:                 url,
+		Fifo:                resp.Attributes["FifoQueue"] == "true",
+		MessagesAvailable:   attr("ApproximateNumberOfMessages"),
+		MessagesInFlight:    attr("ApproximateNumberOfMessagesNotVisible"),
+		MessagesDelayed:     attr("ApproximateNumberOfMessagesDelayed"),
+		HasDeadLetterTarget: resp.Attributes["RedrivePolicy"] != "",
+		Region:              region,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// SSM Parameter Store
+
+type ssmDescribeParametersOutput struct {
+	Parameters []struct {
+		Name             string `json:"Name"`
+		Type             string `json:"Type"`
+		Tier             string `json:"Tier"`
+		DataType         string `json:"DataType"`
+		Description      string `json:"Description"`
+		Version          int64  `json:"Version"`
+		LastModifiedDate string `json:"LastModifiedDate"`
+		LastModifiedUser string `json:"LastModifiedUser"`
+	} `json:"Parameters"`
+}
+
+func (s *resourceService) getSSMParameters(ctx context.Context, region string) (types.ServiceResources, error) {
+	params, msg, err := forRegions(ctx, s, region, s.getSSMParametersSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	// Advanced-tier parameters are billed per parameter per month, so surface
+	// how many there are alongside any region-skip message.
+	advanced := 0
+	for _, p := range params {
+		if p.Tier == "Advanced" {
+			advanced++
+		}
+	}
+	if advanced > 0 {
+		note := fmt.Sprintf("%d advanced-tier parameters (billed monthly)", advanced)
+		if msg != "" {
+			msg = note + ". " + msg
+		} else {
+			msg = note
+		}
+	}
+
+	return types.ServiceResources{
+		Service:       "ssm",
+		SSMParameters: params,
+		Message:       msg,
+	}, nil
+}
+
+func (s *resourceService) getSSMParametersSingleRegion(ctx context.Context, region string) ([]types.SSMParameter, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ssm", "describe-parameters"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ssmDescribeParametersOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-parameters output: %w", err)
+	}
+
+	var params []types.SSMParameter
+	for _, p := range resp.Parameters {
+		params = append(params, types.SSMParameter{
+			Name:             p.Name,
+			Type:             p.Type,
+			Tier:             p.Tier,
+			DataType:         p.DataType,
+			Description:      p.Description,
+			Version:          p.Version,
+			LastModifiedDate: p.LastModifiedDate,
+			LastModifiedUser: p.LastModifiedUser,
+			Region:           region,
+		})
+	}
+	return params, nil
+}
@@ -0,0 +1,101 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/netip"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Subnets
+
+type ec2DescribeSubnetsOutput struct {
+	Subnets []struct {
+		SubnetID                string   `json:"SubnetId"`
+		VpcID                   string   `json:"VpcId"`
+		CidrBlock               string   `json:"CidrBlock"`
+		AvailabilityZone        string   `json:"AvailabilityZone"`
+		AvailableIPAddressCount int      `json:"AvailableIpAddressCount"`
+		MapPublicIPOnLaunch     bool     `json:"MapPublicIpOnLaunch"`
+		DefaultForAz            bool     `json:"DefaultForAz"`
+		State                   string   `json:"State"`
+		Tags                    []awsTag `json:"Tags"`
+	} `json:"Subnets"`
+}
+
+// awsReservedSubnetIPs is the number of addresses AWS reserves in every
+// subnet (network, router, DNS, future use and broadcast).
+const awsReservedSubnetIPs = 5
+
+func (s *resourceService) getSubnets(ctx context.Context, region string) (types.ServiceResources, error) {
+	subnets, msg, err := forRegions(ctx, s, region, s.getSubnetsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service: "subnet",
+		Subnets: subnets,
+		Message: msg,
+	}, nil
+}
+
+func (s *resourceService) getSubnetsSingleRegion(ctx context.Context, region string) ([]types.Subnet, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-subnets"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeSubnetsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-subnets output: %w", err)
+	}
+
+	var subnets []types.Subnet
+	for _, sn := range resp.Subnets {
+		usable := usableSubnetIPs(sn.CidrBlock)
+		utilization := 0.0
+		if usable > 0 {
+			used := usable - sn.AvailableIPAddressCount
+			utilization = math.Round(float64(used)/float64(usable)*1000) / 10
+		}
+
+		snRegion := region
+		if snRegion == "" {
+			snRegion = regionFromAZ(sn.AvailabilityZone)
+		}
+
+		subnets = append(subnets, types.Subnet{
+			SubnetID:                sn.SubnetID,
+			Name:                    tagValue(sn.Tags, "Name"),
+			VpcID:                   sn.VpcID,
+			CIDRBlock:               sn.CidrBlock,
+			AvailabilityZone:        sn.AvailabilityZone,
+			AvailableIPAddressCount: sn.AvailableIPAddressCount,
+			TotalIPAddressCount:     usable,
+			UtilizationPercent:      utilization,
+			MapPublicIPOnLaunch:     sn.MapPublicIPOnLaunch,
+			DefaultForAz:            sn.DefaultForAz,
+			State:                   sn.State,
+			Region:                  snRegion,
+			Tags:                    tagMap(sn.Tags),
+		})
+	}
+	return subnets, nil
+}
+
+// usableSubnetIPs returns the number of assignable addresses in an IPv4 CIDR.
+func usableSubnetIPs(cidr string) int {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Addr().Is4() {
+		return 0
+	}
+	total := 1 << (32 - prefix.Bits())
+	if total <= awsReservedSubnetIPs {
+		return 0
+	}
+	return total - awsReservedSubnetIPs
+}
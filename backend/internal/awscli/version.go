@@ -0,0 +1,23 @@
+package awscli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CLIVersion returns the version of the installed aws CLI, e.g. "2.15.30",
+// parsed from "aws --version" (which v1 prints to stderr).
+func CLIVersion(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "aws", "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run aws --version: %w", err)
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	version, ok := strings.CutPrefix(first, "aws-cli/")
+	if !ok || version == "" {
+		return "", fmt.Errorf("unexpected aws --version output %q", strings.TrimSpace(string(out)))
+	}
+	return version, nil
+}
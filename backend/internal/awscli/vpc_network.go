@@ -0,0 +1,244 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Internet gateways
+
+type ec2DescribeInternetGatewaysOutput struct {
+	InternetGateways []struct {
+		InternetGatewayID string `json:"InternetGatewayId"`
+		OwnerID           string `json:"OwnerId"`
+		Attachments       []struct {
+			VpcID string `json:"VpcId"`
+			State string `json:"State"`
+		} `json:"Attachments"`
+		Tags []awsTag `json:"Tags"`
+	} `json:"InternetGateways"`
+}
+
+func (s *resourceService) getInternetGateways(ctx context.Context, region string) (types.ServiceResources, error) {
+	igws, msg, err := forRegions(ctx, s, region, s.getInternetGatewaysSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:          "igw",
+		InternetGateways: igws,
+		Message:          msg,
+	}, nil
+}
+
+func (s *resourceService) getInternetGatewaysSingleRegion(ctx context.Context, region string) ([]types.InternetGateway, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-internet-gateways"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeInternetGatewaysOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-internet-gateways output: %w", err)
+	}
+
+	var igws []types.InternetGateway
+	for _, g := range resp.InternetGateways {
+		igw := types.InternetGateway{
+			InternetGatewayID: g.InternetGatewayID,
+			Name:              tagValue(g.Tags, "Name"),
+			OwnerID:           g.OwnerID,
+			State:             "detached",
+			Region:            region,
+		}
+		// A gateway can only be attached to a single VPC at a time.
+		if len(g.Attachments) > 0 {
+			igw.VpcID = g.Attachments[0].VpcID
+			igw.State = g.Attachments[0].State
+		}
+		igws = append(igws, igw)
+	}
+	return igws, nil
+}
+
+// Route tables
+
+type ec2DescribeRouteTablesOutput struct {
+	RouteTables []struct {
+		RouteTableID string `json:"RouteTableId"`
+		VpcID        string `json:"VpcId"`
+		Associations []struct {
+			Main      bool   `json:"Main"`
+			SubnetID  string `json:"SubnetId"`
+			GatewayID string `json:"GatewayId"`
+		} `json:"Associations"`
+		Routes []struct {
+			DestinationCidrBlock     string `json:"DestinationCidrBlock"`
+			DestinationIPv6CidrBlock string `json:"DestinationIpv6CidrBlock"`
+			DestinationPrefixListID  string `json:"DestinationPrefixListId"`
+			GatewayID                string `json:"GatewayId"`
+			NatGatewayID             string `json:"NatGatewayId"`
+			TransitGatewayID         string `json:"TransitGatewayId"`
+			VpcPeeringConnectionID   string `json:"VpcPeeringConnectionId"`
+			NetworkInterfaceID       string `json:"NetworkInterfaceId"`
+			InstanceID               string `json:"InstanceId"`
+			EgressOnlyIGWID          string `json:"EgressOnlyInternetGatewayId"`
+			State                    string `json:"State"`
+		} `json:"Routes"`
+		Tags []awsTag `json:"Tags"`
+	} `json:"RouteTables"`
+}
+
+func (s *resourceService) getRouteTables(ctx context.Context, region string) (types.ServiceResources, error) {
+	tables, msg, err := forRegions(ctx, s, region, s.getRouteTablesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:     "routetable",
+		RouteTables: tables,
+		Message:     msg,
+	}, nil
+}
+
+func (s *resourceService) getRouteTablesSingleRegion(ctx context.Context, region string) ([]types.RouteTable, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-route-tables"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeRouteTablesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-route-tables output: %w", err)
+	}
+
+	var tables []types.RouteTable
+	for _, rt := range resp.RouteTables {
+		table := types.RouteTable{
+			RouteTableID: rt.RouteTableID,
+			Name:         tagValue(rt.Tags, "Name"),
+			VpcID:        rt.VpcID,
+			Region:       region,
+		}
+
+		for _, a := range rt.Associations {
+			if a.Main {
+				table.Main = true
+			}
+			if a.SubnetID != "" {
+				table.AssociatedSubnets = append(table.AssociatedSubnets, a.SubnetID)
+			}
+		}
+
+		for _, r := range rt.Routes {
+			dest := firstNonEmpty(r.DestinationCidrBlock, r.DestinationIPv6CidrBlock, r.DestinationPrefixListID)
+			target := firstNonEmpty(r.GatewayID, r.NatGatewayID, r.TransitGatewayID,
+				r.VpcPeeringConnectionID, r.EgressOnlyIGWID, r.NetworkInterfaceID, r.InstanceID)
+			table.Routes = append(table.Routes, types.Route{
+				Destination: dest,
+				Target:      target,
+				State:       r.State,
+			})
+		}
+
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// firstNonEmpty returns the first argument that is not the empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// VPC peering connections
+
+type ec2PeeringVpcInfo struct {
+	VpcID     string `json:"VpcId"`
+	OwnerID   string `json:"OwnerId"`
+	Region    string `json:"Region"`
+	CidrBlock string `json:"CidrBlock"`
+}
+
+type ec2DescribeVpcPeeringConnectionsOutput struct {
+	VpcPeeringConnections []struct {
+		VpcPeeringConnectionID string            `json:"VpcPeeringConnectionId"`
+		RequesterVpcInfo       ec2PeeringVpcInfo `json:"RequesterVpcInfo"`
+		AccepterVpcInfo        ec2PeeringVpcInfo `json:"AccepterVpcInfo"`
+		Status                 struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Status"`
+		Tags []awsTag `json:"Tags"`
+	} `json:"VpcPeeringConnections"`
+}
+
+func (s *resourceService) getVPCPeeringConnections(ctx context.Context, region string) (types.ServiceResources, error) {
+	peerings, msg, err := forRegions(ctx, s, region, s.getVPCPeeringConnectionsSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:               "vpc-peering",
+		VPCPeeringConnections: dedupePeerings(peerings),
+		Message:               msg,
+	}, nil
+}
+
+func (s *resourceService) getVPCPeeringConnectionsSingleRegion(ctx context.Context, region string) ([]types.VPCPeeringConnection, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"ec2", "describe-vpc-peering-connections"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ec2DescribeVpcPeeringConnectionsOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-vpc-peering-connections output: %w", err)
+	}
+
+	var peerings []types.VPCPeeringConnection
+	for _, p := range resp.VpcPeeringConnections {
+		peerings = append(peerings, types.VPCPeeringConnection{
+			PeeringConnectionID: p.VpcPeeringConnectionID,
+			Name:                tagValue(p.Tags, "Name"),
+			Status:              p.Status.Code,
+			StatusMessage:       p.Status.Message,
+			RequesterVpcID:      p.RequesterVpcInfo.VpcID,
+			RequesterOwnerID:    p.RequesterVpcInfo.OwnerID,
+			RequesterCIDR:       p.RequesterVpcInfo.CidrBlock,
+			RequesterRegion:     p.RequesterVpcInfo.Region,
+			AccepterVpcID:       p.AccepterVpcInfo.VpcID,
+			AccepterOwnerID:     p.AccepterVpcInfo.OwnerID,
+			AccepterCIDR:        p.AccepterVpcInfo.CidrBlock,
+			AccepterRegion:      p.AccepterVpcInfo.Region,
+			Region:              region,
+		})
+	}
+	return peerings, nil
+}
+
+// dedupePeerings drops repeated connections. A cross-region peering is
+// reported by both the requester and accepter regions.
+func dedupePeerings(peerings []types.VPCPeeringConnection) []types.VPCPeeringConnection {
+	seen := make(map[string]bool, len(peerings))
+	var out []types.VPCPeeringConnection
+	for _, p := range peerings {
+		if seen[p.PeeringConnectionID] {
+			continue
+		}
+		seen[p.PeeringConnectionID] = true
+		out = append(out, p)
+	}
+	return out
+}
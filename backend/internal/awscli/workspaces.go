@@ -0,0 +1,69 @@
+package awscli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// WorkSpaces
+
+type workspacesDescribeWorkspacesOutput struct {
+	Workspaces []struct {
+		WorkspaceID         string `json:"WorkspaceId"`
+		DirectoryID         string `json:"DirectoryId"`
+		UserName            string `json:"UserName"`
+		ComputerName        string `json:"ComputerName"`
+		IPAddress           string `json:"IpAddress"`
+		State               string `json:"State"`
+		BundleID            string `json:"BundleId"`
+		WorkspaceProperties struct {
+			RunningMode     string `json:"RunningMode"`
+			ComputeTypeName string `json:"ComputeTypeName"`
+		} `json:"WorkspaceProperties"`
+	} `json:"Workspaces"`
+}
+
+func (s *resourceService) getWorkSpaces(ctx context.Context, region string) (types.ServiceResources, error) {
+	workspaces, msg, err := forRegions(ctx, s, region, s.getWorkSpacesSingleRegion)
+	if err != nil {
+		return types.ServiceResources{}, err
+	}
+
+	return types.ServiceResources{
+		Service:    "workspaces",
+		WorkSpaces: workspaces,
+		Message:    msg,
+	}, nil
+}
+
+func (s *resourceService) getWorkSpacesSingleRegion(ctx context.Context, region string) ([]types.WorkSpace, error) {
+	out, err := s.exec.RunJSON(ctx, regionArgs([]string{"workspaces", "describe-workspaces"}, region)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp workspacesDescribeWorkspacesOutput
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse describe-workspaces output: %w", err)
+	}
+
+	var workspaces []types.WorkSpace
+	for _, w := range resp.Workspaces {
+		workspaces = append(workspaces, types.WorkSpace{
+			WorkspaceID:  w.WorkspaceID,
+			UserName:     w.UserName,
+			ComputerName: w.ComputerName,
+			DirectoryID:  w.DirectoryID,
+			BundleID:     w.BundleID,
+			ComputeType:  w.WorkspaceProperties.ComputeTypeName,
+			RunningMode:  w.WorkspaceProperties.RunningMode,
+			State:        w.State,
+			IPAddress:    w.IPAddress,
+			Region:       region,
+		})
+	}
+	return workspaces, nil
+}
@@ -0,0 +1,263 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// FormatVersion is bumped whenever the archive layout changes incompatibly.
+const FormatVersion = 1
+
+const (
+	// maxArchiveSize caps the uncompressed size of an archive being
+	// restored, since a small gzip stream can expand enormously.
+	maxArchiveSize = 64 << 20
+	// maxArchiveEntries caps the number of files in an archive being
+	// restored; Write produces one per component plus the manifest.
+	maxArchiveEntries = 64
+)
+
+// ErrPassphraseRequired is returned when restoring an encrypted component
+// without a passphrase.
+var ErrPassphraseRequired = errors.New("backup contains encrypted data; a passphrase is required")
+
+// Component is a piece of server state that can be saved and restored.
+type Component struct {
+	// Name identifies the component inside the archive (e.g. "profiles").
+	Name string
+	// Sensitive components are encrypted when a passphrase is supplied.
+	Sensitive bool
+	Export    func() ([]byte, error)
+	Import    func(data []byte) error
+	// Validate, if set, checks data the way Import would, without changing
+	// anything. Restore validates every component before importing any.
+	Validate func(data []byte) error
+}
+
+type manifestEntry struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Encrypted bool   `json:"encrypted"`
+}
+
+type manifest struct {
+	Version    int             `json:"version"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Components []manifestEntry `json:"components"`
+}
+
+// Manager bundles registered components into a single tar.gz archive.
+type Manager struct {
+	mu         sync.RWMutex
+	components []Component
+}
+
+// NewManager creates an empty backup Manager.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Register adds a component to future backups and restores.
+func (m *Manager) Register(c Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, c)
+}
+
+// Components returns the names of all registered components.
+func (m *Manager) Components() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.components))
+	for _, c := range m.components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// Write exports every component into a tar.gz archive written to w. When
+// passphrase is non-empty, sensitive components are encrypted with it.
+func (m *Manager) Write(w io.Writer, passphrase string) error {
+	m.mu.RLock()
+	components := append([]Component(nil), m.components...)
+	m.mu.RUnlock()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now().UTC()
+
+	man := manifest{Version: FormatVersion, CreatedAt: now}
+
+	for _, c := range components {
+		data, err := c.Export()
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", c.Name, err)
+		}
+
+		entry := manifestEntry{Name: c.Name, File: c.Name + ".json"}
+		if c.Sensitive && passphrase != "" {
+			data, err = encrypt(data, passphrase)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", c.Name, err)
+			}
+			entry.File += ".enc"
+			entry.Encrypted = true
+		}
+
+		if err := writeTarFile(tw, entry.File, data, now); err != nil {
+			return err
+		}
+		man.Components = append(man.Components, entry)
+	}
+
+	manData, err := json.MarshalIndent(man, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, "manifest.json", manData, now); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Restore reads an archive produced by Write and imports every component it
+// contains that is registered on this Manager. It returns the names of the
+// components that were restored. Either all of them are, or, on error, none:
+// components imported before a failure are put back as they were.
+func (m *Manager) Restore(r io.Reader, passphrase string) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	var total int64
+	tr := tar.NewReader(gz)
+	for entries := 0; ; entries++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		if entries == maxArchiveEntries {
+			return nil, fmt.Errorf("invalid backup archive: more than %d files", maxArchiveEntries)
+		}
+		// Read at most one byte past what is left of the size limit, to
+		// tell an entry that fits from one that doesn't.
+		data, err := io.ReadAll(io.LimitReader(tr, maxArchiveSize-total+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		if total += int64(len(data)); total > maxArchiveSize {
+			return nil, fmt.Errorf("invalid backup archive: larger than %d MiB uncompressed", maxArchiveSize>>20)
+		}
+		files[hdr.Name] = data
+	}
+
+	manData, ok := files["manifest.json"]
+	if !ok {
+		return nil, fmt.Errorf("invalid backup archive: manifest.json missing")
+	}
+	var man manifest
+	if err := json.Unmarshal(manData, &man); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if man.Version > FormatVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than supported version %d", man.Version, FormatVersion)
+	}
+
+	m.mu.RLock()
+	byName := make(map[string]Component, len(m.components))
+	for _, c := range m.components {
+		byName[c.Name] = c
+	}
+	m.mu.RUnlock()
+
+	// Decode and check everything before importing anything so a bad
+	// passphrase, a corrupt entry or a refused command doesn't leave the
+	// server half-restored.
+	type pending struct {
+		component Component
+		data      []byte
+		// previous is the component's state before the restore.
+		previous []byte
+	}
+	var todo []pending
+	for _, entry := range man.Components {
+		c, ok := byName[entry.Name]
+		if !ok {
+			continue
+		}
+		data, ok := files[entry.File]
+		if !ok {
+			return nil, fmt.Errorf("invalid backup archive: %s missing", entry.File)
+		}
+		if entry.Encrypted {
+			if passphrase == "" {
+				return nil, ErrPassphraseRequired
+			}
+			data, err = decrypt(data, passphrase)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s: %w", entry.Name, err)
+			}
+		}
+		if c.Validate != nil {
+			if err := c.Validate(data); err != nil {
+				return nil, fmt.Errorf("failed to restore %s: %w", c.Name, err)
+			}
+		}
+		previous, err := c.Export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to save the current %s: %w", c.Name, err)
+		}
+		todo = append(todo, pending{component: c, data: data, previous: previous})
+	}
+
+	for i, p := range todo {
+		if err := p.component.Import(p.data); err != nil {
+			err = fmt.Errorf("failed to restore %s: %w", p.component.Name, err)
+			// Put back what was replaced, the failed component included in
+			// case it was partly imported.
+			for j := i; j >= 0; j-- {
+				if rbErr := todo[j].component.Import(todo[j].previous); rbErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to roll back %s: %w", todo[j].component.Name, rbErr))
+				}
+			}
+			return nil, err
+		}
+	}
+
+	restored := make([]string, len(todo))
+	for i, p := range todo {
+		restored[i] = p.component.Name
+	}
+	return restored, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
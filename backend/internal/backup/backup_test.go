@@ -0,0 +1,118 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRestoreRoundTrip(t *testing.T) {
+	var restored []byte
+	m := NewManager()
+	m.Register(Component{
+		Name:   "alerts",
+		Export: func() ([]byte, error) { return []byte(`[{"id":"a"}]`), nil },
+		Import: func(data []byte) error { restored = data; return nil },
+	})
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	names, err := m.Restore(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || string(restored) != `[{"id":"a"}]` {
+		t.Errorf("restored %v with %q", names, restored)
+	}
+}
+
+// stateComponent is a component keeping its state in a string.
+func stateComponent(name string, state *string, importErr error) Component {
+	return Component{
+		Name:   name,
+		Export: func() ([]byte, error) { return []byte(*state), nil },
+		Import: func(data []byte) error {
+			if importErr != nil && string(data) != "old" {
+				*state = "partial"
+				return importErr
+			}
+			*state = string(data)
+			return nil
+		},
+	}
+}
+
+func TestRestoreIsAllOrNothing(t *testing.T) {
+	profiles, commands := "new", "new"
+	src := NewManager()
+	src.Register(stateComponent("profiles", &profiles, nil))
+	src.Register(stateComponent("commands", &commands, nil))
+	var buf bytes.Buffer
+	if err := src.Write(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+
+	t.Run("refused by validation", func(t *testing.T) {
+		profiles, commands := "old", "old"
+		m := NewManager()
+		m.Register(stateComponent("profiles", &profiles, nil))
+		c := stateComponent("commands", &commands, nil)
+		c.Validate = func([]byte) error { return errors.New("command not allowed") }
+		m.Register(c)
+
+		if _, err := m.Restore(bytes.NewReader(archive), ""); err == nil {
+			t.Fatal("Restore() succeeded")
+		}
+		if profiles != "old" || commands != "old" {
+			t.Errorf("profiles = %q, commands = %q; want both untouched", profiles, commands)
+		}
+	})
+
+	t.Run("failed import", func(t *testing.T) {
+		profiles, commands := "old", "old"
+		m := NewManager()
+		m.Register(stateComponent("profiles", &profiles, nil))
+		m.Register(stateComponent("commands", &commands, errors.New("disk full")))
+
+		if _, err := m.Restore(bytes.NewReader(archive), ""); err == nil {
+			t.Fatal("Restore() succeeded")
+		}
+		if profiles != "old" || commands != "old" {
+			t.Errorf("profiles = %q, commands = %q; want both rolled back", profiles, commands)
+		}
+	})
+}
+
+func TestRestoreRejectsOversizedArchive(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	size := int64(maxArchiveSize + 1)
+	if err := tw.WriteHeader(&tar.Header{Name: "bomb", Mode: 0o600, Size: size}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.CopyN(tw, zeros{}, size); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+
+	_, err := NewManager().Restore(&buf, "")
+	if err == nil || !strings.Contains(err.Error(), "uncompressed") {
+		t.Fatalf("Restore() error = %v, want the size limit", err)
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
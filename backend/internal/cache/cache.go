@@ -7,26 +7,50 @@ import (
 
 type entry[V any] struct {
 	value     V
+	storedAt  time.Time
 	expiresAt time.Time
 }
 
-// Cache is a simple in-memory TTL cache.
-type Cache[V any] struct {
-	mu   sync.RWMutex
-	data map[string]entry[V]
-	ttl  time.Duration
+// Cache stores values for a TTL. Memory keeps them in the process and Redis
+// in a Redis server shared by several dashboards; either way lookups may
+// miss at any time, so callers fetch the data again.
+type Cache[V any] interface {
+	// Get returns the value for key if it exists and has not expired.
+	Get(key string) (V, bool)
+	// Stale returns the value for key even if it has expired, as long as
+	// it is younger than the cache's maximum age, along with when it was
+	// stored. It is meant as a fallback when the data can't be fetched
+	// again.
+	Stale(key string) (V, time.Time, bool)
+	Set(key string, value V)
+	// DeleteFunc removes every entry whose key matches.
+	DeleteFunc(match func(key string) bool)
+	// Clear removes all entries.
+	Clear()
+	// Shared reports whether other dashboards use the cache too, so keys
+	// must not depend on anything local, such as profile IDs.
+	Shared() bool
 }
 
-// New creates a new Cache with the given TTL.
-func New[V any](ttl time.Duration) *Cache[V] {
-	return &Cache[V]{
+// Memory is a simple in-memory TTL cache, optionally saved to disk with
+// Persist.
+type Memory[V any] struct {
+	mu      sync.RWMutex
+	data    map[string]entry[V]
+	ttl     time.Duration
+	persist *persistence
+}
+
+// New creates a new Memory cache with the given TTL.
+func New[V any](ttl time.Duration) *Memory[V] {
+	return &Memory[V]{
 		data: make(map[string]entry[V]),
 		ttl:  ttl,
 	}
 }
 
 // Get returns the cached value for the given key, if it exists and is not expired.
-func (c *Cache[V]) Get(key string) (V, bool) {
+func (c *Memory[V]) Get(key string) (V, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -43,20 +67,40 @@ func (c *Cache[V]) Get(key string) (V, bool) {
 }
 
 // Set stores a value in the cache.
-func (c *Cache[V]) Set(key string, value V) {
+func (c *Memory[V]) Set(key string, value V) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	now := time.Now()
 	c.data[key] = entry[V]{
 		value:     value,
-		expiresAt: time.Now().Add(c.ttl),
+		storedAt:  now,
+		expiresAt: now.Add(c.ttl),
 	}
+	c.changed()
+}
+
+// DeleteFunc removes every entry whose key matches.
+func (c *Memory[V]) DeleteFunc(match func(key string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.data {
+		if match(k) {
+			delete(c.data, k)
+		}
+	}
+	c.changed()
 }
 
 // Clear removes all entries from the cache.
-func (c *Cache[V]) Clear() {
+func (c *Memory[V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.data = make(map[string]entry[V])
+	c.changed()
 }
+
+// Shared implements Cache; a Memory cache belongs to one dashboard.
+func (c *Memory[V]) Shared() bool { return false }
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// saveDelay batches the changes made in quick succession, such as the
+// lookups of an all-regions scan, into a single write.
+const saveDelay = time.Second
+
+// persistence is where a Memory cache is saved and how long saved entries last.
+type persistence struct {
+	path   string
+	maxAge time.Duration
+
+	mu    sync.Mutex
+	timer *time.Timer
+	// saving orders the writes of overlapping saves.
+	saving sync.Mutex
+}
+
+// snapshotEntry is a cache entry as saved on disk or in Redis.
+type snapshotEntry[V any] struct {
+	Value    V         `json:"value"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// Persist loads the entries saved at path, if any, and from then on saves
+// the cache there shortly after every change, so cached data survives a
+// restart. Entries older than maxAge are dropped; younger ones that have
+// expired are still served by Stale. Call it before the cache is used. If
+// the saved file can't be read the error is returned, but the cache is
+// still saved there, replacing it.
+func (c *Memory[V]) Persist(path string, maxAge time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.persist = &persistence{path: path, maxAge: maxAge}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var saved map[string]snapshotEntry[V]
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid cache snapshot %s: %w", path, err)
+	}
+	for k, e := range saved {
+		if time.Since(e.StoredAt) >= maxAge {
+			continue
+		}
+		c.data[k] = entry[V]{
+			value:     e.Value,
+			storedAt:  e.StoredAt,
+			expiresAt: e.StoredAt.Add(c.ttl),
+		}
+	}
+	return nil
+}
+
+// Stale implements Cache, serving entries younger than the Persist maxAge.
+// It always misses for a cache that isn't persisted.
+func (c *Memory[V]) Stale(key string) (V, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var zero V
+
+	e, ok := c.data[key]
+	if !ok || c.persist == nil || time.Since(e.storedAt) >= c.persist.maxAge {
+		return zero, time.Time{}, false
+	}
+	return e.value, e.storedAt, true
+}
+
+// changed schedules a save of a persisted cache.
+func (c *Memory[V]) changed() {
+	p := c.persist
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.timer == nil {
+		p.timer = time.AfterFunc(saveDelay, func() {
+			p.mu.Lock()
+			p.timer = nil
+			p.mu.Unlock()
+			if err := c.save(); err != nil {
+				log.Printf("cache: failed to save %s: %v", p.path, err)
+			}
+		})
+	}
+}
+
+// save writes the entries younger than maxAge to the persist path.
+func (c *Memory[V]) save() error {
+	p := c.persist
+	p.saving.Lock()
+	defer p.saving.Unlock()
+
+	c.mu.RLock()
+	saved := make(map[string]snapshotEntry[V], len(c.data))
+	for k, e := range c.data {
+		if time.Since(e.storedAt) < p.maxAge {
+			saved[k] = snapshotEntry[V]{Value: e.value, StoredAt: e.storedAt}
+		}
+	}
+	data, err := json.Marshal(saved)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), ".cache-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
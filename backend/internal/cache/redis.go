@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redis is a TTL cache kept in a Redis server, so several dashboards, such
+// as replicas behind a load balancer or a laptop and a team server, share
+// what any of them fetched. Values are stored as JSON under the namespace
+// prefix. Redis being unavailable makes lookups miss and stores fail; the
+// failures are logged, not returned.
+type Redis[V any] struct {
+	client    *RedisClient
+	namespace string
+	ttl       time.Duration
+	maxAge    time.Duration
+}
+
+// NewRedis returns a cache storing its entries in Redis under keys starting
+// with namespace, e.g. "aws-local-dashboard:costs:". Entries are fresh for
+// ttl and served by Stale until maxAge, after which Redis expires them.
+func NewRedis[V any](client *RedisClient, namespace string, ttl, maxAge time.Duration) *Redis[V] {
+	return &Redis[V]{client: client, namespace: namespace, ttl: ttl, maxAge: maxAge}
+}
+
+// Get implements Cache.
+func (c *Redis[V]) Get(key string) (V, bool) {
+	var zero V
+	e, ok := c.load(key)
+	if !ok || time.Since(e.StoredAt) >= c.ttl {
+		return zero, false
+	}
+	return e.Value, true
+}
+
+// Stale implements Cache.
+func (c *Redis[V]) Stale(key string) (V, time.Time, bool) {
+	var zero V
+	e, ok := c.load(key)
+	if !ok || time.Since(e.StoredAt) >= c.maxAge {
+		return zero, time.Time{}, false
+	}
+	return e.Value, e.StoredAt, true
+}
+
+func (c *Redis[V]) load(key string) (snapshotEntry[V], bool) {
+	var e snapshotEntry[V]
+	reply, err := c.client.Do("GET", c.namespace+key)
+	if err != nil {
+		log.Printf("cache: redis GET failed: %v", err)
+		return e, false
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return e, false
+	}
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		log.Printf("cache: ignoring invalid redis entry %q: %v", c.namespace+key, err)
+		return e, false
+	}
+	return e, true
+}
+
+// Set implements Cache. The entry is kept for the longer of the TTL and the
+// maximum age.
+func (c *Redis[V]) Set(key string, value V) {
+	keep := max(c.ttl, c.maxAge)
+	if keep <= 0 {
+		return
+	}
+	data, err := json.Marshal(snapshotEntry[V]{Value: value, StoredAt: time.Now()})
+	if err != nil {
+		log.Printf("cache: failed to encode %q: %v", key, err)
+		return
+	}
+	// PX takes milliseconds; round up so a sub-millisecond TTL isn't 0.
+	ms := (keep + time.Millisecond - 1) / time.Millisecond
+	if _, err := c.client.Do("SET", c.namespace+key, string(data), "PX", strconv.FormatInt(int64(ms), 10)); err != nil {
+		log.Printf("cache: redis SET failed: %v", err)
+	}
+}
+
+// DeleteFunc implements Cache, scanning every key in the namespace.
+func (c *Redis[V]) DeleteFunc(match func(key string) bool) {
+	pattern := globEscaper.Replace(c.namespace) + "*"
+	cursor := "0"
+	for {
+		reply, err := c.client.Do("SCAN", cursor, "MATCH", pattern, "COUNT", "500")
+		if err != nil {
+			log.Printf("cache: redis SCAN failed: %v", err)
+			return
+		}
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			log.Printf("cache: unexpected redis SCAN reply %v", reply)
+			return
+		}
+		keys, _ := page[1].([]any)
+		del := []string{"DEL"}
+		for _, k := range keys {
+			if k, ok := k.(string); ok && match(strings.TrimPrefix(k, c.namespace)) {
+				del = append(del, k)
+			}
+		}
+		if len(del) > 1 {
+			if _, err := c.client.Do(del...); err != nil {
+				log.Printf("cache: redis DEL failed: %v", err)
+				return
+			}
+		}
+		if cursor, _ = page[0].(string); cursor == "0" || cursor == "" {
+			return
+		}
+	}
+}
+
+// Clear implements Cache, removing the namespace's entries for every
+// dashboard sharing it.
+func (c *Redis[V]) Clear() {
+	c.DeleteFunc(func(string) bool { return true })
+}
+
+// Shared implements Cache.
+func (c *Redis[V]) Shared() bool { return true }
+
+// globEscaper escapes the characters special to Redis MATCH patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// Group deduplicates concurrent calls that share a key: while one call for a
+// key is in flight, later callers wait for it and receive its result instead
+// of starting their own.
+type Group[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[V]
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which
+// case it waits for that call. shared reports whether the result came from
+// another caller's call.
+func (g *Group[V]) Do(key string, fn func() (V, error)) (v V, err error, shared bool) {
+	c, started := g.start(key)
+	if !started {
+		<-c.done
+		return c.val, c.err, true
+	}
+	g.run(key, c, fn)
+	return c.val, c.err, false
+}
+
+// DoContext is like Do, but fn runs in its own goroutine and each caller,
+// the first one included, stops waiting when its own ctx is done, getting
+// ctx.Err(). The call carries on for the callers still waiting, so fn must
+// not depend on any one caller's context.
+func (g *Group[V]) DoContext(ctx context.Context, key string, fn func() (V, error)) (v V, err error, shared bool) {
+	c, started := g.start(key)
+	if started {
+		go g.run(key, c, fn)
+	}
+	select {
+	case <-c.done:
+		return c.val, c.err, !started
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err(), !started
+	}
+}
+
+// start returns the call in flight for key, or registers a new one, in
+// which case started is true and the caller must run it.
+func (g *Group[V]) start(key string) (c *call[V], started bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		return c, false
+	}
+	c = &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	return c, true
+}
+
+// run runs fn as the call c for key and releases its waiters.
+func (g *Group[V]) run(key string, c *call[V], fn func() (V, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+}
@@ -2,11 +2,12 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/local/aws-local-dashboard/internal/awscli"
 )
@@ -20,30 +21,57 @@ type Command struct {
 	Service        string   `json:"service"`
 	Args           []string `json:"args"`
 	SupportsRegion bool     `json:"supportsRegion"`
+	// Category groups commands in listings, e.g. "Networking"; commands
+	// without one are listed under DefaultCategory.
+	Category string `json:"category,omitempty"`
+	// Tags are lowercase keywords for filtering, e.g. "vpc" or "security".
+	Tags []string `json:"tags,omitempty"`
+	// Steps make the command a pipeline run instead of Args, each step
+	// able to use values extracted from earlier ones.
+	Steps []Step `json:"steps,omitempty"`
 }
 
+// DefaultCategory is the category of commands configured without one.
+const DefaultCategory = "Other"
+
 // PublicCommand is what we send to the frontend (no raw args).
 type PublicCommand struct {
-	ID             string `json:"id"`
-	Label          string `json:"label"`
-	Description    string `json:"description"`
-	Service        string `json:"service"`
-	SupportsRegion bool   `json:"supportsRegion"`
+	ID             string   `json:"id"`
+	Label          string   `json:"label"`
+	Description    string   `json:"description"`
+	Service        string   `json:"service"`
+	SupportsRegion bool     `json:"supportsRegion"`
+	Category       string   `json:"category"`
+	Tags           []string `json:"tags"`
+	// Steps is the number of steps of a pipeline command.
+	Steps int `json:"steps,omitempty"`
+	// Favorite is set by the server from the pinned commands.
+	Favorite bool `json:"favorite"`
 }
 
 type Manager struct {
-	exec     awscli.Executor
+	exec       awscli.Executor
+	configPath string
+
+	mu       sync.RWMutex
 	commands map[string]Command
 }
 
-// LoadManager loads commands from a JSON config file (if present). If the file
-// is missing, we fall back to the baked-in default set in command-config.json.
+// LoadManager loads commands from a JSON or YAML config file (if present),
+// by its .json, .yaml or .yml extension. Without a path we use
+// ./command-config.json, or command-config.yaml/.yml if that is the one
+// present. A config that doesn't fit the schema is refused as a whole, with
+// the line and field of every problem.
 func LoadManager(exec awscli.Executor, configPath string) (*Manager, error) {
 	if configPath == "" {
-		configPath = filepath.Join(".", "command-config.json")
+		configPath = defaultConfigPath()
 	}
 
-	commands := map[string]Command{}
+	m := &Manager{
+		exec:       exec,
+		configPath: configPath,
+		commands:   map[string]Command{},
+	}
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -51,27 +79,155 @@ func LoadManager(exec awscli.Executor, configPath string) (*Manager, error) {
 			return nil, fmt.Errorf("failed to read command config: %w", err)
 		}
 		// If the file doesn't exist we just start with an empty set.
+		return m, nil
+	}
+
+	commands, err := parseConfig(data, isYAMLPath(configPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	m.commands = commands
+
+	return m, nil
+}
+
+// defaultConfigPath returns the first of the default config file names that
+// exists, or command-config.json if none does.
+func defaultConfigPath() string {
+	for _, name := range []string{"command-config.json", "command-config.yaml", "command-config.yml"} {
+		path := filepath.Join(".", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(".", "command-config.json")
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// parseConfig decodes a JSON or YAML command config into a map keyed by
+// command id.
+func parseConfig(data []byte, yaml bool) (map[string]Command, error) {
+	var root *docNode
+	var err error
+	if yaml {
+		root, err = parseYAMLDocument(data)
 	} else {
-		var list []Command
-		if err := json.Unmarshal(data, &list); err != nil {
-			return nil, fmt.Errorf("failed to parse command config: %w", err)
+		root, err = parseJSONDocument(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse command config: %w", err)
+	}
+	list, err := decodeCommands(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command config: %w", err)
+	}
+
+	commands := map[string]Command{}
+	for _, c := range list {
+		c.Category = strings.TrimSpace(c.Category)
+		if c.Category == "" {
+			c.Category = DefaultCategory
+		}
+		c.Tags = normalizeTags(c.Tags)
+		commands[c.ID] = c
+	}
+	return commands, nil
+}
+
+// normalizeTags lowercases and trims tags, dropping empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// RawConfig returns the contents of the command config file, for backups.
+func (m *Manager) RawConfig() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []byte("[]"), nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReplaceConfig validates data as a command config in the config file's
+// format, writes it to the config file and reloads the command set. Unless
+// check is nil, the args of every command and pipeline step must pass it,
+// e.g. the raw command safety filter for a config from an untrusted source.
+func (m *Manager) ReplaceConfig(data []byte, check func(args []string) error) error {
+	commands, err := m.checkConfig(data, check)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.WriteFile(m.configPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write command config: %w", err)
+	}
+	m.commands = commands
+	return nil
+}
+
+// CheckConfig reports the error ReplaceConfig would return for data and
+// check, without replacing anything.
+func (m *Manager) CheckConfig(data []byte, check func(args []string) error) error {
+	_, err := m.checkConfig(data, check)
+	return err
+}
+
+// checkConfig parses data as a command config and checks its commands'
+// args with check, if not nil.
+func (m *Manager) checkConfig(data []byte, check func(args []string) error) (map[string]Command, error) {
+	commands, err := parseConfig(data, isYAMLPath(m.configPath))
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return commands, nil
+	}
+	for _, c := range commands {
+		argLists := [][]string{c.Args}
+		for _, step := range c.Steps {
+			argLists = append(argLists, step.Args)
 		}
-		for _, c := range list {
-			if c.ID == "" || len(c.Args) == 0 {
+		for _, args := range argLists {
+			if len(args) == 0 {
 				continue
 			}
-			commands[c.ID] = c
+			if err := check(args); err != nil {
+				return nil, fmt.Errorf("command %q is not allowed: %w", c.ID, err)
+			}
 		}
 	}
-
-	return &Manager{
-		exec:     exec,
-		commands: commands,
-	}, nil
+	return commands, nil
 }
 
-// List returns public metadata for all configured commands.
+// List returns public metadata for all configured commands, ordered by
+// category and then label.
 func (m *Manager) List() []PublicCommand {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var out []PublicCommand
 	for _, c := range m.commands {
 		out = append(out, PublicCommand{
@@ -80,43 +236,136 @@ func (m *Manager) List() []PublicCommand {
 			Description:    c.Description,
 			Service:        c.Service,
 			SupportsRegion: c.SupportsRegion,
+			Category:       c.Category,
+			Tags:           append([]string{}, c.Tags...),
+			Steps:          len(c.Steps),
 		})
 	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Category != out[j].Category {
+			return out[i].Category < out[j].Category
+		}
+		return out[i].Label < out[j].Label
+	})
 	return out
 }
 
-// Execute runs a configured command by id and returns its raw JSON output and the
-// concrete arguments used.
-func (m *Manager) Execute(ctx context.Context, id string, region string) ([]byte, []string, error) {
+// CommandGroup is the commands of one category.
+type CommandGroup struct {
+	Category string          `json:"category"`
+	Commands []PublicCommand `json:"commands"`
+}
+
+// GroupByCategory groups list by category, in category order, keeping the
+// order of commands within each category.
+func GroupByCategory(list []PublicCommand) []CommandGroup {
+	var groups []CommandGroup
+	index := map[string]int{}
+	for _, c := range list {
+		i, ok := index[c.Category]
+		if !ok {
+			i = len(groups)
+			index[c.Category] = i
+			groups = append(groups, CommandGroup{Category: c.Category})
+		}
+		groups[i].Commands = append(groups[i].Commands, c)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Category < groups[j].Category })
+	return groups
+}
+
+// Args returns the aws CLI arguments Execute would use for a configured
+// command, so callers can vet them first. For a pipeline these are the first
+// step's; see StepArgs.
+func (m *Manager) Args(id string, region string) ([]string, error) {
+	steps, err := m.StepArgs(id, region)
+	if err != nil {
+		return nil, err
+	}
+	return steps[0], nil
+}
+
+// StepArgs returns the arguments of each step of a configured command, a
+// single one unless it is a pipeline. Placeholders are left unresolved.
+func (m *Manager) StepArgs(id string, region string) ([][]string, error) {
+	m.mu.RLock()
 	cmd, ok := m.commands[id]
+	m.mu.RUnlock()
 	if !ok {
-		return nil, nil, fmt.Errorf("unknown command id %q", id)
+		return nil, fmt.Errorf("unknown command id %q", id)
+	}
+
+	if len(cmd.Steps) == 0 {
+		return [][]string{withRegion(cmd, append([]string{}, cmd.Args...), region)}, nil
+	}
+	steps := make([][]string, 0, len(cmd.Steps))
+	for _, step := range cmd.Steps {
+		steps = append(steps, withRegion(cmd, append([]string{}, step.Args...), region))
+	}
+	return steps, nil
+}
+
+// Execute runs a configured command by id and returns its raw output in the
+// given awscli format ("" for JSON) and the concrete arguments used.
+// Pipelines return {"steps": [...]} and only support JSON.
+func (m *Manager) Execute(ctx context.Context, id string, region string, format string) ([]byte, []string, error) {
+	m.mu.RLock()
+	cmd, ok := m.commands[id]
+	m.mu.RUnlock()
+	if ok && len(cmd.Steps) > 0 {
+		return m.runPipeline(ctx, cmd, region, format)
 	}
 
-	args := append([]string{}, cmd.Args...)
-	if cmd.SupportsRegion && strings.TrimSpace(region) != "" {
-		args = append(args, "--region", region)
+	args, err := m.Args(id, region)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	out, err := m.exec.RunJSON(ctx, args...)
+	out, err := awscli.RunFormat(ctx, m.exec, format, args...)
 	if err != nil {
 		return nil, nil, err
 	}
 	return out, args, nil
 }
 
-// ExecuteRaw runs an arbitrary aws CLI command (still using --output json under
-// the hood). The caller is responsible for validating that the args are safe
-// (read-only).
-func (m *Manager) ExecuteRaw(ctx context.Context, args []string) ([]byte, []string, error) {
+// ExecuteRaw runs an arbitrary aws CLI command in the given awscli format (""
+// for JSON); any --output the args carry is dropped. JSON output that
+// carries a next-page token is followed for up to maxPages pages and merged.
+// The caller is responsible for validating that the args are safe
+// (read-only), e.g. with profiles.Manager.CheckReadOnly.
+func (m *Manager) ExecuteRaw(ctx context.Context, args []string, format string, maxPages int) ([]byte, []string, error) {
 	if len(args) == 0 {
 		return nil, nil, fmt.Errorf("no arguments provided")
 	}
-	out, err := m.exec.RunJSON(ctx, args...)
+	args = awscli.WithoutOutput(args)
+	out, err := m.runRaw(ctx, args, format, maxPages)
 	if err != nil {
 		return nil, nil, err
 	}
 	return out, args, nil
 }
 
+// Plan describes how Execute would run a configured command, without
+// running it. Pipelines can't be planned, since later steps depend on the
+// output of earlier ones.
+func (m *Manager) Plan(ctx context.Context, id string, region string, format string) (awscli.Invocation, error) {
+	steps, err := m.StepArgs(id, region)
+	if err != nil {
+		return awscli.Invocation{}, err
+	}
+	if len(steps) > 1 {
+		return awscli.Invocation{}, fmt.Errorf("%w: %s is a pipeline", awscli.ErrDryRunUnsupported, id)
+	}
+	args := steps[0]
+	return awscli.PlanFormat(ctx, m.exec, format, args...)
+}
+
+// PlanRaw describes how ExecuteRaw would run args, without running them.
+func (m *Manager) PlanRaw(ctx context.Context, args []string, format string) (awscli.Invocation, error) {
+	if len(args) == 0 {
+		return awscli.Invocation{}, fmt.Errorf("no arguments provided")
+	}
+	return awscli.PlanFormat(ctx, m.exec, format, awscli.WithoutOutput(args)...)
+}
+
 
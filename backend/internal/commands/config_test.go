@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplaceConfigChecksArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "command-config.json")
+	m, err := LoadManager(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnly := func(args []string) error {
+		if !strings.HasPrefix(args[1], "describe-") {
+			return errors.New("not read-only")
+		}
+		return nil
+	}
+
+	bad := `[
+  {"id": "ok", "args": ["ec2", "describe-instances"]},
+  {"id": "nuke", "steps": [{"args": ["ec2", "terminate-instances", "--instance-ids", "i-1"]}]}
+]`
+	if err := m.ReplaceConfig([]byte(bad), readOnly); err == nil || !strings.Contains(err.Error(), `"nuke"`) {
+		t.Fatalf("ReplaceConfig() error = %v, want command \"nuke\" refused", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("refused config was written: %v", err)
+	}
+	if len(m.List()) != 0 {
+		t.Errorf("refused config was loaded: %v", m.List())
+	}
+
+	good := `[{"id": "ok", "args": ["ec2", "describe-instances"]}]`
+	if err := m.ReplaceConfig([]byte(good), readOnly); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.List()) != 1 {
+		t.Errorf("List() = %v, want the restored command", m.List())
+	}
+}
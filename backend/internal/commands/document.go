@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	listNode
+	mapNode
+)
+
+// docNode is a value of a command config file, JSON or YAML, with the line
+// it starts on so validation errors can point at it.
+type docNode struct {
+	kind nodeKind
+	line int
+	// value is a scalar's string, float64, bool or nil.
+	value any
+	// plain is set for unquoted YAML scalars, text being what was written,
+	// so that e.g. "- 5" can be taken as an arg.
+	plain bool
+	text  string
+	items []*docNode
+	keys  []docKey
+}
+
+type docKey struct {
+	name  string
+	line  int
+	value *docNode
+}
+
+// configError is a problem with a command config file at a given line. path
+// is the offending field, e.g. "[2].args", if known.
+type configError struct {
+	line int
+	path string
+	msg  string
+}
+
+func (e *configError) Error() string {
+	if e.path == "" {
+		return fmt.Sprintf("line %d: %s", e.line, e.msg)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.line, e.path, e.msg)
+}
+
+// parseJSONDocument decodes data into a docNode tree.
+func parseJSONDocument(data []byte) (*docNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	root, err := decodeJSONNode(dec, data)
+	if err != nil {
+		return nil, jsonError(err, data)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &configError{line: lineAt(data, dec.InputOffset()), msg: "unexpected data after the command list"}
+	}
+	return root, nil
+}
+
+func decodeJSONNode(dec *json.Decoder, data []byte) (*docNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &docNode{line: lineAt(data, dec.InputOffset())}
+	switch tok {
+	case json.Delim('['):
+		n.kind = listNode
+		for dec.More() {
+			item, err := decodeJSONNode(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		_, err = dec.Token()
+		return n, err
+	case json.Delim('{'):
+		n.kind = mapNode
+		for dec.More() {
+			name, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			line := lineAt(data, dec.InputOffset())
+			value, err := decodeJSONNode(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			n.keys = append(n.keys, docKey{name: name.(string), line: line, value: value})
+		}
+		_, err = dec.Token()
+		return n, err
+	}
+	n.value = tok
+	return n, nil
+}
+
+// jsonError gives a JSON decoding error the line it happened on.
+func jsonError(err error, data []byte) error {
+	var syntax *json.SyntaxError
+	switch {
+	case errors.As(err, &syntax):
+		return &configError{line: lineAt(data, syntax.Offset), msg: syntax.Error()}
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &configError{line: lineAt(data, int64(len(data))), msg: "unexpected end of file"}
+	}
+	return err
+}
+
+// lineAt returns the 1-based line of the byte at offset in data.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return 1 + bytes.Count(data[:offset], []byte("\n"))
+}
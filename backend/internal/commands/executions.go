@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxExecutions is how many recent command results are kept.
+	maxExecutions = 50
+	// maxExecutionOutput caps the output of a kept result; larger outputs
+	// aren't kept.
+	maxExecutionOutput = 10 << 20
+)
+
+// Execution describes a command that ran, whose output can be downloaded
+// again later.
+type Execution struct {
+	ID      string `json:"id"`
+	Command string `json:"command"`
+	// CommandID is the configured command's id; empty for raw commands.
+	CommandID  string    `json:"commandId,omitempty"`
+	Profile    string    `json:"profile"`
+	Region     string    `json:"region,omitempty"`
+	Format     string    `json:"format"`
+	ExecutedAt time.Time `json:"executedAt"`
+	Size       int       `json:"size"`
+
+	Args   []string `json:"-"`
+	output []byte
+}
+
+// Executions keeps the results of recent command executions in memory,
+// newest first. They are shared by everyone using the dashboard and lost on
+// restart.
+type Executions struct {
+	mu   sync.Mutex
+	list []Execution
+}
+
+// NewExecutions returns an empty Executions.
+func NewExecutions() *Executions {
+	return &Executions{}
+}
+
+// Add records e with output, filling in its id, command and size, and
+// drops the oldest result once maxExecutions are kept. It reports false,
+// keeping nothing, when output is over maxExecutionOutput.
+func (x *Executions) Add(e Execution, output []byte) (Execution, bool) {
+	if len(output) > maxExecutionOutput {
+		return Execution{}, false
+	}
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	e.ID = hex.EncodeToString(buf[:])
+	e.Command = "aws " + strings.Join(e.Args, " ")
+	e.Size = len(output)
+	e.output = output
+	if e.ExecutedAt.IsZero() {
+		e.ExecutedAt = time.Now().UTC()
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.list = append([]Execution{e}, x.list...)
+	if len(x.list) > maxExecutions {
+		x.list = x.list[:maxExecutions]
+	}
+	return e, true
+}
+
+// List returns the kept executions, newest first.
+func (x *Executions) List() []Execution {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return append([]Execution{}, x.list...)
+}
+
+// Get returns the execution with the given id and its output.
+func (x *Executions) Get(id string) (Execution, []byte, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, e := range x.list {
+		if e.ID == id {
+			return e, e.output, true
+		}
+	}
+	return Execution{}, nil, false
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SavedCommand is an ad-hoc aws CLI command kept for one-click reuse. It is
+// run like any raw command, so it is vetted again every time.
+type SavedCommand struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	Args      string    `json:"args"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type favoritesState struct {
+	NextID    int64          `json:"nextId"`
+	Favorites []string       `json:"favorites"`
+	Saved     []SavedCommand `json:"saved"`
+}
+
+// Favorites holds the pinned configured commands and saved raw commands.
+// They are shared by everyone using the dashboard.
+type Favorites struct {
+	mu        sync.Mutex
+	storePath string
+	nextID    int64
+	favorites []string
+	saved     []SavedCommand
+}
+
+// LoadFavorites loads favorites from storePath, if it exists. An empty
+// storePath keeps them in memory only.
+func LoadFavorites(storePath string) (*Favorites, error) {
+	f := &Favorites{storePath: storePath, nextID: 1}
+	if storePath == "" {
+		return f, nil
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, err
+	}
+
+	var state favoritesState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid favorites store %s: %w", storePath, err)
+	}
+	f.applyStateLocked(state)
+	return f, nil
+}
+
+// IsFavorite reports whether the configured command id is pinned.
+func (f *Favorites) IsFavorite(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, fav := range f.favorites {
+		if fav == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFavorite pins or unpins the configured command id.
+func (f *Favorites) SetFavorite(id string, favorite bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	kept := f.favorites[:0]
+	for _, fav := range f.favorites {
+		if fav != id {
+			kept = append(kept, fav)
+		}
+	}
+	f.favorites = kept
+	if favorite {
+		f.favorites = append(f.favorites, id)
+	}
+	f.saveLocked()
+}
+
+// Saved returns the saved commands, oldest first.
+func (f *Favorites) Saved() []SavedCommand {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]SavedCommand{}, f.saved...)
+}
+
+// SavedCommand returns the saved command with the given id.
+func (f *Favorites) SavedCommand(id string) (SavedCommand, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.saved {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return SavedCommand{}, false
+}
+
+// Save validates c, assigns it an ID and persists it. The label defaults to
+// the command itself.
+func (f *Favorites) Save(c SavedCommand) (SavedCommand, error) {
+	c.Args = strings.Join(strings.Fields(c.Args), " ")
+	c.Label = strings.TrimSpace(c.Label)
+	if c.Args == "" {
+		return SavedCommand{}, fmt.Errorf("args must not be empty")
+	}
+	if c.Label == "" {
+		c.Label = "aws " + c.Args
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c.ID = strconv.FormatInt(f.nextID, 10)
+	f.nextID++
+	c.CreatedAt = time.Now().UTC()
+	f.saved = append(f.saved, c)
+	f.saveLocked()
+	return c, nil
+}
+
+// DeleteSaved removes a saved command.
+func (f *Favorites) DeleteSaved(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, c := range f.saved {
+		if c.ID == id {
+			f.saved = append(f.saved[:i], f.saved[i+1:]...)
+			f.saveLocked()
+			return nil
+		}
+	}
+	return fmt.Errorf("saved command %q not found", id)
+}
+
+// ExportState returns favorites and saved commands as JSON, for backups.
+func (f *Favorites) ExportState() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return json.MarshalIndent(f.stateLocked(), "", "  ")
+}
+
+// ImportState replaces everything with data (as produced by ExportState) and
+// persists the result.
+func (f *Favorites) ImportState(data []byte) error {
+	var state favoritesState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid favorites state: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.applyStateLocked(state)
+	f.saveLocked()
+	return nil
+}
+
+func (f *Favorites) stateLocked() favoritesState {
+	return favoritesState{NextID: f.nextID, Favorites: f.favorites, Saved: f.saved}
+}
+
+// applyStateLocked replaces the in-memory state. Caller must hold f.mu.
+func (f *Favorites) applyStateLocked(state favoritesState) {
+	if state.NextID > 0 {
+		f.nextID = state.NextID
+	}
+	f.favorites = state.Favorites
+	f.saved = state.Saved
+}
+
+// saveLocked persists the current state. Caller must hold f.mu.
+func (f *Favorites) saveLocked() {
+	if f.storePath == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(f.stateLocked(), "", "  ")
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(f.storePath, data, 0o600)
+}
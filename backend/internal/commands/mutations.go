@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// confirmationTTL is how long a mutation waits for its confirmation.
+const confirmationTTL = 5 * time.Minute
+
+// mutation is a curated mutating operation: the options it accepts and how
+// to describe what it does.
+type mutation struct {
+	// values are the options taking a value, checked by their pattern;
+	// multiple ones may take several. flags take none.
+	values   map[string]func(string) bool
+	multiple map[string]bool
+	flags    map[string]bool
+	// required lists options of which exactly one must be set.
+	required []string
+	summary  func(opts map[string][]string) string
+}
+
+var (
+	instanceIDPattern   = regexp.MustCompile(`^i-[0-9a-f]{8,17}$`)
+	allocationIDPattern = regexp.MustCompile(`^eipalloc-[0-9a-f]{8,17}$`)
+)
+
+func isIP(v string) bool { return net.ParseIP(v) != nil }
+
+// mutations are the mutating commands that can be enabled, keyed by
+// "service operation".
+var mutations = map[string]mutation{
+	"ec2 stop-instances": {
+		values:   map[string]func(string) bool{"--instance-ids": instanceIDPattern.MatchString},
+		multiple: map[string]bool{"--instance-ids": true},
+		flags:    map[string]bool{"--force": true, "--hibernate": true},
+		required: []string{"--instance-ids"},
+		summary: func(opts map[string][]string) string {
+			ids := opts["--instance-ids"]
+			s := fmt.Sprintf("Stop %d EC2 %s (%s).", len(ids), plural(len(ids), "instance", "instances"), strings.Join(ids, ", "))
+			if _, ok := opts["--hibernate"]; ok {
+				s += " They are hibernated, keeping their memory on the root volume."
+			}
+			if _, ok := opts["--force"]; ok {
+				s += " They are forced to stop without flushing file system caches."
+			}
+			return s + " Whatever runs on them goes down and instance store data is lost."
+		},
+	},
+	"ec2 release-address": {
+		values: map[string]func(string) bool{
+			"--allocation-id": allocationIDPattern.MatchString,
+			"--public-ip":     isIP,
+		},
+		required: []string{"--allocation-id", "--public-ip"},
+		summary: func(opts map[string][]string) string {
+			id := opts["--allocation-id"]
+			if len(id) == 0 {
+				id = opts["--public-ip"]
+			}
+			return fmt.Sprintf("Release Elastic IP address %s. The address goes back to AWS and may not be recoverable.", id[0])
+		},
+	},
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// MutatingCommands lists the mutating commands that can be enabled, as
+// "service operation".
+func MutatingCommands() []string {
+	list := make([]string, 0, len(mutations))
+	for name := range mutations {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// DescribeMutation checks that args are one of MutatingCommands with only
+// the options it understands, plus --region, and returns a human-readable
+// summary of its impact.
+func DescribeMutation(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("expected <service> <operation> [options]")
+	}
+	name := args[0] + " " + args[1]
+	m, ok := mutations[name]
+	if !ok {
+		return "", fmt.Errorf("%q is not an enabled mutating command; supported: %s", name, strings.Join(MutatingCommands(), ", "))
+	}
+
+	opts := map[string][]string{}
+	var current string
+	for _, arg := range args[2:] {
+		if !strings.HasPrefix(arg, "--") {
+			if current == "" {
+				return "", fmt.Errorf("unexpected argument %q", arg)
+			}
+			opts[current] = append(opts[current], arg)
+			continue
+		}
+		opt, value, hasValue := strings.Cut(arg, "=")
+		if _, dup := opts[opt]; dup {
+			return "", fmt.Errorf("%s given more than once", opt)
+		}
+		switch {
+		case opt == "--region" || m.values[opt] != nil:
+			opts[opt] = nil
+			current = opt
+			if hasValue {
+				opts[opt] = []string{value}
+			}
+		case m.flags[opt] && !hasValue:
+			opts[opt] = nil
+			current = ""
+		default:
+			return "", fmt.Errorf("option %s is not supported for %s", opt, name)
+		}
+	}
+
+	for opt, values := range opts {
+		if m.flags[opt] {
+			continue
+		}
+		switch {
+		case len(values) == 0:
+			return "", fmt.Errorf("%s needs a value", opt)
+		case len(values) > 1 && !m.multiple[opt]:
+			return "", fmt.Errorf("%s takes one value", opt)
+		}
+		if valid := m.values[opt]; valid != nil {
+			for _, v := range values {
+				if !valid(v) {
+					return "", fmt.Errorf("invalid %s value %q", opt, v)
+				}
+			}
+		}
+	}
+	set := 0
+	for _, opt := range m.required {
+		if _, ok := opts[opt]; ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", fmt.Errorf("%s needs exactly one of %s", name, strings.Join(m.required, ", "))
+	}
+	return m.summary(opts), nil
+}
+
+// PendingMutation is a mutating command waiting to be confirmed with its
+// token.
+type PendingMutation struct {
+	Token   string `json:"token"`
+	Command string `json:"command"`
+	Summary string `json:"summary"`
+	// Profile and Region are what the command runs with once confirmed.
+	Profile   string    `json:"profile,omitempty"`
+	Region    string    `json:"region,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Args      []string  `json:"-"`
+}
+
+// Confirmations holds mutating commands until they are confirmed. Tokens
+// are single-use and expire after five minutes; they are not persisted.
+type Confirmations struct {
+	mu      sync.Mutex
+	pending map[string]PendingMutation
+}
+
+// NewConfirmations returns an empty Confirmations.
+func NewConfirmations() *Confirmations {
+	return &Confirmations{pending: map[string]PendingMutation{}}
+}
+
+// Request records p, filling in its token, command and expiry.
+func (c *Confirmations) Request(p PendingMutation) PendingMutation {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	p.Token = hex.EncodeToString(buf[:])
+	p.Command = "aws " + strings.Join(p.Args, " ")
+	p.ExpiresAt = time.Now().UTC().Add(confirmationTTL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked()
+	c.pending[p.Token] = p
+	return p
+}
+
+// Confirm returns the pending mutation for token and forgets it, so it runs
+// at most once. It reports false for unknown or expired tokens.
+func (c *Confirmations) Confirm(token string) (PendingMutation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked()
+
+	p, ok := c.pending[token]
+	delete(c.pending, token)
+	return p, ok
+}
+
+// pruneLocked drops expired mutations. Caller must hold c.mu.
+func (c *Confirmations) pruneLocked() {
+	now := time.Now()
+	for token, p := range c.pending {
+		if now.After(p.ExpiresAt) {
+			delete(c.pending, token)
+		}
+	}
+}
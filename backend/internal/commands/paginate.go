@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+)
+
+// pageTokens are the top-level keys AWS APIs return a next-page token in,
+// and the option that passes it back, in the order they are looked for.
+var pageTokens = []struct{ key, option string }{
+	{"NextToken", "--next-token"},
+	{"nextToken", "--next-token"},
+	{"NextMarker", "--marker"},
+	{"Marker", "--marker"},
+	{"NextContinuationToken", "--continuation-token"},
+}
+
+// pagingOptions mean the caller is paging by hand, so output is returned as
+// the CLI gives it.
+var pagingOptions = map[string]bool{
+	"--no-paginate": true, "--max-items": true, "--starting-token": true,
+	"--next-token": true, "--marker": true, "--continuation-token": true,
+}
+
+// runPages runs args and, while the JSON output carries a next-page token,
+// runs them again with it, up to maxPages runs in all. Lists at the top
+// level of each page are concatenated and other keys take the last page's
+// value. If maxPages runs out first, the remaining token is left in the
+// output so the listing doesn't look complete.
+func (m *Manager) runPages(ctx context.Context, args []string, maxPages int) ([]byte, error) {
+	out, err := m.exec.RunJSON(ctx, args...)
+	if err != nil || maxPages <= 1 || pagedByHand(args) {
+		return out, err
+	}
+
+	var merged map[string]json.RawMessage
+	if json.Unmarshal(out, &merged) != nil {
+		return out, nil
+	}
+	seen := map[string]bool{}
+	pages := 1
+	for ; pages < maxPages; pages++ {
+		key, option, token := nextPage(merged)
+		if token == "" || seen[token] {
+			break
+		}
+		seen[token] = true
+
+		next, err := m.exec.RunJSON(ctx, append(append([]string{}, args...), option, token)...)
+		if err != nil {
+			return nil, err
+		}
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(next, &page); err != nil {
+			return nil, err
+		}
+		delete(merged, key)
+		mergePage(merged, page)
+	}
+	if pages == 1 {
+		return out, nil
+	}
+	return json.Marshal(merged)
+}
+
+// pagedByHand reports whether args already control pagination.
+func pagedByHand(args []string) bool {
+	for _, a := range args {
+		name, _, _ := strings.Cut(a, "=")
+		if pagingOptions[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// nextPage returns the key, option and value of the next-page token in
+// page, or an empty token on the last page.
+func nextPage(page map[string]json.RawMessage) (key, option, token string) {
+	for _, t := range pageTokens {
+		raw, ok := page[t.key]
+		if !ok {
+			continue
+		}
+		var s string
+		if json.Unmarshal(raw, &s) == nil && s != "" {
+			return t.key, t.option, s
+		}
+	}
+	return "", "", ""
+}
+
+// mergePage adds page to merged: lists present in both are concatenated,
+// anything else is replaced.
+func mergePage(merged, page map[string]json.RawMessage) {
+	for k, v := range page {
+		var prev, cur []json.RawMessage
+		if json.Unmarshal(merged[k], &prev) == nil && json.Unmarshal(v, &cur) == nil && prev != nil && cur != nil {
+			if combined, err := json.Marshal(append(prev, cur...)); err == nil {
+				merged[k] = combined
+				continue
+			}
+		}
+		merged[k] = v
+	}
+}
+
+// runRaw runs args in the given format, following pagination for JSON.
+func (m *Manager) runRaw(ctx context.Context, args []string, format string, maxPages int) ([]byte, error) {
+	if format == "" || format == awscli.FormatJSON {
+		return m.runPages(ctx, args, maxPages)
+	}
+	return awscli.RunFormat(ctx, m.exec, format, args...)
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/jmespath"
+)
+
+// Step is one aws CLI call of a pipeline command. Values picked out of its
+// JSON output by Extract can be used in later steps' args as {{name}}: an
+// arg that is just the placeholder becomes one arg per value, otherwise the
+// values are joined with commas, e.g. "Name=attachment.instance-id,Values={{ids}}".
+type Step struct {
+	Args []string `json:"args"`
+	// Extract maps names to JMESPath expressions evaluated on the output,
+	// e.g. {"ids": "Reservations[].Instances[].InstanceId"}.
+	Extract map[string]string `json:"extract,omitempty"`
+
+	compiled map[string]*jmespath.Expression
+}
+
+// StepResult is the outcome of one pipeline step.
+type StepResult struct {
+	Command string          `json:"command"`
+	Output  json.RawMessage `json:"output,omitempty"`
+	// Skipped says why the step didn't run, e.g. an earlier step found
+	// nothing to look up.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	extractNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// compileSteps checks a pipeline's steps and compiles their expressions.
+// Every placeholder must be extracted by an earlier step.
+func compileSteps(steps []Step) error {
+	defined := map[string]bool{}
+	for i := range steps {
+		step := &steps[i]
+		if len(step.Args) == 0 {
+			return fmt.Errorf("step %d has no args", i+1)
+		}
+		for _, arg := range step.Args {
+			for _, m := range placeholderPattern.FindAllStringSubmatch(arg, -1) {
+				if !defined[m[1]] {
+					return fmt.Errorf("step %d uses {{%s}}, which no earlier step extracts", i+1, m[1])
+				}
+			}
+		}
+		step.compiled = map[string]*jmespath.Expression{}
+		for name, src := range step.Extract {
+			if !extractNamePattern.MatchString(name) {
+				return fmt.Errorf("step %d: invalid extract name %q", i+1, name)
+			}
+			expr, err := jmespath.Compile(src)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			step.compiled[name] = expr
+			defined[name] = true
+		}
+	}
+	return nil
+}
+
+// runPipeline runs cmd's steps in order and returns {"steps": [...]} with
+// each step's command and output, and the whole pipeline as arguments
+// joined by "| aws". Once a step finds nothing for a later one to look up,
+// the remaining steps are skipped.
+func (m *Manager) runPipeline(ctx context.Context, cmd Command, region string, format string) ([]byte, []string, error) {
+	if format != "" && format != awscli.FormatJSON {
+		return nil, nil, fmt.Errorf("%w: pipelines only return json", awscli.ErrFormatUnsupported)
+	}
+
+	values := map[string]any{}
+	results := make([]StepResult, 0, len(cmd.Steps))
+	var all []string
+	skipped := ""
+	for i, step := range cmd.Steps {
+		args, empty, err := substitute(step.Args, values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		args = withRegion(cmd, args, region)
+		if i > 0 {
+			all = append(all, "|", "aws")
+		}
+		all = append(all, args...)
+
+		result := StepResult{Command: "aws " + strings.Join(args, " ")}
+		if skipped == "" && empty != "" {
+			skipped = fmt.Sprintf("no %s to look up", empty)
+		}
+		if skipped != "" {
+			result.Skipped = skipped
+			results = append(results, result)
+			continue
+		}
+
+		out, err := m.exec.RunJSON(ctx, args...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		var data any
+		if err := json.Unmarshal(out, &data); err != nil {
+			return nil, nil, fmt.Errorf("step %d: invalid JSON output: %w", i+1, err)
+		}
+		for name, expr := range step.compiled {
+			values[name] = expr.Search(data)
+		}
+		result.Output = json.RawMessage(out)
+		results = append(results, result)
+	}
+
+	out, err := json.Marshal(struct {
+		Steps []StepResult `json:"steps"`
+	}{results})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, all, nil
+}
+
+// substitute replaces the placeholders in args with extracted values. It
+// returns the name of a placeholder without values, in which case its
+// placeholders are left as they are.
+func substitute(args []string, values map[string]any) ([]string, string, error) {
+	out := make([]string, 0, len(args))
+	empty := ""
+	for _, arg := range args {
+		matches := placeholderPattern.FindAllStringSubmatch(arg, -1)
+		if len(matches) == 0 {
+			out = append(out, arg)
+			continue
+		}
+		resolved := map[string][]string{}
+		for _, m := range matches {
+			vals, err := argValues(m[1], values[m[1]])
+			if err != nil {
+				return nil, "", err
+			}
+			if len(vals) == 0 && empty == "" {
+				empty = m[1]
+			}
+			resolved[m[1]] = vals
+		}
+		if len(matches) == 1 && matches[0][0] == arg && len(resolved[matches[0][1]]) > 0 {
+			out = append(out, resolved[matches[0][1]]...)
+			continue
+		}
+		out = append(out, placeholderPattern.ReplaceAllStringFunc(arg, func(p string) string {
+			vals := resolved[placeholderPattern.FindStringSubmatch(p)[1]]
+			if len(vals) == 0 {
+				return p
+			}
+			return strings.Join(vals, ",")
+		}))
+	}
+	return out, empty, nil
+}
+
+// argValues turns an extracted value into CLI arguments: one per list
+// element, strings as they are and anything else as JSON.
+func argValues(name string, v any) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		list = []any{v}
+	}
+	var out []string
+	for _, item := range list {
+		var s string
+		switch item := item.(type) {
+		case nil:
+			continue
+		case string:
+			s = item
+		case float64:
+			s = strconv.FormatFloat(item, 'f', -1, 64)
+		default:
+			data, err := json.Marshal(item)
+			if err != nil {
+				return nil, err
+			}
+			s = string(data)
+		}
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, "-") {
+			return nil, fmt.Errorf("value %q of {{%s}} would be read as an option", s, name)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// withRegion adds --region to args when cmd supports it and region is set.
+func withRegion(cmd Command, args []string, region string) []string {
+	if cmd.SupportsRegion && strings.TrimSpace(region) != "" {
+		args = append(args, "--region", region)
+	}
+	return args
+}
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// schemaDecoder turns a parsed command config into commands, recording an
+// error with line and field for every entry that doesn't fit the schema
+// rather than stopping at the first.
+type schemaDecoder struct {
+	errs []error
+	// ids maps the command ids seen so far to their line.
+	ids map[string]int
+}
+
+// decodeCommands validates root against the command config schema: a list
+// of commands, each with a unique id and either args or steps.
+func decodeCommands(root *docNode) ([]Command, error) {
+	d := &schemaDecoder{ids: map[string]int{}}
+	if root.kind == scalarNode && root.value == nil {
+		return nil, nil
+	}
+	if root.kind != listNode {
+		return nil, &configError{line: root.line, msg: "the command config must be a list of commands"}
+	}
+
+	var list []Command
+	for i, item := range root.items {
+		if c, ok := d.command(item, fmt.Sprintf("[%d]", i)); ok {
+			list = append(list, c)
+		}
+	}
+	sort.SliceStable(d.errs, func(i, j int) bool {
+		return d.errs[i].(*configError).line < d.errs[j].(*configError).line
+	})
+	return list, errors.Join(d.errs...)
+}
+
+func (d *schemaDecoder) fail(line int, path, format string, args ...any) {
+	d.errs = append(d.errs, &configError{line: line, path: path, msg: fmt.Sprintf(format, args...)})
+}
+
+func (d *schemaDecoder) command(n *docNode, path string) (Command, bool) {
+	var c Command
+	if n.kind != mapNode {
+		d.fail(n.line, path, "must be a command with an id and args")
+		return c, false
+	}
+
+	before := len(d.errs)
+	text := map[string]*string{
+		"id": &c.ID, "label": &c.Label, "description": &c.Description,
+		"service": &c.Service, "category": &c.Category,
+	}
+	lines := map[string]int{}
+	// invalid is set when args or steps already have an error, so that a
+	// command isn't also reported as having neither.
+	invalid := false
+	for _, k := range n.keys {
+		field := path + "." + k.name
+		if _, dup := lines[k.name]; dup {
+			d.fail(k.line, field, "is set twice")
+			continue
+		}
+		lines[k.name] = k.line
+		switch k.name {
+		case "id", "label", "description", "service", "category":
+			*text[k.name], _ = d.string(k.value, field)
+		case "args":
+			errs := len(d.errs)
+			c.Args = d.stringList(k.value, field)
+			invalid = invalid || len(d.errs) > errs
+		case "tags":
+			c.Tags = d.stringList(k.value, field)
+		case "supportsRegion":
+			c.SupportsRegion = d.bool(k.value, field)
+		case "steps":
+			errs := len(d.errs)
+			c.Steps = d.steps(k.value, field)
+			invalid = invalid || len(d.errs) > errs
+		default:
+			d.fail(k.line, field, "unknown field")
+		}
+	}
+
+	switch line, ok := lines["id"]; {
+	case !ok:
+		d.fail(n.line, path, `missing required field "id"`)
+	case c.ID == "":
+		d.fail(line, path+".id", "must not be empty")
+	default:
+		if first, dup := d.ids[c.ID]; dup {
+			d.fail(line, path+".id", "duplicate id %q (first used on line %d)", c.ID, first)
+		} else {
+			d.ids[c.ID] = line
+		}
+	}
+	switch {
+	case len(c.Args) == 0 && len(c.Steps) == 0 && !invalid:
+		d.fail(n.line, path, `needs non-empty "args" or "steps"`)
+	case len(c.Args) > 0 && len(c.Steps) > 0:
+		d.fail(lines["steps"], path, `set either "args" or "steps", not both`)
+	}
+	return c, len(d.errs) == before
+}
+
+func (d *schemaDecoder) steps(n *docNode, path string) []Step {
+	if n.kind != listNode {
+		d.fail(n.line, path, "must be a list of steps")
+		return nil
+	}
+	before := len(d.errs)
+	steps := make([]Step, len(n.items))
+	for i, item := range n.items {
+		stepPath := fmt.Sprintf("%s[%d]", path, i)
+		if item.kind != mapNode {
+			d.fail(item.line, stepPath, "must be a step with args")
+			continue
+		}
+		for _, k := range item.keys {
+			switch k.name {
+			case "args":
+				steps[i].Args = d.stringList(k.value, stepPath+".args")
+			case "extract":
+				steps[i].Extract = d.stringMap(k.value, stepPath+".extract")
+			default:
+				d.fail(k.line, stepPath+"."+k.name, "unknown field")
+			}
+		}
+	}
+	if len(d.errs) == before {
+		if err := compileSteps(steps); err != nil {
+			d.fail(n.line, path, "%v", err)
+		}
+	}
+	return steps
+}
+
+// string returns a string field; null counts as empty. Unquoted YAML
+// scalars are taken as written, so "version: 2" is "2".
+func (d *schemaDecoder) string(n *docNode, path string) (string, bool) {
+	if n.kind == scalarNode {
+		switch v := n.value.(type) {
+		case nil:
+			return "", true
+		case string:
+			return v, true
+		}
+		if n.plain {
+			return n.text, true
+		}
+	}
+	d.fail(n.line, path, "must be a string")
+	return "", false
+}
+
+func (d *schemaDecoder) stringList(n *docNode, path string) []string {
+	if n.kind == scalarNode && n.value == nil {
+		return nil
+	}
+	if n.kind != listNode {
+		d.fail(n.line, path, "must be a list of strings")
+		return nil
+	}
+	out := make([]string, 0, len(n.items))
+	for i, item := range n.items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if item.kind == scalarNode && item.value == nil {
+			d.fail(item.line, itemPath, "must be a string")
+			continue
+		}
+		if s, ok := d.string(item, itemPath); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (d *schemaDecoder) stringMap(n *docNode, path string) map[string]string {
+	if n.kind != mapNode {
+		d.fail(n.line, path, "must be a map of names to strings")
+		return nil
+	}
+	out := map[string]string{}
+	for _, k := range n.keys {
+		if _, dup := out[k.name]; dup {
+			d.fail(k.line, path+"."+k.name, "is set twice")
+			continue
+		}
+		out[k.name], _ = d.string(k.value, path+"."+k.name)
+	}
+	return out
+}
+
+func (d *schemaDecoder) bool(n *docNode, path string) bool {
+	if n.kind == scalarNode {
+		switch v := n.value.(type) {
+		case nil:
+			return false
+		case bool:
+			return v
+		}
+	}
+	d.fail(n.line, path, "must be true or false")
+	return false
+}
@@ -0,0 +1,530 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parseYAMLDocument decodes the YAML subset command configs need into a
+// docNode tree: block mappings and sequences, flow [...] and {...}
+// collections, plain and quoted scalars, | and > block scalars and comments.
+// Anchors, aliases, tags and multiple documents are refused.
+func parseYAMLDocument(data []byte) (*docNode, error) {
+	p := &yamlParser{raw: strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\n")}
+	for i := range p.raw {
+		p.raw[i] = strings.TrimSuffix(p.raw[i], "\r")
+		trimmed := strings.TrimLeft(p.raw[i], " ")
+		indent := len(p.raw[i]) - len(trimmed)
+		text := strings.TrimRight(stripComment(trimmed), " \t")
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(trimmed, "\t"):
+			return nil, &configError{line: i + 1, msg: "tabs can't be used for indentation"}
+		case indent == 0 && text == "---":
+			if len(p.lines) > 0 {
+				return nil, &configError{line: i + 1, msg: "multiple documents are not supported"}
+			}
+			continue
+		case indent == 0 && strings.HasPrefix(text, "%"):
+			return nil, &configError{line: i + 1, msg: "directives are not supported"}
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: indent, text: text})
+	}
+	if len(p.lines) == 0 {
+		return &docNode{line: 1}, nil
+	}
+
+	root, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.unexpected()
+	}
+	return root, nil
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	// text is the line without indentation and comments.
+	text string
+}
+
+type yamlParser struct {
+	raw   []string
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the node starting at the current line.
+func (p *yamlParser) block() (*docNode, error) {
+	l := p.lines[p.pos]
+	if isSeqItem(l.text) {
+		return p.sequence(l.indent)
+	}
+	if _, _, ok, err := splitKey(l.text); err != nil {
+		return nil, &configError{line: l.num, msg: err.Error()}
+	} else if ok {
+		return p.mapping(l.indent)
+	}
+	p.pos++
+	if l.text[0] == '|' || l.text[0] == '>' {
+		return p.blockScalar(l.text, l.num, l.indent-1)
+	}
+	return p.inline(l.text, l.num)
+}
+
+func (p *yamlParser) sequence(indent int) (*docNode, error) {
+	n := &docNode{kind: listNode, line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSeqItem(p.lines[p.pos].text) {
+		l := p.lines[p.pos]
+		rest := strings.TrimLeft(l.text[1:], " ")
+		var item *docNode
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			item = &docNode{line: l.num}
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				item, err = p.block()
+			}
+		case rest[0] == '|' || rest[0] == '>':
+			p.pos++
+			item, err = p.blockScalar(rest, l.num, indent)
+		default:
+			// What follows the dash is a node indented past it, so a
+			// mapping can go on on the next lines: "- id: x\n  args: [...]".
+			p.lines[p.pos] = yamlLine{num: l.num, indent: indent + len(l.text) - len(rest), text: rest}
+			item, err = p.block()
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.items = append(n.items, item)
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return nil, p.unexpected()
+	}
+	return n, nil
+}
+
+func (p *yamlParser) mapping(indent int) (*docNode, error) {
+	n := &docNode{kind: mapNode, line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		l := p.lines[p.pos]
+		key, rest, ok, err := splitKey(l.text)
+		if err != nil {
+			return nil, &configError{line: l.num, msg: err.Error()}
+		}
+		if !ok {
+			return nil, &configError{line: l.num, msg: fmt.Sprintf("expected \"key: value\", found %q", l.text)}
+		}
+		p.pos++
+
+		var value *docNode
+		switch {
+		case rest == "":
+			value = &docNode{line: l.num}
+			if p.pos < len(p.lines) {
+				next := p.lines[p.pos]
+				if next.indent > indent || (next.indent == indent && isSeqItem(next.text)) {
+					value, err = p.block()
+				}
+			}
+		case rest[0] == '|' || rest[0] == '>':
+			value, err = p.blockScalar(rest, l.num, indent)
+		default:
+			value, err = p.inline(rest, l.num)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.keys = append(n.keys, docKey{name: key, line: l.num, value: value})
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return nil, p.unexpected()
+	}
+	return n, nil
+}
+
+// blockScalar parses a | or > scalar whose header is on line num, taking
+// the raw lines indented past parentIndent that follow it.
+func (p *yamlParser) blockScalar(header string, num, parentIndent int) (*docNode, error) {
+	chomp := header[1:]
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, &configError{line: num, msg: fmt.Sprintf("unsupported block scalar header %q", header)}
+	}
+
+	var body []string
+	indent := -1
+	end := num
+	for ; end < len(p.raw); end++ {
+		line := p.raw[end]
+		if strings.TrimSpace(line) == "" {
+			body = append(body, "")
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if lineIndent <= parentIndent {
+			break
+		}
+		if indent < 0 {
+			indent = lineIndent
+		}
+		if lineIndent < indent {
+			return nil, &configError{line: end + 1, msg: "block scalar line is indented less than the first one"}
+		}
+		body = append(body, line[indent:])
+	}
+	for p.pos < len(p.lines) && p.lines[p.pos].num <= end {
+		p.pos++
+	}
+
+	content := len(body)
+	for content > 0 && body[content-1] == "" {
+		content--
+	}
+	var text string
+	if header[0] == '|' {
+		text = strings.Join(body[:content], "\n")
+	} else {
+		var b strings.Builder
+		for i, line := range body[:content] {
+			switch {
+			case line == "":
+				b.WriteString("\n")
+			case i > 0 && body[i-1] != "":
+				b.WriteString(" " + line)
+			default:
+				b.WriteString(line)
+			}
+		}
+		text = b.String()
+	}
+	switch {
+	case chomp == "+":
+		text += strings.Repeat("\n", len(body)-content+1)
+	case chomp == "" && content > 0:
+		text += "\n"
+	}
+	return &docNode{line: num, value: text}, nil
+}
+
+// inline parses a scalar or flow collection written on line num. A flow
+// collection may go on over the following lines.
+func (p *yamlParser) inline(text string, num int) (*docNode, error) {
+	switch text[0] {
+	case '[', '{':
+		f := &flowParser{s: text, starts: []flowLine{{0, num}}}
+		for {
+			f.i = 0
+			n, err := f.value()
+			if errors.Is(err, errFlowUnclosed) && p.pos < len(p.lines) {
+				f.starts = append(f.starts, flowLine{len(f.s) + 1, p.lines[p.pos].num})
+				f.s += " " + p.lines[p.pos].text
+				p.pos++
+				continue
+			}
+			if errors.Is(err, errFlowUnclosed) {
+				return nil, &configError{line: num, msg: fmt.Sprintf("unclosed '%c'", text[0])}
+			}
+			if err != nil {
+				return nil, err
+			}
+			closing := f.s[f.i-1]
+			f.space()
+			if f.i < len(f.s) {
+				return nil, &configError{line: f.line(f.i), msg: fmt.Sprintf("unexpected %q after the closing '%c'", f.s[f.i:], closing)}
+			}
+			return n, nil
+		}
+	case '"', '\'':
+		value, end, err := unquote(text, 0)
+		if errors.Is(err, errFlowUnclosed) {
+			return nil, &configError{line: num, msg: "unterminated quoted string (multi-line quoted strings are not supported)"}
+		}
+		if err != nil {
+			return nil, &configError{line: num, msg: err.Error()}
+		}
+		if rest := strings.TrimSpace(text[end:]); rest != "" {
+			return nil, &configError{line: num, msg: fmt.Sprintf("unexpected %q after quoted string", rest)}
+		}
+		return &docNode{line: num, value: value}, nil
+	case '&', '*', '!':
+		return nil, &configError{line: num, msg: "anchors, aliases and tags are not supported"}
+	}
+	return plainNode(text, num), nil
+}
+
+func (p *yamlParser) unexpected() error {
+	l := p.lines[p.pos]
+	return &configError{line: l.num, msg: fmt.Sprintf("unexpected indentation before %q", l.text)}
+}
+
+var errFlowUnclosed = errors.New("unclosed")
+
+type flowLine struct{ offset, num int }
+
+// flowParser parses a flow collection, possibly joined from several lines.
+type flowParser struct {
+	s      string
+	i      int
+	starts []flowLine
+}
+
+func (f *flowParser) line(i int) int {
+	num := f.starts[0].num
+	for _, l := range f.starts {
+		if l.offset <= i {
+			num = l.num
+		}
+	}
+	return num
+}
+
+func (f *flowParser) space() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+func (f *flowParser) fail(format string, args ...any) error {
+	return &configError{line: f.line(f.i), msg: fmt.Sprintf(format, args...)}
+}
+
+func (f *flowParser) value() (*docNode, error) {
+	f.space()
+	if f.i >= len(f.s) {
+		return nil, errFlowUnclosed
+	}
+	line := f.line(f.i)
+	switch c := f.s[f.i]; c {
+	case '[':
+		f.i++
+		n := &docNode{kind: listNode, line: line}
+		for {
+			f.space()
+			if f.i >= len(f.s) {
+				return nil, errFlowUnclosed
+			}
+			if f.s[f.i] == ']' {
+				f.i++
+				return n, nil
+			}
+			item, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.i++
+		n := &docNode{kind: mapNode, line: line}
+		for {
+			f.space()
+			if f.i >= len(f.s) {
+				return nil, errFlowUnclosed
+			}
+			if f.s[f.i] == '}' {
+				f.i++
+				return n, nil
+			}
+			keyLine := f.line(f.i)
+			key, err := f.scalar(true)
+			if err != nil {
+				return nil, err
+			}
+			f.space()
+			if f.i >= len(f.s) {
+				return nil, errFlowUnclosed
+			}
+			if f.s[f.i] != ':' {
+				return nil, f.fail("expected ':' after key %q", key.text)
+			}
+			f.i++
+			f.space()
+			value := &docNode{line: keyLine}
+			if f.i < len(f.s) && f.s[f.i] != ',' && f.s[f.i] != '}' {
+				if value, err = f.value(); err != nil {
+					return nil, err
+				}
+			}
+			name, _ := key.value.(string)
+			if key.plain {
+				name = key.text
+			}
+			n.keys = append(n.keys, docKey{name: name, line: keyLine, value: value})
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case ']', '}', ',', ':':
+		return nil, f.fail("unexpected %q", c)
+	case '&', '*', '!':
+		return nil, f.fail("anchors, aliases and tags are not supported")
+	}
+	return f.scalar(false)
+}
+
+// separator skips the ',' after a flow collection entry, or stops before
+// the closing bracket.
+func (f *flowParser) separator(closing byte) error {
+	f.space()
+	switch {
+	case f.i >= len(f.s):
+		return errFlowUnclosed
+	case f.s[f.i] == ',':
+		f.i++
+	case f.s[f.i] != closing:
+		return f.fail("expected ',' or '%c', found %q", closing, f.s[f.i])
+	}
+	return nil
+}
+
+// scalar parses a quoted or plain scalar; plain keys end at ':'.
+func (f *flowParser) scalar(key bool) (*docNode, error) {
+	line := f.line(f.i)
+	if c := f.s[f.i]; c == '"' || c == '\'' {
+		value, end, err := unquote(f.s, f.i)
+		if err != nil {
+			if errors.Is(err, errFlowUnclosed) {
+				return nil, err
+			}
+			return nil, f.fail("%v", err)
+		}
+		f.i = end
+		return &docNode{line: line, value: value}, nil
+	}
+	start := f.i
+	for ; f.i < len(f.s); f.i++ {
+		c := f.s[f.i]
+		if c == ',' || c == '[' || c == ']' || c == '{' || c == '}' {
+			break
+		}
+		if c == ':' && (key || f.i+1 == len(f.s) || f.s[f.i+1] == ' ') {
+			break
+		}
+	}
+	return plainNode(strings.TrimSpace(f.s[start:f.i]), line), nil
+}
+
+// unquote parses the quoted scalar starting at s[start] and returns its
+// value and the index after the closing quote.
+func unquote(s string, start int) (string, int, error) {
+	if s[start] == '\'' {
+		for i := start + 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(s[start+1:i], "''", "'"), i + 1, nil
+		}
+		return "", 0, errFlowUnclosed
+	}
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[start : i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid escape in %s", s[start:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, errFlowUnclosed
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// plainNode resolves an unquoted scalar as YAML 1.2's core schema does,
+// to null, a bool, a number or a string.
+func plainNode(text string, line int) *docNode {
+	n := &docNode{line: line, plain: true, text: text}
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+	case "true", "True", "TRUE":
+		n.value = true
+	case "false", "False", "FALSE":
+		n.value = false
+	default:
+		n.value = text
+		if yamlNumber.MatchString(text) {
+			if f, err := strconv.ParseFloat(text, 64); err == nil {
+				n.value = f
+			}
+		}
+	}
+	return n
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits a "key: value" line. ok is false when text isn't one.
+func splitKey(text string) (key, rest string, ok bool, err error) {
+	switch text[0] {
+	case '[', '{', '|', '>', '&', '*', '!':
+		return "", "", false, nil
+	case '"', '\'':
+		value, end, err := unquote(text, 0)
+		if err != nil {
+			return "", "", false, nil
+		}
+		after := strings.TrimLeft(text[end:], " ")
+		if !strings.HasPrefix(after, ":") || (len(after) > 1 && after[1] != ' ') {
+			return "", "", false, nil
+		}
+		return value, strings.TrimSpace(after[1:]), true, nil
+	}
+	if isSeqItem(text) {
+		return "", "", false, nil
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			key = strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false, fmt.Errorf("missing key before ':'")
+			}
+			return key, strings.TrimSpace(text[i+1:]), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// stripComment removes a trailing # comment from a line, ignoring # inside
+// quoted scalars.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote == '\'' && c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" [{,:", s[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Command
+	}{
+		{
+			name: "block sequences",
+			in: `- id: vpcs
+  args:
+    - ec2
+    - describe-vpcs
+  tags:
+    - VPC
+    - network
+`,
+			want: Command{ID: "vpcs", Args: []string{"ec2", "describe-vpcs"}, Tags: []string{"vpc", "network"}},
+		},
+		{
+			name: "flow sequence over several lines",
+			in: `- id: vpcs
+  args: [ec2,
+    describe-vpcs, --max-items,
+    5]
+`,
+			want: Command{ID: "vpcs", Args: []string{"ec2", "describe-vpcs", "--max-items", "5"}},
+		},
+		{
+			name: "quoted scalars with colons and hashes",
+			in: `- id: "vpcs"
+  label: 'it''s: #1'
+  args: [ec2, "a: b # c", 'x,y']
+`,
+			want: Command{ID: "vpcs", Label: "it's: #1", Args: []string{"ec2", "a: b # c", "x,y"}},
+		},
+		{
+			name: "inline comments",
+			in: `# commands
+- id: vpcs # the id
+  label: C#sharp x # only this is a comment
+  args: [ec2, describe-vpcs] # trailing
+  supportsRegion: true
+`,
+			want: Command{ID: "vpcs", Label: "C#sharp x", Args: []string{"ec2", "describe-vpcs"}, SupportsRegion: true},
+		},
+		{
+			name: "literal and folded blocks",
+			in: `- id: vpcs
+  description: |
+    one
+    two
+  label: >
+    a
+    b
+  args: [ec2, describe-vpcs]
+`,
+			want: Command{ID: "vpcs", Label: "a b\n", Description: "one\ntwo\n", Args: []string{"ec2", "describe-vpcs"}},
+		},
+		{
+			name: "steps with flow maps",
+			in: `- id: pipeline
+  category: Networking
+  steps:
+    - args: [ec2, describe-vpcs]
+      extract: {ids: "Vpcs[].VpcId"}
+    - args: [ec2, describe-subnets, --filters, "Name=vpc-id,Values={{ids}}"]
+`,
+			want: Command{ID: "pipeline", Category: "Networking", Steps: []Step{
+				{Args: []string{"ec2", "describe-vpcs"}, Extract: map[string]string{"ids": "Vpcs[].VpcId"}},
+				{Args: []string{"ec2", "describe-subnets", "--filters", "Name=vpc-id,Values={{ids}}"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds, err := parseConfig([]byte(tt.in), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := cmds[tt.want.ID]
+			if !ok || len(cmds) != 1 {
+				t.Fatalf("parseConfig() = %v, want only %q", cmds, tt.want.ID)
+			}
+			if got.Label != tt.want.Label || got.Description != tt.want.Description || got.SupportsRegion != tt.want.SupportsRegion {
+				t.Errorf("label, description, supportsRegion = %q, %q, %v, want %q, %q, %v",
+					got.Label, got.Description, got.SupportsRegion, tt.want.Label, tt.want.Description, tt.want.SupportsRegion)
+			}
+			if tt.want.Category != "" && got.Category != tt.want.Category {
+				t.Errorf("category = %q, want %q", got.Category, tt.want.Category)
+			}
+			if !reflect.DeepEqual(got.Args, tt.want.Args) {
+				t.Errorf("args = %q, want %q", got.Args, tt.want.Args)
+			}
+			if len(tt.want.Tags) > 0 && !reflect.DeepEqual(got.Tags, tt.want.Tags) {
+				t.Errorf("tags = %q, want %q", got.Tags, tt.want.Tags)
+			}
+			if len(got.Steps) != len(tt.want.Steps) {
+				t.Fatalf("got %d steps, want %d", len(got.Steps), len(tt.want.Steps))
+			}
+			for i, step := range tt.want.Steps {
+				if !reflect.DeepEqual(got.Steps[i].Args, step.Args) || len(got.Steps[i].Extract) != len(step.Extract) {
+					t.Errorf("step %d = %q %v, want %q %v", i+1, got.Steps[i].Args, got.Steps[i].Extract, step.Args, step.Extract)
+				}
+				for k, v := range step.Extract {
+					if got.Steps[i].Extract[k] != v {
+						t.Errorf("step %d extract %q = %q, want %q", i+1, k, got.Steps[i].Extract[k], v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseYAMLConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"scalar instead of list", "- id: a\n  args: ec2\n", "line 2: [0].args: must be a list of strings"},
+		{"missing id", "- id: a\n  args: [ec2, describe-vpcs]\n- label: x\n  args: [ec2, describe-vpcs]\n", `line 3: [1]: missing required field "id"`},
+		{"duplicate key", "- id: a\n  id: b\n", "line 2: [0].id: is set twice"},
+		{"bad bool", "- id: a\n  args: [ec2, describe-vpcs]\n  supportsRegion: maybe\n", "line 3: [0].supportsRegion: must be true or false"},
+		{"map in args", "- id: a\n  args:\n    - ec2\n    - {x: 1}\n", "line 4: [0].args[1]: must be a string"},
+		{"bad extract", "- id: a\n  steps:\n    - args: [ec2, describe-vpcs]\n      extract: {ids: \"[\"}\n", `[0].steps: step 1: invalid JMESPath "["`},
+		{"tab indentation", "- id: a\n\targs: [ec2]\n", "line 2: tabs can't be used for indentation"},
+		{"unterminated quote", "- id: \"a\n", "line 1: unterminated quoted string"},
+		{"unclosed flow sequence", "- id: a\n  args: [ec2, describe-vpcs\n", `line 2: unclosed '['`},
+		{"text after flow sequence", "- id: a\n  args: [ec2] x\n", `line 2: unexpected "x" after the closing ']'`},
+		{"anchor", "- &x id: a\n", "line 1: anchors, aliases and tags are not supported"},
+		{"second document", "- id: a\n  args: [ec2]\n---\n- id: b\n", "line 3: multiple documents are not supported"},
+		{"bad indentation", "- id: a\n  args: [ec2, describe-vpcs]\n bogus: 1\n", `line 3: unexpected indentation before "bogus: 1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.in), true)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseConfig(%q) error = %v, want %q", tt.in, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/currency"
+)
+
+// Config holds the resolved runtime configuration for the server. Values come
+// from command-line flags, falling back to environment variables and then to
+// built-in defaults.
+type Config struct {
+	// Port is the TCP port used when BindAddr does not include one.
+	Port string
+	// BindAddr is the host (or host:port) to listen on. It defaults to
+	// loopback so the credential-wielding API isn't reachable from the
+	// network; "0.0.0.0" (or empty) means all interfaces.
+	BindAddr string
+	// SocketPath, when set, makes the server listen on a Unix domain socket
+	// instead of TCP.
+	SocketPath string
+
+	// TLSCert and TLSKey are PEM files to serve HTTPS with. With TLSSelfSigned
+	// and no files at those paths, a self-signed certificate is generated
+	// there on first start.
+	TLSCert       string
+	TLSKey        string
+	TLSSelfSigned bool
+
+	// Auth requires a bearer token on every /api route. The token is
+	// AuthToken if set, otherwise the one stored at AuthTokenPath, generated
+	// on first run. Setting AuthToken turns Auth on.
+	Auth          bool
+	AuthToken     string
+	AuthTokenPath string
+
+	// TrustedOrigins are origins besides the server's own whose browser
+	// pages may make state-changing API requests, e.g. a reverse proxy's
+	// public https://dashboard.example.com.
+	TrustedOrigins []string
+
+	StaticDir         string
+	CommandConfigPath string
+	// CommandIAMCheck makes raw and configured commands pass an IAM policy
+	// simulation as read actions before they run, instead of the verb
+	// allowlist.
+	CommandIAMCheck bool
+	// CommandPermissionCheck simulates a command's IAM action against the
+	// profile's own policies before it runs, to report a missing permission
+	// by name.
+	CommandPermissionCheck bool
+	// MutatingCommands enables the curated mutating commands, which run
+	// only after a confirmation step.
+	MutatingCommands bool
+	// RawMaxPages caps the pages of raw command output followed through
+	// next-page tokens; 1 disables pagination.
+	RawMaxPages int
+	CacheTTL    time.Duration
+	// GraphQL serves /api/graphql; it is on unless GRAPHQL=false.
+	GraphQL bool
+	// RequestTimeout bounds API requests and ScanTimeout resource scans,
+	// which may cover every region; zero means no limit.
+	RequestTimeout time.Duration
+	ScanTimeout    time.Duration
+	// JobWorkers run ?async=true requests in the background, each for at
+	// most JobTimeout; zero means no limit.
+	JobWorkers int
+	JobTimeout time.Duration
+
+	// DemoMode is "record" (save sanitized AWS CLI responses to FixtureDir),
+	// "replay" (serve them back without credentials) or empty for live mode.
+	DemoMode   string
+	FixtureDir string
+
+	// Locale is the default display locale for formatted values when a
+	// request's Accept-Language names no supported locale.
+	Locale string
+
+	// DisplayCurrency, when set, adds amounts converted to this ISO 4217
+	// currency alongside the original Cost Explorer amounts.
+	DisplayCurrency string
+	// ExchangeRates are units of each currency per 1 USD, used directly or as
+	// the fallback when ExchangeRatesURL is unreachable.
+	ExchangeRates map[string]float64
+	// ExchangeRatesURL is an optional provider returning
+	// {"base": "USD", "rates": {...}}.
+	ExchangeRatesURL string
+
+	// AlertStorePath is where cost alert rules are persisted.
+	AlertStorePath string
+	// FavoritesStorePath is where pinned and saved commands are persisted.
+	FavoritesStorePath string
+	// CostHistoryPath is the on-disk cost history; empty (the default)
+	// disables it.
+	CostHistoryPath string
+	// CacheDir is where the resource and cost caches are saved so they
+	// survive restarts; empty keeps them in memory only. CacheMaxStale is
+	// how long saved data is kept and served when AWS can't be reached.
+	CacheDir      string
+	CacheMaxStale time.Duration
+	// CacheBackend is "memory" (the default) or "redis", which shares the
+	// caches through the server at CacheRedisURL under keys starting with
+	// CacheRedisPrefix; CacheDir applies to the memory backend only.
+	CacheBackend     string
+	CacheRedisURL    string
+	CacheRedisPrefix string
+	// AuditLogPath is the append-only audit log of API actions; empty
+	// disables it.
+	AuditLogPath string
+
+	// EventSinks lists where server events are delivered: any of "log",
+	// "webhook" and "websocket".
+	EventSinks []string
+	// EventWebhookURL receives events when the "webhook" sink is enabled.
+	EventWebhookURL string
+
+	// TUI runs the interactive terminal interface instead of the HTTP server.
+	TUI bool
+}
+
+// Load parses flags from args (normally os.Args[1:]) on top of environment
+// defaults.
+func Load(args []string) (Config, error) {
+	cfg := Config{
+		Port:               envOr("PORT", "8080"),
+		BindAddr:           envOr("BIND_ADDR", "127.0.0.1"),
+		SocketPath:         os.Getenv("UNIX_SOCKET"),
+		StaticDir:          envOr("STATIC_DIR", "./static"),
+		CommandConfigPath:  os.Getenv("COMMAND_CONFIG_PATH"),
+		CacheTTL:           60 * time.Second,
+		RequestTimeout:     30 * time.Second,
+		ScanTimeout:        2 * time.Minute,
+		JobWorkers:         4,
+		RawMaxPages:        10,
+		JobTimeout:         15 * time.Minute,
+		DemoMode:           os.Getenv("DEMO_MODE"),
+		FixtureDir:         envOr("FIXTURE_DIR", "./fixtures"),
+		Locale:             envOr("LOCALE", "en-US"),
+		EventWebhookURL:    os.Getenv("EVENT_WEBHOOK_URL"),
+		DisplayCurrency:    os.Getenv("DISPLAY_CURRENCY"),
+		ExchangeRatesURL:   os.Getenv("EXCHANGE_RATES_URL"),
+		AlertStorePath:     envOr("ALERT_STORE_PATH", "./.aws-local-dashboard-alerts.json"),
+		FavoritesStorePath: envOr("FAVORITES_STORE_PATH", "./.aws-local-dashboard-favorites.json"),
+		CostHistoryPath:    os.Getenv("COST_HISTORY_PATH"),
+		CacheDir:           os.Getenv("CACHE_DIR"),
+		CacheMaxStale:      24 * time.Hour,
+		CacheBackend:       envOr("CACHE_BACKEND", "memory"),
+		CacheRedisURL:      envOr("CACHE_REDIS_URL", "redis://localhost:6379/0"),
+		CacheRedisPrefix:   envOr("CACHE_REDIS_PREFIX", "aws-local-dashboard"),
+		AuditLogPath:       envOr("AUDIT_LOG_PATH", "./.aws-local-dashboard-audit.log"),
+		TLSCert:            os.Getenv("TLS_CERT"),
+		TLSKey:             os.Getenv("TLS_KEY"),
+		AuthToken:          os.Getenv("API_TOKEN"),
+		AuthTokenPath:      envOr("API_TOKEN_PATH", "./.aws-local-dashboard-token"),
+	}
+	cfg.Auth, _ = strconv.ParseBool(os.Getenv("API_AUTH"))
+	cfg.TLSSelfSigned, _ = strconv.ParseBool(os.Getenv("TLS_SELF_SIGNED"))
+	eventSinks := envOr("EVENT_SINKS", "websocket")
+	trustedOrigins := os.Getenv("TRUSTED_ORIGINS")
+	exchangeRates := os.Getenv("EXCHANGE_RATES")
+
+	cfg.CommandIAMCheck, _ = strconv.ParseBool(os.Getenv("COMMAND_IAM_CHECK"))
+	cfg.CommandPermissionCheck, _ = strconv.ParseBool(os.Getenv("COMMAND_PERMISSION_CHECK"))
+	cfg.MutatingCommands, _ = strconv.ParseBool(os.Getenv("MUTATING_COMMANDS"))
+	cfg.GraphQL = true
+	if v := os.Getenv("GRAPHQL"); v != "" {
+		cfg.GraphQL, _ = strconv.ParseBool(v)
+	}
+
+	if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.CacheTTL = time.Duration(secs) * time.Second
+		}
+	}
+	if v := os.Getenv("CACHE_MAX_STALE_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.CacheMaxStale = time.Duration(secs) * time.Second
+		}
+	}
+	if v := os.Getenv("REQUEST_TIMEOUT_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.RequestTimeout = time.Duration(secs) * time.Second
+		}
+	}
+	if v := os.Getenv("SCAN_TIMEOUT_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.ScanTimeout = time.Duration(secs) * time.Second
+		}
+	}
+	if v := os.Getenv("RAW_MAX_PAGES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.RawMaxPages = n
+		}
+	}
+	if v := os.Getenv("JOB_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.JobWorkers = n
+		}
+	}
+	if v := os.Getenv("JOB_TIMEOUT_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.JobTimeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "TCP port to listen on (env PORT)")
+	fs.StringVar(&cfg.BindAddr, "addr", cfg.BindAddr, "interface or host:port to bind; 0.0.0.0 for all interfaces (env BIND_ADDR)")
+	fs.StringVar(&cfg.SocketPath, "socket", cfg.SocketPath, "listen on a Unix domain socket at this path instead of TCP (env UNIX_SOCKET)")
+	fs.StringVar(&cfg.TLSCert, "tls-cert", cfg.TLSCert, "PEM certificate to serve HTTPS with (env TLS_CERT)")
+	fs.StringVar(&cfg.TLSKey, "tls-key", cfg.TLSKey, "PEM private key for -tls-cert (env TLS_KEY)")
+	fs.BoolVar(&cfg.TLSSelfSigned, "tls-self-signed", cfg.TLSSelfSigned, "serve HTTPS with a self-signed certificate generated on first start (env TLS_SELF_SIGNED)")
+	fs.BoolVar(&cfg.Auth, "auth", cfg.Auth, "require an API token on /api routes (env API_AUTH)")
+	fs.StringVar(&cfg.AuthToken, "api-token", cfg.AuthToken, "static API token; implies -auth (env API_TOKEN)")
+	fs.StringVar(&cfg.AuthTokenPath, "api-token-file", cfg.AuthTokenPath, "where the generated API token is kept (env API_TOKEN_PATH)")
+	fs.StringVar(&cfg.StaticDir, "static-dir", cfg.StaticDir, "directory with the built frontend (env STATIC_DIR)")
+	fs.StringVar(&cfg.CommandConfigPath, "command-config", cfg.CommandConfigPath, "path to the command config file, JSON or YAML (env COMMAND_CONFIG_PATH)")
+	fs.BoolVar(&cfg.GraphQL, "graphql", cfg.GraphQL, "serve the GraphQL API at /api/graphql (env GRAPHQL)")
+	fs.BoolVar(&cfg.CommandIAMCheck, "command-iam-check", cfg.CommandIAMCheck, "only run commands IAM simulation allows as reads for the active profile (env COMMAND_IAM_CHECK)")
+	fs.BoolVar(&cfg.CommandPermissionCheck, "command-permission-check", cfg.CommandPermissionCheck, "check with IAM simulation that the active profile may run a command's action before running it (env COMMAND_PERMISSION_CHECK)")
+	fs.IntVar(&cfg.RawMaxPages, "raw-max-pages", cfg.RawMaxPages, "follow next-page tokens in raw command output for at most this many pages; 1 disables (env RAW_MAX_PAGES)")
+	fs.BoolVar(&cfg.MutatingCommands, "mutating-commands", cfg.MutatingCommands, "allow stop-instances and release-address after a confirmation step (env MUTATING_COMMANDS)")
+	fs.DurationVar(&cfg.CacheTTL, "cache-ttl", cfg.CacheTTL, "cache time-to-live (env CACHE_TTL_SECONDS)")
+	fs.StringVar(&cfg.CacheDir, "cache-dir", cfg.CacheDir, "directory where cached AWS data is saved across restarts; empty disables (env CACHE_DIR)")
+	fs.DurationVar(&cfg.CacheMaxStale, "cache-max-stale", cfg.CacheMaxStale, "how long saved cache data is kept and served when AWS can't be reached (env CACHE_MAX_STALE_SECONDS)")
+	fs.StringVar(&cfg.CacheBackend, "cache-backend", cfg.CacheBackend, "where cached AWS data is kept: memory or redis (env CACHE_BACKEND)")
+	fs.StringVar(&cfg.CacheRedisURL, "cache-redis-url", cfg.CacheRedisURL, "Redis server of the redis cache backend, redis:// or rediss:// (env CACHE_REDIS_URL)")
+	fs.StringVar(&cfg.CacheRedisPrefix, "cache-redis-prefix", cfg.CacheRedisPrefix, "prefix of the redis cache backend's keys (env CACHE_REDIS_PREFIX)")
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", cfg.RequestTimeout, "cancel API requests after this long; 0 disables (env REQUEST_TIMEOUT_SECONDS)")
+	fs.DurationVar(&cfg.ScanTimeout, "scan-timeout", cfg.ScanTimeout, "cancel resource scans after this long; 0 disables (env SCAN_TIMEOUT_SECONDS)")
+	fs.IntVar(&cfg.JobWorkers, "job-workers", cfg.JobWorkers, "background jobs run at once (env JOB_WORKERS)")
+	fs.DurationVar(&cfg.JobTimeout, "job-timeout", cfg.JobTimeout, "cancel background jobs after this long; 0 disables (env JOB_TIMEOUT_SECONDS)")
+
+	fs.StringVar(&cfg.DemoMode, "demo-mode", cfg.DemoMode, "record or replay AWS CLI fixtures (env DEMO_MODE)")
+	fs.StringVar(&cfg.FixtureDir, "fixture-dir", cfg.FixtureDir, "directory for recorded fixtures (env FIXTURE_DIR)")
+
+	fs.StringVar(&cfg.Locale, "locale", cfg.Locale, "default display locale, e.g. en-IN or de-DE (env LOCALE)")
+	fs.StringVar(&trustedOrigins, "trusted-origins", trustedOrigins, "comma-separated origins, besides the server's own, allowed to make state-changing requests (env TRUSTED_ORIGINS)")
+	fs.StringVar(&eventSinks, "event-sinks", eventSinks, "comma-separated event sinks: log, webhook, websocket (env EVENT_SINKS)")
+	fs.StringVar(&cfg.EventWebhookURL, "event-webhook", cfg.EventWebhookURL, "URL that receives events when the webhook sink is enabled (env EVENT_WEBHOOK_URL)")
+	fs.StringVar(&cfg.DisplayCurrency, "display-currency", cfg.DisplayCurrency, "also report costs converted to this currency, e.g. EUR (env DISPLAY_CURRENCY)")
+	fs.StringVar(&exchangeRates, "exchange-rates", exchangeRates, "static rates per 1 USD, e.g. EUR=0.92,GBP=0.79 (env EXCHANGE_RATES)")
+	fs.StringVar(&cfg.ExchangeRatesURL, "exchange-rates-url", cfg.ExchangeRatesURL, "exchange-rate provider URL (env EXCHANGE_RATES_URL)")
+	fs.StringVar(&cfg.AlertStorePath, "alert-store", cfg.AlertStorePath, "file where cost alert rules are saved (env ALERT_STORE_PATH)")
+	fs.StringVar(&cfg.FavoritesStorePath, "favorites-store", cfg.FavoritesStorePath, "file where pinned and saved commands are kept (env FAVORITES_STORE_PATH)")
+	fs.StringVar(&cfg.CostHistoryPath, "cost-history", cfg.CostHistoryPath, "file where fetched costs are kept; empty disables (env COST_HISTORY_PATH)")
+	fs.StringVar(&cfg.AuditLogPath, "audit-log", cfg.AuditLogPath, "append-only log of profile changes, cache clears and commands; empty disables (env AUDIT_LOG_PATH)")
+	fs.BoolVar(&cfg.TUI, "tui", false, "run the terminal UI instead of the HTTP server")
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	for _, origin := range strings.Split(trustedOrigins, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return Config{}, fmt.Errorf("invalid trusted origin %q: want scheme://host[:port]", origin)
+		}
+		cfg.TrustedOrigins = append(cfg.TrustedOrigins, origin)
+	}
+
+	for _, sink := range strings.Split(eventSinks, ",") {
+		sink = strings.TrimSpace(sink)
+		switch sink {
+		case "":
+			continue
+		case "log", "websocket":
+		case "webhook":
+			if cfg.EventWebhookURL == "" {
+				return Config{}, fmt.Errorf("the webhook event sink requires EVENT_WEBHOOK_URL")
+			}
+		default:
+			return Config{}, fmt.Errorf("unknown event sink %q", sink)
+		}
+		cfg.EventSinks = append(cfg.EventSinks, sink)
+	}
+
+	if exchangeRates != "" {
+		rates, err := currency.ParseRates(exchangeRates)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.ExchangeRates = rates
+	}
+	cfg.DisplayCurrency = strings.ToUpper(strings.TrimSpace(cfg.DisplayCurrency))
+	if cfg.DisplayCurrency != "" && cfg.DisplayCurrency != "USD" && cfg.ExchangeRatesURL == "" {
+		if _, ok := cfg.ExchangeRates[cfg.DisplayCurrency]; !ok {
+			return Config{}, fmt.Errorf("display currency %s needs a rate in EXCHANGE_RATES or an EXCHANGE_RATES_URL", cfg.DisplayCurrency)
+		}
+	}
+
+	if cfg.AuthToken != "" {
+		cfg.Auth = true
+	}
+
+	if cfg.TLSSelfSigned {
+		if cfg.TLSCert == "" {
+			cfg.TLSCert = "./.aws-local-dashboard-tls-cert.pem"
+		}
+		if cfg.TLSKey == "" {
+			cfg.TLSKey = "./.aws-local-dashboard-tls-key.pem"
+		}
+	}
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return Config{}, fmt.Errorf("TLS needs both a certificate and a key")
+	}
+
+	switch cfg.CacheBackend {
+	case "memory":
+	case "redis":
+		if cfg.CacheDir != "" {
+			return Config{}, fmt.Errorf("CACHE_DIR can't be used with the redis cache backend, which keeps the data in Redis")
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown cache backend %q (expected memory or redis)", cfg.CacheBackend)
+	}
+
+	switch cfg.DemoMode {
+	case "", "record", "replay":
+	default:
+		return Config{}, fmt.Errorf("unknown demo mode %q (expected record or replay)", cfg.DemoMode)
+	}
+
+	return cfg, nil
+}
+
+// Describe returns a human-readable description of where the server listens.
+func (c Config) Describe() string {
+	if c.SocketPath != "" {
+		return fmt.Sprintf("unix:%s", c.SocketPath)
+	}
+	if c.TLSCert != "" {
+		return "https://" + c.ListenAddr()
+	}
+	return c.ListenAddr()
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
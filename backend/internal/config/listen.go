@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// Listen opens the listener described by the config: a Unix domain socket when
+// SocketPath is set, otherwise TCP on ListenAddr.
+func (c Config) Listen() (net.Listener, error) {
+	if c.SocketPath == "" {
+		return net.Listen("tcp", c.ListenAddr())
+	}
+
+	// Remove a stale socket left behind by a previous run, but never clobber
+	// a regular file that happens to live at the configured path.
+	if info, err := os.Lstat(c.SocketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("socket path %s exists and is not a socket", c.SocketPath)
+		}
+		if err := os.Remove(c.SocketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", c.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API wields real AWS credentials, so restrict the socket to the
+	// owning user.
+	if err := os.Chmod(c.SocketPath, 0o600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// ListenAddr returns the TCP address to listen on, combining BindAddr and Port.
+func (c Config) ListenAddr() string {
+	if c.BindAddr == "" {
+		return ":" + c.Port
+	}
+	// A BindAddr that already carries a port wins over Port.
+	if _, _, err := net.SplitHostPort(c.BindAddr); err == nil {
+		return c.BindAddr
+	}
+	return net.JoinHostPort(c.BindAddr, c.Port)
+}
+
+// Exposed reports whether the server listens beyond the local machine: on a
+// TCP address other than loopback.
+func (c Config) Exposed() bool {
+	if c.SocketPath != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(c.ListenAddr())
+	if err != nil {
+		return true
+	}
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
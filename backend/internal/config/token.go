@@ -0,0 +1,44 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIToken returns the token required on /api routes, or "" when Auth is
+// off. Without a static AuthToken it reads AuthTokenPath, generating a random
+// token there on first run; generated reports whether that happened, so the
+// caller can show it to the user.
+func (c Config) APIToken() (token string, generated bool, err error) {
+	if !c.Auth {
+		return "", false, nil
+	}
+	if c.AuthToken != "" {
+		return c.AuthToken, false, nil
+	}
+	if c.AuthTokenPath == "" {
+		return "", false, fmt.Errorf("API auth needs API_TOKEN or API_TOKEN_PATH")
+	}
+
+	data, err := os.ReadFile(c.AuthTokenPath)
+	if err == nil {
+		if token = strings.TrimSpace(string(data)); token != "" {
+			return token, false, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("failed to read API token: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	if err := os.WriteFile(c.AuthTokenPath, []byte(token+"\n"), 0o600); err != nil {
+		return "", false, fmt.Errorf("failed to save API token: %w", err)
+	}
+	return token, true, nil
+}
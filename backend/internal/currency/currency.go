@@ -0,0 +1,163 @@
+// Package currency converts cost amounts into a configured display currency.
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providerRefresh is how long rates fetched from a provider are reused.
+const providerRefresh = 12 * time.Hour
+
+// Converter converts amounts to a single display currency. Rates are units of
+// a currency per 1 USD; static rates from config act as the fallback when a
+// provider is configured but unreachable.
+type Converter struct {
+	target      string
+	static      map[string]float64
+	providerURL string
+	client      *http.Client
+
+	mu        sync.Mutex
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+// NewConverter creates a Converter for target (an ISO 4217 code). static may
+// be nil; providerURL may be empty to use static rates only. A provider must
+// return JSON of the form {"base": "USD", "rates": {"EUR": 0.92, ...}}.
+func NewConverter(target string, static map[string]float64, providerURL string) *Converter {
+	rates := map[string]float64{"USD": 1}
+	for code, rate := range static {
+		rates[strings.ToUpper(code)] = rate
+	}
+	return &Converter{
+		target:      strings.ToUpper(strings.TrimSpace(target)),
+		static:      rates,
+		providerURL: providerURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Target returns the display currency code.
+func (c *Converter) Target() string { return c.target }
+
+// Rate returns how many units of the display currency one unit of from is
+// worth.
+func (c *Converter) Rate(ctx context.Context, from string) (float64, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	if from == "" || from == c.target {
+		return 1, nil
+	}
+
+	rates := c.currentRates(ctx)
+	src, ok1 := rates[from]
+	dst, ok2 := rates[c.target]
+	if !ok1 || !ok2 || src <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s to %s", from, c.target)
+	}
+	return dst / src, nil
+}
+
+func (c *Converter) currentRates(ctx context.Context) map[string]float64 {
+	if c.providerURL == "" {
+		return c.static
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && time.Since(c.fetchedAt) < providerRefresh {
+		return c.rates
+	}
+
+	rates, err := c.fetch(ctx)
+	if err != nil {
+		log.Printf("currency: failed to fetch exchange rates, using configured rates: %v", err)
+		// Back off so a down provider is not hit on every request.
+		if c.rates == nil {
+			c.rates = c.static
+		}
+		c.fetchedAt = time.Now().Add(-providerRefresh + time.Minute)
+		return c.rates
+	}
+
+	c.rates = rates
+	c.fetchedAt = time.Now()
+	return c.rates
+}
+
+func (c *Converter) fetch(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.providerURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("provider returned %s", resp.Status)
+	}
+
+	var body struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse provider response: %w", err)
+	}
+
+	rates := make(map[string]float64, len(c.static)+len(body.Rates))
+	for code, rate := range c.static {
+		rates[code] = rate
+	}
+
+	// Rebase to USD if the provider quotes against another currency.
+	scale := 1.0
+	if base := strings.ToUpper(body.Base); base != "" && base != "USD" {
+		usd, ok := body.Rates["USD"]
+		if !ok || usd <= 0 {
+			return nil, fmt.Errorf("provider rates are based on %s and do not include USD", base)
+		}
+		scale = 1 / usd
+	}
+	for code, rate := range body.Rates {
+		rates[strings.ToUpper(code)] = rate * scale
+	}
+	rates["USD"] = 1
+	return rates, nil
+}
+
+// ParseRates parses "EUR=0.92,GBP=0.79" into a rate map.
+func ParseRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid exchange rate %q (expected CODE=rate)", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %q", code, value)
+		}
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates, nil
+}
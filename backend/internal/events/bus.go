@@ -0,0 +1,161 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of event.
+type Type string
+
+const (
+	ProfileAdded    Type = "profile.added"
+	ProfileSwitched Type = "profile.switched"
+	ProfileRenamed  Type = "profile.renamed"
+	ProfileDeleted  Type = "profile.deleted"
+	ProfileUpdated  Type = "profile.updated"
+	CacheCleared    Type = "cache.cleared"
+	ScanCompleted   Type = "scan.completed"
+	CommandExecuted Type = "command.executed"
+	AlertFired      Type = "alert.fired"
+	JobFinished     Type = "job.finished"
+)
+
+// Event is a structured notification emitted by the server.
+type Event struct {
+	ID   string         `json:"id"`
+	Type Type           `json:"type"`
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Sink receives events asynchronously. Implementations must be safe for use
+// from a single dispatch goroutine; they are never called concurrently.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, e Event) error
+}
+
+// sinkQueueSize bounds how many events may queue for a slow sink before new
+// events are dropped for it.
+const sinkQueueSize = 256
+
+// sinkTimeout bounds how long a single Handle call may take.
+const sinkTimeout = 10 * time.Second
+
+type sinkWorker struct {
+	sink  Sink
+	queue chan Event
+}
+
+// Bus fans events out to in-process subscribers and external sinks.
+//
+// Subscribers run synchronously inside Publish, so state changes they make
+// (such as clearing caches) are complete when Publish returns. Sinks run on
+// their own goroutine each, so a slow webhook never blocks a request.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[Type][]func(Event)
+	workers     []*sinkWorker
+	wg          sync.WaitGroup
+	closed      bool
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[Type][]func(Event)),
+	}
+}
+
+// Subscribe registers fn to run synchronously for every event of type t.
+func (b *Bus) Subscribe(t Type, fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[t] = append(b.subscribers[t], fn)
+}
+
+// AddSink registers a sink that receives every event.
+func (b *Bus) AddSink(s Sink) {
+	w := &sinkWorker{sink: s, queue: make(chan Event, sinkQueueSize)}
+
+	b.mu.Lock()
+	b.workers = append(b.workers, w)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for e := range w.queue {
+			ctx, cancel := context.WithTimeout(context.Background(), sinkTimeout)
+			if err := w.sink.Handle(ctx, e); err != nil {
+				log.Printf("events: sink %s failed for %s: %v", w.sink.Name(), e.Type, err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// Publish emits an event. It is safe to call on a nil Bus, which makes event
+// emission optional for callers.
+func (b *Bus) Publish(t Type, data map[string]any) {
+	if b == nil {
+		return
+	}
+
+	e := Event{
+		ID:   newID(),
+		Type: t,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+
+	b.mu.RLock()
+	subs := append([]func(Event){}, b.subscribers[t]...)
+	b.mu.RUnlock()
+
+	// Subscribers run without the lock held so they may publish in turn.
+	for _, fn := range subs {
+		fn(e)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+	for _, w := range b.workers {
+		select {
+		case w.queue <- e:
+		default:
+			log.Printf("events: sink %s is backed up; dropping %s", w.sink.Name(), e.Type)
+		}
+	}
+}
+
+// Close stops accepting events and waits for sinks to drain their queues.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, w := range b.workers {
+		close(w.queue)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+func newID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,688 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Field is a single selected field in a query document.
+type Field struct {
+	Alias     string
+	Name      string
+	Arguments map[string]Value
+	Selection []*Field
+
+	// spread marks the placeholder a "...Name" spread leaves in a selection
+	// set until Parse replaces it with the fragment's fields.
+	spread *spread
+}
+
+type spread struct {
+	name      string
+	line, col int
+}
+
+// ResponseKey is the key the field's value is returned under.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Value is an argument value that may reference a variable.
+type Value struct {
+	Variable string
+	Literal  any
+}
+
+// Operation is a parsed query operation.
+type Operation struct {
+	Name      string
+	Selection []*Field
+}
+
+// Parse parses a GraphQL document and returns its operations. Only query
+// operations are supported; mutations, subscriptions and directives are
+// rejected with a descriptive error. Fragment spreads and inline fragments
+// are expanded in place, so operations hold plain fields.
+func Parse(src string) ([]*Operation, error) {
+	p := &parser{lex: newLexer(src), fragments: map[string][]*Field{}, expanded: map[string][]*Field{}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var ops []*Operation
+	for p.tok.kind != tokEOF {
+		if p.tok.kind == tokName && p.tok.value == "fragment" {
+			if err := p.parseFragment(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("document contains no operations")
+	}
+	for _, op := range ops {
+		sel, err := p.expand(op.Selection, nil)
+		if err != nil {
+			return nil, err
+		}
+		op.Selection = sel
+	}
+	return ops, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+
+	fragments map[string][]*Field
+	// expanded caches each fragment's expansion, so fragments spreading
+	// each other several times don't blow up exponentially.
+	expanded map[string][]*Field
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("syntax error at line %d, column %d: %s", p.tok.line, p.tok.col, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expectPunct(s string) error {
+	if p.tok.kind != tokPunct || p.tok.value != s {
+		return p.errorf("expected %q, found %s", s, p.tok.describe())
+	}
+	return p.advance()
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokPunct && p.tok.value == s
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.errorf("expected name, found %s", p.tok.describe())
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{}
+
+	if p.tok.kind == tokName {
+		switch p.tok.value {
+		case "query":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case "mutation", "subscription":
+			return nil, p.errorf("%s operations are not supported; the API is read-only", p.tok.value)
+		default:
+			return nil, p.errorf("unexpected %s", p.tok.describe())
+		}
+
+		if p.tok.kind == tokName {
+			op.Name = p.tok.value
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if p.isPunct("(") {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selection = sel
+	return op, nil
+}
+
+// skipVariableDefinitions consumes "($a: Type!, $b: [Type] = default)".
+// Variable types are not enforced; values are taken as supplied.
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for {
+		if p.tok.kind == tokEOF {
+			return p.errorf("unterminated variable definitions")
+		}
+		if p.isPunct("(") {
+			depth++
+		} else if p.isPunct(")") {
+			depth--
+			if depth == 0 {
+				return p.advance()
+			}
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseSelectionSet() ([]*Field, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []*Field
+	for !p.isPunct("}") {
+		if p.tok.kind == tokEOF {
+			return nil, p.errorf("unterminated selection set")
+		}
+		if p.isPunct("...") {
+			sub, err := p.parseSpread()
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, sub...)
+			continue
+		}
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, p.errorf("selection set must not be empty")
+	}
+	return fields, p.advance()
+}
+
+func (p *parser) parseField() (*Field, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Field{Name: name}
+	if p.isPunct(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		f.Alias = name
+		if f.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if f.Arguments, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isPunct("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+	if p.isPunct("{") {
+		if f.Selection, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseFragment parses "fragment Name on Type { ... }". Type conditions
+// aren't checked: every schema type is a plain object type, so the fields
+// are validated against the type of the field the fragment is spread in.
+func (p *parser) parseFragment() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == tokName {
+		if _, ok := p.fragments[p.tok.value]; ok {
+			return p.errorf("fragment %q is defined twice", p.tok.value)
+		}
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+	if err := p.parseTypeCondition(); err != nil {
+		return err
+	}
+	if p.isPunct("@") {
+		return p.errorf("directives are not supported")
+	}
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return err
+	}
+	p.fragments[name] = sel
+	return nil
+}
+
+func (p *parser) parseTypeCondition() error {
+	if p.tok.kind != tokName || p.tok.value != "on" {
+		return p.errorf("expected \"on\", found %s", p.tok.describe())
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	_, err := p.expectName()
+	return err
+}
+
+// parseSpread parses "...Name", returning a placeholder for Parse to
+// expand once every fragment is known, or an inline fragment
+// "... on Type { ... }", returning its fields.
+func (p *parser) parseSpread() ([]*Field, error) {
+	line, col := p.tok.line, p.tok.col
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokName && p.tok.value != "on" {
+		name := p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.isPunct("@") {
+			return nil, p.errorf("directives are not supported")
+		}
+		return []*Field{{spread: &spread{name: name, line: line, col: col}}}, nil
+	}
+	if p.tok.kind == tokName {
+		if err := p.parseTypeCondition(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isPunct("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+	return p.parseSelectionSet()
+}
+
+// expand replaces the fragment spreads in fields, at any depth, with the
+// fragments' fields, and merges fields selected more than once under the
+// same response key, as spreads often repeat fields selected beside them.
+// active lists the fragments being expanded, to catch cycles.
+func (p *parser) expand(fields []*Field, active []string) ([]*Field, error) {
+	var out []*Field
+	byKey := map[string]*Field{}
+	add := func(f *Field) error {
+		prev, ok := byKey[f.ResponseKey()]
+		if !ok {
+			byKey[f.ResponseKey()] = f
+			out = append(out, f)
+			return nil
+		}
+		if prev.Name != f.Name || !reflect.DeepEqual(prev.Arguments, f.Arguments) {
+			return fmt.Errorf("%q is selected for different fields or arguments", f.ResponseKey())
+		}
+		if len(f.Selection) > 0 {
+			sel, err := p.expand(append(prev.Selection[:len(prev.Selection):len(prev.Selection)], f.Selection...), active)
+			if err != nil {
+				return err
+			}
+			prev.Selection = sel
+		}
+		return nil
+	}
+
+	for _, f := range fields {
+		if f.spread == nil {
+			c := *f
+			if len(f.Selection) > 0 {
+				sel, err := p.expand(f.Selection, active)
+				if err != nil {
+					return nil, err
+				}
+				c.Selection = sel
+			}
+			if err := add(&c); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		s := f.spread
+		sel, ok := p.expanded[s.name]
+		if !ok {
+			def, ok := p.fragments[s.name]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q at line %d, column %d", s.name, s.line, s.col)
+			}
+			if slices.Contains(active, s.name) {
+				return nil, fmt.Errorf("fragment %q spreads itself at line %d, column %d", s.name, s.line, s.col)
+			}
+			var err error
+			if sel, err = p.expand(def, append(active[:len(active):len(active)], s.name)); err != nil {
+				return nil, err
+			}
+			p.expanded[s.name] = sel
+		}
+		for _, sub := range sel {
+			c := *sub
+			if err := add(&c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+func (p *parser) parseArguments() (map[string]Value, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	args := map[string]Value{}
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = v
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseValue() (Value, error) {
+	if p.isPunct("$") {
+		if err := p.advance(); err != nil {
+			return Value{}, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Variable: name}, nil
+	}
+
+	lit, err := p.parseLiteral()
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{Literal: lit}, nil
+}
+
+func (p *parser) parseLiteral() (any, error) {
+	t := p.tok
+	switch t.kind {
+	case tokString:
+		return t.value, p.advance()
+	case tokInt:
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %s", t.value)
+		}
+		return n, p.advance()
+	case tokFloat:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", t.value)
+		}
+		return f, p.advance()
+	case tokName:
+		var v any
+		switch t.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			// Enum values are passed to resolvers as plain strings.
+			v = t.value
+		}
+		return v, p.advance()
+	case tokPunct:
+		switch t.value {
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.isPunct("]") {
+				v, err := p.parseLiteral()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			obj := map[string]any{}
+			for !p.isPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseLiteral()
+				if err != nil {
+					return nil, err
+				}
+				obj[name] = v
+			}
+			return obj, p.advance()
+		}
+	}
+	return nil, p.errorf("unexpected %s in value", t.describe())
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind      tokenKind
+	value     string
+	line, col int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of document"
+	case tokString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	line, col int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) peek() byte {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) bump() byte {
+	c := l.src[l.pos]
+	l.pos++
+	if c == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return c
+}
+
+func (l *lexer) next() (token, error) {
+	// Skip whitespace, commas (insignificant in GraphQL) and comments.
+	for l.pos < len(l.src) {
+		c := l.peek()
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.bump()
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(l.src) && l.peek() != '\n' {
+				l.bump()
+			}
+			continue
+		}
+		break
+	}
+
+	t := token{line: l.line, col: l.col}
+	if l.pos >= len(l.src) {
+		t.kind = tokEOF
+		return t, nil
+	}
+
+	c := l.peek()
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.bump()
+		l.bump()
+		l.bump()
+		t.kind, t.value = tokPunct, "..."
+		return t, nil
+	case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+		l.bump()
+		t.kind, t.value = tokPunct, string(c)
+		return t, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.peek() == '_' || isLetter(l.peek()) || isDigit(l.peek())) {
+			l.bump()
+		}
+		t.kind, t.value = tokName, l.src[start:l.pos]
+		return t, nil
+	case c == '-' || isDigit(c):
+		start := l.pos
+		t.kind = tokInt
+		l.bump()
+		for l.pos < len(l.src) {
+			d := l.peek()
+			if isDigit(d) {
+				l.bump()
+				continue
+			}
+			if d == '.' || d == 'e' || d == 'E' || ((d == '+' || d == '-') && t.kind == tokFloat) {
+				t.kind = tokFloat
+				l.bump()
+				continue
+			}
+			break
+		}
+		t.value = l.src[start:l.pos]
+		return t, nil
+	case c == '"':
+		s, err := l.readString()
+		if err != nil {
+			return token{}, err
+		}
+		t.kind, t.value = tokString, s
+		return t, nil
+	}
+
+	return token{}, fmt.Errorf("syntax error at line %d, column %d: unexpected character %q", l.line, l.col, c)
+}
+
+func (l *lexer) readString() (string, error) {
+	line, col := l.line, l.col
+	l.bump() // opening quote
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.bump()
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\n':
+			return "", fmt.Errorf("syntax error at line %d, column %d: unterminated string", line, col)
+		case '\\':
+			if l.pos >= len(l.src) {
+				break
+			}
+			e := l.bump()
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return "", fmt.Errorf("syntax error at line %d, column %d: invalid unicode escape", l.line, l.col)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("syntax error at line %d, column %d: invalid unicode escape", l.line, l.col)
+				}
+				for i := 0; i < 4; i++ {
+					l.bump()
+				}
+				b.WriteRune(rune(code))
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("syntax error at line %d, column %d: unterminated string", line, col)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,183 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []*Operation
+	}{
+		{
+			name:  "shorthand query",
+			query: `{ profiles { name region } }`,
+			want: []*Operation{{Selection: []*Field{
+				{Name: "profiles", Selection: []*Field{{Name: "name"}, {Name: "region"}}},
+			}}},
+		},
+		{
+			name: "nested selections, arguments and aliases",
+			query: `query Costs {
+				ec2: resources(service: "ec2", region: "all") {
+					ec2Instances { instanceId tags }
+				}
+				# comments and commas are ignored
+				costOverview(start: "2024-01-01", limit: 5, ratio: 1.5e2, flag: true, none: null, kind: DAILY,
+					list: [1, "two", [3]], object: {a: {b: false}}) { netTotal, currency }
+			}`,
+			want: []*Operation{{Name: "Costs", Selection: []*Field{
+				{Alias: "ec2", Name: "resources",
+					Arguments: map[string]Value{"service": {Literal: "ec2"}, "region": {Literal: "all"}},
+					Selection: []*Field{{Name: "ec2Instances", Selection: []*Field{{Name: "instanceId"}, {Name: "tags"}}}}},
+				{Name: "costOverview",
+					Arguments: map[string]Value{
+						"start":  {Literal: "2024-01-01"},
+						"limit":  {Literal: int64(5)},
+						"ratio":  {Literal: 150.0},
+						"flag":   {Literal: true},
+						"none":   {},
+						"kind":   {Literal: "DAILY"},
+						"list":   {Literal: []any{int64(1), "two", []any{int64(3)}}},
+						"object": {Literal: map[string]any{"a": map[string]any{"b": false}}},
+					},
+					Selection: []*Field{{Name: "netTotal"}, {Name: "currency"}}},
+			}}},
+		},
+		{
+			name:  "variables",
+			query: `query Resources($service: String!, $regions: [String] = ["all"]) { resources(service: $service, region: "all") { count } }`,
+			want: []*Operation{{Name: "Resources", Selection: []*Field{
+				{Name: "resources",
+					Arguments: map[string]Value{"service": {Variable: "service"}, "region": {Literal: "all"}},
+					Selection: []*Field{{Name: "count"}}},
+			}}},
+		},
+		{
+			name:  "escaped strings",
+			query: `{ f(s: "a\"b\\c\né") }`,
+			want: []*Operation{{Selection: []*Field{
+				{Name: "f", Arguments: map[string]Value{"s": {Literal: "a\"b\\c\né"}}},
+			}}},
+		},
+		{
+			name: "named and inline fragments",
+			query: `
+				fragment Instance on EC2Instance { instanceId ...State }
+				query {
+					resources(service: "ec2") { ec2Instances { ...Instance } ... on ResourceList { count } }
+				}
+				fragment State on EC2Instance { state region }`,
+			want: []*Operation{{Selection: []*Field{
+				{Name: "resources", Arguments: map[string]Value{"service": {Literal: "ec2"}}, Selection: []*Field{
+					{Name: "ec2Instances", Selection: []*Field{{Name: "instanceId"}, {Name: "state"}, {Name: "region"}}},
+					{Name: "count"},
+				}},
+			}}},
+		},
+		{
+			name: "fragments merge with repeated fields",
+			query: `{
+				resources { ec2Instances { instanceId } ...Rest count }
+				... { resources { ec2Instances { state } } }
+			}
+			fragment Rest on ResourceList { count ec2Instances { instanceId region } }`,
+			want: []*Operation{{Selection: []*Field{
+				{Name: "resources", Selection: []*Field{
+					{Name: "ec2Instances", Selection: []*Field{{Name: "instanceId"}, {Name: "region"}, {Name: "state"}}},
+					{Name: "count"},
+				}},
+			}}},
+		},
+		{
+			name:  "several operations",
+			query: `query A { profiles { name } } query B { commands { id } }`,
+			want: []*Operation{
+				{Name: "A", Selection: []*Field{{Name: "profiles", Selection: []*Field{{Name: "name"}}}}},
+				{Name: "B", Selection: []*Field{{Name: "commands", Selection: []*Field{{Name: "id"}}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tt.want)
+				t.Errorf("Parse() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{``, "document contains no operations"},
+		{`{`, "line 1, column 2: unterminated selection set"},
+		{`{ a { b }`, "line 1, column 10: unterminated selection set"},
+		{`{ }`, "selection set must not be empty"},
+		{"{\n  a(x: ) }", `line 2, column 8: unexpected ")" in value`},
+		{`{ a(x 1) }`, `expected ":", found "1"`},
+		{`{ a(x: 1 }`, `expected name, found "}"`},
+		{`{ a(x: [1, 2) }`, `unexpected ")" in value`},
+		{`{ a(x: {b 1}) }`, `expected ":", found "1"`},
+		{`{ a(x: $) }`, `expected name, found ")"`},
+		{`{ a(x: 99999999999999999999) }`, "invalid integer 99999999999999999999"},
+		{`{ a(x: 1.2.3) }`, "invalid float 1.2.3"},
+		{`{ a(x: "abc) }`, "line 1, column 8: unterminated string"},
+		{"{ a(x: \"a\nb\") }", "unterminated string"},
+		{`{ a(x: "\u12") }`, "invalid unicode escape"},
+		{`{ alias: }`, `expected name, found "}"`},
+		{`{ a } }`, `line 1, column 7: expected "{", found "}"`},
+		{`{ a ^ }`, `unexpected character '^'`},
+		{`query ($a: Int { a }`, "unterminated variable definitions"},
+		{`mutation { a }`, "mutation operations are not supported"},
+		{`subscription { a }`, "subscription operations are not supported"},
+		{`{ a @include(if: true) }`, "directives are not supported"},
+		{`fragment F on T { a }`, "document contains no operations"},
+		{`{ ...F }`, `unknown fragment "F" at line 1, column 3`},
+		{`{ ...F } fragment F on T { a ...G } fragment G on T { ...F }`, `fragment "F" spreads itself`},
+		{`{ ...F } fragment F on T { a } fragment F on T { b }`, `fragment "F" is defined twice`},
+		{`{ ...F } fragment F { a }`, `expected "on", found "{"`},
+		{`{ ... on { a } }`, `expected name, found "{"`},
+		{`{ ...F @skip(if: true) } fragment F on T { a }`, "directives are not supported"},
+		{`{ ... }`, `expected "{", found "}"`},
+		{`{ a: b a: c }`, `"a" is selected for different fields or arguments`},
+		{`{ a(x: 1) ...F } fragment F on T { a(x: 2) }`, `"a" is selected for different fields or arguments`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+// TestParseNeverPanics parses every prefix of a well-formed document, which
+// covers most ways a query can be cut short.
+func TestParseNeverPanics(t *testing.T) {
+	doc := `query Q($s: String = "xé", $n: [Int!]!) {
+		a: resources(service: $s, list: [1, -2.5e3, {k: null}]) { ...F ... on T { b { c } } }
+	}
+	fragment F on T { d e(f: "g\n") }`
+	for i := 0; i <= len(doc); i++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("Parse(%q) panicked: %v", doc[:i], r)
+				}
+			}()
+			Parse(doc[:i])
+		}()
+	}
+}
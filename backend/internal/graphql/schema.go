@@ -0,0 +1,370 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Resolver produces the value of a root query field. The returned value is
+// converted to its JSON form and then narrowed to the requested selection.
+type Resolver func(ctx context.Context, args map[string]any) (any, error)
+
+// RootField is a field of the Query type.
+type RootField struct {
+	// Args are the field's arguments, all of type String.
+	Args []Argument
+	// Returns is a value of the Go type Resolve returns, e.g.
+	// types.CostOverview{}; the field's GraphQL type is derived from it.
+	Returns any
+	Resolve Resolver
+}
+
+// Argument is an argument of a root field.
+type Argument struct {
+	Name     string
+	Required bool
+}
+
+// Schema is the set of root query fields and the types they return.
+type Schema struct {
+	Query map[string]RootField
+	// ErrorCode, when set, maps a resolver error to a code reported as the
+	// error's extensions.code.
+	ErrorCode func(error) string
+
+	types   *typeSet
+	returns map[string]*typeRef
+}
+
+// NewSchema builds a schema over the given root fields, deriving object types
+// from the JSON encoding of their Returns values.
+func NewSchema(query map[string]RootField) *Schema {
+	s := &Schema{Query: query, types: newTypeSet(), returns: make(map[string]*typeRef, len(query))}
+	for name, f := range query {
+		ref := s.types.ref(reflect.TypeOf(f.Returns), exportName(name))
+		// A failing resolver yields null.
+		ref.nonNull = false
+		s.returns[name] = ref
+	}
+	return s
+}
+
+// Request is the standard GraphQL-over-HTTP request body.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Error is a GraphQL error entry.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Response is the standard GraphQL response body.
+type Response struct {
+	Data   *Object `json:"data"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Object is a JSON object that preserves the order of the query's selection,
+// as the GraphQL spec requires for response keys.
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+func newObject(size int) *Object {
+	return &Object{keys: make([]string, 0, size), values: make(map[string]any, size)}
+}
+
+// Set adds or replaces a key, keeping its first position.
+func (o *Object) Set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// MarshalJSON encodes the object with keys in insertion order.
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// resolverError reports a failed resolver, with its code if ErrorCode gives
+// one.
+func (s *Schema) resolverError(err error, path []any) Error {
+	e := Error{Message: err.Error(), Path: path}
+	if s.ErrorCode != nil {
+		if code := s.ErrorCode(err); code != "" {
+			e.Extensions = map[string]any{"code": code}
+		}
+	}
+	return e
+}
+
+// Execute parses, validates and runs req against the schema. Root fields are
+// resolved concurrently; a failing field is returned as null with an error
+// entry rather than failing the whole query. A query that doesn't match the
+// schema fails as a whole, before anything is resolved.
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	ops, err := Parse(req.Query)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	op, err := selectOperation(ops, req.OperationName)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	if errs := s.validate(op, req.Variables); len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	type result struct {
+		key   string
+		value any
+		errs  []Error
+	}
+
+	results := make([]result, len(op.Selection))
+	var wg sync.WaitGroup
+
+	for i, f := range op.Selection {
+		results[i].key = f.ResponseKey()
+
+		if f.Name == "__typename" {
+			results[i].value = "Query"
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, f *Field) {
+			defer wg.Done()
+
+			path := []any{f.ResponseKey()}
+
+			raw, err := s.Query[f.Name].Resolve(ctx, resolveArguments(f.Arguments, req.Variables))
+			if err != nil {
+				results[i].errs = []Error{s.resolverError(err, path)}
+				return
+			}
+
+			generic, err := toGeneric(raw)
+			if err != nil {
+				results[i].errs = []Error{{Message: err.Error(), Path: path}}
+				return
+			}
+
+			results[i].value = project(generic, f, s.returns[f.Name])
+		}(i, f)
+	}
+	wg.Wait()
+
+	resp := Response{Data: newObject(len(results))}
+	for _, r := range results {
+		resp.Data.Set(r.key, r.value)
+		resp.Errors = append(resp.Errors, r.errs...)
+	}
+	return resp
+}
+
+// validate checks op against the schema: fields must exist on their type,
+// objects need a selection and scalars can't have one, and root field
+// arguments must be known and required ones given.
+func (s *Schema) validate(op *Operation, vars map[string]any) []Error {
+	var errs []Error
+	for _, f := range op.Selection {
+		path := []any{f.ResponseKey()}
+		if f.Name == "__typename" {
+			continue
+		}
+		root, ok := s.Query[f.Name]
+		if !ok {
+			errs = append(errs, Error{
+				Message: fmt.Sprintf("cannot query field %q on type \"Query\"", f.Name),
+				Path:    path,
+			})
+			continue
+		}
+
+		args := resolveArguments(f.Arguments, vars)
+		known := make(map[string]bool, len(root.Args))
+		for _, a := range root.Args {
+			known[a.Name] = true
+			if v, ok := args[a.Name]; a.Required && (!ok || v == nil) {
+				errs = append(errs, Error{
+					Message: fmt.Sprintf("argument %q of field %q is required", a.Name, f.Name),
+					Path:    path,
+				})
+			}
+		}
+		for name := range f.Arguments {
+			if !known[name] {
+				errs = append(errs, Error{
+					Message: fmt.Sprintf("unknown argument %q on field %q", name, f.Name),
+					Path:    path,
+				})
+			}
+		}
+
+		validateSelection(f, s.returns[f.Name], path, &errs)
+	}
+	return errs
+}
+
+func validateSelection(f *Field, t *typeRef, path []any, errs *[]Error) {
+	t = t.named()
+	if t.object == nil {
+		if len(f.Selection) > 0 {
+			*errs = append(*errs, Error{
+				Message: fmt.Sprintf("field %q is of scalar type %s and cannot have a selection", f.Name, t.scalar),
+				Path:    path,
+			})
+		}
+		return
+	}
+	if len(f.Selection) == 0 {
+		*errs = append(*errs, Error{
+			Message: fmt.Sprintf("field %q of type %s requires a selection of subfields", f.Name, t.object.name),
+			Path:    path,
+		})
+		return
+	}
+	for _, sub := range f.Selection {
+		subPath := append(append([]any{}, path...), sub.ResponseKey())
+		if len(sub.Arguments) > 0 {
+			*errs = append(*errs, Error{
+				Message: fmt.Sprintf("field %q does not accept arguments", sub.Name),
+				Path:    subPath,
+			})
+		}
+		if sub.Name == "__typename" {
+			continue
+		}
+		def, ok := t.object.byName[sub.Name]
+		if !ok {
+			*errs = append(*errs, Error{
+				Message: fmt.Sprintf("cannot query field %q on type %q", sub.Name, t.object.name),
+				Path:    subPath,
+			})
+			continue
+		}
+		validateSelection(sub, def.typ, subPath, errs)
+	}
+}
+
+// FieldNames returns the root query field names in sorted order.
+func (s *Schema) FieldNames() []string {
+	names := make([]string, 0, len(s.Query))
+	for name := range s.Query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func selectOperation(ops []*Operation, name string) (*Operation, error) {
+	if name == "" {
+		if len(ops) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains multiple operations")
+		}
+		return ops[0], nil
+	}
+	for _, op := range ops {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+func resolveArguments(args map[string]Value, vars map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for name, v := range args {
+		if v.Variable == "" {
+			out[name] = v.Literal
+			continue
+		}
+		val, ok := vars[v.Variable]
+		if !ok {
+			// An unset variable behaves like an omitted argument.
+			continue
+		}
+		out[name] = val
+	}
+	return out
+}
+
+// toGeneric converts a Go value into maps, slices and scalars via its JSON
+// encoding, so field names match the REST API exactly.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return out, nil
+}
+
+// project narrows a generic value of type t to the fields selected by f,
+// which validate has already checked against t.
+func project(v any, f *Field, t *typeRef) any {
+	if v == nil {
+		return nil
+	}
+	if items, ok := v.([]any); ok && t.elem != nil {
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = project(item, f, t.elem)
+		}
+		return out
+	}
+	obj, ok := v.(map[string]any)
+	if !ok || t.object == nil {
+		return v
+	}
+	out := newObject(len(f.Selection))
+	for _, sub := range f.Selection {
+		if sub.Name == "__typename" {
+			out.Set(sub.ResponseKey(), t.object.name)
+			continue
+		}
+		// Fields omitted from the JSON (omitempty) resolve to null.
+		out.Set(sub.ResponseKey(), project(obj[sub.Name], sub, t.object.byName[sub.Name].typ))
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Built-in scalars. JSON covers maps and other values without a fixed shape,
+// such as tags; time.Time is a String in RFC 3339 form.
+const (
+	scalarString  = "String"
+	scalarInt     = "Int"
+	scalarFloat   = "Float"
+	scalarBoolean = "Boolean"
+	scalarJSON    = "JSON"
+)
+
+// objectType is an object type derived from a Go struct.
+type objectType struct {
+	name   string
+	fields []*fieldDef
+	byName map[string]*fieldDef
+}
+
+type fieldDef struct {
+	name string
+	typ  *typeRef
+}
+
+// typeRef is a use of a type: a list, or a named scalar or object, either of
+// which may be non-null.
+type typeRef struct {
+	nonNull bool
+	elem    *typeRef // set for lists
+	scalar  string   // set for scalars
+	object  *objectType
+}
+
+// named returns the scalar or object type behind any lists.
+func (t *typeRef) named() *typeRef {
+	for t.elem != nil {
+		t = t.elem
+	}
+	return t
+}
+
+func (t *typeRef) String() string {
+	var s string
+	switch {
+	case t.elem != nil:
+		s = "[" + t.elem.String() + "]"
+	case t.object != nil:
+		s = t.object.name
+	default:
+		s = t.scalar
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawType       = reflect.TypeOf(json.RawMessage(nil))
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// typeSet derives GraphQL types from Go types through their JSON encoding,
+// so type and field names match what the resolvers return.
+type typeSet struct {
+	objects map[reflect.Type]*objectType
+	names   map[string]reflect.Type
+}
+
+func newTypeSet() *typeSet {
+	return &typeSet{objects: map[reflect.Type]*objectType{}, names: map[string]reflect.Type{}}
+}
+
+// ref returns the type of values of Go type t. hint names anonymous
+// structs.
+func (ts *typeSet) ref(t reflect.Type, hint string) *typeRef {
+	nonNull := true
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nonNull = false
+	}
+
+	switch {
+	case t == timeType:
+		return &typeRef{nonNull: nonNull, scalar: scalarString}
+	case t == rawType, t.Implements(marshalerType), reflect.PointerTo(t).Implements(marshalerType):
+		return &typeRef{scalar: scalarJSON}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &typeRef{nonNull: nonNull, scalar: scalarString}
+	case reflect.Bool:
+		return &typeRef{nonNull: nonNull, scalar: scalarBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &typeRef{nonNull: nonNull, scalar: scalarInt}
+	case reflect.Float32, reflect.Float64:
+		return &typeRef{nonNull: nonNull, scalar: scalarFloat}
+	case reflect.Slice, reflect.Array:
+		// A nil slice encodes as null, so lists are nullable.
+		return &typeRef{elem: ts.ref(t.Elem(), hint)}
+	case reflect.Struct:
+		return &typeRef{nonNull: nonNull, object: ts.object(t, hint)}
+	}
+	return &typeRef{scalar: scalarJSON}
+}
+
+// object returns the object type for struct t, deriving it on first use.
+func (ts *typeSet) object(t reflect.Type, hint string) *objectType {
+	if obj, ok := ts.objects[t]; ok {
+		return obj
+	}
+	obj := &objectType{name: ts.name(t, hint), byName: map[string]*fieldDef{}}
+	ts.objects[t] = obj
+	ts.addFields(obj, t)
+	return obj
+}
+
+// name picks a unique type name: the Go type name, prefixed with its
+// package's when two packages use the same one.
+func (ts *typeSet) name(t reflect.Type, hint string) string {
+	name := t.Name()
+	if name == "" {
+		name = hint
+	}
+	if other, taken := ts.names[name]; taken && other != t {
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = exportName(pkg) + name
+	}
+	ts.names[name] = t
+	return name
+}
+
+func (ts *typeSet) addFields(obj *objectType, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				ts.addFields(obj, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		ref := ts.ref(f.Type, obj.name+exportName(f.Name))
+		if strings.Contains(opts, "omitempty") {
+			ref.nonNull = false
+		}
+		if _, dup := obj.byName[name]; dup {
+			continue
+		}
+		def := &fieldDef{name: name, typ: ref}
+		obj.fields = append(obj.fields, def)
+		obj.byName[name] = def
+	}
+}
+
+func exportName(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// SDL returns the schema in GraphQL schema definition language.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	b.WriteString("scalar JSON\n\ntype Query {\n")
+	for _, name := range s.FieldNames() {
+		root := s.Query[name]
+		b.WriteString("  " + name)
+		if len(root.Args) > 0 {
+			args := make([]string, len(root.Args))
+			for i, a := range root.Args {
+				args[i] = a.Name + ": String"
+				if a.Required {
+					args[i] += "!"
+				}
+			}
+			b.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+		fmt.Fprintf(&b, ": %s\n", s.returns[name])
+	}
+	b.WriteString("}\n")
+
+	objects := make([]*objectType, 0, len(s.types.objects))
+	for _, obj := range s.types.objects {
+		objects = append(objects, obj)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].name < objects[j].name })
+	for _, obj := range objects {
+		fmt.Fprintf(&b, "\ntype %s {\n", obj.name)
+		for _, f := range obj.fields {
+			fmt.Fprintf(&b, "  %s: %s\n", f.name, f.typ)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+// Package history persists fetched cost data on disk so past periods and
+// long-term trends can be served without repeating Cost Explorer queries,
+// which are billed per request.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// Retention is how long records are kept.
+const Retention = 400 * 24 * time.Hour
+
+// Entry is one fetched cost result for a profile and date range, recorded on
+// Day. Re-fetching the same range on the same day replaces the entry.
+type Entry struct {
+	Profile   string              `json:"profile"`
+	Day       string              `json:"day"`
+	Start     string              `json:"start"`
+	End       string              `json:"end"`
+	Overview  types.CostOverview  `json:"overview"`
+	Services  []types.ServiceCost `json:"services"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+}
+
+func (e Entry) key() string {
+	return e.Profile + "|" + e.Start + "|" + e.End + "|" + e.Day
+}
+
+// Store is a JSON-file backed cost history. The whole file is rewritten on
+// each change, which is fine for the few records a day the dashboard makes.
+type Store struct {
+	path    string
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// Open loads the history at path, creating an empty store if the file does
+// not exist yet.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid cost history %s: %w", path, err)
+	}
+	for _, e := range entries {
+		s.entries[e.key()] = e
+	}
+	return s, nil
+}
+
+// Record stores a fetched result and persists the store.
+func (s *Store) Record(e Entry) error {
+	if e.FetchedAt.IsZero() {
+		e.FetchedAt = time.Now().UTC()
+	}
+	if e.Day == "" {
+		e.Day = e.FetchedAt.Format("2006-01-02")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[e.key()] = e
+	s.pruneLocked(e.FetchedAt.Add(-Retention))
+	return s.saveLocked()
+}
+
+// Lookup returns the most recently recorded entry for exactly this profile
+// and range (start and end as sent to Cost Explorer).
+func (s *Store) Lookup(profile, start, end string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best Entry
+	found := false
+	for _, e := range s.entries {
+		if e.Profile != profile || e.Start != start || e.End != end {
+			continue
+		}
+		if !found || e.FetchedAt.After(best.FetchedAt) {
+			best = e
+			found = true
+		}
+	}
+	return best, found
+}
+
+// List returns the entries for profile recorded between from and to
+// (inclusive YYYY-MM-DD; empty means unbounded), ordered by day.
+func (s *Store) List(profile, from, to string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Entry
+	for _, e := range s.entries {
+		if e.Profile != profile {
+			continue
+		}
+		if (from != "" && e.Day < from) || (to != "" && e.Day > to) {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		if out[i].Start != out[j].Start {
+			return out[i].Start < out[j].Start
+		}
+		return out[i].End < out[j].End
+	})
+	return out
+}
+
+// pruneLocked drops entries fetched before cutoff. Caller must hold s.mu.
+func (s *Store) pruneLocked(cutoff time.Time) {
+	for k, e := range s.entries {
+		if e.FetchedAt.Before(cutoff) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+// saveLocked writes the store atomically. Caller must hold s.mu.
+func (s *Store) saveLocked() error {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key() < entries[j].key() })
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".cost-history-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
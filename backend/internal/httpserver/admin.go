@@ -0,0 +1,94 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/backup"
+	"github.com/local/aws-local-dashboard/internal/events"
+)
+
+// maxRestoreSize bounds the size of an uploaded backup archive.
+const maxRestoreSize = 32 << 20
+
+// handleBackup handles POST /api/admin/backup and streams a tar.gz archive of
+// the server state. An optional JSON body {"passphrase": "..."} encrypts the
+// sensitive parts (profiles) of the archive.
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.backups == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Backups are not configured on server",
+		})
+		return
+	}
+
+	var body struct {
+		Passphrase string `json:"passphrase"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("aws-dashboard-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// Headers are already committed once we start streaming, so on failure
+	// the best we can do is log and truncate the archive.
+	if err := s.backups.Write(w, body.Passphrase); err != nil {
+		log.Printf("backup: %v", err)
+	}
+}
+
+// handleRestore handles POST /api/admin/restore. The request body is an archive
+// produced by /api/admin/backup; the passphrase, if any, is passed in the
+// X-Backup-Passphrase header.
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.backups == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Backups are not configured on server",
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxRestoreSize)
+	restored, err := s.backups.Restore(body, r.Header.Get("X-Backup-Passphrase"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, backup.ErrPassphraseRequired) {
+			status = http.StatusUnauthorized
+		}
+		writeJSON(w, status, errorResponse{
+			Error:   "Failed to restore backup",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// Restored profiles may point at different accounts; drop cached data.
+	s.events.Publish(events.CacheCleared, map[string]any{"reason": "restore"})
+
+	writeJSON(w, http.StatusOK, struct {
+		Restored []string `json:"restored"`
+	}{
+		Restored: restored,
+	})
+}
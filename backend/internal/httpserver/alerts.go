@@ -0,0 +1,148 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/alerts"
+	"github.com/local/aws-local-dashboard/internal/events"
+)
+
+// alertEvaluationTimeout bounds a background evaluation; it may need a
+// Cost Explorer call when the daily series is not cached yet.
+const alertEvaluationTimeout = 2 * time.Minute
+
+type alertsResponse struct {
+	Rules     []alerts.Rule  `json:"rules"`
+	Triggered []alerts.Alert `json:"triggered"`
+}
+
+// handleAlerts serves GET (rules and triggered alerts) and POST (create a
+// rule) on /api/alerts.
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Alerts not configured on server",
+		})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.alertsStatus())
+	case http.MethodPost:
+		var rule alerts.Rule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		created, err := s.alerts.AddRule(rule)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Failed to add alert rule",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		// Check the new rule right away instead of waiting for a refresh.
+		s.evaluateAlertsAsync()
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAlertItem serves DELETE /api/alerts/{id} and POST
+// /api/alerts/evaluate, which evaluates all rules synchronously.
+func (s *Server) handleAlertItem(w http.ResponseWriter, r *http.Request) {
+	if s.alerts == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Alerts not configured on server",
+		})
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/alerts/"), "/")
+
+	if id == "evaluate" {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.evaluateAlerts(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{
+				Error:   "Failed to evaluate alerts",
+				Details: err.Error(),
+				Code:    errorCode(err),
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.alertsStatus())
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.alerts.DeleteRule(id); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Failed to delete alert rule",
+			Details: err.Error(),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.alertsStatus())
+}
+
+func (s *Server) alertsStatus() alertsResponse {
+	return alertsResponse{
+		Rules:     s.alerts.Rules(),
+		Triggered: s.alerts.Triggered(),
+	}
+}
+
+// evaluateAlertsAsync evaluates alert rules in the background after cost data
+// has been refreshed, so the cost response is not delayed.
+func (s *Server) evaluateAlertsAsync() {
+	if s.alerts == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertEvaluationTimeout)
+		defer cancel()
+		if err := s.evaluateAlerts(ctx); err != nil {
+			log.Printf("alerts: evaluation failed: %v", err)
+		}
+	}()
+}
+
+func (s *Server) evaluateAlerts(ctx context.Context) error {
+	fired, err := s.alerts.Evaluate(ctx, s.costService, s.activeProfileKey(), time.Now())
+	if err != nil {
+		return err
+	}
+	for _, a := range fired {
+		s.events.Publish(events.AlertFired, map[string]any{
+			"ruleId":    a.RuleID,
+			"ruleName":  a.RuleName,
+			"metric":    string(a.Metric),
+			"value":     a.Value,
+			"threshold": a.Threshold,
+			"currency":  a.Currency,
+			"profile":   a.Profile,
+			"period":    a.Period,
+			"message":   a.Message,
+		})
+	}
+	return nil
+}
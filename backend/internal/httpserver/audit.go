@@ -0,0 +1,175 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/audit"
+)
+
+// maxAuditEntries caps ?limit= on /api/audit.
+const maxAuditEntries = 1000
+
+// auditResponse is returned from /api/audit.
+type auditResponse struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+type auditNoteKey struct{}
+
+// auditNote lets a handler refine the entry its audited wrapper records.
+type auditNote struct {
+	action string
+	args   []string
+	// denied marks a refusal that isn't a 401 or 403, such as the raw
+	// command safety filter's 400.
+	denied bool
+}
+
+// noteAudit sets the action (if non-empty) and appends args to the audit
+// entry for r. It does nothing for requests that are not audited.
+func noteAudit(r *http.Request, action string, args ...string) {
+	note, ok := r.Context().Value(auditNoteKey{}).(*auditNote)
+	if !ok {
+		return
+	}
+	if action != "" {
+		note.action = action
+	}
+	note.args = append(note.args, args...)
+}
+
+// denyAudit records the request for r as denied.
+func denyAudit(r *http.Request) {
+	if note, ok := r.Context().Value(auditNoteKey{}).(*auditNote); ok {
+		note.denied = true
+	}
+}
+
+// audited records every non-GET request to next in the audit log under
+// action, with the response status as the outcome. Handlers add details with
+// noteAudit.
+func (s *Server) audited(action string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.audit == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		profile := ""
+		if s.profileManager != nil {
+			profile = s.profileManager.ProfileID(r.Context())
+		}
+		note := &auditNote{action: action}
+		r = r.WithContext(context.WithValue(r.Context(), auditNoteKey{}, note))
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusBadRequest && errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+			// withTimeouts answers this with a 504.
+			status = http.StatusGatewayTimeout
+		}
+		result := audit.ResultOK
+		switch {
+		case note.denied || status == http.StatusUnauthorized || status == http.StatusForbidden:
+			result = audit.ResultDenied
+		case status >= http.StatusBadRequest:
+			result = audit.ResultFailed
+		}
+
+		remote, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			remote = r.RemoteAddr
+		}
+		entry := audit.Entry{
+			Time:       start.UTC(),
+			RequestID:  RequestID(r.Context()),
+			Remote:     remote,
+			UserAgent:  r.UserAgent(),
+			Action:     note.action,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Profile:    profile,
+			Args:       note.args,
+			Status:     status,
+			Result:     result,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err := s.audit.Record(entry); err != nil {
+			s.logger.Error("failed to write audit log", "requestId", entry.RequestID, "error", err)
+		}
+	})
+}
+
+// handleAudit handles GET /api/audit. Query parameters: action (a trailing
+// "." matches a prefix, e.g. "profile."), profile, result, since and until
+// (RFC 3339 or YYYY-MM-DD) and limit (default 100).
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.audit == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Audit log is disabled",
+		})
+		return
+	}
+
+	q := r.URL.Query()
+	query := audit.Query{
+		Action:  q.Get("action"),
+		Profile: q.Get("profile"),
+		Result:  q.Get("result"),
+		Limit:   100,
+	}
+	for name, dst := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", v)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid " + name,
+				Details: "Use RFC 3339 (2026-01-02T15:04:05Z) or YYYY-MM-DD.",
+			})
+			return
+		}
+		*dst = t
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAuditEntries {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid limit",
+				Details: "limit must be between 1 and " + strconv.Itoa(maxAuditEntries),
+			})
+			return
+		}
+		query.Limit = n
+	}
+
+	entries, err := s.audit.Entries(query)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error:   "Failed to read audit log",
+			Details: err.Error(),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, auditResponse{Entries: entries})
+}
@@ -0,0 +1,40 @@
+package httpserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// requireToken rejects /api requests that don't carry token, either as
+// "Authorization: Bearer <token>" or, for WebSockets and download links
+// where headers can't be set, a ?token= query parameter. The frontend's
+// static files stay public so the UI can load and ask for the token.
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") && r.URL.Path != "/api" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		got := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			scheme, value, _ := strings.Cut(auth, " ")
+			if strings.EqualFold(scheme, "Bearer") {
+				got = strings.TrimSpace(value)
+			}
+		}
+
+		if subtle.ConstantTimeCompare([]byte(got), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="aws-local-dashboard"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{
+				Error:   "Missing or invalid API token",
+				Details: "Send the token printed at server startup as \"Authorization: Bearer <token>\".",
+				Code:    "unauthorized",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
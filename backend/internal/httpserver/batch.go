@@ -0,0 +1,150 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+const (
+	// maxBatchRequests caps the lists one batch may ask for.
+	maxBatchRequests = 20
+	// batchConcurrency is how many lists of a batch are fetched at once;
+	// each may itself scan every region.
+	batchConcurrency = 6
+)
+
+// batchRequest is the body of POST /api/resources/batch.
+type batchRequest struct {
+	Requests []batchItem `json:"requests"`
+}
+
+// batchItem names one resource list. An empty region means the service's
+// default, as for /api/services/{service}/resources.
+type batchItem struct {
+	Service string `json:"service"`
+	Region  string `json:"region,omitempty"`
+}
+
+// batchResult is the outcome of one batchItem: its resources, or the error
+// the single-service endpoint would have responded with and its status.
+type batchResult struct {
+	Service   string                  `json:"service"`
+	Region    string                  `json:"region,omitempty"`
+	Status    int                     `json:"status"`
+	Resources *types.ServiceResources `json:"resources,omitempty"`
+	Error     *errorResponse          `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	// Results are in request order.
+	Results []batchResult `json:"results"`
+}
+
+// handleResourcesBatch handles POST /api/resources/batch, fetching several
+// services' resources concurrently so a page needs one round trip instead
+// of one per service. A failing list doesn't fail the others.
+func (s *Server) handleResourcesBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+	if len(body.Requests) == 0 || len(body.Requests) > maxBatchRequests {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid batch",
+			Details: fmt.Sprintf("requests must list between 1 and %d services", maxBatchRequests),
+		})
+		return
+	}
+	for i, item := range body.Requests {
+		if strings.TrimSpace(item.Service) == "" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid batch",
+				Details: fmt.Sprintf("requests[%d] has no service", i),
+			})
+			return
+		}
+	}
+
+	results := make([]batchResult, len(body.Requests))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range body.Requests {
+		region := item.Region
+		if region == "" {
+			region = requestRegion(r)
+		}
+		results[i] = batchResult{Service: item.Service, Region: region}
+
+		wg.Add(1)
+		go func(res *batchResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			started := time.Now()
+			resources, err := s.resourceService.GetResources(r.Context(), res.Service, res.Region, nil)
+			s.publishScan(res.Service, res.Region, started, countResources(resources), err)
+			if err != nil {
+				status, resp := resourceError(err)
+				res.Status, res.Error = status, &resp
+				return
+			}
+			res.Status, res.Resources = http.StatusOK, &resources
+		}(&results[i])
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, batchResponse{Results: results})
+}
+
+// resourceError maps a resource service failure to its response.
+func resourceError(err error) (int, errorResponse) {
+	if status, resp, ok := credentialError(err); ok {
+		return status, resp
+	}
+	switch {
+	case errors.Is(err, profiles.ErrRegionNotAllowed):
+		return http.StatusForbidden, errorResponse{
+			Error:   "Region not allowed for the active profile",
+			Details: err.Error(),
+			Code:    "region_not_allowed",
+		}
+	case errors.Is(err, services.ErrInvalidResourceFilter):
+		return http.StatusBadRequest, errorResponse{
+			Error:   "Invalid resource filter",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		}
+	}
+	resp := errorResponse{
+		Error:   "Failed to fetch resources",
+		Details: err.Error(),
+		Code:    errorCode(err),
+	}
+	switch resp.Code {
+	case awscli.ClassThrottled:
+		return http.StatusTooManyRequests, resp
+	case awscli.ClassAccessDenied:
+		return http.StatusForbidden, resp
+	}
+	return http.StatusInternalServerError, resp
+}
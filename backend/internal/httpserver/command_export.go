@@ -0,0 +1,349 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/jmespath"
+)
+
+// recordExecution keeps a command's output for later download and returns
+// its execution id, or "" if it wasn't kept.
+func (s *Server) recordExecution(r *http.Request, commandID string, args []string, region, format string, out []byte) string {
+	if s.executions == nil {
+		return ""
+	}
+	e := commands.Execution{CommandID: commandID, Region: region, Format: format, Args: args}
+	if e.Format == "" {
+		e.Format = awscli.FormatJSON
+	}
+	if s.profileManager != nil {
+		e.Profile = s.profileManager.ProfileID(r.Context())
+	}
+	if e.Region == "" {
+		e.Region = optionValue(args, "--region")
+	}
+	e, ok := s.executions.Add(e, out)
+	if !ok {
+		return ""
+	}
+	return e.ID
+}
+
+// optionValue returns the value of the CLI option name in args, given as
+// "name value" or "name=value".
+func optionValue(args []string, name string) string {
+	for i, a := range args {
+		if a == name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(a, name+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// handleExecutions handles GET /api/commands/executions, listing the recent
+// command executions whose output can be downloaded, newest first.
+func (s *Server) handleExecutions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.executions.List())
+}
+
+// handleExecutionDownload handles GET
+// /api/commands/executions/{id}/download, sending a kept command output as
+// a file. ?format=json pretty-prints JSON output, csv and table flatten it
+// into rows, one table per top-level list; without it the output is sent in
+// the format it was run with. ?query= picks part of JSON output with a
+// JMESPath expression first, e.g. Reservations[].Instances[] for a row per
+// instance.
+func (s *Server) handleExecutionDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/commands/executions/"), "/")
+	if action != "download" {
+		http.NotFound(w, r)
+		return
+	}
+	e, out, ok := s.executions.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Execution not found",
+			Details: "Only the most recent command results are kept, and none survive a restart.",
+		})
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = e.Format
+	}
+	if contentType, ok := commandContentTypes[e.Format]; ok && format == e.Format && !q.Has("query") {
+		ext := ".txt"
+		if e.Format == awscli.FormatYAML {
+			ext = ".yaml"
+		}
+		writeDownload(w, contentType, executionFilename(e, ext), out)
+		return
+	}
+	if format != "json" && format != "csv" && format != "table" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Unsupported export format",
+			Details: fmt.Sprintf("format %q is not supported; use json, csv or table", format),
+		})
+		return
+	}
+	if e.Format != awscli.FormatJSON {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Unsupported export format",
+			Details: fmt.Sprintf("the command ran with %s output, which can only be downloaded as it is, without a query", e.Format),
+			Code:    errorCode(awscli.ErrFormatUnsupported),
+		})
+		return
+	}
+
+	var data any
+	if err := json.Unmarshal(out, &data); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error:   "Failed to export command output",
+			Details: err.Error(),
+		})
+		return
+	}
+	if query := q.Get("query"); query != "" {
+		expr, err := jmespath.Compile(query)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid query",
+				Details: err.Error(),
+			})
+			return
+		}
+		data = expr.Search(data)
+		if out, err = json.Marshal(data); err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{
+				Error:   "Failed to export command output",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		_ = json.Indent(&buf, out, "", "  ")
+		buf.WriteString("\n")
+		writeDownload(w, "application/json", executionFilename(e, ".json"), buf.Bytes())
+	case "csv":
+		cw := csv.NewWriter(&buf)
+		for i, t := range exportTables(data) {
+			if i > 0 {
+				_ = cw.Write(nil)
+			}
+			_ = cw.Write(t.header)
+			for _, row := range t.rows {
+				for c := range row {
+					row[c] = csvText(row[c])
+				}
+				_ = cw.Write(row)
+			}
+		}
+		cw.Flush()
+		writeDownload(w, "text/csv; charset=utf-8", executionFilename(e, ".csv"), buf.Bytes())
+	default:
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		for i, t := range exportTables(data) {
+			if i > 0 {
+				fmt.Fprintln(tw)
+			}
+			fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+			for _, row := range t.rows {
+				for c := range row {
+					row[c] = strings.NewReplacer("\t", " ", "\n", " ", "\r", "").Replace(row[c])
+				}
+				fmt.Fprintln(tw, strings.Join(row, "\t"))
+			}
+		}
+		_ = tw.Flush()
+		writeDownload(w, "text/plain; charset=utf-8", executionFilename(e, ".txt"), buf.Bytes())
+	}
+}
+
+func writeDownload(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+var filenameUnsafe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// executionFilename names a download after the command's service and
+// operation and when it ran, e.g. "aws-ec2-describe-instances_20260102-150405.csv".
+func executionFilename(e commands.Execution, ext string) string {
+	name := "aws"
+	if service, operation, ok := serviceOperation(e.Args); ok {
+		name += "-" + filenameUnsafe.ReplaceAllString(strings.ToLower(service+"-"+operation), "")
+	}
+	return name + "_" + e.ExecutedAt.Format("20060102-150405") + ext
+}
+
+// exportTable is a flattened list of JSON objects.
+type exportTable struct {
+	header []string
+	rows   [][]string
+}
+
+// exportTables flattens JSON command output into tables: a list is one
+// table, an object gives one table per top-level list (other keys, such as
+// page tokens, are left out) or, without lists, a single row.
+func exportTables(data any) []exportTable {
+	switch v := data.(type) {
+	case []any:
+		return []exportTable{flattenRows(v)}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k, item := range v {
+			if _, ok := item.([]any); ok {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return []exportTable{flattenRows([]any{v})}
+		}
+		sort.Strings(keys)
+		tables := make([]exportTable, 0, len(keys))
+		for _, k := range keys {
+			if list := v[k].([]any); len(list) > 0 {
+				tables = append(tables, flattenRows(list))
+			}
+		}
+		return tables
+	}
+	return []exportTable{{header: []string{"value"}, rows: [][]string{{exportCell(data)}}}}
+}
+
+// flattenRows turns items into rows whose columns are their flattened
+// fields, in the order first seen.
+func flattenRows(items []any) exportTable {
+	var t exportTable
+	index := map[string]int{}
+	flat := make([]map[string]string, len(items))
+	for i, item := range items {
+		flat[i] = map[string]string{}
+		flattenValue("", item, flat[i], func(col string) {
+			if _, ok := index[col]; !ok {
+				index[col] = len(t.header)
+				t.header = append(t.header, col)
+			}
+		})
+	}
+	for _, cells := range flat {
+		row := make([]string, len(t.header))
+		for col, v := range cells {
+			row[index[col]] = v
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// flattenValue adds v to cells under column names joined with ".", e.g.
+// "State.Name", calling seen for each column.
+func flattenValue(prefix string, v any, cells map[string]string, seen func(string)) {
+	obj, ok := v.(map[string]any)
+	if !ok || len(obj) == 0 {
+		col := prefix
+		if col == "" {
+			col = "value"
+		}
+		seen(col)
+		cells[col] = exportCell(v)
+		return
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		col := k
+		if prefix != "" {
+			col = prefix + "." + k
+		}
+		flattenValue(col, obj[k], cells, seen)
+	}
+}
+
+// exportCell formats a value that isn't flattened further: lists of
+// scalars joined with ";", AWS tag lists as Key=Value pairs and other
+// nested values as JSON.
+func exportCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return formatAmount(v)
+	case []any:
+		if pairs, ok := tagPairs(v); ok {
+			return strings.Join(pairs, ";")
+		}
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				data, _ := json.Marshal(v)
+				return string(data)
+			}
+			parts = append(parts, exportCell(item))
+		}
+		return strings.Join(parts, ";")
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// tagPairs renders a list of {"Key": ..., "Value": ...} objects as sorted
+// key=value pairs, reporting false for any other list.
+func tagPairs(list []any) ([]string, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	pairs := make([]string, 0, len(list))
+	for _, item := range list {
+		tag, ok := item.(map[string]any)
+		key, keyOK := tag["Key"].(string)
+		if !ok || !keyOK || len(tag) != 2 {
+			return nil, false
+		}
+		value, ok := tag["Value"].(string)
+		if !ok {
+			return nil, false
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs, true
+}
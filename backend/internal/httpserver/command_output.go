@@ -0,0 +1,79 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+)
+
+// commandContentTypes are the response content types of the non-JSON
+// command output formats.
+var commandContentTypes = map[string]string{
+	awscli.FormatTable: "text/plain; charset=utf-8",
+	awscli.FormatText:  "text/plain; charset=utf-8",
+	awscli.FormatYAML:  "application/yaml; charset=utf-8",
+}
+
+// validCommandFormat writes a 400 and returns false unless format, from a
+// command request body, is empty or one of awscli.Formats.
+func validCommandFormat(w http.ResponseWriter, format string) bool {
+	if format == "" || awscli.ValidFormat(format) {
+		return true
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{
+		Error:   "Unsupported output format",
+		Details: "format must be one of " + strings.Join(awscli.Formats, ", "),
+	})
+	return false
+}
+
+// writeCommandOutput responds with a command's output. JSON output is wrapped
+// with the command that produced it and the id it can be downloaded by later
+// (see handleExecutionDownload); other formats are sent as they are, with
+// the command in the X-AWS-Command header and the id in X-Execution-Id.
+func writeCommandOutput(w http.ResponseWriter, args []string, format string, out []byte, executionID string) {
+	command := "aws " + strings.Join(args, " ")
+	contentType, ok := commandContentTypes[format]
+	if !ok {
+		writeJSON(w, http.StatusOK, struct {
+			Command     string          `json:"command"`
+			Output      json.RawMessage `json:"output"`
+			ExecutionID string          `json:"executionId,omitempty"`
+		}{
+			Command:     command,
+			Output:      json.RawMessage(out),
+			ExecutionID: executionID,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-AWS-Command", command+" --output "+format)
+	if executionID != "" {
+		w.Header().Set("X-Execution-Id", executionID)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+}
+
+// writeDryRun responds with how a command would have run, for requests with
+// dryRun set.
+func writeDryRun(w http.ResponseWriter, inv awscli.Invocation, err error) {
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to plan command",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		DryRun bool `json:"dryRun"`
+		awscli.Invocation
+	}{
+		DryRun:     true,
+		Invocation: inv,
+	})
+}
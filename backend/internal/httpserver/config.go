@@ -0,0 +1,191 @@
+package httpserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+)
+
+// RuntimeInfo describes settings resolved at startup that the server doesn't
+// otherwise need, reported as-is by GET /api/config.
+type RuntimeInfo struct {
+	// Listen is where the server accepts connections, e.g. "127.0.0.1:8080"
+	// or "unix:/tmp/aws-dashboard.sock".
+	Listen          string
+	TLS             bool
+	CacheTTL        time.Duration
+	CacheBackend    string
+	CacheDir        string
+	CacheMaxStale   time.Duration
+	DemoMode        string
+	DisplayCurrency string
+	EventSinks      []string
+}
+
+// configResponse is the effective configuration served by GET /api/config.
+// Secrets such as the API token are never included.
+type configResponse struct {
+	Listen   string         `json:"listen"`
+	TLS      bool           `json:"tls"`
+	Cache    cacheConfig    `json:"cache"`
+	Timeouts timeoutsConfig `json:"timeouts"`
+	Regions  regionsConfig  `json:"regions"`
+	Commands commandsConfig `json:"commands"`
+	Features featuresConfig `json:"features"`
+	AWSCLI   awsCLIConfig   `json:"awsCli"`
+}
+
+type cacheConfig struct {
+	TTLSeconds int `json:"ttlSeconds"`
+	// Backend is "memory" or "redis".
+	Backend string `json:"backend"`
+	// Persisted is true when cached data outlives the server, saved to disk
+	// or in Redis, in which case MaxStaleSeconds is how long it is served
+	// when AWS can't be reached.
+	Persisted       bool `json:"persisted"`
+	MaxStaleSeconds int  `json:"maxStaleSeconds,omitempty"`
+}
+
+// timeoutsConfig holds route timeouts in seconds; 0 means no limit.
+type timeoutsConfig struct {
+	RequestSeconds int `json:"requestSeconds"`
+	ScanSeconds    int `json:"scanSeconds"`
+	JobSeconds     int `json:"jobSeconds"`
+}
+
+// regionsConfig describes the region limits of the profile the request uses.
+type regionsConfig struct {
+	Profile string `json:"profile"`
+	// Default is the X-AWS-Region override, if the request sent one.
+	Default string `json:"default,omitempty"`
+	// Allowed is empty when the profile may use every region.
+	Allowed []string `json:"allowed"`
+}
+
+type commandsConfig struct {
+	// SafetyFilter is how commands are vetted before they run: "allowlist"
+	// (only read-only operation verbs run) or "allowlist+iam-simulation".
+	SafetyFilter string `json:"safetyFilter"`
+	Configured   int    `json:"configured"`
+	// PermissionCheck says commands are checked against the profile's IAM
+	// permissions before they run.
+	PermissionCheck bool `json:"permissionCheck"`
+	// RawMaxPages is how many pages of raw command output are followed.
+	RawMaxPages int `json:"rawMaxPages"`
+	// Mutating lists the mutating commands that may run after confirmation;
+	// empty unless they are enabled.
+	Mutating []string `json:"mutating"`
+}
+
+type featuresConfig struct {
+	Auth            bool     `json:"auth"`
+	Audit           bool     `json:"audit"`
+	Alerts          bool     `json:"alerts"`
+	CostHistory     bool     `json:"costHistory"`
+	Backups         bool     `json:"backups"`
+	Favorites       bool     `json:"favorites"`
+	Jobs            bool     `json:"jobs"`
+	GraphQL         bool     `json:"graphql"`
+	EventSinks      []string `json:"eventSinks"`
+	DemoMode        string   `json:"demoMode,omitempty"`
+	DisplayCurrency string   `json:"displayCurrency,omitempty"`
+	DefaultLocale   string   `json:"defaultLocale"`
+	TrustedOrigins  []string `json:"trustedOrigins"`
+}
+
+type awsCLIConfig struct {
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// cliVersion caches the aws CLI version once it has been read successfully.
+type cliVersion struct {
+	mu      sync.Mutex
+	version string
+}
+
+func (c *cliVersion) get(r *http.Request) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != "" {
+		return c.version, nil
+	}
+	v, err := awscli.CLIVersion(r.Context())
+	if err != nil {
+		return "", err
+	}
+	c.version = v
+	return v, nil
+}
+
+// handleConfig handles GET /api/config, reporting what the server is
+// actually running with.
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := configResponse{
+		Listen: s.runtime.Listen,
+		TLS:    s.runtime.TLS,
+		Cache:  cacheConfig{TTLSeconds: int(s.runtime.CacheTTL / time.Second), Backend: s.runtime.CacheBackend},
+		Timeouts: timeoutsConfig{
+			RequestSeconds: int(s.requestTimeout / time.Second),
+			ScanSeconds:    int(s.scanTimeout / time.Second),
+			JobSeconds:     int(s.jobTimeout / time.Second),
+		},
+		Regions: regionsConfig{
+			Profile: "system",
+			Default: profiles.ContextRegion(r.Context()),
+			Allowed: []string{},
+		},
+		Commands: commandsConfig{SafetyFilter: "allowlist", RawMaxPages: max(s.rawMaxPages, 1), Mutating: []string{}},
+		Features: featuresConfig{
+			Auth:            s.authToken != "",
+			Audit:           s.audit != nil,
+			Alerts:          s.alerts != nil,
+			CostHistory:     s.history != nil,
+			Backups:         s.backups != nil,
+			Favorites:       s.favorites != nil,
+			Jobs:            s.jobs != nil,
+			GraphQL:         s.graphql != nil,
+			EventSinks:      append([]string{}, s.runtime.EventSinks...),
+			DemoMode:        s.runtime.DemoMode,
+			DisplayCurrency: s.runtime.DisplayCurrency,
+			DefaultLocale:   s.defaultLocale,
+			TrustedOrigins:  s.originGuard.origins(),
+		},
+	}
+	if s.runtime.CacheDir != "" || s.runtime.CacheBackend == "redis" {
+		resp.Cache.Persisted = true
+		resp.Cache.MaxStaleSeconds = int(s.runtime.CacheMaxStale / time.Second)
+	}
+	if s.profileManager != nil {
+		resp.Regions.Profile = s.profileManager.ProfileID(r.Context())
+		resp.Regions.Allowed = append(resp.Regions.Allowed, s.profileManager.AllowedRegions(r.Context())...)
+	}
+	if s.commandIAMCheck {
+		resp.Commands.SafetyFilter = "allowlist+iam-simulation"
+	}
+	resp.Commands.PermissionCheck = s.permissionCheck
+	if s.commandManager != nil {
+		resp.Commands.Configured = len(s.commandManager.List())
+	}
+	if s.mutations != nil {
+		resp.Commands.Mutating = commands.MutatingCommands()
+	}
+	if s.runtime.DemoMode != "replay" {
+		if v, err := s.cliVersion.get(r); err != nil {
+			resp.AWSCLI.Error = err.Error()
+		} else {
+			resp.AWSCLI.Version = v
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,145 @@
+package httpserver
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// crossOriginGuard rejects state-changing /api requests that a browser sent
+// on behalf of another site, so a page the user happens to visit can't
+// create profiles or run commands against the local dashboard.
+//
+// Browsers mark such requests with Sec-Fetch-Site, or at least an Origin
+// header, which pages cannot forge. Requests with neither come from scripts
+// and other non-browser clients and are allowed, as are safe methods, which
+// no handler uses to change state. WebSocket handshakes are GETs too, but
+// are checked like state-changing requests: browsers let any page open a
+// WebSocket, and the event stream would show it commands and profiles. The
+// dashboard sets no cookies; a session cookie added later must still be
+// SameSite=Strict.
+//
+// A page can also rebind its own host name to 127.0.0.1, after which its
+// requests look same-origin, Origin and Host both naming the page's host.
+// On a loopback address the guard therefore answers only requests whose
+// Host is localhost, a loopback IP or a trusted origin's host.
+type crossOriginGuard struct {
+	// trusted are origins (scheme://host[:port]) allowed besides the
+	// server's own, such as a reverse proxy's public address.
+	trusted map[string]bool
+	// loopback means the server listens on a loopback address only;
+	// trustedHosts are then the Host names accepted besides local ones.
+	loopback     bool
+	trustedHosts map[string]bool
+}
+
+func newCrossOriginGuard(trustedOrigins []string, loopback bool) *crossOriginGuard {
+	g := &crossOriginGuard{
+		trusted:      make(map[string]bool, len(trustedOrigins)),
+		loopback:     loopback,
+		trustedHosts: make(map[string]bool, len(trustedOrigins)),
+	}
+	for _, o := range trustedOrigins {
+		if o = strings.TrimRight(strings.TrimSpace(o), "/"); o != "" {
+			g.trusted[strings.ToLower(o)] = true
+			if u, err := url.Parse(o); err == nil && u.Hostname() != "" {
+				g.trustedHosts[strings.ToLower(u.Hostname())] = true
+			}
+		}
+	}
+	return g
+}
+
+// origins returns the trusted origins, sorted.
+func (g *crossOriginGuard) origins() []string {
+	origins := make([]string, 0, len(g.trusted))
+	for o := range g.trusted {
+		origins = append(origins, o)
+	}
+	sort.Strings(origins)
+	return origins
+}
+
+func (g *crossOriginGuard) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.hostAllowed(r.Host) {
+			writeJSON(w, http.StatusForbidden, errorResponse{
+				Error:   "Unexpected Host header",
+				Details: "On a loopback address the dashboard only answers requests for localhost, 127.0.0.1 or [::1]; add other names to TRUSTED_ORIGINS.",
+				Code:    "cross_origin",
+			})
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/api/") || g.allowed(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		writeJSON(w, http.StatusForbidden, errorResponse{
+			Error:   "Cross-origin request rejected",
+			Details: "State-changing API requests and event streams must come from the dashboard itself.",
+			Code:    "cross_origin",
+		})
+	})
+}
+
+func (g *crossOriginGuard) allowed(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if !headerHasToken(r.Header, "Upgrade", "websocket") {
+			return true
+		}
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin != "" && g.trusted[strings.ToLower(origin)] {
+		return true
+	}
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "":
+	case "same-origin", "none":
+		return true
+	default:
+		return false
+	}
+
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// hostAllowed reports whether a request for host, a Host header value, may
+// be answered: always, unless the server listens on loopback only.
+func (g *crossOriginGuard) hostAllowed(host string) bool {
+	if !g.loopback {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if host == "localhost" || g.trustedHosts[host] {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// headerHasToken reports whether the comma-separated header name lists
+// token, ignoring case.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
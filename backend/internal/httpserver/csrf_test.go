@@ -0,0 +1,108 @@
+package httpserver
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/local/aws-local-dashboard/internal/events"
+)
+
+func TestEventSocketRejectsForeignOrigin(t *testing.T) {
+	srv := httptest.NewServer(NewServer(Options{
+		EventSocket:    events.NewWebSocketSink(),
+		TrustedOrigins: []string{"https://dashboard.example.com"},
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		header   map[string]string
+		wantCode int
+	}{
+		{"foreign origin", map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden},
+		{"cross-site fetch", map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"other local port", map[string]string{"Origin": "http://localhost:3000", "Sec-Fetch-Site": "same-site"}, http.StatusForbidden},
+		{"same host", map[string]string{"Origin": srv.URL}, http.StatusSwitchingProtocols},
+		{"trusted origin", map[string]string{"Origin": "https://dashboard.example.com"}, http.StatusSwitchingProtocols},
+		{"non-browser client", nil, http.StatusSwitchingProtocols},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/events/ws", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCrossOriginGuardAllowsPlainGETs(t *testing.T) {
+	g := newCrossOriginGuard(nil, false)
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/api/config", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	if !g.allowed(req) {
+		t.Error("a plain cross-site GET was rejected; only WebSocket upgrades should be checked")
+	}
+}
+
+func TestLoopbackRejectsReboundHost(t *testing.T) {
+	srv := httptest.NewServer(NewServer(Options{
+		Loopback:       true,
+		TrustedOrigins: []string{"https://dashboard.example.com"},
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	tests := []struct {
+		name     string
+		method   string
+		host     string
+		rejected bool
+	}{
+		{"rebound POST", http.MethodPost, "rebind.example:" + port, true},
+		{"rebound GET", http.MethodGet, "rebind.example:" + port, true},
+		{"localhost", http.MethodPost, "localhost:" + port, false},
+		{"loopback IPv4", http.MethodPost, "127.0.0.1:" + port, false},
+		{"loopback IPv6", http.MethodPost, "[::1]:" + port, false},
+		{"trusted origin's host", http.MethodPost, "dashboard.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+"/api/cache/clear", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// A DNS-rebinding page's requests name its own host in both
+			// headers, so they look same-origin.
+			req.Host = tt.host
+			req.Header.Set("Origin", "http://"+tt.host)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if rejected := resp.StatusCode == http.StatusForbidden; rejected != tt.rejected {
+				t.Errorf("status = %d, want rejected %v", resp.StatusCode, tt.rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/jobs"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/services"
+)
+
+// errorCode returns the stable errorResponse code for err, so clients can
+// branch on it instead of matching details. AWS CLI failures without a more
+// specific cause use the executor's classification (see
+// awscli.CLIError.Class); errors that aren't about AWS get "".
+func errorCode(err error) string {
+	var cliErr *awscli.CLIError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, services.ErrCredentialsExpired):
+		return "credentials_expired"
+	case errors.Is(err, profiles.ErrMFARequired):
+		return "mfa_required"
+	case errors.Is(err, services.ErrCLINotFound):
+		return "cli_not_found"
+	case errors.Is(err, services.ErrCostExplorerDisabled):
+		return "ce_disabled"
+	case errors.Is(err, services.ErrCostExplorerAccessDenied):
+		return "ce_access_denied"
+	case errors.Is(err, services.ErrCostDataUnavailable):
+		return "cost_data_unavailable"
+	case errors.Is(err, services.ErrCostExplorerThrottled):
+		return awscli.ClassThrottled
+	case errors.Is(err, profiles.ErrRegionNotAllowed):
+		return "region_not_allowed"
+	case errors.Is(err, profiles.ErrNotReadOnly):
+		return "not_read_only"
+	case errors.Is(err, profiles.ErrMissingPermission):
+		return "missing_permission"
+	case errors.Is(err, services.ErrInvalidResourceFilter):
+		return "invalid_filter"
+	case errors.Is(err, jobs.ErrQueueFull):
+		return "queue_full"
+	case errors.Is(err, awscli.ErrFormatUnsupported):
+		return "format_unsupported"
+	case errors.Is(err, awscli.ErrDryRunUnsupported):
+		return "dry_run_unsupported"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &cliErr):
+		return cliErr.Class()
+	}
+	return ""
+}
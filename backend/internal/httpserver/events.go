@@ -0,0 +1,49 @@
+package httpserver
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/events"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// publishScan emits events.ScanCompleted for a resource fetch.
+func (s *Server) publishScan(service, region string, started time.Time, count int, err error) {
+	data := map[string]any{
+		"service":    service,
+		"region":     region,
+		"count":      count,
+		"durationMs": time.Since(started).Milliseconds(),
+		"status":     "ok",
+	}
+	if err != nil {
+		data["status"] = "error"
+		data["error"] = err.Error()
+	}
+	s.events.Publish(events.ScanCompleted, data)
+}
+
+// publishCommand emits events.CommandExecuted; data identifies the command.
+func (s *Server) publishCommand(data map[string]any, started time.Time, err error) {
+	data["durationMs"] = time.Since(started).Milliseconds()
+	data["status"] = "ok"
+	if err != nil {
+		data["status"] = "error"
+		data["error"] = err.Error()
+	}
+	s.events.Publish(events.CommandExecuted, data)
+}
+
+// countResources returns the total number of items across all resource lists
+// in res.
+func countResources(res types.ServiceResources) int {
+	v := reflect.ValueOf(res)
+	n := 0
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Slice {
+			n += f.Len()
+		}
+	}
+	return n
+}
@@ -0,0 +1,245 @@
+package httpserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// handleCostExport streams the service-cost breakdown as a CSV download. With
+// ?daily=true a second table with the daily totals follows after a blank line.
+func (s *Server) handleCostExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	if format := q.Get("format"); format != "" && format != "csv" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Unsupported export format",
+			Details: fmt.Sprintf("format %q is not supported; use csv", format),
+		})
+		return
+	}
+	start := q.Get("start")
+	end := q.Get("end")
+	daily, _ := strconv.ParseBool(q.Get("daily"))
+	filter := costFilterFromQuery(q)
+
+	// Fetch everything before writing so errors can still be reported as JSON.
+	overview, err := s.costService.GetCostOverview(r.Context(), start, end, filter)
+	if err != nil {
+		writeCostError(w, err, "Failed to export cost data")
+		return
+	}
+	svcCosts, err := s.costService.GetServiceCosts(r.Context(), start, end, filter)
+	if err != nil {
+		writeCostError(w, err, "Failed to export cost data")
+		return
+	}
+
+	var series [][]string
+	if daily {
+		ts, err := s.costService.GetCostTimeSeries(r.Context(), start, end, "DAILY", filter)
+		if err != nil {
+			writeCostError(w, err, "Failed to export cost data")
+			return
+		}
+		for _, p := range ts.Points {
+			series = append(series, []string{p.Start, formatAmount(p.Cost), ts.Currency})
+		}
+	}
+
+	filename := fmt.Sprintf("aws-costs_%s_%s.csv", overview.Start, overview.End)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	header := []string{"service", "display_name", "drilldown_key", "cost", "currency", "start", "end"}
+	converted := len(svcCosts) > 0 && svcCosts[0].DisplayCurrency != ""
+	if converted {
+		header = append(header, "converted_cost", "display_currency")
+	}
+	_ = cw.Write(header)
+	for _, c := range svcCosts {
+		row := []string{c.Service, c.DisplayName, c.DrilldownKey, formatAmount(c.Cost), c.Currency, overview.Start, overview.End}
+		if converted {
+			convertedCost := ""
+			if c.ConvertedCost != nil {
+				convertedCost = formatAmount(*c.ConvertedCost)
+			}
+			row = append(row, convertedCost, c.DisplayCurrency)
+		}
+		_ = cw.Write(row)
+	}
+
+	if daily {
+		_ = cw.Write(nil)
+		_ = cw.Write([]string{"date", "cost", "currency"})
+		_ = cw.WriteAll(series)
+	}
+	cw.Flush()
+}
+
+// formatAmount renders a cost without exponent notation or float noise, so
+// spreadsheets import it as a plain number.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// resourceListKey returns the JSON key of the ServiceResources list named by
+// key, matched case-insensitively, or false if there is no such list.
+func resourceListKey(key string) (string, bool) {
+	t := reflect.TypeOf(types.ServiceResources{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Type.Kind() == reflect.Slice && strings.EqualFold(name, key) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// writeResourcesCSV writes each non-empty resource list in res as a CSV
+// table, separated by blank lines like the cost export's tables. list limits
+// the output to one list and fields to some columns.
+func writeResourcesCSV(w http.ResponseWriter, res types.ServiceResources, region, list string, fields []string) {
+	filename := "aws-" + res.Service + "-resources"
+	if region != "" {
+		filename += "_" + region
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+	w.WriteHeader(http.StatusOK)
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	cw := csv.NewWriter(w)
+	v := reflect.ValueOf(res)
+	t := v.Type()
+	tables := 0
+	for i := 0; i < v.NumField(); i++ {
+		items := v.Field(i)
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if items.Kind() != reflect.Slice || items.Type().Elem().Kind() != reflect.Struct || items.Len() == 0 {
+			continue
+		}
+		if list != "" && key != list {
+			continue
+		}
+
+		var header []string
+		var columns []int
+		elem := items.Type().Elem()
+		for j := 0; j < elem.NumField(); j++ {
+			f := elem.Field(j)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" || !f.IsExported() || (len(keep) > 0 && !keep[strings.ToLower(name)]) {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			header = append(header, name)
+			columns = append(columns, j)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		if tables > 0 {
+			_ = cw.Write(nil)
+		}
+		tables++
+		_ = cw.Write(header)
+		row := make([]string, len(columns))
+		for n := 0; n < items.Len(); n++ {
+			item := items.Index(n)
+			for c, j := range columns {
+				row[c] = csvCell(item.Field(j))
+			}
+			_ = cw.Write(row)
+		}
+		// Stream large accounts table by table.
+		cw.Flush()
+	}
+	cw.Flush()
+}
+
+// csvCell formats one field for a spreadsheet: lists joined with ";", tags
+// as key=value pairs and anything else nested as JSON.
+func csvCell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return csvText(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatAmount(v.Float())
+	case reflect.Slice:
+		if k := v.Type().Elem().Kind(); k != reflect.Struct && k != reflect.Slice && k != reflect.Map {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				parts[i] = fmt.Sprint(v.Index(i).Interface())
+			}
+			return csvText(strings.Join(parts, ";"))
+		}
+	case reflect.Map:
+		if tags, ok := v.Interface().(map[string]string); ok {
+			pairs := make([]string, 0, len(tags))
+			for k, val := range tags {
+				pairs = append(pairs, k+"="+val)
+			}
+			sort.Strings(pairs)
+			return csvText(strings.Join(pairs, ";"))
+		}
+	}
+	if v.Kind() == reflect.Slice && v.Len() == 0 {
+		return ""
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return csvText(string(data))
+}
+
+// csvText defuses text that a spreadsheet would run as a formula, such as a
+// resource named "=HYPERLINK(...)", by prefixing it with an apostrophe.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/commands"
+)
+
+// handleCommandItem serves POST and DELETE /api/commands/{id}/favorite,
+// pinning and unpinning a configured command.
+func (s *Server) handleCommandItem(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/commands/"), "/"), "/")
+	if action != "favorite" {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Not found",
+		})
+		return
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.commandManager == nil || s.favorites == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Favorites are not configured on server",
+		})
+		return
+	}
+	if _, err := s.commandManager.Args(id, ""); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Failed to update favorite",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	s.favorites.SetFavorite(id, r.Method == http.MethodPost)
+	writeJSON(w, http.StatusOK, s.commandList())
+}
+
+// handleSavedCommands lists saved raw commands (GET) and saves a new one
+// (POST). Saved commands run through POST /api/commands/execute-raw with
+// their savedId.
+func (s *Server) handleSavedCommands(w http.ResponseWriter, r *http.Request) {
+	if s.favorites == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Saved commands are not configured on server",
+		})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.favorites.Saved())
+	case http.MethodPost:
+		var body commands.SavedCommand
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+		fields := strings.Fields(body.Args)
+		noteAudit(r, "", fields...)
+		// Refuse what execute-raw would refuse anyway; the IAM check's
+		// verdict depends on the profile, so it is left to run time.
+		if len(fields) > 0 {
+			if err := CheckSafeAWSArgs(fields); err != nil {
+				denyAudit(r)
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "Command blocked by safety filter",
+					Details: err.Error(),
+				})
+				return
+			}
+		}
+
+		saved, err := s.favorites.Save(body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Failed to save command",
+				Details: err.Error(),
+			})
+			return
+		}
+		writeJSON(w, http.StatusCreated, saved)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSavedCommand serves DELETE /api/commands/saved/{id}.
+func (s *Server) handleSavedCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.favorites == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Saved commands are not configured on server",
+		})
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/commands/saved/"), "/")
+	if err := s.favorites.DeleteSaved(id); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Failed to delete saved command",
+			Details: err.Error(),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.favorites.Saved())
+}
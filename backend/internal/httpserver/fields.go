@@ -0,0 +1,92 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// multiAccountFields are added to every item of a ?profile=all response and
+// kept by ?fields= so each item still says which account it came from.
+var multiAccountFields = []string{"accountid", "profilename"}
+
+// parseFields reads ?fields=instanceId,state,region, returning lowercased
+// JSON field names. Like sort keys, each must exist on some resource type.
+func parseFields(q url.Values) ([]string, error) {
+	var fields []string
+	for _, v := range q["fields"] {
+		for _, name := range splitList(v) {
+			if !resourceField(strings.ToLower(name)) {
+				return nil, fmt.Errorf("no resource has a %q field", name)
+			}
+			fields = append(fields, strings.ToLower(name))
+		}
+	}
+	return fields, nil
+}
+
+// resourceField reports whether any resource type has a field with the given
+// lowercased JSON name.
+func resourceField(name string) bool {
+	lists := reflect.TypeOf(types.ServiceResources{})
+	for i := 0; i < lists.NumField(); i++ {
+		t := lists.Field(i).Type
+		if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		elem := t.Elem()
+		for j := 0; j < elem.NumField(); j++ {
+			tag, _, _ := strings.Cut(elem.Field(j).Tag.Get("json"), ",")
+			if strings.ToLower(tag) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectResourceFields returns res as a JSON object whose resource lists
+// keep only the given fields of each item. The service name, message and
+// pagination are kept as they are.
+func selectResourceFields(res types.ServiceResources, fields []string) (map[string]any, error) {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	selectFields(obj, fields)
+	return obj, nil
+}
+
+// selectFields trims the items of every list in obj, a decoded resources
+// response, to fields. Names match case-insensitively.
+func selectFields(obj map[string]any, fields []string) {
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+	for key, value := range obj {
+		items, ok := value.([]any)
+		if !ok || key == "accounts" {
+			continue
+		}
+		for _, item := range items {
+			fieldsOf, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			for name := range fieldsOf {
+				if !keep[strings.ToLower(name)] {
+					delete(fieldsOf, name)
+				}
+			}
+		}
+	}
+}
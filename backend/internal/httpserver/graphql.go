@@ -0,0 +1,135 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/graphql"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// costArgs are the arguments of the cost root fields, matching the REST
+// endpoints' query parameters.
+var costArgs = []graphql.Argument{{Name: "start"}, {Name: "end"}, {Name: "service"}, {Name: "region"}, {Name: "usageType"}}
+
+// graphqlSchema exposes the same data as the REST endpoints as root query
+// fields. Types are derived from the REST response types, so field names
+// inside each object match the REST JSON exactly.
+func (s *Server) graphqlSchema() *graphql.Schema {
+	schema := graphql.NewSchema(map[string]graphql.RootField{
+		"costOverview": {
+			Args:    costArgs,
+			Returns: types.CostOverview{},
+			Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+				return s.costService.GetCostOverview(ctx, stringArg(args, "start"), stringArg(args, "end"), costFilterFromArgs(args))
+			},
+		},
+		"serviceCosts": {
+			Args:    costArgs,
+			Returns: []types.ServiceCost{},
+			Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+				return s.costService.GetServiceCosts(ctx, stringArg(args, "start"), stringArg(args, "end"), costFilterFromArgs(args))
+			},
+		},
+		"resources": {
+			Args:    []graphql.Argument{{Name: "service", Required: true}, {Name: "region"}},
+			Returns: types.ServiceResources{},
+			Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+				return s.resourceService.GetResources(ctx, stringArg(args, "service"), stringArg(args, "region"), nil)
+			},
+		},
+		"profiles": {
+			Returns: profiles.Status{},
+			Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+				if s.profileManager == nil {
+					return profiles.Status{}, nil
+				}
+				return s.profileManager.Status(), nil
+			},
+		},
+		"commands": {
+			Returns: []commands.PublicCommand{},
+			Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+				if s.commandManager == nil {
+					return []commands.PublicCommand{}, nil
+				}
+				return s.commandManager.List(), nil
+			},
+		},
+	})
+	schema.ErrorCode = errorCode
+	return schema
+}
+
+// costFilterFromArgs reads the same comma-separated filters as the REST
+// endpoints' query parameters.
+func costFilterFromArgs(args map[string]any) types.CostFilter {
+	return types.CostFilter{
+		Services:   splitList(stringArg(args, "service")),
+		Regions:    splitList(stringArg(args, "region")),
+		UsageTypes: splitList(stringArg(args, "usageType")),
+	}
+}
+
+func stringArg(args map[string]any, name string) string {
+	if v, ok := args[name].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// handleGraphQLSchema handles GET /api/graphql/schema, returning the schema
+// in GraphQL SDL for code generators and editors.
+func (s *Server) handleGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(s.graphql.SDL()))
+}
+
+// handleGraphQL handles GET and POST /api/graphql. POST takes the standard
+// {"query", "variables", "operationName"} body; GET takes ?query=.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if v := r.URL.Query().Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "Invalid variables",
+					Details: err.Error(),
+				})
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.Query == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "Query is required",
+		})
+		return
+	}
+
+	// Per GraphQL-over-HTTP, field errors still return 200 with partial data.
+	writeJSON(w, http.StatusOK, s.graphql.Execute(r.Context(), req))
+}
@@ -0,0 +1,271 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/events"
+	"github.com/local/aws-local-dashboard/internal/jobs"
+)
+
+// maxJobBody caps the request body kept for a background job.
+const maxJobBody = 1 << 20
+
+// asyncable lets clients run next as a background job with ?async=true: the
+// request is answered with 202 and the job, and next runs later with the
+// same method, query, body and profile, bounded by the job timeout instead of
+// the route's.
+func (s *Server) asyncable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		async, _ := strconv.ParseBool(q.Get("async"))
+		if q.Has("async") || q.Has("token") {
+			// Neither is a resource filter, and the API token, already
+			// checked, mustn't be recorded in the job's request.
+			q.Del("async")
+			q.Del("token")
+			r = r.Clone(r.Context())
+			r.URL.RawQuery = q.Encode()
+		}
+		if !async {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.jobs == nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{
+				Error: "Jobs are not configured on server",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJobBody))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		request := r.Method + " " + r.URL.RequestURI()
+		jr := r.Clone(r.Context())
+		job, err := s.jobs.Submit(r.Context(), request, func(ctx context.Context, id string) jobs.Output {
+			started := time.Now()
+			jr := jr.WithContext(ctx)
+			jr.Body = io.NopCloser(bytes.NewReader(body))
+			rec := &jobRecorder{header: http.Header{}}
+			serveWithTimeout(rec, jr, next, s.jobTimeout)
+			out := rec.output()
+			s.publishJob(id, request, started, out.StatusCode)
+			return out
+		})
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{
+				Error:   "Failed to start job",
+				Details: err.Error(),
+				Code:    errorCode(err),
+			})
+			return
+		}
+
+		w.Header().Set("Location", "/api/jobs/"+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+	})
+}
+
+// jobRecorder keeps a background job's response.
+type jobRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *jobRecorder) Header() http.Header { return r.header }
+
+func (r *jobRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *jobRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *jobRecorder) output() jobs.Output {
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return jobs.Output{StatusCode: status, Header: r.header, Body: r.body.Bytes()}
+}
+
+// jobResponse is a job as served by GET /api/jobs/{id}. A completed job's
+// JSON output is included as result; other output is only served by
+// /api/jobs/{id}/result.
+type jobResponse struct {
+	jobs.Job
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// handleJobs handles GET /api/jobs, listing retained jobs newest first.
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.jobs == nil {
+		writeJSON(w, http.StatusOK, []jobs.Job{})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.jobs.List())
+}
+
+// handleJob serves GET and DELETE /api/jobs/{id}, GET /api/jobs/{id}/result
+// and GET /api/jobs/{id}/events.
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if s.jobs == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Jobs are not configured on server",
+		})
+		return
+	}
+
+	id, sub, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/"), "/")
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Job not found",
+			Details: "Jobs are kept for an hour after they finish.",
+		})
+		return
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.jobResponse(job))
+	case sub == "" && r.Method == http.MethodDelete:
+		if err := s.jobs.Cancel(id); err != nil {
+			writeJSON(w, http.StatusNotFound, errorResponse{
+				Error:   "Failed to cancel job",
+				Details: err.Error(),
+			})
+			return
+		}
+		job, _ = s.jobs.Get(id)
+		writeJSON(w, http.StatusOK, job)
+	case sub == "result" && r.Method == http.MethodGet:
+		s.writeJobResult(w, job)
+	case sub == "events" && r.Method == http.MethodGet:
+		s.streamJobEvents(w, r, id)
+	case sub == "" || sub == "result" || sub == "events":
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Not found",
+		})
+	}
+}
+
+func (s *Server) jobResponse(job jobs.Job) jobResponse {
+	resp := jobResponse{Job: job}
+	if out, ok := s.jobs.Output(job.ID); ok && job.Status != jobs.Canceled && strings.HasPrefix(out.Header.Get("Content-Type"), "application/json") {
+		resp.Result = json.RawMessage(bytes.TrimSpace(out.Body))
+	}
+	return resp
+}
+
+// writeJobResult replays a finished job's response as the request would have
+// been answered, headers included.
+func (s *Server) writeJobResult(w http.ResponseWriter, job jobs.Job) {
+	out, ok := s.jobs.Output(job.ID)
+	if !ok {
+		writeJSON(w, http.StatusConflict, errorResponse{
+			Error:   "Job has not finished",
+			Details: fmt.Sprintf("job %s is %s", job.ID, job.Status),
+			Code:    "job_not_finished",
+		})
+		return
+	}
+	if job.Status == jobs.Canceled {
+		writeJSON(w, http.StatusGone, errorResponse{
+			Error: "Job was cancelled",
+			Code:  "job_canceled",
+		})
+		return
+	}
+	for k, v := range out.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(out.StatusCode)
+	_, _ = w.Write(out.Body)
+}
+
+// streamJobEvents sends the job as a server-sent "job" event whenever it
+// changes, ending after it finishes.
+func (s *Server) streamJobEvents(w http.ResponseWriter, r *http.Request, id string) {
+	changed, stop, ok := s.jobs.Watch(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+	defer stop()
+
+	rc := http.NewResponseController(w)
+	// The stream lasts as long as the job, past the server's write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		job, ok := s.jobs.Get(id)
+		if !ok {
+			return
+		}
+		data, err := json.Marshal(s.jobResponse(job))
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: job\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		_ = rc.Flush()
+		if job.Status.Finished() {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// publishJob emits events.JobFinished.
+func (s *Server) publishJob(id, request string, started time.Time, statusCode int) {
+	data := map[string]any{
+		"id":         id,
+		"request":    request,
+		"statusCode": statusCode,
+		"durationMs": time.Since(started).Milliseconds(),
+		"status":     "ok",
+	}
+	if statusCode >= http.StatusBadRequest {
+		data["status"] = "error"
+	}
+	s.events.Publish(events.JobFinished, data)
+}
@@ -0,0 +1,53 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/local/aws-local-dashboard/internal/locale"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// negotiateLocale picks the display locale for r from Accept-Language (or a
+// ?locale= override) and advertises it via Content-Language.
+func (s *Server) negotiateLocale(w http.ResponseWriter, r *http.Request) locale.Locale {
+	accept := r.Header.Get("Accept-Language")
+	if v := r.URL.Query().Get("locale"); v != "" {
+		accept = v
+	}
+
+	loc := locale.Negotiate(accept, s.defaultLocale)
+	w.Header().Set("Content-Language", loc.Tag)
+	w.Header().Add("Vary", "Accept-Language")
+	return loc
+}
+
+func formatOverview(loc locale.Locale, o types.CostOverview) types.CostOverview {
+	o.Formatted = &types.FormattedCostOverview{
+		Locale:         loc.Tag,
+		Total:          loc.FormatCurrency(o.Total, o.Currency),
+		NetTotal:       loc.FormatCurrency(o.NetTotal, o.Currency),
+		CreditsApplied: loc.FormatCurrency(o.CreditsApplied, o.Currency),
+		Tax:            loc.FormatCurrency(o.Tax, o.Currency),
+		Refunds:        loc.FormatCurrency(o.Refunds, o.Currency),
+		Support:        loc.FormatCurrency(o.Support, o.Currency),
+		Fees:           loc.FormatCurrency(o.Fees, o.Currency),
+		OtherCharges:   loc.FormatCurrency(o.OtherCharges, o.Currency),
+		Start:          loc.FormatDate(o.Start),
+		End:            loc.FormatDate(o.End),
+	}
+	if o.PreviousStart != "" {
+		o.Formatted.PreviousPeriodTotal = loc.FormatCurrency(o.PreviousPeriodTotal, o.Currency)
+	}
+	return o
+}
+
+// formatServiceCosts returns a copy of costs with FormattedCost filled in, so
+// cached slices are never mutated.
+func formatServiceCosts(loc locale.Locale, costs []types.ServiceCost) []types.ServiceCost {
+	out := make([]types.ServiceCost, len(costs))
+	for i, c := range costs {
+		c.FormattedCost = loc.FormatCurrency(c.Cost, c.Currency)
+		out[i] = c
+	}
+	return out
+}
@@ -0,0 +1,237 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// maxConcurrentAccounts limits how many profiles a multi-account request
+// queries in parallel; each may itself fan out across regions.
+const maxConcurrentAccounts = 4
+
+// wantsAllProfiles reports whether the request asks for multi-account mode.
+func wantsAllProfiles(r *http.Request) bool {
+	return r.URL.Query().Get("profile") == "all"
+}
+
+// forEachAccount runs fn for every profile selected for multi-account mode,
+// with a context scoped to that profile, and returns one AccountInfo per
+// profile in selection order. It writes an error response and returns false
+// when multi-account mode is unavailable.
+func (s *Server) forEachAccount(ctx context.Context, w http.ResponseWriter, fn func(ctx context.Context, i int, info *types.AccountInfo) error) ([]types.AccountInfo, bool) {
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return nil, false
+	}
+	refs := s.profileManager.MultiAccount()
+	if len(refs) == 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "No profiles selected for multi-account mode",
+			Details: "Select profiles with POST /api/profiles/multi first.",
+		})
+		return nil, false
+	}
+
+	infos := make([]types.AccountInfo, len(refs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentAccounts)
+	for i, ref := range refs {
+		infos[i] = types.AccountInfo{ProfileID: ref.ProfileID, ProfileName: ref.ProfileName}
+
+		wg.Add(1)
+		go func(i int, ref profiles.AccountRef) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pctx := profiles.WithProfile(ctx, ref.ProfileID)
+			info := &infos[i]
+			account, err := s.profileManager.AccountID(pctx, ref.ProfileID)
+			if err != nil {
+				info.Error = err.Error()
+				return
+			}
+			info.AccountID = account
+			if err := fn(pctx, i, info); err != nil {
+				info.Error = err.Error()
+			}
+		}(i, ref)
+	}
+	wg.Wait()
+	return infos, true
+}
+
+// handleMultiAccountCost serves /api/cost and /api/services with
+// ?profile=all.
+func (s *Server) handleMultiAccountCost(w http.ResponseWriter, r *http.Request, withServices bool) {
+	q := r.URL.Query()
+	start, end := q.Get("start"), q.Get("end")
+	filter := costFilterFromQuery(q)
+
+	var mu sync.Mutex
+	overviews := make(map[int]types.CostOverview)
+	services := make(map[int][]types.ServiceCost)
+	infos, ok := s.forEachAccount(r.Context(), w, func(ctx context.Context, i int, info *types.AccountInfo) error {
+		overview, err := s.costService.GetCostOverview(ctx, start, end, filter)
+		if err != nil {
+			return err
+		}
+		if withServices {
+			costs, err := s.costService.GetServiceCosts(ctx, start, end, filter)
+			if err != nil {
+				return err
+			}
+			// The slice may be shared with the cost cache.
+			costs = append([]types.ServiceCost(nil), costs...)
+			for j := range costs {
+				costs[j].AccountID = info.AccountID
+				costs[j].ProfileName = info.ProfileName
+			}
+			mu.Lock()
+			services[i] = costs
+			mu.Unlock()
+		}
+		mu.Lock()
+		overviews[i] = overview
+		mu.Unlock()
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	loc := s.negotiateLocale(w, r)
+	resp := types.MultiAccountCostResponse{}
+	sameCurrency := true
+	for i, info := range infos {
+		acct := types.AccountCostOverview{AccountInfo: info}
+		if overview, ok := overviews[i]; ok {
+			o := formatOverview(loc, overview)
+			acct.Overview = &o
+			if resp.Currency == "" {
+				resp.Currency = o.Currency
+			}
+			sameCurrency = sameCurrency && o.Currency == resp.Currency
+			resp.Total += o.Total
+			resp.NetTotal += o.NetTotal
+			resp.Services = append(resp.Services, formatServiceCosts(loc, services[i])...)
+		}
+		resp.Accounts = append(resp.Accounts, acct)
+	}
+	if !sameCurrency {
+		resp.Total, resp.NetTotal, resp.Currency = 0, 0, ""
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleMultiAccountResources serves /api/services/{service}/resources with
+// ?profile=all. Each profile's resource lists are merged under their usual
+// keys, with every resource annotated with accountId and profileName.
+func (s *Server) handleMultiAccountResources(w http.ResponseWriter, r *http.Request, service, region string) {
+	var mu sync.Mutex
+	perAccount := make(map[int]map[string]any)
+	filter := resourceFilterFromQuery(r.URL.Query())
+
+	infos, ok := s.forEachAccount(r.Context(), w, func(ctx context.Context, i int, info *types.AccountInfo) error {
+		res, err := s.resourceService.GetResources(ctx, service, region, filter)
+		if err != nil {
+			return err
+		}
+		info.Message = res.Message
+
+		data, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		perAccount[i] = fields
+		mu.Unlock()
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	// Merge in selection order so accounts appear in a stable order.
+	merged := map[string]any{"service": service}
+	for i, info := range infos {
+		for key, value := range perAccount[i] {
+			items, ok := value.([]any)
+			if !ok {
+				continue
+			}
+			for _, item := range items {
+				if obj, ok := item.(map[string]any); ok {
+					obj["accountId"] = info.AccountID
+					obj["profileName"] = info.ProfileName
+				}
+			}
+			existing, _ := merged[key].([]any)
+			merged[key] = append(existing, items...)
+		}
+	}
+	if keys, _ := parseSort(r.URL.Query()); len(keys) > 0 {
+		sortMerged(merged, keys)
+	}
+	if page, paged, _ := parsePage(r); paged {
+		pageMerged(merged, page)
+	}
+	if fields, _ := parseFields(r.URL.Query()); len(fields) > 0 {
+		selectFields(merged, append(fields, multiAccountFields...))
+	}
+	merged["accounts"] = infos
+	writeJSON(w, http.StatusOK, merged)
+}
+
+// handleMultiAccountSelection handles POST /api/profiles/multi, selecting
+// the profiles ?profile=all queries cover.
+func (s *Server) handleMultiAccountSelection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return
+	}
+
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	for _, id := range body.IDs {
+		noteAudit(r, "", "id="+id)
+	}
+	if err := s.profileManager.SetMultiAccount(body.IDs); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to select profiles",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.profileManager.Status())
+}
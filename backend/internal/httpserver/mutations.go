@@ -0,0 +1,146 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/awscli"
+	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+)
+
+// mutationsEnabled writes a 403 and returns false unless the server was
+// started with mutating commands enabled.
+func (s *Server) mutationsEnabled(w http.ResponseWriter) bool {
+	if s.mutations != nil && s.commandManager != nil {
+		return true
+	}
+	writeJSON(w, http.StatusForbidden, errorResponse{
+		Error:   "Mutating commands are disabled",
+		Details: "Start the server with MUTATING_COMMANDS=true (-mutating-commands) to enable " + strings.Join(commands.MutatingCommands(), ", ") + ".",
+		Code:    "mutations_disabled",
+	})
+	return false
+}
+
+// handleMutations handles POST /api/commands/mutations, the first step of
+// running a mutating command: it checks the command and answers with what
+// it would do and a token that runs it through
+// /api/commands/mutations/confirm.
+func (s *Server) handleMutations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.mutationsEnabled(w) {
+		return
+	}
+
+	var body struct {
+		Args string `json:"args"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	fields := strings.Fields(body.Args)
+	noteAudit(r, "", fields...)
+	summary, err := commands.DescribeMutation(fields)
+	if err != nil {
+		denyAudit(r)
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Command is not an allowed mutation",
+			Details: err.Error(),
+		})
+		return
+	}
+	if s.permissionCheck && !s.checkCommandPermission(w, r, fields) {
+		return
+	}
+	// Plan pins down the profile and region without running anything, so
+	// the confirmation runs exactly what the summary describes.
+	inv, err := s.commandManager.PlanRaw(r.Context(), fields, "")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to prepare command",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	region := inv.Region
+	if region == "" {
+		region = "the CLI's default region"
+	}
+	pending := s.mutations.Request(commands.PendingMutation{
+		Summary: fmt.Sprintf("%s Runs in %s with profile %s.", summary, region, inv.Profile),
+		Profile: inv.Profile,
+		Region:  inv.Region,
+		Args:    awscli.WithoutOutput(inv.Argv[1:]),
+	})
+	writeJSON(w, http.StatusOK, pending)
+}
+
+// handleConfirmMutation handles POST /api/commands/mutations/confirm,
+// running a mutating command requested through /api/commands/mutations.
+// Each token runs its command once.
+func (s *Server) handleConfirmMutation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.mutationsEnabled(w) {
+		return
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pending, ok := s.mutations.Confirm(body.Token)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Confirmation token not found",
+			Details: "Tokens expire five minutes after they are issued and can be used once; request the command again.",
+			Code:    "confirmation_invalid",
+		})
+		return
+	}
+	noteAudit(r, "", pending.Args...)
+
+	ctx := r.Context()
+	if pending.Profile != "" {
+		ctx = profiles.WithProfile(ctx, pending.Profile)
+	}
+	started := time.Now()
+	out, args, err := s.commandManager.ExecuteRaw(ctx, pending.Args, "", 1)
+	s.publishCommand(map[string]any{"args": pending.Args, "mutating": true}, started, err)
+	if err != nil {
+		if writeCredentialError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to execute command",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	writeCommandOutput(w, args, "", out, s.recordExecution(r.WithContext(ctx), "", args, pending.Region, "", out))
+}
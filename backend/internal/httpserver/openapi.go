@@ -0,0 +1,528 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/alerts"
+	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/graphql"
+	"github.com/local/aws-local-dashboard/internal/history"
+	"github.com/local/aws-local-dashboard/internal/jobs"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// apiOperation describes one endpoint for the OpenAPI document. Request and
+// response schemas are derived from Go values, so they follow the types the
+// handlers actually encode.
+type apiOperation struct {
+	method  string
+	path    string
+	tag     string
+	summary string
+	params  []apiParam
+	// body is a value of the JSON request body type, if any.
+	body any
+	// bodyType overrides the request content type for non-JSON uploads.
+	bodyType string
+	// response is a value of the 200 response type; nil means no body.
+	response any
+	// responseType overrides the response content type for downloads.
+	responseType string
+	status       int
+}
+
+type apiParam struct {
+	name string
+	in   string
+	desc string
+	typ  string
+}
+
+func queryParam(name, desc string) apiParam {
+	return apiParam{name: name, in: "query", desc: desc, typ: "string"}
+}
+
+func boolParam(name, desc string) apiParam {
+	return apiParam{name: name, in: "query", desc: desc, typ: "boolean"}
+}
+
+func pathParam(name, desc string) apiParam {
+	return apiParam{name: name, in: "path", desc: desc, typ: "string"}
+}
+
+var (
+	rangeParams = []apiParam{
+		queryParam("start", "Start date (YYYY-MM-DD); defaults to the start of the current month"),
+		queryParam("end", "End date (YYYY-MM-DD, exclusive); defaults to today"),
+	}
+	filterParams = []apiParam{
+		queryParam("service", "Only include these services (comma-separated, repeatable)"),
+		queryParam("region", "Only include these regions (comma-separated, repeatable)"),
+		queryParam("usageType", "Only include these usage types (comma-separated, repeatable)"),
+	}
+	localeParam  = queryParam("locale", "Locale for formatted values; overrides Accept-Language")
+	profileParam = queryParam("profile", `"all" queries every profile selected with POST /api/profiles/multi`)
+	asyncParam   = boolParam("async", "Run as a background job: respond 202 with the job, see /api/jobs/{id}")
+)
+
+func params(groups ...[]apiParam) []apiParam {
+	var out []apiParam
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+// apiOperations lists every API endpoint. Keep it in step with the routes
+// registered in NewServer.
+func apiOperations() []apiOperation {
+	commandResult := struct {
+		Command     string          `json:"command"`
+		Output      json.RawMessage `json:"output"`
+		ExecutionID string          `json:"executionId,omitempty"`
+	}{}
+
+	return []apiOperation{
+		{method: "GET", path: "/api/cost", tag: "cost", summary: "Cost overview for a period",
+			params: params(rangeParams, filterParams, []apiParam{localeParam, profileParam}), response: types.CostResponse{}},
+		{method: "GET", path: "/api/services", tag: "cost", summary: "Cost overview with per-service costs",
+			params: params(rangeParams, filterParams, []apiParam{localeParam, profileParam}), response: types.ServicesResponse{}},
+		{method: "GET", path: "/api/cost/history", tag: "cost", summary: "Locally recorded cost history for the active profile",
+			params: []apiParam{queryParam("from", "First day (YYYY-MM-DD)"), queryParam("to", "Last day (YYYY-MM-DD)")},
+			response: struct {
+				Profile string          `json:"profile"`
+				Entries []history.Entry `json:"entries"`
+			}{}},
+		{method: "GET", path: "/api/cost/untagged", tag: "cost", summary: "Usage cost without a value for a tag key, per service",
+			params: params([]apiParam{queryParam("tagKey", "Tag key to check (required)")}, rangeParams), response: types.UntaggedCostReport{}},
+		{method: "GET", path: "/api/cost/purchase-types", tag: "cost", summary: "Amortized cost by purchase option",
+			params: params(rangeParams, filterParams), response: types.PurchaseTypeReport{}},
+		{method: "GET", path: "/api/cost/status", tag: "cost", summary: "Cost Explorer availability and billed request count",
+			params: []apiParam{boolParam("probe", "Make one request if nothing is known yet")}, response: types.CostExplorerStatus{}},
+		{method: "GET", path: "/api/cost/ec2-other", tag: "cost", summary: `"EC2 - Other" cost in readable buckets`,
+			params: rangeParams, response: types.EC2OtherBreakdown{}},
+		{method: "GET", path: "/api/cost/sparklines", tag: "cost", summary: "Monthly cost per service for the trailing twelve months",
+			response: types.ServiceSparklines{}},
+		{method: "GET", path: "/api/cost/export", tag: "cost", summary: "Service costs as a CSV download",
+			params: params(rangeParams, filterParams, []apiParam{
+				queryParam("format", `Export format; only "csv"`),
+				boolParam("daily", "Append a table of daily totals"),
+			}), response: "", responseType: "text/csv"},
+		{method: "GET", path: "/api/cost/timeseries", tag: "cost", summary: "Spend per period",
+			params:   params(rangeParams, filterParams, []apiParam{queryParam("granularity", "DAILY (default), MONTHLY or HOURLY")}),
+			response: types.CostTimeSeries{}},
+		{method: "GET", path: "/api/cost/service/{service}", tag: "cost", summary: "One service's cost by usage type",
+			params:   params([]apiParam{pathParam("service", `Cost Explorer service name or drilldown key such as "ec2"`)}, rangeParams),
+			response: types.ServiceCostBreakdown{}},
+
+		{method: "GET", path: "/api/alerts", tag: "alerts", summary: "Alert rules and triggered alerts", response: alertsResponse{}},
+		{method: "POST", path: "/api/alerts", tag: "alerts", summary: "Create an alert rule",
+			body: alerts.Rule{}, response: alerts.Rule{}, status: http.StatusCreated},
+		{method: "DELETE", path: "/api/alerts/{id}", tag: "alerts", summary: "Delete an alert rule",
+			params: []apiParam{pathParam("id", "Rule ID")}, response: alertsResponse{}},
+		{method: "POST", path: "/api/alerts/evaluate", tag: "alerts", summary: "Evaluate all rules now", response: alertsResponse{}},
+
+		{method: "GET", path: "/api/services/{service}/resources", tag: "resources",
+			summary: "List a service's resources; other query parameters filter them, e.g. state=running, name~=web, state!=terminated, tag:Team=payments",
+			params: []apiParam{
+				pathParam("service", `Service key, e.g. "ec2", "s3" or "rds"`),
+				queryParam("region", `AWS region, or "all" for every enabled region`),
+				profileParam,
+				queryParam("sort", "Sort keys such as launchTime:desc,name (comma-separated, asc by default)"),
+				queryParam("fields", "Only return these fields of each resource, e.g. instanceId,state,region"),
+				queryParam("limit", "Page size applied to every resource list (1-1000)"),
+				queryParam("offset", "Items to skip in every resource list"),
+				queryParam("format", `"json" (default) or "csv" to download the lists as CSV tables`),
+				queryParam("list", "With format=csv, export only this list, e.g. ec2Instances"),
+				asyncParam,
+			}, response: types.ServiceResources{}},
+		{method: "POST", path: "/api/resources/batch", tag: "resources",
+			summary: "Fetch several services' resources concurrently; each result carries its own status",
+			params:  []apiParam{asyncParam}, body: batchRequest{}, response: batchResponse{}},
+		{method: "GET", path: "/api/resources/summary", tag: "resources", summary: "Resource counts per service",
+			params: []apiParam{asyncParam}, response: types.ResourcesSummaryResponse{}},
+
+		{method: "GET", path: "/api/profiles", tag: "profiles", summary: "Profile status", response: profiles.Status{}},
+		{method: "POST", path: "/api/profiles", tag: "profiles", summary: "Create and activate a profile",
+			body: struct {
+				Name            string   `json:"name"`
+				AccessKeyID     string   `json:"accessKeyId,omitempty"`
+				SecretAccessKey string   `json:"secretAccessKey,omitempty"`
+				SessionToken    string   `json:"sessionToken,omitempty"`
+				Region          string   `json:"region,omitempty"`
+				RoleARN         string   `json:"roleArn,omitempty"`
+				ExternalID      string   `json:"externalId,omitempty"`
+				BaseProfileID   string   `json:"baseProfileId,omitempty"`
+				MFASerial       string   `json:"mfaSerial,omitempty"`
+				VaultProfile    string   `json:"vaultProfile,omitempty"`
+				AllowedRegions  []string `json:"allowedRegions,omitempty"`
+			}{}, response: profiles.Status{}},
+		{method: "DELETE", path: "/api/profiles/{id}", tag: "profiles", summary: "Delete a custom profile",
+			params: []apiParam{pathParam("id", "Profile ID")}, response: profiles.Status{}},
+		{method: "PATCH", path: "/api/profiles/{id}", tag: "profiles", summary: "Rename a profile or change its regions",
+			params: []apiParam{pathParam("id", "Profile ID")},
+			body: struct {
+				Name           string   `json:"name,omitempty"`
+				Region         string   `json:"region,omitempty"`
+				AllowedRegions []string `json:"allowedRegions,omitempty"`
+			}{}, response: profiles.Status{}},
+		{method: "POST", path: "/api/profiles/{id}/mfa", tag: "profiles", summary: "Activate an MFA profile with a TOTP code",
+			params: []apiParam{pathParam("id", "Profile ID")},
+			body: struct {
+				Code string `json:"code"`
+			}{}, response: profiles.Status{}},
+		{method: "POST", path: "/api/profiles/{id}/validate", tag: "profiles", summary: "Account and principal a profile points at",
+			params: []apiParam{pathParam("id", `Profile ID or "system"`)}, response: profiles.Identity{}},
+		{method: "POST", path: "/api/profiles/{id}/permissions", tag: "profiles", summary: "Dashboard features a profile can use",
+			params: []apiParam{pathParam("id", `Profile ID or "system"`)}, response: profiles.Permissions{}},
+		{method: "GET", path: "/api/profiles/export", tag: "profiles", summary: "Download custom profiles as a bundle",
+			params: []apiParam{
+				queryParam("ids", "Comma-separated profile IDs; default all"),
+				boolParam("redact", "Leave out access keys"),
+			}, response: profiles.Bundle{}},
+		{method: "POST", path: "/api/profiles/import", tag: "profiles", summary: "Add the profiles in a bundle",
+			body: profiles.Bundle{},
+			response: struct {
+				profiles.ImportResult
+				Status profiles.Status `json:"status"`
+			}{}},
+		{method: "POST", path: "/api/profiles/multi", tag: "profiles", summary: "Select profiles for multi-account mode",
+			body: struct {
+				IDs []string `json:"ids"`
+			}{}, response: profiles.Status{}},
+		{method: "POST", path: "/api/profiles/select", tag: "profiles", summary: "Switch the active profile",
+			body: struct {
+				ID string `json:"id"`
+			}{}, response: profiles.Status{}},
+
+		{method: "POST", path: "/api/cache/clear", tag: "admin", summary: "Clear cached AWS data", status: http.StatusNoContent},
+
+		{method: "GET", path: "/api/commands", tag: "commands", summary: "Configured commands, favorites first and then by category and label",
+			params: []apiParam{
+				queryParam("category", "Only these categories, e.g. Networking (comma-separated)"),
+				queryParam("tag", "Only commands with one of these tags (comma-separated)"),
+				queryParam("group", `"category" returns [{category, commands}] instead of a flat list`),
+			}, response: []commands.PublicCommand{}},
+		{method: "POST", path: "/api/commands/execute", tag: "commands", summary: "Run a configured command; format table, text or yaml returns the CLI's output as text instead, and dryRun how it would run",
+			body: struct {
+				ID     string `json:"id"`
+				Region string `json:"region,omitempty"`
+				Format string `json:"format,omitempty"`
+				DryRun bool   `json:"dryRun,omitempty"`
+			}{}, params: []apiParam{asyncParam}, response: commandResult},
+		{method: "POST", path: "/api/commands/execute-raw", tag: "commands", summary: "Run a read-only aws CLI command; format table, text or yaml returns the CLI's output as text instead, and dryRun how it would run",
+			body: struct {
+				Args    string `json:"args"`
+				SavedID string `json:"savedId,omitempty"`
+				Format  string `json:"format,omitempty"`
+				DryRun  bool   `json:"dryRun,omitempty"`
+			}{}, params: []apiParam{asyncParam}, response: commandResult},
+		{method: "POST", path: "/api/commands/{id}/favorite", tag: "commands", summary: "Pin a configured command",
+			params: []apiParam{pathParam("id", "Command ID")}, response: []commands.PublicCommand{}},
+		{method: "DELETE", path: "/api/commands/{id}/favorite", tag: "commands", summary: "Unpin a configured command",
+			params: []apiParam{pathParam("id", "Command ID")}, response: []commands.PublicCommand{}},
+		{method: "GET", path: "/api/commands/saved", tag: "commands", summary: "Saved raw commands", response: []commands.SavedCommand{}},
+		{method: "POST", path: "/api/commands/saved", tag: "commands", summary: "Save a raw command; run it with savedId on execute-raw",
+			body: struct {
+				Label string `json:"label,omitempty"`
+				Args  string `json:"args"`
+			}{}, response: commands.SavedCommand{}, status: http.StatusCreated},
+		{method: "POST", path: "/api/commands/mutations", tag: "commands", summary: "Request a mutating command; returns its impact summary and a confirmation token",
+			body: struct {
+				Args string `json:"args"`
+			}{}, response: commands.PendingMutation{}},
+		{method: "POST", path: "/api/commands/mutations/confirm", tag: "commands", summary: "Run a requested mutating command",
+			body: struct {
+				Token string `json:"token"`
+			}{}, response: commandResult},
+		{method: "DELETE", path: "/api/commands/saved/{id}", tag: "commands", summary: "Delete a saved command",
+			params: []apiParam{pathParam("id", "Saved command ID")}, response: []commands.SavedCommand{}},
+		{method: "GET", path: "/api/commands/executions", tag: "commands", summary: "Recent command executions whose output can be downloaded, newest first",
+			response: []commands.Execution{}},
+		{method: "GET", path: "/api/commands/executions/{id}/download", tag: "commands", summary: "A command execution's output as a file",
+			params: []apiParam{
+				pathParam("id", "Execution ID, the executionId of the command's response"),
+				queryParam("format", `"json", "csv" or "table" (a flattened text table); by default the format the command ran with`),
+				queryParam("query", "JMESPath expression picking the rows from JSON output, e.g. Reservations[].Instances[]"),
+			}, response: "", responseType: "application/octet-stream"},
+
+		{method: "GET", path: "/api/graphql", tag: "graphql", summary: "Run a GraphQL query",
+			params: []apiParam{
+				queryParam("query", "GraphQL query"),
+				queryParam("operationName", "Operation to run"),
+				queryParam("variables", "JSON-encoded variables"),
+			}, response: graphql.Response{}},
+		{method: "POST", path: "/api/graphql", tag: "graphql", summary: "Run a GraphQL query",
+			body: graphql.Request{}, response: graphql.Response{}},
+		{method: "GET", path: "/api/graphql/schema", tag: "graphql", summary: "The GraphQL schema in SDL",
+			response: "", responseType: "text/plain"},
+
+		{method: "POST", path: "/api/admin/backup", tag: "admin", summary: "Download a tar.gz backup of the server state",
+			body: struct {
+				Passphrase string `json:"passphrase,omitempty"`
+			}{}, response: "", responseType: "application/gzip"},
+		{method: "POST", path: "/api/admin/restore", tag: "admin", summary: "Restore a backup archive",
+			params: []apiParam{{name: "X-Backup-Passphrase", in: "header", desc: "Passphrase the backup was encrypted with", typ: "string"}},
+			body:   "", bodyType: "application/gzip",
+			response: struct {
+				Restored []string `json:"restored"`
+			}{}},
+
+		{method: "GET", path: "/api/jobs", tag: "jobs", summary: "Background jobs, newest first", response: []jobs.Job{}},
+		{method: "GET", path: "/api/jobs/{id}", tag: "jobs", summary: "A background job, with its JSON result once it has finished",
+			params: []apiParam{pathParam("id", "Job ID")}, response: jobResponse{}},
+		{method: "DELETE", path: "/api/jobs/{id}", tag: "jobs", summary: "Cancel a job, or forget a finished one",
+			params: []apiParam{pathParam("id", "Job ID")}, response: jobs.Job{}},
+		{method: "GET", path: "/api/jobs/{id}/result", tag: "jobs", summary: "The finished job's response, as the request would have been answered",
+			params: []apiParam{pathParam("id", "Job ID")}, response: "", responseType: "application/octet-stream"},
+		{method: "GET", path: "/api/jobs/{id}/events", tag: "jobs", summary: `Server-sent "job" events with the job whenever it changes, until it finishes`,
+			params: []apiParam{pathParam("id", "Job ID")}, response: "", responseType: "text/event-stream"},
+
+		{method: "GET", path: "/api/audit", tag: "admin", summary: "Audited actions, newest first",
+			params: []apiParam{
+				queryParam("action", `Action, or a prefix ending in "." such as "profile."`),
+				queryParam("profile", "Profile active when the action ran"),
+				queryParam("result", "ok, denied or failed"),
+				queryParam("since", "Earliest time (RFC 3339 or YYYY-MM-DD)"),
+				queryParam("until", "Latest time, exclusive (RFC 3339 or YYYY-MM-DD)"),
+				queryParam("limit", "Maximum entries (1-1000, default 100)"),
+			}, response: auditResponse{}},
+
+		{method: "GET", path: "/api/config", tag: "meta", summary: "Effective server configuration",
+			response: configResponse{}},
+		{method: "GET", path: "/api/openapi.json", tag: "meta", summary: "This document", response: map[string]any{}},
+	}
+}
+
+// handleOpenAPI serves the OpenAPI 3 description of the API.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.openAPIDocument())
+}
+
+// openAPIDocument builds the OpenAPI document from apiOperations.
+func (s *Server) openAPIDocument() map[string]any {
+	gen := &schemaGen{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+	errorSchema := gen.schema(reflect.TypeOf(errorResponse{}))
+
+	paths := map[string]map[string]any{}
+	for _, op := range apiOperations() {
+		operation := map[string]any{
+			"summary":     op.summary,
+			"tags":        []string{op.tag},
+			"operationId": operationID(op),
+		}
+
+		var parameters []map[string]any
+		for _, p := range op.params {
+			parameters = append(parameters, map[string]any{
+				"name":        p.name,
+				"in":          p.in,
+				"description": p.desc,
+				"required":    p.in == "path",
+				"schema":      map[string]any{"type": p.typ},
+			})
+		}
+		if parameters != nil {
+			operation["parameters"] = parameters
+		}
+
+		if op.body != nil {
+			contentType, schema := "application/json", gen.schema(reflect.TypeOf(op.body))
+			if op.bodyType != "" {
+				contentType, schema = op.bodyType, map[string]any{"type": "string", "format": "binary"}
+			}
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{contentType: map[string]any{"schema": schema}},
+			}
+		}
+
+		status := op.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		if op.response != nil {
+			contentType, schema := "application/json", gen.schema(reflect.TypeOf(op.response))
+			if op.responseType != "" {
+				contentType, schema = op.responseType, map[string]any{"type": "string", "format": "binary"}
+			}
+			success["content"] = map[string]any{contentType: map[string]any{"schema": schema}}
+		}
+		failure := map[string]any{
+			"description": "Error",
+			"content":     map[string]any{"application/json": map[string]any{"schema": errorSchema}},
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(status): success,
+			"default":            failure,
+		}
+
+		if paths[op.path] == nil {
+			paths[op.path] = map[string]any{}
+		}
+		paths[op.path][strings.ToLower(op.method)] = operation
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "AWS Local Dashboard API",
+			"version": "1.0.0",
+			"description": "Local API for AWS cost and resource visibility, backed by the aws CLI. " +
+				"Any request may set X-AWS-Profile (profile ID or name) and X-AWS-Region to use another profile or " +
+				"default region for that request only. Requests that outlive their timeout are answered with a 504 " +
+				"whose code is \"timeout\".",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": gen.schemas},
+	}
+	if s.authToken != "" {
+		doc["components"].(map[string]any)["securitySchemes"] = map[string]any{
+			"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+		}
+		doc["security"] = []map[string]any{{"bearerAuth": []string{}}}
+	}
+	return doc
+}
+
+// operationID derives a stable operationId such as "getCostService".
+func operationID(op apiOperation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.method))
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(op.path, "/api/"), func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// schemaGen converts Go types to OpenAPI schemas, registering named structs
+// under components.schemas.
+type schemaGen struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawJSONType:
+		return map[string]any{}
+	case t.Kind() != reflect.Pointer && (t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType)):
+		// Custom encodings (ordered objects and the like) are free-form.
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			return map[string]any{"allOf": []any{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.componentName(t)
+			g.names[t] = name
+			// Register before recursing so self-references terminate.
+			g.schemas[name] = map[string]any{}
+			g.schemas[name] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+// componentName picks a schema name, qualifying it with the package when two
+// packages define types of the same name.
+func (g *schemaGen) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+// object describes a struct's JSON encoding, flattening embedded structs the
+// way encoding/json does.
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	g.addFields(t, props, &required)
+
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		obj["required"] = required
+	}
+	return obj
+}
+
+func (g *schemaGen) addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(f.Type, props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
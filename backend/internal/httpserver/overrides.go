@@ -0,0 +1,80 @@
+package httpserver
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/profiles"
+)
+
+// Headers that point a single request at another profile or region without
+// switching the dashboard's active profile.
+const (
+	profileHeader = "X-AWS-Profile"
+	regionHeader  = "X-AWS-Region"
+)
+
+// regionPattern matches AWS region names such as us-east-1 or
+// us-gov-west-1.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// withOverrides applies X-AWS-Profile (a profile ID or name) and X-AWS-Region
+// to /api requests. AWS calls made for the request then use that profile and
+// default to that region; other requests are unaffected.
+func (s *Server) withOverrides(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile := strings.TrimSpace(r.Header.Get(profileHeader))
+		region := strings.ToLower(strings.TrimSpace(r.Header.Get(regionHeader)))
+		if !strings.HasPrefix(r.URL.Path, "/api/") || (profile == "" && region == "") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		if profile != "" {
+			if s.profileManager == nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error: "Profile management not configured on server",
+				})
+				return
+			}
+			id, err := s.profileManager.ResolveProfile(profile)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "Invalid " + profileHeader + " header",
+					Details: err.Error(),
+				})
+				return
+			}
+			ctx = profiles.WithProfile(ctx, id)
+		}
+		if region != "" {
+			if !regionPattern.MatchString(region) {
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "Invalid " + regionHeader + " header",
+					Details: "Expected a region name such as us-east-1.",
+				})
+				return
+			}
+			if s.profileManager != nil && !s.profileManager.RegionAllowed(ctx, region) {
+				writeJSON(w, http.StatusForbidden, errorResponse{
+					Error:   "Region not allowed for the active profile",
+					Details: region,
+					Code:    "region_not_allowed",
+				})
+				return
+			}
+			ctx = profiles.WithRegion(ctx, region)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestRegion returns ?region=, falling back to the X-AWS-Region override.
+func requestRegion(r *http.Request) string {
+	if region := r.URL.Query().Get("region"); region != "" {
+		return region
+	}
+	return profiles.ContextRegion(r.Context())
+}
@@ -0,0 +1,102 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// maxPageLimit caps ?limit= so a single page stays a reasonable size.
+const maxPageLimit = 1000
+
+// pageRequest is the ?limit=&offset= of a resources request.
+type pageRequest struct {
+	limit  int // 0 means no limit
+	offset int
+}
+
+// parsePage reads ?limit= and ?offset=. ok is false when neither is set, in
+// which case responses are not paginated.
+func parsePage(r *http.Request) (page pageRequest, ok bool, err error) {
+	q := r.URL.Query()
+	limit, offset := q.Get("limit"), q.Get("offset")
+	if limit == "" && offset == "" {
+		return pageRequest{}, false, nil
+	}
+	if limit != "" {
+		page.limit, err = strconv.Atoi(limit)
+		if err != nil || page.limit < 1 || page.limit > maxPageLimit {
+			return pageRequest{}, false, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+	}
+	if offset != "" {
+		page.offset, err = strconv.Atoi(offset)
+		if err != nil || page.offset < 0 {
+			return pageRequest{}, false, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return page, true, nil
+}
+
+// bounds returns the slice bounds of the page within a list of n items.
+func (p pageRequest) bounds(n int) (int, int) {
+	start := min(p.offset, n)
+	end := n
+	if p.limit > 0 {
+		end = min(start+p.limit, n)
+	}
+	return start, end
+}
+
+// pagination starts the envelope for this page.
+func (p pageRequest) pagination() *types.Pagination {
+	return &types.Pagination{Limit: p.limit, Offset: p.offset, Totals: map[string]int{}}
+}
+
+// add records a list of n items in the envelope.
+func (p pageRequest) add(pg *types.Pagination, key string, n int) {
+	pg.Total += n
+	pg.Totals[key] = n
+	if _, end := p.bounds(n); end < n {
+		pg.NextOffset = end
+	}
+}
+
+// pageResources cuts each resource list in res down to the requested page.
+// The lists are resliced, not modified, so cached results stay intact.
+func pageResources(res types.ServiceResources, p pageRequest) types.ServiceResources {
+	pg := p.pagination()
+	v := reflect.ValueOf(&res).Elem()
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Slice || f.Len() == 0 {
+			continue
+		}
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		p.add(pg, key, f.Len())
+		f.Set(f.Slice(p.bounds(f.Len())))
+	}
+	res.Pagination = pg
+	return res
+}
+
+// pageMerged applies the page to the lists of a merged multi-account
+// response.
+func pageMerged(merged map[string]any, p pageRequest) {
+	pg := p.pagination()
+	for key, value := range merged {
+		items, ok := value.([]any)
+		if !ok || key == "accounts" {
+			continue
+		}
+		p.add(pg, key, len(items))
+		start, end := p.bounds(len(items))
+		merged[key] = items[start:end]
+	}
+	merged["pagination"] = pg
+}
@@ -0,0 +1,119 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/local/aws-local-dashboard/internal/events"
+	"github.com/local/aws-local-dashboard/internal/profiles"
+)
+
+// maxProfileBundleSize bounds the size of an uploaded profile bundle.
+const maxProfileBundleSize = 1 << 20
+
+// profileExportRequest is the body of POST /api/profiles/export.
+type profileExportRequest struct {
+	IDs []string `json:"ids"`
+	// IncludeSecrets keeps the access keys in the bundle.
+	IncludeSecrets bool `json:"includeSecrets"`
+}
+
+// handleProfileExport handles /api/profiles/export and downloads the custom
+// profiles as a bundle. GET ?ids=1,2 limits it to those profiles (plus their
+// base profiles) and always leaves out access keys; they are only included
+// by a POST with "includeSecrets": true, which the cross-origin guard checks,
+// so other sites can't read them.
+func (s *Server) handleProfileExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return
+	}
+
+	var req profileExportRequest
+	if r.Method == http.MethodPost {
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxProfileBundleSize)).Decode(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	} else {
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				req.IDs = append(req.IDs, id)
+			}
+		}
+	}
+
+	bundle, err := s.profileManager.ExportProfiles(req.IDs, !req.IncludeSecrets)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to export profiles",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("aws-dashboard-profiles-%s.json", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	writeJSON(w, http.StatusOK, bundle)
+}
+
+// handleProfileImport handles POST /api/profiles/import. The body is a bundle
+// from /api/profiles/export; its profiles are added alongside the existing
+// ones without changing the active profile.
+func (s *Server) handleProfileImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return
+	}
+
+	var bundle profiles.Bundle
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxProfileBundleSize)).Decode(&bundle); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	result, err := s.profileManager.ImportProfiles(bundle)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to import profiles",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	for _, p := range result.Imported {
+		s.events.Publish(events.ProfileAdded, map[string]any{"id": p.ID, "name": p.Name})
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		profiles.ImportResult
+		Status profiles.Status `json:"status"`
+	}{
+		ImportResult: result,
+		Status:       s.profileManager.Status(),
+	})
+}
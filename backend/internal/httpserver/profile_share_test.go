@@ -0,0 +1,69 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local/aws-local-dashboard/internal/profiles"
+)
+
+func TestProfileExportRedactsByDefault(t *testing.T) {
+	t.Setenv("PROFILE_STORE_PATH", filepath.Join(t.TempDir(), "profiles.json"))
+	pm := profiles.NewManager(context.Background(), profiles.Options{Offline: true})
+	_, err := pm.ImportProfiles(profiles.Bundle{
+		Version:  profiles.BundleVersion,
+		Profiles: []profiles.Profile{{Name: "prod", AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "topsecret", Source: profiles.SourceCustom}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewServer(Options{ProfileManager: pm}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		origin     string
+		wantCode   int
+		wantSecret bool
+	}{
+		{"plain GET", http.MethodGet, "", "", http.StatusOK, false},
+		{"GET from another site", http.MethodGet, "", "https://evil.example", http.StatusOK, false},
+		{"POST without opt-in", http.MethodPost, `{}`, "", http.StatusOK, false},
+		{"POST with opt-in", http.MethodPost, `{"includeSecrets":true}`, "", http.StatusOK, true},
+		{"POST with opt-in from another site", http.MethodPost, `{"includeSecrets":true}`, "https://evil.example", http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+"/api/profiles/export", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var body strings.Builder
+			if _, err := io.Copy(&body, resp.Body); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantCode, body.String())
+			}
+			if got := strings.Contains(body.String(), "topsecret"); got != tt.wantSecret {
+				t.Errorf("secret in bundle = %v, want %v: %s", got, tt.wantSecret, body.String())
+			}
+		})
+	}
+}
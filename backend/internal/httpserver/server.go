@@ -2,13 +2,28 @@ package httpserver
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/local/aws-local-dashboard/internal/alerts"
+	"github.com/local/aws-local-dashboard/internal/audit"
+	"github.com/local/aws-local-dashboard/internal/backup"
 	"github.com/local/aws-local-dashboard/internal/commands"
+	"github.com/local/aws-local-dashboard/internal/events"
+	"github.com/local/aws-local-dashboard/internal/graphql"
+	"github.com/local/aws-local-dashboard/internal/history"
+	"github.com/local/aws-local-dashboard/internal/jobs"
 	"github.com/local/aws-local-dashboard/internal/profiles"
 	"github.com/local/aws-local-dashboard/internal/services"
 	"github.com/local/aws-local-dashboard/internal/types"
@@ -19,43 +34,204 @@ type Server struct {
 	resourceService services.ResourceService
 	profileManager  *profiles.Manager
 	commandManager  *commands.Manager
+	commandIAMCheck bool
+	// permissionCheck runs checkCommandPermission before commands.
+	permissionCheck bool
+	favorites       *commands.Favorites
+	mutations       *commands.Confirmations
+	executions      *commands.Executions
+	rawMaxPages     int
+	backups         *backup.Manager
+	alerts          *alerts.Manager
+	jobs            *jobs.Manager
+	jobTimeout      time.Duration
+	history         *history.Store
 	staticDir       string
-	clearCaches     func()
+	events          *events.Bus
+	eventSocket     http.Handler
+	defaultLocale   string
+	graphql         *graphql.Schema
+	authToken       string
+	originGuard     *crossOriginGuard
+	logger          *slog.Logger
+	audit           *audit.Log
+	requestTimeout  time.Duration
+	scanTimeout     time.Duration
+	runtime         RuntimeInfo
+	cliVersion      cliVersion
+}
+
+// Options configures the HTTP server. Only CostService and ResourceService
+// are required; features whose dependency is nil respond with an error.
+type Options struct {
+	CostService     services.CostService
+	ResourceService services.ResourceService
+	ProfileManager  *profiles.Manager
+	CommandManager  *commands.Manager
+	// CommandIAMCheck verifies commands with CheckReadOnly on the profile
+	// manager before running them, replacing the raw-command allowlist.
+	CommandIAMCheck bool
+	// CommandPermissionCheck verifies with CheckPermission on the profile
+	// manager that the profile may run a command's action before running
+	// it, so a missing permission is reported by name.
+	CommandPermissionCheck bool
+	// MutatingCommands enables the curated mutating commands (see
+	// commands.MutatingCommands), each run only after a confirmation step.
+	MutatingCommands bool
+	// RawMaxPages caps the pages of raw command output followed through
+	// next-page tokens; 1 or less disables pagination.
+	RawMaxPages int
+	// Favorites, when set, enables pinning commands and saving raw ones.
+	Favorites *commands.Favorites
+	Backups   *backup.Manager
+	// Alerts, when set, enables /api/alerts and evaluates its rules after
+	// each cost refresh.
+	Alerts *alerts.Manager
+	// Jobs, when set, runs requests made with ?async=true in the background,
+	// each bounded by JobTimeout (zero means no limit).
+	Jobs       *jobs.Manager
+	JobTimeout time.Duration
+	// History, when set, serves recorded cost data at /api/cost/history.
+	History   *history.Store
+	StaticDir string
+	// Events receives server events. Caches are cleared by subscribers to
+	// events.CacheCleared, so a nil bus disables /api/cache/clear.
+	Events *events.Bus
+	// EventSocket, when set, serves live events at /api/events/ws.
+	EventSocket http.Handler
+	// DefaultLocale is used for formatted values when the request's
+	// Accept-Language names no supported locale.
+	DefaultLocale string
+	// AuthToken, when set, is required on every /api route.
+	AuthToken string
+	// TrustedOrigins may make state-changing requests from a browser
+	// besides the server's own origin.
+	TrustedOrigins []string
+	// Loopback means the server listens on a loopback address only, so
+	// requests for other host names, as sent by DNS-rebinding pages, are
+	// refused.
+	Loopback bool
+	// Logger receives one record per request. Defaults to JSON on stderr.
+	Logger *slog.Logger
+	// RequestTimeout bounds each API request and ScanTimeout resource scans
+	// (resource listings, GraphQL and ?profile=all queries); AWS calls still
+	// running are cancelled and the client gets a 504. Zero means no limit.
+	RequestTimeout time.Duration
+	ScanTimeout    time.Duration
+	// Audit, when set, records profile changes, cache clears and command
+	// executions and serves them at /api/audit.
+	Audit *audit.Log
+	// GraphQL serves the GraphQL API at /api/graphql.
+	GraphQL bool
+	// Runtime is reported by /api/config alongside the server's own settings.
+	Runtime RuntimeInfo
 }
 
 // NewServer wires HTTP routes for the API and static frontend.
-func NewServer(costService services.CostService, resourceService services.ResourceService, profileManager *profiles.Manager, commandManager *commands.Manager, staticDir string, clearCaches func()) http.Handler {
+func NewServer(opts Options) http.Handler {
 	s := &Server{
-		costService:     costService,
-		resourceService: resourceService,
-		profileManager:  profileManager,
-		commandManager:  commandManager,
-		staticDir:       staticDir,
-		clearCaches:     clearCaches,
+		costService:     opts.CostService,
+		resourceService: opts.ResourceService,
+		profileManager:  opts.ProfileManager,
+		commandManager:  opts.CommandManager,
+		commandIAMCheck: opts.CommandIAMCheck,
+		permissionCheck: opts.CommandPermissionCheck,
+		executions:      commands.NewExecutions(),
+		rawMaxPages:     opts.RawMaxPages,
+		favorites:       opts.Favorites,
+		backups:         opts.Backups,
+		alerts:          opts.Alerts,
+		jobs:            opts.Jobs,
+		jobTimeout:      opts.JobTimeout,
+		history:         opts.History,
+		staticDir:       opts.StaticDir,
+		events:          opts.Events,
+		eventSocket:     opts.EventSocket,
+		defaultLocale:   opts.DefaultLocale,
+		authToken:       opts.AuthToken,
+		originGuard:     newCrossOriginGuard(opts.TrustedOrigins, opts.Loopback),
+		logger:          opts.Logger,
+		audit:           opts.Audit,
+		requestTimeout:  opts.RequestTimeout,
+		scanTimeout:     opts.ScanTimeout,
+		runtime:         opts.Runtime,
+	}
+	if s.logger == nil {
+		s.logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	}
+	if opts.GraphQL {
+		s.graphql = s.graphqlSchema()
+	}
+	if opts.MutatingCommands {
+		s.mutations = commands.NewConfirmations()
 	}
 
 	mux := http.NewServeMux()
 
-	mux.Handle("/api/cost", loggingMiddleware(http.HandlerFunc(s.handleCost)))
-	mux.Handle("/api/services", loggingMiddleware(http.HandlerFunc(s.handleServices)))
-	mux.Handle("/api/services/", loggingMiddleware(http.HandlerFunc(s.handleServiceResources)))
-	mux.Handle("/api/resources/summary", loggingMiddleware(http.HandlerFunc(s.handleResourcesSummary)))
-	mux.Handle("/api/profiles", loggingMiddleware(http.HandlerFunc(s.handleProfiles)))
-	mux.Handle("/api/profiles/select", loggingMiddleware(http.HandlerFunc(s.handleSelectProfile)))
-	mux.Handle("/api/cache/clear", loggingMiddleware(http.HandlerFunc(s.handleCacheClear)))
-	mux.Handle("/api/commands", loggingMiddleware(http.HandlerFunc(s.handleCommands)))
-	mux.Handle("/api/commands/execute", loggingMiddleware(http.HandlerFunc(s.handleExecuteCommand)))
-	mux.Handle("/api/commands/execute-raw", loggingMiddleware(http.HandlerFunc(s.handleExecuteRawCommand)))
+	mux.Handle("/api/cost", s.route(http.HandlerFunc(s.handleCost)))
+	mux.Handle("/api/cost/history", s.route(http.HandlerFunc(s.handleCostHistory)))
+	mux.Handle("/api/cost/untagged", s.route(http.HandlerFunc(s.handleUntaggedCosts)))
+	mux.Handle("/api/cost/purchase-types", s.route(http.HandlerFunc(s.handlePurchaseTypeCosts)))
+	mux.Handle("/api/cost/status", s.route(http.HandlerFunc(s.handleCostStatus)))
+	mux.Handle("/api/cost/ec2-other", s.route(http.HandlerFunc(s.handleEC2OtherCosts)))
+	mux.Handle("/api/cost/sparklines", s.route(http.HandlerFunc(s.handleServiceSparklines)))
+	mux.Handle("/api/cost/export", s.route(http.HandlerFunc(s.handleCostExport)))
+	mux.Handle("/api/cost/timeseries", s.route(http.HandlerFunc(s.handleCostTimeSeries)))
+	mux.Handle("/api/cost/service/", s.route(http.HandlerFunc(s.handleServiceCostBreakdown)))
+	mux.Handle("/api/alerts", s.route(http.HandlerFunc(s.handleAlerts)))
+	mux.Handle("/api/alerts/", s.route(http.HandlerFunc(s.handleAlertItem)))
+	mux.Handle("/api/services", s.route(http.HandlerFunc(s.handleServices)))
+	mux.Handle("/api/services/", s.route(s.asyncable(http.HandlerFunc(s.handleServiceResources))))
+	mux.Handle("/api/resources/batch", s.route(s.asyncable(http.HandlerFunc(s.handleResourcesBatch))))
+	mux.Handle("/api/resources/summary", s.route(s.asyncable(http.HandlerFunc(s.handleResourcesSummary))))
+	mux.Handle("/api/profiles", s.route(s.audited("profile.add", http.HandlerFunc(s.handleProfiles))))
+	mux.Handle("/api/profiles/", s.route(s.audited("profile", http.HandlerFunc(s.handleProfileByID))))
+	mux.Handle("/api/profiles/export", s.route(s.audited("profile.export", http.HandlerFunc(s.handleProfileExport))))
+	mux.Handle("/api/profiles/import", s.route(s.audited("profile.import", http.HandlerFunc(s.handleProfileImport))))
+	mux.Handle("/api/profiles/multi", s.route(s.audited("profile.multi", http.HandlerFunc(s.handleMultiAccountSelection))))
+	mux.Handle("/api/profiles/select", s.route(s.audited("profile.select", http.HandlerFunc(s.handleSelectProfile))))
+	mux.Handle("/api/cache/clear", s.route(s.audited("cache.clear", http.HandlerFunc(s.handleCacheClear))))
+	mux.Handle("/api/commands", s.route(http.HandlerFunc(s.handleCommands)))
+	mux.Handle("/api/commands/execute", s.route(s.asyncable(s.audited("command.execute", http.HandlerFunc(s.handleExecuteCommand)))))
+	mux.Handle("/api/commands/execute-raw", s.route(s.asyncable(s.audited("command.execute-raw", http.HandlerFunc(s.handleExecuteRawCommand)))))
+	mux.Handle("/api/commands/saved", s.route(s.audited("command.save", http.HandlerFunc(s.handleSavedCommands))))
+	mux.Handle("/api/commands/saved/", s.route(http.HandlerFunc(s.handleSavedCommand)))
+	mux.Handle("/api/commands/mutations", s.route(s.audited("command.mutate.request", http.HandlerFunc(s.handleMutations))))
+	mux.Handle("/api/commands/mutations/confirm", s.route(s.audited("command.mutate", http.HandlerFunc(s.handleConfirmMutation))))
+	mux.Handle("/api/commands/executions", s.route(http.HandlerFunc(s.handleExecutions)))
+	mux.Handle("/api/commands/executions/", s.route(http.HandlerFunc(s.handleExecutionDownload)))
+	mux.Handle("/api/commands/", s.route(http.HandlerFunc(s.handleCommandItem)))
+	if s.graphql != nil {
+		mux.Handle("/api/graphql", s.route(http.HandlerFunc(s.handleGraphQL)))
+		mux.Handle("/api/graphql/schema", s.route(http.HandlerFunc(s.handleGraphQLSchema)))
+	}
+	if s.eventSocket != nil {
+		mux.Handle("/api/events/ws", s.route(s.eventSocket))
+	}
+	mux.Handle("/api/admin/backup", s.route(s.audited("admin.backup", http.HandlerFunc(s.handleBackup))))
+	mux.Handle("/api/admin/restore", s.route(s.audited("admin.restore", http.HandlerFunc(s.handleRestore))))
+	mux.Handle("/api/jobs", s.route(http.HandlerFunc(s.handleJobs)))
+	mux.Handle("/api/jobs/", s.route(http.HandlerFunc(s.handleJob)))
+	mux.Handle("/api/audit", s.route(http.HandlerFunc(s.handleAudit)))
+	mux.Handle("/api/config", s.route(http.HandlerFunc(s.handleConfig)))
+	mux.Handle("/api/openapi.json", s.route(http.HandlerFunc(s.handleOpenAPI)))
 
 	// SPA handler for React build output
-	mux.Handle("/", loggingMiddleware(spaHandler(staticDir, "index.html")))
+	mux.Handle("/", s.route(spaHandler(s.staticDir, "index.html")))
 
-	return mux
+	handler := s.withOverrides(mux)
+	if s.authToken != "" {
+		handler = requireToken(s.authToken, handler)
+	}
+	return s.originGuard.handler(handler)
 }
 
 type errorResponse struct {
 	Error   string `json:"error"`
 	Details string `json:"details,omitempty"`
+	// Code is a stable identifier for errors the UI acts on, such as
+	// "credentials_expired".
+	Code string `json:"code,omitempty"`
 }
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -64,34 +240,170 @@ func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// activeProfileKey identifies the active profile the same way the cost
+// service scopes its caches.
+func (s *Server) activeProfileKey() string {
+	if s.profileManager != nil {
+		if id := s.profileManager.ActiveID(); id != "" {
+			return id
+		}
+	}
+	return "system"
+}
+
+// writeCostError responds to a cost service failure, mapping Cost Explorer
+// conditions to specific statuses and a generic 500 otherwise.
+func writeCostError(w http.ResponseWriter, err error, message string) {
+	if writeCredentialError(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrCostExplorerDisabled):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error:   "Cost Explorer not enabled",
+			Details: "AWS Cost Explorer is not enabled for this account. Enable it in the AWS console to view cost data.",
+			Code:    "ce_disabled",
+		})
+	case errors.Is(err, services.ErrCostExplorerAccessDenied):
+		writeJSON(w, http.StatusForbidden, errorResponse{
+			Error:   "Access to Cost Explorer denied",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+	case errors.Is(err, services.ErrCostDataUnavailable):
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error:   "Cost data not available",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+	case errors.Is(err, services.ErrCostExplorerThrottled):
+		writeJSON(w, http.StatusTooManyRequests, errorResponse{
+			Error:   "Cost Explorer rate limit exceeded",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error:   message,
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+	}
+}
+
+// writeCredentialError responds 401 when err means the active profile must
+// re-authenticate, and reports whether it did.
+func writeCredentialError(w http.ResponseWriter, err error) bool {
+	status, resp, ok := credentialError(err)
+	if ok {
+		writeJSON(w, status, resp)
+	}
+	return ok
+}
+
+// credentialError maps expired credentials and missing MFA codes to their
+// response; ok is false for other errors.
+func credentialError(err error) (status int, resp errorResponse, ok bool) {
+	switch {
+	case errors.Is(err, services.ErrCredentialsExpired):
+		return http.StatusUnauthorized, errorResponse{
+			Error:   "AWS credentials expired",
+			Details: err.Error(),
+			Code:    "credentials_expired",
+		}, true
+	case errors.Is(err, profiles.ErrMFARequired):
+		return http.StatusUnauthorized, errorResponse{
+			Error:   "MFA code required",
+			Details: err.Error(),
+			Code:    "mfa_required",
+		}, true
+	}
+	return 0, errorResponse{}, false
+}
+
+// costFilterFromQuery reads ?service=, ?region= and ?usageType= filters. Each
+// may be repeated or comma-separated, e.g. ?service=ec2&region=us-east-1.
+func costFilterFromQuery(q url.Values) types.CostFilter {
+	var f types.CostFilter
+	for _, v := range q["service"] {
+		f.Services = append(f.Services, splitList(v)...)
+	}
+	for _, v := range q["region"] {
+		f.Regions = append(f.Regions, splitList(v)...)
+	}
+	for _, v := range q["usageType"] {
+		f.UsageTypes = append(f.UsageTypes, splitList(v)...)
+	}
+	return f
+}
+
+// resourceQueryParams are the resources query parameters that are not
+// filters.
+var resourceQueryParams = map[string]bool{
+	"region": true, "profile": true, "limit": true, "offset": true, "sort": true, "fields": true, "format": true, "list": true, "token": true, "locale": true,
+}
+
+// resourceFilterFromQuery reads resource filters from the remaining query
+// parameters: state=running, name~=web (substring), state!=terminated and
+// tag:Team=payments. Comma-separated values are alternatives.
+func resourceFilterFromQuery(q url.Values) types.ResourceFilter {
+	var f types.ResourceFilter
+	for key, values := range q {
+		if resourceQueryParams[key] {
+			continue
+		}
+		c := types.ResourceCondition{Field: key, Op: "="}
+		if field, ok := strings.CutSuffix(key, "~"); ok {
+			c.Field, c.Op = field, "~="
+		} else if field, ok := strings.CutSuffix(key, "!"); ok {
+			c.Field, c.Op = field, "!="
+		}
+		for _, v := range values {
+			c.Values = append(c.Values, splitList(v)...)
+		}
+		f = append(f, c)
+	}
+	return f
+}
+
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func (s *Server) handleCost(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
+	if wantsAllProfiles(r) {
+		s.handleMultiAccountCost(w, r, false)
+		return
+	}
+
 	q := r.URL.Query()
 	start := q.Get("start")
 	end := q.Get("end")
+	filter := costFilterFromQuery(q)
 
-	overview, err := s.costService.GetCostOverview(r.Context(), start, end)
+	overview, err := s.costService.GetCostOverview(r.Context(), start, end, filter)
 	if err != nil {
-		if err == services.ErrCostExplorerDisabled {
-			writeJSON(w, http.StatusServiceUnavailable, errorResponse{
-				Error:   "Cost Explorer not enabled",
-				Details: "AWS Cost Explorer is not enabled for this account. Enable it in the AWS console to view cost data.",
-			})
-			return
-		}
-		writeJSON(w, http.StatusInternalServerError, errorResponse{
-			Error:   "Failed to fetch cost overview",
-			Details: err.Error(),
-		})
+		writeCostError(w, err, "Failed to fetch cost overview")
 		return
 	}
 
+	s.evaluateAlertsAsync()
+
+	loc := s.negotiateLocale(w, r)
 	writeJSON(w, http.StatusOK, types.CostResponse{
-		Overview: overview,
+		Overview: formatOverview(loc, overview),
 	})
 }
 
@@ -101,46 +413,215 @@ func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if wantsAllProfiles(r) {
+		s.handleMultiAccountCost(w, r, true)
+		return
+	}
+
 	q := r.URL.Query()
 	start := q.Get("start")
 	end := q.Get("end")
+	filter := costFilterFromQuery(q)
 
-	overview, err := s.costService.GetCostOverview(r.Context(), start, end)
+	overview, err := s.costService.GetCostOverview(r.Context(), start, end, filter)
 	if err != nil {
-		if err == services.ErrCostExplorerDisabled {
-			writeJSON(w, http.StatusServiceUnavailable, errorResponse{
-				Error:   "Cost Explorer not enabled",
-				Details: "AWS Cost Explorer is not enabled for this account. Enable it in the AWS console to view cost data.",
-			})
-			return
-		}
+		writeCostError(w, err, "Failed to fetch cost overview")
+		return
+	}
+
+	svcCosts, err := s.costService.GetServiceCosts(r.Context(), start, end, filter)
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch service costs")
+		return
+	}
+
+	s.evaluateAlertsAsync()
+
+	loc := s.negotiateLocale(w, r)
+	writeJSON(w, http.StatusOK, types.ServicesResponse{
+		Overview: formatOverview(loc, overview),
+		Services: formatServiceCosts(loc, svcCosts),
+	})
+}
+
+// handleCostHistory returns cost data previously fetched for the active
+// profile, recorded between ?from and ?to (inclusive days), without querying
+// Cost Explorer.
+func (s *Server) handleCostHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.history == nil {
 		writeJSON(w, http.StatusInternalServerError, errorResponse{
-			Error:   "Failed to fetch cost overview",
-			Details: err.Error(),
+			Error: "Cost history not configured on server",
+		})
+		return
+	}
+
+	q := r.URL.Query()
+	profile := s.activeProfileKey()
+	entries := s.history.List(profile, q.Get("from"), q.Get("to"))
+	if entries == nil {
+		entries = []history.Entry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"profile": profile,
+		"entries": entries,
+	})
+}
+
+// handleCostStatus reports whether Cost Explorer is usable and how many
+// billed requests have been made. ?probe=true makes one request if nothing is
+// known yet.
+func (s *Server) handleCostStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	probe, _ := strconv.ParseBool(r.URL.Query().Get("probe"))
+	status, err := s.costService.GetStatus(r.Context(), probe)
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch Cost Explorer status")
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleServiceSparklines returns trailing twelve-month cost per service for
+// the sparklines next to each service row.
+func (s *Server) handleServiceSparklines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	sparklines, err := s.costService.GetServiceSparklines(r.Context())
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch service sparklines")
+		return
+	}
+	writeJSON(w, http.StatusOK, sparklines)
+}
+
+// handleUntaggedCosts reports usage cost missing the ?tagKey= tag (e.g.
+// "team" or "project"), per service.
+func (s *Server) handleUntaggedCosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	tagKey := strings.TrimSpace(q.Get("tagKey"))
+	if tagKey == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "tagKey is required",
 		})
 		return
 	}
 
-	svcCosts, err := s.costService.GetServiceCosts(r.Context(), start, end)
+	report, err := s.costService.GetUntaggedCosts(r.Context(), tagKey, q.Get("start"), q.Get("end"))
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch untagged costs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handlePurchaseTypeCosts splits cost by purchase option; combine with
+// ?service=ec2 to see how much compute is already discounted.
+func (s *Server) handlePurchaseTypeCosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	report, err := s.costService.GetPurchaseTypeCosts(r.Context(), q.Get("start"), q.Get("end"), costFilterFromQuery(q))
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch purchase type costs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleCostTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	series, err := s.costService.GetCostTimeSeries(r.Context(), q.Get("start"), q.Get("end"), q.Get("granularity"), costFilterFromQuery(q))
 	if err != nil {
-		if err == services.ErrCostExplorerDisabled {
-			writeJSON(w, http.StatusServiceUnavailable, errorResponse{
-				Error:   "Cost Explorer not enabled",
-				Details: "AWS Cost Explorer is not enabled for this account. Enable it in the AWS console to view cost data.",
+		if errors.Is(err, services.ErrInvalidGranularity) {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid granularity",
+				Details: err.Error(),
 			})
 			return
 		}
-		writeJSON(w, http.StatusInternalServerError, errorResponse{
-			Error:   "Failed to fetch service costs",
-			Details: err.Error(),
+		writeCostError(w, err, "Failed to fetch cost time series")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
+
+func (s *Server) handleServiceCostBreakdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Path format: /api/cost/service/{service}/breakdown. Use the escaped path
+	// so Cost Explorer names containing "/" survive the split.
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/cost/service/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[1] != "breakdown" {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Not found",
+		})
+		return
+	}
+	service, err := url.PathUnescape(parts[0])
+	if err != nil || strings.TrimSpace(service) == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "Service name is required",
 		})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, types.ServicesResponse{
-		Overview: overview,
-		Services: svcCosts,
-	})
+	q := r.URL.Query()
+	breakdown, err := s.costService.GetServiceBreakdown(r.Context(), service, q.Get("start"), q.Get("end"))
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch service cost breakdown")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, breakdown)
+}
+
+// handleEC2OtherCosts splits the "EC2 - Other" line into NAT gateway, EBS,
+// data transfer and public IP buckets.
+func (s *Server) handleEC2OtherCosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	breakdown, err := s.costService.GetEC2OtherCosts(r.Context(), q.Get("start"), q.Get("end"))
+	if err != nil {
+		writeCostError(w, err, "Failed to fetch EC2-Other cost breakdown")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, breakdown)
 }
 
 func (s *Server) handleServiceResources(w http.ResponseWriter, r *http.Request) {
@@ -168,17 +649,95 @@ func (s *Server) handleServiceResources(w http.ResponseWriter, r *http.Request)
 
 	service := parts[0]
 
-	region := r.URL.Query().Get("region")
+	region := requestRegion(r)
 
-	resources, err := s.resourceService.GetResources(r.Context(), service, region)
+	page, paged, err := parsePage(r)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResponse{
-			Error:   "Failed to fetch resources",
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid pagination",
+			Details: err.Error(),
+		})
+		return
+	}
+	sortKeys, err := parseSort(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid sort",
 			Details: err.Error(),
 		})
 		return
 	}
+	fields, err := parseFields(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid fields",
+			Details: err.Error(),
+		})
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Unsupported export format",
+			Details: "format must be json or csv",
+		})
+		return
+	}
+	var list string
+	if key := r.URL.Query().Get("list"); key != "" {
+		var ok bool
+		if list, ok = resourceListKey(key); !ok {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Invalid list",
+				Details: fmt.Sprintf("no resource list is named %q", key),
+			})
+			return
+		}
+	}
+
+	if wantsAllProfiles(r) {
+		if format == "csv" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Unsupported export format",
+				Details: "csv export covers one profile at a time",
+			})
+			return
+		}
+		s.handleMultiAccountResources(w, r, service, region)
+		return
+	}
+
+	started := time.Now()
+	resources, err := s.resourceService.GetResources(r.Context(), service, region, resourceFilterFromQuery(r.URL.Query()))
+	s.publishScan(service, region, started, countResources(resources), err)
+	if err != nil {
+		status, resp := resourceError(err)
+		writeJSON(w, status, resp)
+		return
+	}
 
+	if len(sortKeys) > 0 {
+		resources = sortResources(resources, sortKeys)
+	}
+	if paged {
+		resources = pageResources(resources, page)
+	}
+	if format == "csv" {
+		writeResourcesCSV(w, resources, region, list, fields)
+		return
+	}
+	if len(fields) > 0 {
+		selected, err := selectResourceFields(resources, fields)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{
+				Error:   "Failed to select fields",
+				Details: err.Error(),
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, selected)
+		return
+	}
 	writeJSON(w, http.StatusOK, resources)
 }
 
@@ -204,9 +763,12 @@ func (s *Server) handleResourcesSummary(w http.ResponseWriter, r *http.Request)
 		{Key: "s3", DisplayName: "S3", ResourceKey: "s3Buckets"},
 		{Key: "rekognition", DisplayName: "Rekognition", ResourceKey: "rekognitionCollections"},
 		{Key: "rds", DisplayName: "RDS", ResourceKey: "rdsInstances"},
+		{Key: "lambda", DisplayName: "Lambda", ResourceKey: "lambdaFunctions"},
+		{Key: "ebs", DisplayName: "EBS Volumes", ResourceKey: "ebsVolumes"},
 	}
 
 	ctx := r.Context()
+	started := time.Now()
 
 	type result struct {
 		Svc   svcDef
@@ -219,7 +781,7 @@ func (s *Server) handleResourcesSummary(w http.ResponseWriter, r *http.Request)
 	for _, svc := range servicesToCheck {
 		svc := svc
 		go func() {
-			res, err := s.resourceService.GetResources(ctx, svc.Key, "all")
+			res, err := s.resourceService.GetResources(ctx, svc.Key, "all", nil)
 			if err != nil {
 				resultsCh <- result{Svc: svc, Err: err}
 				return
@@ -239,6 +801,10 @@ func (s *Server) handleResourcesSummary(w http.ResponseWriter, r *http.Request)
 				count = len(res.RekognitionCollections)
 			case "rdsInstances":
 				count = len(res.RDSInstances)
+			case "lambdaFunctions":
+				count = len(res.LambdaFunctions)
+			case "ebsVolumes":
+				count = len(res.EBSVolumes)
 			}
 
 			resultsCh <- result{Svc: svc, Count: count}
@@ -263,6 +829,12 @@ func (s *Server) handleResourcesSummary(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
+	total := 0
+	for _, sum := range summaries {
+		total += sum.Count
+	}
+	s.publishScan("summary", "all", started, total, nil)
+
 	writeJSON(w, http.StatusOK, types.ResourcesSummaryResponse{
 		Summaries: summaries,
 	})
@@ -295,6 +867,19 @@ func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
 			SecretAccessKey string `json:"secretAccessKey"`
 			SessionToken    string `json:"sessionToken"`
 			Region          string `json:"region"`
+			// RoleARN makes an assume-role profile based on BaseProfileID
+			// (default "system") instead of a profile with its own keys.
+			RoleARN       string `json:"roleArn"`
+			ExternalID    string `json:"externalId"`
+			BaseProfileID string `json:"baseProfileId"`
+			// MFASerial is the MFA device ARN for profiles whose policies
+			// require MFA; activate with POST /api/profiles/{id}/mfa.
+			MFASerial string `json:"mfaSerial"`
+			// VaultProfile makes a profile that gets its credentials from
+			// `aws-vault exec`, with no keys stored by the dashboard.
+			VaultProfile string `json:"vaultProfile"`
+			// AllowedRegions restricts the profile to these regions.
+			AllowedRegions []string `json:"allowedRegions"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			writeJSON(w, http.StatusBadRequest, errorResponse{
@@ -304,15 +889,43 @@ func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		_, err := s.profileManager.AddAndActivateProfile(r.Context(), body.Name, body.AccessKeyID, body.SecretAccessKey, body.SessionToken, body.Region)
+		noteAudit(r, "", "name="+body.Name)
+		var p profiles.Profile
+		var err error
+		switch {
+		case body.VaultProfile != "":
+			p, err = s.profileManager.AddVaultProfile(r.Context(), body.Name, body.VaultProfile, body.Region)
+		case body.RoleARN != "":
+			p, err = s.profileManager.AddAssumeRoleProfile(r.Context(), body.Name, body.BaseProfileID, body.RoleARN, body.ExternalID, body.Region)
+		default:
+			p, err = s.profileManager.AddAndActivateProfile(r.Context(), body.Name, body.AccessKeyID, body.SecretAccessKey, body.SessionToken, body.Region, body.MFASerial)
+		}
 		if err != nil {
 			writeJSON(w, http.StatusBadRequest, errorResponse{
 				Error:   "Failed to add profile",
 				Details: err.Error(),
+				Code:    errorCode(err),
 			})
 			return
 		}
 
+		if len(body.AllowedRegions) > 0 {
+			if _, err := s.profileManager.SetRegions(p.ID, p.Region, body.AllowedRegions); err != nil {
+				// Don't leave an unrestricted profile behind.
+				_ = s.profileManager.DeleteProfile(p.ID)
+				writeJSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "Failed to add profile",
+					Details: err.Error(),
+					Code:    errorCode(err),
+				})
+				return
+			}
+		}
+
+		noteAudit(r, "", "id="+p.ID)
+		s.events.Publish(events.ProfileAdded, map[string]any{"id": p.ID, "name": p.Name})
+		s.events.Publish(events.ProfileSwitched, map[string]any{"id": p.ID})
+
 		writeJSON(w, http.StatusOK, s.profileManager.Status())
 		return
 	}
@@ -320,6 +933,228 @@ func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusMethodNotAllowed)
 }
 
+// handleProfileByID handles:
+// - DELETE /api/profiles/{id} : removes a custom profile and its cached data
+// - PATCH /api/profiles/{id} : renames a custom profile
+// - POST /api/profiles/{id}/mfa : activates an MFA profile with a TOTP code
+// - POST /api/profiles/{id}/validate : reports the account the profile uses
+// - POST /api/profiles/{id}/permissions : probes which features it can use
+func (s *Server) handleProfileByID(w http.ResponseWriter, r *http.Request) {
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/profiles/"), "/")
+	id, action, _ := strings.Cut(path, "/")
+	if id == "" || (action != "" && action != "mfa" && action != "validate" && action != "permissions") {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Not found",
+		})
+		return
+	}
+
+	auditAction := action
+	switch {
+	case action != "":
+	case r.Method == http.MethodDelete:
+		auditAction = "delete"
+	default:
+		auditAction = "update"
+	}
+	noteAudit(r, "profile."+auditAction, "id="+id)
+
+	switch action {
+	case "mfa":
+		s.handleProfileMFA(w, r, id)
+		return
+	case "validate":
+		s.handleValidateProfile(w, r, id)
+		return
+	case "permissions":
+		s.handleProfilePermissions(w, r, id)
+		return
+	}
+	if r.Method != http.MethodDelete && r.Method != http.MethodPatch {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		previous := s.profileManager.ActiveID()
+		if err := s.profileManager.DeleteProfile(id); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Failed to delete profile",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		// Subscribers drop the profile's cache entries.
+		s.events.Publish(events.ProfileDeleted, map[string]any{"id": id})
+		if previous == id {
+			s.events.Publish(events.ProfileSwitched, map[string]any{"id": s.profileManager.ActiveID(), "previousId": id})
+		}
+
+		writeJSON(w, http.StatusOK, s.profileManager.Status())
+		return
+	}
+
+	// Omitted fields are left unchanged.
+	var body struct {
+		Name           *string   `json:"name"`
+		Region         *string   `json:"region"`
+		AllowedRegions *[]string `json:"allowedRegions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if body.Name != nil {
+		p, err := s.profileManager.RenameProfile(id, *body.Name)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:   "Failed to rename profile",
+				Details: err.Error(),
+			})
+			return
+		}
+		s.events.Publish(events.ProfileRenamed, map[string]any{"id": p.ID, "name": p.Name})
+	}
+
+	if body.Region != nil || body.AllowedRegions != nil {
+		if !s.updateProfileRegions(w, id, body.Region, body.AllowedRegions) {
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.profileManager.Status())
+}
+
+// updateProfileRegions applies a region change to profile id, keeping any
+// value that is nil, and reports whether it succeeded (writing the error
+// response if not).
+func (s *Server) updateProfileRegions(w http.ResponseWriter, id string, region *string, allowed *[]string) bool {
+	var current profiles.PublicProfile
+	for _, p := range s.profileManager.Status().Profiles {
+		if p.ID == id {
+			current = p
+		}
+	}
+	newRegion, newAllowed := current.Region, current.AllowedRegions
+	if region != nil {
+		newRegion = *region
+	}
+	if allowed != nil {
+		newAllowed = *allowed
+	}
+
+	p, err := s.profileManager.SetRegions(id, newRegion, newAllowed)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to update profile regions",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return false
+	}
+
+	// Subscribers drop the profile's cache entries, as all-regions results
+	// depend on the allowed regions.
+	s.events.Publish(events.ProfileUpdated, map[string]any{"id": p.ID, "region": p.Region, "allowedRegions": p.AllowedRegions})
+	return true
+}
+
+// handleValidateProfile confirms which account and principal a profile
+// points at, without switching to it.
+func (s *Server) handleValidateProfile(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ident, err := s.profileManager.Identify(r.Context(), id)
+	if err != nil {
+		if writeCredentialError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to validate profile",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ident)
+}
+
+// handleProfilePermissions reports which dashboard features a profile can
+// use, so the UI can hide panels it cannot serve.
+func (s *Server) handleProfilePermissions(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	perms, err := s.profileManager.ProbePermissions(r.Context(), id)
+	if err != nil {
+		if writeCredentialError(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to probe profile permissions",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, perms)
+}
+
+// handleProfileMFA exchanges a TOTP code for session credentials of an MFA
+// profile and makes it active.
+func (s *Server) handleProfileMFA(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	previous := s.profileManager.ActiveID()
+	if _, err := s.profileManager.ActivateMFA(r.Context(), id, strings.TrimSpace(body.Code)); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to activate MFA session",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	if previous != id {
+		s.events.Publish(events.ProfileSwitched, map[string]any{"id": id, "previousId": previous})
+	}
+
+	writeJSON(w, http.StatusOK, s.profileManager.Status())
+}
+
 // handleSelectProfile handles POST /api/profiles/select to switch active profile.
 func (s *Server) handleSelectProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -345,66 +1180,320 @@ func (s *Server) handleSelectProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	noteAudit(r, "", "id="+body.ID)
+	previous := s.profileManager.ActiveID()
 	if err := s.profileManager.SetActiveProfile(body.ID); err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResponse{
 			Error:   "Failed to select profile",
 			Details: err.Error(),
+			Code:    errorCode(err),
 		})
 		return
 	}
 
+	s.events.Publish(events.ProfileSwitched, map[string]any{"id": body.ID, "previousId": previous})
+
 	writeJSON(w, http.StatusOK, s.profileManager.Status())
 }
 
 // handleCacheClear clears in-memory caches so subsequent requests refetch data.
+// The caches themselves subscribe to events.CacheCleared.
 func (s *Server) handleCacheClear(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	if s.clearCaches != nil {
-		s.clearCaches()
-	}
+	s.events.Publish(events.CacheCleared, map[string]any{"reason": "api"})
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// basic safety filter to reject obviously destructive raw AWS CLI operations.
-func isSafeAWSArgs(args []string) bool {
-	if len(args) == 0 {
-		return false
+// readOnlyPrefixes are the operation verbs the safety filter lets through.
+var readOnlyPrefixes = []string{"describe-", "list-", "get-", "lookup-", "search-", "head-"}
+
+// readOnlyCommands are read-only operations whose names don't follow the
+// verb convention, keyed by service.
+var readOnlyCommands = map[string][]string{
+	"s3": {"ls"},
+}
+
+// globalValueOptions are aws CLI global options that take a value, so the
+// value isn't mistaken for the service or operation.
+var globalValueOptions = map[string]bool{
+	"--region": true, "--profile": true, "--output": true, "--query": true,
+	"--endpoint-url": true, "--color": true, "--ca-bundle": true,
+	"--cli-read-timeout": true, "--cli-connect-timeout": true,
+	"--cli-binary-format": true,
+}
+
+// globalFlagOptions are aws CLI global options without a value.
+var globalFlagOptions = map[string]bool{
+	"--debug": true, "--no-verify-ssl": true, "--no-paginate": true,
+	"--no-sign-request": true, "--no-cli-pager": true,
+	"--cli-auto-prompt": true, "--no-cli-auto-prompt": true,
+}
+
+// unsafeOptions are options the safety filter refuses wherever they appear:
+// --endpoint-url would send signed requests to any host and --ca-bundle
+// reads a local file; --profile would run under a profile from ~/.aws
+// instead of the dashboard's, bypassing its region and read-only checks,
+// and --debug puts signed headers and session tokens in the error output
+// returned to the client; the others make read-only operations return
+// decrypted secrets or API key values.
+var unsafeOptions = map[string]bool{
+	"--endpoint-url": true, "--ca-bundle": true, "--profile": true, "--debug": true,
+	"--with-decryption": true, "--include-value": true, "--include-values": true,
+}
+
+// secretOperations are read-only operations, keyed by service, that return
+// credentials, tokens or secrets rather than a description of resources.
+var secretOperations = map[string][]string{
+	"sts":                 {"get-session-token", "get-federation-token"},
+	"secretsmanager":      {"get-secret-value"},
+	"ecr":                 {"get-login", "get-login-password", "get-authorization-token"},
+	"ecr-public":          {"get-login-password", "get-authorization-token"},
+	"codeartifact":        {"get-authorization-token"},
+	"eks":                 {"get-token"},
+	"sso":                 {"get-role-credentials"},
+	"cognito-identity":    {"get-credentials-for-identity", "get-open-id-token", "get-open-id-token-for-developer-identity"},
+	"redshift":            {"get-cluster-credentials", "get-cluster-credentials-with-iam"},
+	"redshift-serverless": {"get-credentials"},
+	"lightsail":           {"get-instance-access-details", "get-relational-database-master-user-password"},
+	"gamelift":            {"get-instance-access"},
+}
+
+// outfileOperations are read-only operations, keyed by service, that take
+// a positional local path and write their output there.
+var outfileOperations = map[string][]string{
+	"s3api":                        {"get-object", "get-object-torrent"},
+	"glacier":                      {"get-job-output"},
+	"mediastore-data":              {"get-object"},
+	"ebs":                          {"get-snapshot-block"},
+	"kinesis-video-media":          {"get-media"},
+	"kinesis-video-archived-media": {"get-clip"},
+	"apigateway":                   {"get-export", "get-sdk"},
+	"codeartifact":                 {"get-package-version-asset"},
+	"workmailmessageflow":          {"get-raw-message-content"},
+}
+
+// errNotReadOnly is the safety filter's answer for operations that aren't
+// read-only by name.
+var errNotReadOnly = errors.New("only describe, list, get, lookup, search and head operations are allowed from the dashboard")
+
+// CheckSafeAWSArgs is the safety filter for raw AWS CLI commands: it finds
+// the service and operation among args and allows only read-only operations
+// (describe-, list-, get-, lookup-, search- and head- ones, plus "s3 ls").
+// Options it can't place before the operation make it refuse the command,
+// as do arguments that would read or write local files, point the CLI at
+// another endpoint or fetch secrets.
+func CheckSafeAWSArgs(args []string) error {
+	service, operation, ok := serviceOperation(args)
+	if !ok {
+		return errNotReadOnly
 	}
-	joined := strings.ToLower(strings.Join(args, " "))
-	// Very conservative blocklist – focuses on irreversible or mutating verbs.
-	block := []string{
-		" delete-", " delete",
-		" terminate-", " terminate",
-		" stop-", " stop ",
-		" start-", " start ",
-		" reboot-", " reboot",
-		" destroy", " drop-",
-		" modify-", " update-",
-		" put-", " create-",
-		" attach-", " detach-",
+	// configure reads and writes the local credentials files.
+	if service == "configure" {
+		return errNotReadOnly
 	}
-	for _, b := range block {
-		if strings.Contains(joined, b) {
-			return false
+	readOnly := slices.Contains(readOnlyCommands[service], operation)
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(operation, prefix) && len(operation) > len(prefix) {
+			readOnly = true
 		}
 	}
+	if !readOnly {
+		return errNotReadOnly
+	}
+	if slices.Contains(secretOperations[service], operation) {
+		return fmt.Errorf("%s %s returns credentials or secrets", service, operation)
+	}
+	if slices.Contains(outfileOperations[service], operation) {
+		return fmt.Errorf("%s %s writes to a local file", service, operation)
+	}
+
+	seenOperation := false
+	// afterOption is set once an option has been seen since the operation,
+	// so what follows may be its values.
+	afterOption := false
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+		value := lower
+		if strings.HasPrefix(lower, "-") {
+			name, v, _ := strings.Cut(lower, "=")
+			if unsafeOptions[name] {
+				return fmt.Errorf("the %s option is not allowed", name)
+			}
+			value = v
+		}
+		if strings.HasPrefix(value, "file://") || strings.HasPrefix(value, "fileb://") {
+			return errors.New("file:// and fileb:// parameter values are not allowed")
+		}
+
+		switch {
+		case !seenOperation:
+			seenOperation = lower == operation
+		case strings.HasPrefix(lower, "-"):
+			afterOption = true
+		case !afterOption && !(service == "s3" && strings.HasPrefix(lower, "s3://")):
+			return fmt.Errorf("unexpected argument %q after the operation", arg)
+		}
+	}
+	return nil
+}
+
+// serviceOperation returns the first two positional arguments of an aws CLI
+// command, skipping global options and their values.
+func serviceOperation(args []string) (service, operation string, ok bool) {
+	var positional []string
+	for i := 0; i < len(args) && len(positional) < 2; i++ {
+		arg := strings.ToLower(args[i])
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			positional = append(positional, arg)
+		case strings.Contains(arg, "="):
+			name, _, _ := strings.Cut(arg, "=")
+			if !globalValueOptions[name] {
+				return "", "", false
+			}
+		case globalValueOptions[arg]:
+			i++
+		case globalFlagOptions[arg]:
+		default:
+			return "", "", false
+		}
+	}
+	if len(positional) < 2 {
+		return "", "", false
+	}
+	return positional[0], positional[1], true
+}
+
+// checkCommandReadOnly verifies args with an IAM policy simulation for the
+// active profile and reports whether the command may run, writing the error
+// response if not.
+func (s *Server) checkCommandReadOnly(w http.ResponseWriter, r *http.Request, args []string) bool {
+	if s.profileManager == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{
+			Error: "Profile management not configured on server",
+		})
+		return false
+	}
+
+	// Global options may come first ("--region us-east-1 ec2 ..."), so the
+	// service and operation are picked out as for the permission check.
+	if service, operation, ok := serviceOperation(args); ok {
+		args = []string{service, operation}
+	}
+	err := s.profileManager.CheckReadOnly(r.Context(), args)
+	switch {
+	case err == nil:
+		return true
+	case writeCredentialError(w, err):
+	case errors.Is(err, profiles.ErrNotReadOnly):
+		writeJSON(w, http.StatusForbidden, errorResponse{
+			Error:   "Command blocked by IAM policy check",
+			Details: err.Error(),
+			Code:    "not_read_only",
+		})
+	default:
+		writeJSON(w, http.StatusBadGateway, errorResponse{
+			Error:   "Failed to verify command with IAM",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+	}
+	return false
+}
+
+// checkCommandPermission verifies with an IAM policy simulation that the
+// active profile may run args' action and reports whether the command may
+// run, writing a 403 naming the missing permission if not. When the
+// simulation can't tell, the command runs and AWS has the last word.
+func (s *Server) checkCommandPermission(w http.ResponseWriter, r *http.Request, args []string) bool {
+	service, operation, ok := serviceOperation(args)
+	if s.profileManager == nil || !ok {
+		return true
+	}
+
+	err := s.profileManager.CheckPermission(r.Context(), []string{service, operation})
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, profiles.ErrMissingPermission):
+		writeJSON(w, http.StatusForbidden, errorResponse{
+			Error:   "Missing IAM permission",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return false
+	}
+	log.Printf("permission check: %v", err)
 	return true
 }
 
 // handleCommands returns the list of configured read-only AWS CLI commands.
+// ?category= and ?tag= narrow it (case-insensitive, comma-separated values
+// are alternatives) and ?group=category returns it grouped by category.
 func (s *Server) handleCommands(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	if s.commandManager == nil {
-		writeJSON(w, http.StatusOK, []commands.PublicCommand{})
+
+	q := r.URL.Query()
+	group := q.Get("group")
+	if group != "" && group != "category" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Invalid group",
+			Details: `group must be "category"`,
+		})
+		return
+	}
+
+	list := filterCommands(s.commandList(), splitList(q.Get("category")), splitList(q.Get("tag")))
+	if group == "category" {
+		writeJSON(w, http.StatusOK, commands.GroupByCategory(list))
 		return
 	}
-	writeJSON(w, http.StatusOK, s.commandManager.List())
+	writeJSON(w, http.StatusOK, list)
+}
+
+// filterCommands keeps the commands in one of categories that have one of
+// tags; an empty list matches everything.
+func filterCommands(list []commands.PublicCommand, categories, tags []string) []commands.PublicCommand {
+	out := []commands.PublicCommand{}
+	for _, c := range list {
+		if len(categories) > 0 && !containsFold(categories, c.Category) {
+			continue
+		}
+		if len(tags) > 0 && !slices.ContainsFunc(c.Tags, func(t string) bool { return containsFold(tags, t) }) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// commandList returns the configured commands, favorites first and then by
+// category and label.
+func (s *Server) commandList() []commands.PublicCommand {
+	if s.commandManager == nil {
+		return []commands.PublicCommand{}
+	}
+	list := s.commandManager.List()
+	if s.favorites != nil {
+		for i := range list {
+			list[i].Favorite = s.favorites.IsFavorite(list[i].ID)
+		}
+	}
+	// List is already ordered by category and label.
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Favorite && !list[j].Favorite
+	})
+	return list
 }
 
 // handleExecuteCommand executes a configured read-only AWS CLI command.
@@ -423,6 +1512,9 @@ func (s *Server) handleExecuteCommand(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		ID     string `json:"id"`
 		Region string `json:"region"`
+		Format string `json:"format"`
+		// DryRun reports how the command would run instead of running it.
+		DryRun bool `json:"dryRun"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResponse{
@@ -431,33 +1523,64 @@ func (s *Server) handleExecuteCommand(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !validCommandFormat(w, body.Format) {
+		return
+	}
+
+	steps, err := s.commandManager.StepArgs(body.ID, body.Region)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Error:   "Failed to execute command",
+			Details: err.Error(),
+			Code:    errorCode(err),
+		})
+		return
+	}
+	if body.DryRun {
+		noteAudit(r, "command.dry-run", steps[0]...)
+		inv, err := s.commandManager.Plan(r.Context(), body.ID, body.Region, body.Format)
+		writeDryRun(w, inv, err)
+		return
+	}
+	for i, args := range steps {
+		if i > 0 {
+			noteAudit(r, "", "|")
+		}
+		noteAudit(r, "", args...)
+	}
+	for _, args := range steps {
+		if s.commandIAMCheck && !s.checkCommandReadOnly(w, r, args) {
+			return
+		}
+		if s.permissionCheck && !s.checkCommandPermission(w, r, args) {
+			return
+		}
+	}
 
-	out, args, err := s.commandManager.Execute(r.Context(), body.ID, body.Region)
+	started := time.Now()
+	out, args, err := s.commandManager.Execute(r.Context(), body.ID, body.Region, body.Format)
+	s.publishCommand(map[string]any{"id": body.ID, "region": body.Region}, started, err)
 	if err != nil {
+		if writeCredentialError(w, err) {
+			return
+		}
 		msg := err.Error()
 		if strings.Contains(msg, "usage: aws") || strings.Contains(msg, "argument command: Invalid choice") {
 			writeJSON(w, http.StatusBadRequest, errorResponse{
 				Error:   "Invalid AWS command configuration",
-				Details: "The configured command is not a valid aws CLI command. Please check command-config.json.",
+				Details: "The configured command is not a valid aws CLI command. Please check the command config.",
 			})
 			return
 		}
 		writeJSON(w, http.StatusBadRequest, errorResponse{
 			Error:   "Failed to execute command",
 			Details: msg,
+			Code:    errorCode(err),
 		})
 		return
 	}
 
-	res := struct {
-		Command string          `json:"command"`
-		Output  json.RawMessage `json:"output"`
-	}{
-		Command: "aws " + strings.Join(args, " "),
-		Output:  json.RawMessage(out),
-	}
-
-	writeJSON(w, http.StatusOK, res)
+	writeCommandOutput(w, args, body.Format, out, s.recordExecution(r, body.ID, args, body.Region, body.Format, out))
 }
 
 // handleExecuteRawCommand executes arbitrary read-only AWS CLI commands as entered
@@ -476,6 +1599,11 @@ func (s *Server) handleExecuteRawCommand(w http.ResponseWriter, r *http.Request)
 
 	var body struct {
 		Args string `json:"args"`
+		// SavedID runs a saved command instead of Args.
+		SavedID string `json:"savedId"`
+		Format  string `json:"format"`
+		// DryRun reports how the command would run instead of running it.
+		DryRun bool `json:"dryRun"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResponse{
@@ -484,6 +1612,23 @@ func (s *Server) handleExecuteRawCommand(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	if !validCommandFormat(w, body.Format) {
+		return
+	}
+	if body.SavedID != "" {
+		saved, ok := commands.SavedCommand{}, false
+		if s.favorites != nil {
+			saved, ok = s.favorites.SavedCommand(body.SavedID)
+		}
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{
+				Error:   "Saved command not found",
+				Details: body.SavedID,
+			})
+			return
+		}
+		body.Args = saved.Args
+	}
 
 	fields := strings.Fields(body.Args)
 	if len(fields) == 0 {
@@ -493,16 +1638,38 @@ func (s *Server) handleExecuteRawCommand(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if !isSafeAWSArgs(fields) {
+	if body.DryRun {
+		noteAudit(r, "command.dry-run")
+	}
+	noteAudit(r, "", fields...)
+	if err := CheckSafeAWSArgs(fields); err != nil {
+		denyAudit(r)
 		writeJSON(w, http.StatusBadRequest, errorResponse{
 			Error:   "Command blocked by safety filter",
-			Details: "Only read/list/describe operations are allowed from the dashboard.",
+			Details: err.Error(),
 		})
 		return
 	}
+	if body.DryRun {
+		// The IAM check runs the CLI, so dry runs skip it.
+		inv, err := s.commandManager.PlanRaw(r.Context(), fields, body.Format)
+		writeDryRun(w, inv, err)
+		return
+	}
+	if s.commandIAMCheck && !s.checkCommandReadOnly(w, r, fields) {
+		return
+	}
+	if s.permissionCheck && !s.checkCommandPermission(w, r, fields) {
+		return
+	}
 
-	out, args, err := s.commandManager.ExecuteRaw(r.Context(), fields)
+	started := time.Now()
+	out, args, err := s.commandManager.ExecuteRaw(r.Context(), fields, body.Format, s.rawMaxPages)
+	s.publishCommand(map[string]any{"args": fields}, started, err)
 	if err != nil {
+		if writeCredentialError(w, err) {
+			return
+		}
 		msg := err.Error()
 		if strings.Contains(msg, "usage: aws") || strings.Contains(msg, "argument command: Invalid choice") {
 			writeJSON(w, http.StatusBadRequest, errorResponse{
@@ -514,19 +1681,12 @@ func (s *Server) handleExecuteRawCommand(w http.ResponseWriter, r *http.Request)
 		writeJSON(w, http.StatusBadRequest, errorResponse{
 			Error:   "Failed to execute command",
 			Details: msg,
+			Code:    errorCode(err),
 		})
 		return
 	}
 
-	res := struct {
-		Command string          `json:"command"`
-		Output  json.RawMessage `json:"output"`
-	}{
-		Command: "aws " + strings.Join(args, " "),
-		Output:  json.RawMessage(out),
-	}
-
-	writeJSON(w, http.StatusOK, res)
+	writeCommandOutput(w, args, body.Format, out, s.recordExecution(r, "", args, "", body.Format, out))
 }
 
 // spaHandler serves a built SPA from a static directory, falling back to index.html
@@ -554,11 +1714,3 @@ func spaHandler(staticDir, indexFile string) http.Handler {
 		http.ServeFile(w, r, filepath.Join(staticDir, indexFile))
 	})
 }
-
-// loggingMiddleware logs basic request information.
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s", r.Method, r.URL.Path)
-		next.ServeHTTP(w, r)
-	})
-}
@@ -0,0 +1,67 @@
+package httpserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSafeAWSArgs(t *testing.T) {
+	tests := []struct {
+		args string
+		safe bool
+	}{
+		{"ec2 describe-instances --region us-east-1", true},
+		{"--region us-east-1 ec2 describe-instances --instance-ids i-1 i-2", true},
+		{"ec2 describe-instances --filters Name=tag:Env,Values=prod", true},
+		{"ssm get-parameters-by-path --path /app/prod", true},
+		{"s3 ls", true},
+		{"s3 ls s3://bucket/prefix/", true},
+		{"sts get-caller-identity", true},
+
+		{"ec2 terminate-instances --instance-ids i-1", false},
+		{"configure list", false},
+		{"s3api get-object --bucket b --key k /any/path", false},
+		{"ec2 describe-instances /any/path", false},
+		{"ec2 describe-instances --cli-input-json file:///etc/passwd", false},
+		{"ec2 describe-instances --cli-input-json=FILE:///etc/passwd", false},
+		{"ec2 describe-instances --filters fileb:///etc/passwd", false},
+		{"ec2 describe-instances --endpoint-url https://evil.example", false},
+		{"--endpoint-url=https://evil.example ec2 describe-instances", false},
+		{"ec2 describe-instances --ca-bundle /etc/ssl/cert.pem", false},
+		{"sts get-session-token", false},
+		{"secretsmanager get-secret-value --secret-id db", false},
+		{"ssm get-parameter --name /db/password --with-decryption", false},
+		{"apigateway get-api-keys --include-values", false},
+		{"ecr get-login-password", false},
+		{"--profile prod ec2 describe-instances", false},
+		{"ec2 describe-instances --profile=prod", false},
+		{"ec2 describe-instances --debug", false},
+		{"--debug sts get-caller-identity", false},
+	}
+	for _, tt := range tests {
+		err := CheckSafeAWSArgs(strings.Fields(tt.args))
+		if (err == nil) != tt.safe {
+			t.Errorf("CheckSafeAWSArgs(%q) = %v, want safe %v", tt.args, err, tt.safe)
+		}
+	}
+}
+
+func TestServiceOperationSkipsGlobalOptions(t *testing.T) {
+	tests := []struct {
+		args               string
+		service, operation string
+		ok                 bool
+	}{
+		{"ec2 describe-instances", "ec2", "describe-instances", true},
+		{"--region us-east-1 ec2 describe-instances", "ec2", "describe-instances", true},
+		{"--region=us-east-1 --no-paginate rds describe-db-instances --max-items 5", "rds", "describe-db-instances", true},
+		{"--region us-east-1", "", "", false},
+		{"--unknown ec2 describe-instances", "", "", false},
+	}
+	for _, tt := range tests {
+		service, operation, ok := serviceOperation(strings.Fields(tt.args))
+		if service != tt.service || operation != tt.operation || ok != tt.ok {
+			t.Errorf("serviceOperation(%q) = %q, %q, %v; want %q, %q, %v", tt.args, service, operation, ok, tt.service, tt.operation, tt.ok)
+		}
+	}
+}
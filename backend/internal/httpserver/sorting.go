@@ -0,0 +1,212 @@
+package httpserver
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+// sortKey is one term of ?sort=, e.g. launchTime:desc.
+type sortKey struct {
+	field string // lowercased JSON field name
+	desc  bool
+}
+
+// parseSort reads ?sort=field[:asc|desc][,field...]. Later keys break ties
+// in earlier ones. Fields are checked against the resource types so a typo
+// is reported rather than ignored.
+func parseSort(q url.Values) ([]sortKey, error) {
+	var keys []sortKey
+	for _, v := range q["sort"] {
+		for _, term := range splitList(v) {
+			field, dir, _ := strings.Cut(term, ":")
+			key := sortKey{field: strings.ToLower(field)}
+			switch strings.ToLower(dir) {
+			case "", "asc":
+			case "desc":
+				key.desc = true
+			default:
+				return nil, fmt.Errorf("sort direction for %s must be asc or desc", field)
+			}
+			if !sortableField(key.field) {
+				return nil, fmt.Errorf("no resource has a sortable %q field", field)
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// sortableField reports whether any resource type has a scalar field with
+// the given lowercased JSON name.
+func sortableField(name string) bool {
+	lists := reflect.TypeOf(types.ServiceResources{})
+	for i := 0; i < lists.NumField(); i++ {
+		t := lists.Field(i).Type
+		if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := jsonFieldIndex(t.Elem(), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldIndex finds the scalar field of struct type t with the given
+// lowercased JSON name.
+func jsonFieldIndex(t reflect.Type, name string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if strings.ToLower(tag) != name {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface:
+			return 0, false
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// sortResources sorts each resource list in res by keys. Lists whose type has
+// none of the key fields keep their order. The lists are copied first so
+// cached results aren't reordered under other requests.
+func sortResources(res types.ServiceResources, keys []sortKey) types.ServiceResources {
+	v := reflect.ValueOf(&res).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		list := v.Field(i)
+		if list.Kind() != reflect.Slice || list.Type().Elem().Kind() != reflect.Struct || list.Len() < 2 {
+			continue
+		}
+
+		elem := list.Type().Elem()
+		var fields []int
+		var fieldKeys []sortKey
+		for _, k := range keys {
+			if fi, ok := jsonFieldIndex(elem, k.field); ok {
+				fields = append(fields, fi)
+				fieldKeys = append(fieldKeys, k)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		items := make([]reflect.Value, list.Len())
+		for j := range items {
+			items[j] = list.Index(j)
+		}
+		slices.SortStableFunc(items, func(a, b reflect.Value) int {
+			for n, fi := range fields {
+				if c := compareSortValues(a.Field(fi).Interface(), b.Field(fi).Interface(), fieldKeys[n].desc); c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+
+		sorted := reflect.MakeSlice(list.Type(), len(items), len(items))
+		for j, item := range items {
+			sorted.Index(j).Set(item)
+		}
+		list.Set(sorted)
+	}
+	return res
+}
+
+// sortMerged sorts the lists of a merged multi-account response, whose items
+// are decoded JSON objects.
+func sortMerged(merged map[string]any, keys []sortKey) {
+	for key, value := range merged {
+		items, ok := value.([]any)
+		if !ok || key == "accounts" {
+			continue
+		}
+		slices.SortStableFunc(items, func(a, b any) int {
+			objA, _ := a.(map[string]any)
+			objB, _ := b.(map[string]any)
+			for _, k := range keys {
+				if c := compareSortValues(lookupFold(objA, k.field), lookupFold(objB, k.field), k.desc); c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+}
+
+func lookupFold(obj map[string]any, name string) any {
+	for k, v := range obj {
+		if strings.ToLower(k) == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// compareSortValues orders numbers numerically, strings case-insensitively
+// and false before true. Missing values sort last in either direction, so
+// empty fields such as an unset launch time don't lead a descending list.
+func compareSortValues(a, b any, desc bool) int {
+	na, nb := isEmptySortValue(a), isEmptySortValue(b)
+	switch {
+	case na && nb:
+		return 0
+	case na:
+		return 1
+	case nb:
+		return -1
+	}
+
+	var c int
+	switch x := a.(type) {
+	case string:
+		y, _ := b.(string)
+		c = cmp.Compare(strings.ToLower(x), strings.ToLower(y))
+	case bool:
+		y, _ := b.(bool)
+		c = cmp.Compare(boolRank(x), boolRank(y))
+	default:
+		c = cmp.Compare(sortNumber(a), sortNumber(b))
+	}
+	if desc {
+		return -c
+	}
+	return c
+}
+
+func isEmptySortValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && s == ""
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortNumber(v any) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return 0
+}
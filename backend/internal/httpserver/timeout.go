@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// route applies the middleware every route shares: request logging, then the
+// route's timeout, so logs show the 504 a timed-out request got.
+func (s *Server) route(next http.Handler) http.Handler {
+	return s.loggingMiddleware(s.withTimeouts(next))
+}
+
+// routeTimeout returns how long a request may run before its context is
+// cancelled, or 0 for no limit. Resource scans, which may cover every region
+// or every multi-account profile, get the longer scan timeout.
+func (s *Server) routeTimeout(r *http.Request) time.Duration {
+	path := r.URL.Path
+	switch {
+	case !strings.HasPrefix(path, "/api/"):
+		return 0
+	case path == "/api/events/ws",
+		strings.HasPrefix(path, "/api/jobs/") && strings.HasSuffix(path, "/events"):
+		// Long-lived by design.
+		return 0
+	case strings.HasPrefix(path, "/api/services/"),
+		path == "/api/resources/summary",
+		path == "/api/resources/batch",
+		path == "/api/graphql",
+		wantsAllProfiles(r):
+		return s.scanTimeout
+	}
+	return s.requestTimeout
+}
+
+// withTimeouts cancels each request's context after its route timeout. The
+// aws CLI runs under that context, so the subprocess is killed too, and a
+// handler that fails because of it is answered with a 504 instead of its own
+// error.
+func (s *Server) withTimeouts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveWithTimeout(w, r, next, s.routeTimeout(r))
+	})
+}
+
+// serveWithTimeout serves r with next, cancelling it after timeout (if
+// positive) as withTimeouts does.
+func serveWithTimeout(w http.ResponseWriter, r *http.Request, next http.Handler, timeout time.Duration) {
+	if timeout <= 0 {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	tw := &timeoutWriter{ResponseWriter: w, ctx: ctx, timeout: timeout}
+	next.ServeHTTP(tw, r.WithContext(ctx))
+	if !tw.wrote && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		tw.WriteHeader(http.StatusGatewayTimeout)
+	}
+}
+
+// timeoutWriter replaces an error response written after the deadline
+// passed with a 504.
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	timeout  time.Duration
+	wrote    bool
+	timedOut bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if tw.wrote {
+		return
+	}
+	tw.wrote = true
+	if status >= http.StatusBadRequest && errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		// Drop headers the handler set for its own body.
+		tw.ResponseWriter.Header().Del("Content-Disposition")
+		writeJSON(tw.ResponseWriter, http.StatusGatewayTimeout, errorResponse{
+			Error:   "Request timed out",
+			Details: "The request did not finish within " + tw.timeout.String() + "; AWS calls still running were cancelled.",
+			Code:    "timeout",
+		})
+		return
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	if !tw.wrote {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.timedOut {
+		// The handler's error body is discarded in favour of the 504.
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+func (tw *timeoutWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,237 @@
+// Package jmespath evaluates the subset of JMESPath (https://jmespath.org)
+// command pipelines need to pick values out of aws CLI output: identifiers,
+// sub-expressions (a.b), indexes (a[0], a[-1]), wildcard (a[*], a.*),
+// flatten (a[]) and filter (a[?b=='x']) projections, comparisons, &&, ||,
+// !, pipes, @, parentheses and raw ('x') or JSON (`1`) literals. Slices,
+// multi-selects and functions are not supported.
+package jmespath
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Expression is a compiled JMESPath expression.
+type Expression struct {
+	src  string
+	root node
+}
+
+// Compile parses expr.
+func Compile(expr string) (*Expression, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JMESPath %q: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JMESPath %q: %w", expr, err)
+	}
+	return &Expression{src: expr, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string { return e.src }
+
+// Search evaluates the expression against data, as decoded by encoding/json
+// into an any.
+func (e *Expression) Search(data any) any {
+	return e.root.eval(data)
+}
+
+type node interface {
+	eval(v any) any
+}
+
+// identity is @, the current value.
+type identity struct{}
+
+func (identity) eval(v any) any { return v }
+
+type literal struct{ value any }
+
+func (n literal) eval(any) any { return n.value }
+
+type field struct{ name string }
+
+func (n field) eval(v any) any {
+	if m, ok := v.(map[string]any); ok {
+		return m[n.name]
+	}
+	return nil
+}
+
+// subexpr evaluates right on the result of left (a.b, a | b).
+type subexpr struct{ left, right node }
+
+func (n subexpr) eval(v any) any { return n.right.eval(n.left.eval(v)) }
+
+type index struct {
+	left node
+	i    int
+}
+
+func (n index) eval(v any) any {
+	list, ok := n.left.eval(v).([]any)
+	if !ok {
+		return nil
+	}
+	i := n.i
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil
+	}
+	return list[i]
+}
+
+// projection evaluates right on every element of the list left yields,
+// keeping the non-null results.
+type projection struct{ left, right node }
+
+func (n projection) eval(v any) any {
+	list, ok := n.left.eval(v).([]any)
+	if !ok {
+		return nil
+	}
+	return project(list, n.right)
+}
+
+// valueProjection is a projection over the values of an object (a.*).
+type valueProjection struct{ left, right node }
+
+func (n valueProjection) eval(v any) any {
+	m, ok := n.left.eval(v).(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]any, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, m[k])
+	}
+	return project(list, n.right)
+}
+
+// filterProjection is a projection over the elements for which cond holds.
+type filterProjection struct{ left, cond, right node }
+
+func (n filterProjection) eval(v any) any {
+	list, ok := n.left.eval(v).([]any)
+	if !ok {
+		return nil
+	}
+	kept := make([]any, 0, len(list))
+	for _, elem := range list {
+		if truthy(n.cond.eval(elem)) {
+			kept = append(kept, elem)
+		}
+	}
+	return project(kept, n.right)
+}
+
+func project(list []any, right node) []any {
+	out := make([]any, 0, len(list))
+	for _, elem := range list {
+		if r := right.eval(elem); r != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// flatten merges the lists inside the list left yields into it.
+type flatten struct{ left node }
+
+func (n flatten) eval(v any) any {
+	list, ok := n.left.eval(v).([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]any, 0, len(list))
+	for _, elem := range list {
+		if inner, ok := elem.([]any); ok {
+			out = append(out, inner...)
+		} else {
+			out = append(out, elem)
+		}
+	}
+	return out
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (n comparison) eval(v any) any {
+	l, r := n.left.eval(v), n.right.eval(v)
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(l, r)
+	case "!=":
+		return !reflect.DeepEqual(l, r)
+	}
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil
+	}
+	switch n.op {
+	case "<":
+		return lf < rf
+	case "<=":
+		return lf <= rf
+	case ">":
+		return lf > rf
+	default:
+		return lf >= rf
+	}
+}
+
+type and struct{ left, right node }
+
+func (n and) eval(v any) any {
+	if l := n.left.eval(v); !truthy(l) {
+		return l
+	}
+	return n.right.eval(v)
+}
+
+type or struct{ left, right node }
+
+func (n or) eval(v any) any {
+	if l := n.left.eval(v); truthy(l) {
+		return l
+	}
+	return n.right.eval(v)
+}
+
+type not struct{ expr node }
+
+func (n not) eval(v any) any { return !truthy(n.expr.eval(v)) }
+
+// truthy reports whether v is true in JMESPath terms: false, null and empty
+// strings, lists and objects are false.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testData = `{
+	"Reservations": [
+		{"Instances": [
+			{"InstanceId": "i-1", "State": {"Name": "running"}, "Tags": [{"Key": "Name", "Value": "web"}], "CpuCount": 2},
+			{"InstanceId": "i-2", "State": {"Name": "stopped"}, "Tags": [], "CpuCount": 8}
+		]},
+		{"Instances": [
+			{"InstanceId": "i-3", "State": {"Name": "running"}, "CpuCount": 4}
+		]}
+	],
+	"Regions": {"b": {"Name": "us-west-2"}, "a": {"Name": "us-east-1"}},
+	"Nested": [[1, 2], 3, [4, [5]]],
+	"with space": "quoted",
+	"Empty": [],
+	"Flag": false
+}`
+
+func TestSearch(t *testing.T) {
+	var data any
+	if err := json.Unmarshal([]byte(testData), &data); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// Identifiers, sub-expressions and indexes.
+		{"Reservations[0].Instances[0].InstanceId", `"i-1"`},
+		{"Reservations[-1].Instances[0].State.Name", `"running"`},
+		{`"with space"`, `"quoted"`},
+		{"Reservations[5]", `null`},
+		{"Missing.Field", `null`},
+		{"@.Flag", `false`},
+
+		// Projections.
+		{"Reservations[*].Instances[*].InstanceId", `[["i-1", "i-2"], ["i-3"]]`},
+		{"Reservations[0].Instances[*].State.Name", `["running", "stopped"]`},
+		{"Regions.*.Name", `["us-east-1", "us-west-2"]`},
+		{"Reservations[0].Instances[*].Tags[0].Value", `["web"]`},
+		{"Regions[*]", `null`},
+
+		// Flatten.
+		{"Reservations[].Instances[].InstanceId", `["i-1", "i-2", "i-3"]`},
+		{"Nested[]", `[1, 2, 3, 4, [5]]`},
+		{"Nested[][]", `[1, 2, 3, 4, 5]`},
+		{"Empty[]", `[]`},
+
+		// Filters.
+		{"Reservations[].Instances[?State.Name=='running'].InstanceId", `[["i-1"], ["i-3"]]`},
+		{"Reservations[].Instances[] | [?State.Name=='running'].InstanceId", `["i-1", "i-3"]`},
+		{"Reservations[].Instances[] | [?State.Name!='running'].InstanceId", `["i-2"]`},
+		{"Reservations[].Instances[] | [?CpuCount > `2`].InstanceId", `["i-2", "i-3"]`},
+		{"Reservations[].Instances[] | [?CpuCount <= `4` && Tags].InstanceId", `["i-1"]`},
+		{"Reservations[].Instances[] | [?CpuCount == `8` || InstanceId == 'i-3'].InstanceId", `["i-2", "i-3"]`},
+		{"Reservations[].Instances[] | [?!Tags].InstanceId", `["i-2", "i-3"]`},
+		{"Reservations[].Instances[] | [?Tags[?Key=='Name']].InstanceId", `["i-1"]`},
+		{"Reservations[].Instances[] | [?CpuCount > 'x'].InstanceId", `[]`},
+
+		// Pipes stop projections.
+		{"Reservations[].Instances[].InstanceId | [0]", `"i-1"`},
+		{"Reservations[*].Instances | [1]", `[{"InstanceId": "i-3", "State": {"Name": "running"}, "CpuCount": 4}]`},
+		{"Regions | a | Name", `"us-east-1"`},
+
+		// Literals, parentheses and boolean operators.
+		{"'it\\'s'", `"it's"`},
+		{"`{\"a\": [1]}`", `{"a": [1]}`},
+		{"Flag || 'default'", `"default"`},
+		{"Empty && 'x'", `[]`},
+		{"!(Flag || Empty)", `true`},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var want any
+		if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+			t.Fatal(err)
+		}
+		if got := e.Search(data); !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.expr, got, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "unexpected end of expression"},
+		{"a.", "unexpected end of expression"},
+		{"a..b", `unexpected "." at 2`},
+		{"a[", "unexpected end of expression"},
+		{"a[0", "expected ']' at end of expression"},
+		{"a[x]", `unexpected "x" at 2`},
+		{"a[?b=='c'", "expected ']' at end of expression"},
+		{"(a", "expected ')' at end of expression"},
+		{"a)", `unexpected ")" at 1`},
+		{"a b", `unexpected "b" at 2`},
+		{"a[*]b", `unexpected "b" at 4`},
+		{"'abc", "unterminated ' at 0"},
+		{`"abc`, `unterminated " at 0`},
+		{"`{`", "bad JSON literal at 0"},
+		{`"\q"`, "bad quoted identifier at 0"},
+		{"a[-]", "bad number at 2"},
+		{"a # b", `unexpected '#' at 2`},
+		{"a | ", "unexpected end of expression"},
+		{"== a", `unexpected "==" at 0`},
+		// Slices, multi-selects and functions are outside the subset.
+		{"a[0:2]", `":" at 3 is not supported`},
+		{"[a, b]", "multi-select lists are not supported (at 0)"},
+		{"{x: a}", `"{" at 0 is not supported`},
+		{"length(a)", "functions are not supported (at 6)"},
+		{"sort_by(a, &b)", "functions are not supported (at 7)"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+// TestCompileNeverPanics compiles every prefix of well-formed expressions,
+// which covers most ways an expression can be cut short.
+func TestCompileNeverPanics(t *testing.T) {
+	var data any
+	if err := json.Unmarshal([]byte(testData), &data); err != nil {
+		t.Fatal(err)
+	}
+	exprs := []string{
+		"Reservations[].Instances[?State.Name=='running' && !(CpuCount < `4`)].Tags[*].Value | [0]",
+		`Regions.*."Name" || @[-1][*]`,
+		"a[?b[?c==`[1, {\"d\": 2}]`]].e[]",
+		"!!a[0].b[*].c[]",
+	}
+	for _, expr := range exprs {
+		for i := 0; i <= len(expr); i++ {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("Compile(%q) panicked: %v", expr[:i], r)
+					}
+				}()
+				if e, err := Compile(expr[:i]); err == nil {
+					e.Search(data)
+				}
+			}()
+		}
+	}
+}
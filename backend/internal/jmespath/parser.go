@@ -0,0 +1,382 @@
+package jmespath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdentifier
+	tokQuoted
+	tokLiteral
+	tokNumber
+	tokDot
+	tokStar
+	tokAt
+	tokLBracket
+	tokRBracket
+	tokFlatten
+	tokFilter
+	tokLParen
+	tokRParen
+	tokPipe
+	tokOr
+	tokAnd
+	tokNot
+	tokCompare
+	tokUnsupported
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// bindingPower is how tightly each token binds to the expression on its
+// left, as in the JMESPath reference parser.
+var bindingPower = map[tokenKind]int{
+	tokPipe:     1,
+	tokOr:       2,
+	tokAnd:      3,
+	tokCompare:  5,
+	tokFlatten:  9,
+	tokStar:     20,
+	tokFilter:   21,
+	tokDot:      40,
+	tokNot:      45,
+	tokLBracket: 55,
+	tokLParen:   60,
+}
+
+// projectionStop is the binding power below which tokens end a projection.
+const projectionStop = 10
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '_' || unicode.IsLetter(rune(c)):
+			for i < len(s) && (s[i] == '_' || unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdentifier, text: s[start:i], pos: start})
+			continue
+		case c == '-' || unicode.IsDigit(rune(c)):
+			i++
+			for i < len(s) && unicode.IsDigit(rune(s[i])) {
+				i++
+			}
+			n, err := strconv.Atoi(s[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("bad number at %d", start)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: s[start:i], value: n, pos: start})
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			end, err := closing(s, i)
+			if err != nil {
+				return nil, err
+			}
+			body := s[i+1 : end]
+			i = end + 1
+			t := token{pos: start, text: s[start:i]}
+			switch c {
+			case '"':
+				var name string
+				if err := json.Unmarshal([]byte(s[start:i]), &name); err != nil {
+					return nil, fmt.Errorf("bad quoted identifier at %d", start)
+				}
+				t.kind, t.text = tokQuoted, name
+			case '\'':
+				t.kind, t.value = tokLiteral, strings.ReplaceAll(body, `\'`, `'`)
+			default:
+				var v any
+				if err := json.Unmarshal([]byte(strings.ReplaceAll(body, "\\`", "`")), &v); err != nil {
+					return nil, fmt.Errorf("bad JSON literal at %d", start)
+				}
+				t.kind, t.value = tokLiteral, v
+			}
+			tokens = append(tokens, t)
+			continue
+		}
+
+		two := ""
+		if i+1 < len(s) {
+			two = s[i : i+2]
+		}
+		switch {
+		case two == "[]":
+			tokens = append(tokens, token{kind: tokFlatten, text: two, pos: i})
+			i += 2
+		case two == "[?":
+			tokens = append(tokens, token{kind: tokFilter, text: two, pos: i})
+			i += 2
+		case two == "||":
+			tokens = append(tokens, token{kind: tokOr, text: two, pos: i})
+			i += 2
+		case two == "&&":
+			tokens = append(tokens, token{kind: tokAnd, text: two, pos: i})
+			i += 2
+		case two == "==" || two == "!=" || two == "<=" || two == ">=":
+			tokens = append(tokens, token{kind: tokCompare, text: two, pos: i})
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, token{kind: tokCompare, text: string(c), pos: i})
+			i++
+		default:
+			kind, ok := map[byte]tokenKind{
+				'.': tokDot, '*': tokStar, '@': tokAt, '[': tokLBracket, ']': tokRBracket,
+				'(': tokLParen, ')': tokRParen, '|': tokPipe, '!': tokNot,
+				'{': tokUnsupported, '}': tokUnsupported, ',': tokUnsupported, ':': tokUnsupported, '&': tokUnsupported,
+			}[c]
+			if !ok {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{kind: kind, text: string(c), pos: i})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+// closing returns the index of the quote closing the one at s[start],
+// skipping backslash escapes.
+func closing(s string, start int) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[start]:
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c at %d", s[start], start)
+}
+
+// parser is a Pratt parser over the JMESPath grammar subset.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parse() (node, error) {
+	n, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		switch t.kind {
+		case tokEOF:
+			return fmt.Errorf("expected %s at end of expression", what)
+		case tokUnsupported:
+			return p.unexpected(t)
+		}
+		return fmt.Errorf("expected %s at %d, found %q", what, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	switch t.kind {
+	case tokEOF:
+		return fmt.Errorf("unexpected end of expression")
+	case tokUnsupported:
+		return fmt.Errorf("%q at %d is not supported (no slices, multi-selects or functions)", t.text, t.pos)
+	}
+	return fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+func (p *parser) expression(bp int) (node, error) {
+	left, err := p.nud(p.next())
+	if err != nil {
+		return nil, err
+	}
+	for bp < bindingPower[p.peek().kind] {
+		if left, err = p.led(p.next(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// nud parses a token that starts an expression.
+func (p *parser) nud(t token) (node, error) {
+	switch t.kind {
+	case tokIdentifier, tokQuoted:
+		return field{name: t.text}, nil
+	case tokLiteral:
+		return literal{value: t.value}, nil
+	case tokAt:
+		return identity{}, nil
+	case tokStar:
+		right, err := p.projectionRHS(bindingPower[tokStar])
+		if err != nil {
+			return nil, err
+		}
+		return valueProjection{left: identity{}, right: right}, nil
+	case tokFlatten:
+		return p.led(t, identity{})
+	case tokFilter:
+		return p.led(t, identity{})
+	case tokLBracket:
+		if k := p.peek().kind; k != tokNumber && k != tokStar {
+			return nil, fmt.Errorf("multi-select lists are not supported (at %d)", t.pos)
+		}
+		return p.led(t, identity{})
+	case tokNot:
+		expr, err := p.expression(bindingPower[tokNot])
+		if err != nil {
+			return nil, err
+		}
+		return not{expr: expr}, nil
+	case tokLParen:
+		expr, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(tokRParen, "')'")
+	}
+	return nil, p.unexpected(t)
+}
+
+// led parses a token that continues the expression left.
+func (p *parser) led(t token, left node) (node, error) {
+	switch t.kind {
+	case tokDot:
+		if p.peek().kind == tokStar {
+			p.next()
+			right, err := p.projectionRHS(bindingPower[tokDot])
+			if err != nil {
+				return nil, err
+			}
+			return valueProjection{left: left, right: right}, nil
+		}
+		right, err := p.dotRHS()
+		if err != nil {
+			return nil, err
+		}
+		return subexpr{left: left, right: right}, nil
+	case tokPipe:
+		right, err := p.expression(bindingPower[tokPipe])
+		if err != nil {
+			return nil, err
+		}
+		return subexpr{left: left, right: right}, nil
+	case tokOr, tokAnd:
+		right, err := p.expression(bindingPower[t.kind])
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tokOr {
+			return or{left: left, right: right}, nil
+		}
+		return and{left: left, right: right}, nil
+	case tokCompare:
+		right, err := p.expression(bindingPower[tokCompare])
+		if err != nil {
+			return nil, err
+		}
+		return comparison{op: t.text, left: left, right: right}, nil
+	case tokFlatten:
+		right, err := p.projectionRHS(bindingPower[tokFlatten])
+		if err != nil {
+			return nil, err
+		}
+		return projection{left: flatten{left: left}, right: right}, nil
+	case tokFilter:
+		cond, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRBracket, "']'"); err != nil {
+			return nil, err
+		}
+		right, err := p.projectionRHS(bindingPower[tokFilter])
+		if err != nil {
+			return nil, err
+		}
+		return filterProjection{left: left, cond: cond, right: right}, nil
+	case tokLParen:
+		return nil, fmt.Errorf("functions are not supported (at %d)", t.pos)
+	case tokLBracket:
+		switch inner := p.next(); inner.kind {
+		case tokNumber:
+			if err := p.expect(tokRBracket, "']'"); err != nil {
+				return nil, err
+			}
+			return index{left: left, i: inner.value.(int)}, nil
+		case tokStar:
+			if err := p.expect(tokRBracket, "']'"); err != nil {
+				return nil, err
+			}
+			right, err := p.projectionRHS(bindingPower[tokStar])
+			if err != nil {
+				return nil, err
+			}
+			return projection{left: left, right: right}, nil
+		default:
+			return nil, p.unexpected(inner)
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// dotRHS parses what follows a '.'.
+func (p *parser) dotRHS() (node, error) {
+	t := p.next()
+	if t.kind == tokIdentifier || t.kind == tokQuoted {
+		return field{name: t.text}, nil
+	}
+	return nil, p.unexpected(t)
+}
+
+// projectionRHS parses the expression a projection applies to each element,
+// which ends at the first token binding less tightly than projections.
+func (p *parser) projectionRHS(bp int) (node, error) {
+	switch t := p.peek(); {
+	case bindingPower[t.kind] < projectionStop:
+		return identity{}, nil
+	case t.kind == tokLBracket || t.kind == tokFilter:
+		return p.expression(bp)
+	case t.kind == tokDot:
+		p.next()
+		switch next := p.peek(); next.kind {
+		case tokIdentifier, tokQuoted, tokStar:
+			return p.expression(bp)
+		default:
+			return nil, p.unexpected(next)
+		}
+	default:
+		return nil, p.unexpected(t)
+	}
+}
@@ -0,0 +1,261 @@
+// Package jobs runs slow API requests in the background so clients can poll
+// for, or be notified of, their results instead of holding a request open.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is where a job is in its lifecycle.
+type Status string
+
+const (
+	Queued    Status = "queued"
+	Running   Status = "running"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Canceled  Status = "canceled"
+)
+
+// Finished reports whether the job has stopped and won't change again.
+func (s Status) Finished() bool {
+	return s == Succeeded || s == Failed || s == Canceled
+}
+
+const (
+	// maxQueued caps jobs waiting for a worker.
+	maxQueued = 100
+	// retention is how long a finished job and its output are kept.
+	retention = time.Hour
+)
+
+// ErrQueueFull is returned by Submit when too many jobs are waiting.
+var ErrQueueFull = errors.New("too many jobs are queued")
+
+// Output is the response a job's request produced.
+type Output struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Func runs the job with the given id. It should stop when ctx is cancelled.
+type Func func(ctx context.Context, id string) Output
+
+// Job describes a submitted job. Its output is fetched separately.
+type Job struct {
+	ID string `json:"id"`
+	// Request is what the job runs, e.g. "GET /api/services/ec2/resources?region=all".
+	Request    string     `json:"request"`
+	Status     Status     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// StatusCode is the HTTP status the request finished with.
+	StatusCode int `json:"statusCode,omitempty"`
+}
+
+type entry struct {
+	job      Job
+	fn       Func
+	ctx      context.Context
+	cancel   context.CancelFunc
+	output   Output
+	watchers map[chan struct{}]bool
+}
+
+// Manager runs jobs on a fixed pool of workers.
+type Manager struct {
+	mu    sync.Mutex
+	jobs  map[string]*entry
+	queue chan *entry
+}
+
+// NewManager starts a Manager with the given number of workers.
+func NewManager(workers int) *Manager {
+	if workers < 1 {
+		workers = 1
+	}
+	m := &Manager{jobs: map[string]*entry{}, queue: make(chan *entry, maxQueued)}
+	for i := 0; i < workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// Submit queues fn. The job runs with ctx's values, such as the active
+// profile, but not its cancellation, so it outlives the submitting request.
+func (m *Manager) Submit(ctx context.Context, request string, fn Func) (Job, error) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e := &entry{
+		job:      Job{ID: newID(), Request: request, Status: Queued, CreatedAt: time.Now().UTC()},
+		fn:       fn,
+		ctx:      ctx,
+		cancel:   cancel,
+		watchers: map[chan struct{}]bool{},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked()
+	select {
+	case m.queue <- e:
+	default:
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	m.jobs[e.job.ID] = e
+	return e.job, nil
+}
+
+// Get returns the job with the given id.
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return e.job, true
+}
+
+// Output returns the output of a finished job.
+func (m *Manager) Output(id string) (Output, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok || !e.job.Status.Finished() {
+		return Output{}, false
+	}
+	return e.output, true
+}
+
+// List returns all retained jobs, newest first.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked()
+
+	list := make([]Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		list = append(list, e.job)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job removes it.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+	switch e.job.Status {
+	case Queued:
+		// The worker that picks it up skips it.
+		m.finishLocked(e, Canceled, Output{})
+	case Running:
+		// The worker marks it cancelled once fn returns.
+	default:
+		delete(m.jobs, id)
+	}
+	e.cancel()
+	return nil
+}
+
+// Watch returns a channel that receives a value whenever the job changes,
+// and a function to stop watching. Changes may be coalesced, so watchers
+// should read the job again with Get.
+func (m *Manager) Watch(id string) (<-chan struct{}, func(), bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, nil, false
+	}
+	ch := make(chan struct{}, 1)
+	e.watchers[ch] = true
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(e.watchers, ch)
+	}, true
+}
+
+func (m *Manager) work() {
+	for e := range m.queue {
+		m.mu.Lock()
+		if e.job.Status != Queued {
+			m.mu.Unlock()
+			continue
+		}
+		now := time.Now().UTC()
+		e.job.Status = Running
+		e.job.StartedAt = &now
+		m.notifyLocked(e)
+		m.mu.Unlock()
+
+		out := e.fn(e.ctx, e.job.ID)
+
+		m.mu.Lock()
+		status := Succeeded
+		switch {
+		case errors.Is(e.ctx.Err(), context.Canceled):
+			status = Canceled
+		case out.StatusCode >= http.StatusBadRequest:
+			status = Failed
+		}
+		m.finishLocked(e, status, out)
+		m.mu.Unlock()
+		e.cancel()
+	}
+}
+
+// finishLocked records the outcome of e. Caller must hold m.mu.
+func (m *Manager) finishLocked(e *entry, status Status, out Output) {
+	now := time.Now().UTC()
+	e.job.Status = status
+	e.job.FinishedAt = &now
+	e.job.StatusCode = out.StatusCode
+	e.output = out
+	m.notifyLocked(e)
+}
+
+// notifyLocked wakes the job's watchers. Caller must hold m.mu.
+func (m *Manager) notifyLocked(e *entry) {
+	for ch := range e.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// pruneLocked drops jobs that finished more than retention ago. Caller must
+// hold m.mu.
+func (m *Manager) pruneLocked() {
+	cutoff := time.Now().Add(-retention)
+	for id, e := range m.jobs {
+		if e.job.FinishedAt != nil && e.job.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func newID() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
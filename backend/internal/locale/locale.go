@@ -0,0 +1,235 @@
+package locale
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale describes how numbers, currencies and dates are displayed.
+type Locale struct {
+	// Tag is the BCP 47 language tag, e.g. "en-US".
+	Tag string
+
+	Decimal string
+	Group   string
+	// IndianGrouping groups digits as 12,34,56,789 instead of 123,456,789.
+	IndianGrouping bool
+
+	// SymbolAfter places the currency symbol after the amount ("12,50 €").
+	SymbolAfter bool
+	// SymbolSpace separates the symbol and amount with a (non-breaking) space.
+	SymbolSpace bool
+
+	// DateLayout is a Go time layout used for YYYY-MM-DD dates.
+	DateLayout string
+}
+
+const nbsp = " "
+
+// DefaultTag is used when neither the request nor the configuration names a
+// supported locale.
+const DefaultTag = "en-US"
+
+var locales = map[string]Locale{
+	"en-US": {Tag: "en-US", Decimal: ".", Group: ",", DateLayout: "Jan 2, 2006"},
+	"en-GB": {Tag: "en-GB", Decimal: ".", Group: ",", DateLayout: "2 Jan 2006"},
+	"en-IN": {Tag: "en-IN", Decimal: ".", Group: ",", IndianGrouping: true, DateLayout: "2 Jan 2006"},
+	"en-AU": {Tag: "en-AU", Decimal: ".", Group: ",", DateLayout: "2 Jan 2006"},
+	"en-CA": {Tag: "en-CA", Decimal: ".", Group: ",", DateLayout: "Jan 2, 2006"},
+	"hi-IN": {Tag: "hi-IN", Decimal: ".", Group: ",", IndianGrouping: true, DateLayout: "2/1/2006"},
+	"de-DE": {Tag: "de-DE", Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true, DateLayout: "02.01.2006"},
+	"fr-FR": {Tag: "fr-FR", Decimal: ",", Group: " ", SymbolAfter: true, SymbolSpace: true, DateLayout: "02/01/2006"},
+	"es-ES": {Tag: "es-ES", Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true, DateLayout: "2/1/2006"},
+	"it-IT": {Tag: "it-IT", Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true, DateLayout: "2/1/2006"},
+	"nl-NL": {Tag: "nl-NL", Decimal: ",", Group: ".", SymbolSpace: true, DateLayout: "2-1-2006"},
+	"pt-BR": {Tag: "pt-BR", Decimal: ",", Group: ".", SymbolSpace: true, DateLayout: "02/01/2006"},
+	"sv-SE": {Tag: "sv-SE", Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true, DateLayout: "2006-01-02"},
+	"ja-JP": {Tag: "ja-JP", Decimal: ".", Group: ",", DateLayout: "2006/01/02"},
+	"zh-CN": {Tag: "zh-CN", Decimal: ".", Group: ",", DateLayout: "2006/1/2"},
+	"ko-KR": {Tag: "ko-KR", Decimal: ".", Group: ",", DateLayout: "2006. 1. 2."},
+}
+
+// languageDefaults maps a bare language ("de") to its most common locale.
+var languageDefaults = map[string]string{
+	"en": "en-US",
+	"hi": "hi-IN",
+	"de": "de-DE",
+	"fr": "fr-FR",
+	"es": "es-ES",
+	"it": "it-IT",
+	"nl": "nl-NL",
+	"pt": "pt-BR",
+	"sv": "sv-SE",
+	"ja": "ja-JP",
+	"zh": "zh-CN",
+	"ko": "ko-KR",
+}
+
+// Lookup returns the locale for tag, matching case-insensitively and falling
+// back from "de-AT" to the language default "de-DE".
+func Lookup(tag string) (Locale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return Locale{}, false
+	}
+
+	lang, region, _ := strings.Cut(tag, "-")
+	lang = strings.ToLower(lang)
+	if region != "" {
+		if l, ok := locales[lang+"-"+strings.ToUpper(region)]; ok {
+			return l, true
+		}
+	}
+	if def, ok := languageDefaults[lang]; ok {
+		return locales[def], true
+	}
+	return Locale{}, false
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header,
+// falling back to fallback and then to DefaultTag.
+func Negotiate(acceptLanguage, fallback string) Locale {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: tag, q: q})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if l, ok := Lookup(c.tag); ok {
+			return l
+		}
+	}
+	if l, ok := Lookup(fallback); ok {
+		return l
+	}
+	return locales[DefaultTag]
+}
+
+// FormatNumber formats v with the locale's separators and a fixed number of
+// decimals.
+func (l Locale) FormatNumber(v float64, decimals int) string {
+	neg := v < 0
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	out := l.group(intPart)
+	if frac != "" {
+		out += l.Decimal + frac
+	}
+	// Avoid rendering "-0.00".
+	if neg && strings.Trim(intPart+frac, "0") != "" {
+		out = "-" + out
+	}
+	return out
+}
+
+func (l Locale) group(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	size := 3
+	if l.IndianGrouping {
+		size = 2
+	}
+
+	var parts []string
+	for len(head) > size {
+		parts = append([]string{head[len(head)-size:]}, parts...)
+		head = head[:len(head)-size]
+	}
+	parts = append([]string{head}, parts...)
+	return strings.Join(append(parts, tail), l.Group)
+}
+
+// FormatCurrency formats amount in the given ISO 4217 currency, placing the
+// symbol according to the locale.
+func (l Locale) FormatCurrency(amount float64, currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = "USD"
+	}
+
+	symbol, hasSymbol := currencySymbols[currency]
+	if !hasSymbol {
+		symbol = currency
+	}
+	decimals := 2
+	if d, ok := currencyDecimals[currency]; ok {
+		decimals = d
+	}
+
+	num := l.FormatNumber(math.Abs(amount), decimals)
+	sep := ""
+	// ISO codes used in place of a symbol always need a separator ("CHF 10").
+	if l.SymbolSpace || !hasSymbol {
+		sep = nbsp
+	}
+
+	var out string
+	if l.SymbolAfter {
+		out = num + sep + symbol
+	} else {
+		out = symbol + sep + num
+	}
+	if amount < 0 && num != l.FormatNumber(0, decimals) {
+		out = "-" + out
+	}
+	return out
+}
+
+// FormatDate renders a YYYY-MM-DD date in the locale's layout. Values that
+// don't parse are returned unchanged.
+func (l Locale) FormatDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format(l.DateLayout)
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"INR": "₹",
+	"JPY": "¥",
+	"CNY": "¥",
+	"KRW": "₩",
+	"AUD": "A$",
+	"CAD": "CA$",
+	"BRL": "R$",
+	"SEK": "kr",
+	"NZD": "NZ$",
+	"SGD": "S$",
+	"HKD": "HK$",
+	"MXN": "MX$",
+}
+
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
@@ -0,0 +1,315 @@
+package profiles
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sources of ambient role credentials, reported for the system profile when
+// the server runs on AWS compute without keys of its own.
+const (
+	// SourceInstanceRole is an EC2 instance profile, from IMDS.
+	SourceInstanceRole Source = "instance-role"
+	// SourceContainerRole is an ECS task role or EKS Pod Identity, from the
+	// container credentials endpoint.
+	SourceContainerRole Source = "container-role"
+	// SourceWebIdentity is EKS IAM Roles for Service Accounts (IRSA).
+	SourceWebIdentity Source = "web-identity"
+)
+
+const (
+	imdsEndpoint = "http://169.254.169.254"
+	// ecsEndpoint is the host AWS_CONTAINER_CREDENTIALS_RELATIVE_URI is
+	// relative to.
+	ecsEndpoint = "http://169.254.170.2"
+	// metadataTimeout keeps detection quick off AWS, where the link-local
+	// endpoints don't answer.
+	metadataTimeout = time.Second
+	// ambientRecheck is the minimum interval between lookups of an unknown or
+	// passed ambient expiry.
+	ambientRecheck = time.Minute
+)
+
+// ambientRole describes the role behind the system profile's credentials.
+type ambientRole struct {
+	source   Source
+	roleName string
+	roleARN  string
+	// expiration is when the current credentials expire; zero if unknown.
+	expiration time.Time
+	checkedAt  time.Time
+}
+
+// public returns the system profile entry for r.
+func (r *ambientRole) public() *PublicProfile {
+	pub := &PublicProfile{
+		ID:      "system",
+		Name:    r.roleName,
+		Source:  r.source,
+		RoleARN: r.roleARN,
+	}
+	if !r.expiration.IsZero() {
+		pub.ExpiresAt = r.expiration.UTC().Format(time.RFC3339)
+	}
+	return pub
+}
+
+// detectAmbientRole looks for role credentials supplied by the environment
+// (IRSA, the container credentials endpoint or IMDS, in the order the aws
+// CLI tries them) and confirms with sts get-caller-identity that the system
+// profile actually uses that role. It returns nil when the system profile
+// uses keys or a named profile instead.
+func detectAmbientRole(ctx context.Context) *ambientRole {
+	if os.Getenv("AWS_ACCESS_KEY_ID") != "" || os.Getenv("AWS_PROFILE") != "" {
+		return nil
+	}
+
+	role, err := lookupAmbientRole(ctx)
+	if err != nil || role == nil {
+		return nil
+	}
+
+	caller, err := callerIdentity(ctx, nil)
+	if err != nil {
+		return nil
+	}
+	arn, ok := policySourceARN(caller.ARN)
+	if !ok || !strings.Contains(arn, ":role/") {
+		return nil
+	}
+	name := arn[strings.LastIndex(arn, "/")+1:]
+	if role.roleName != "" && !strings.EqualFold(role.roleName, name) {
+		// Shared config or credentials files take precedence.
+		return nil
+	}
+	role.roleName = name
+	if role.roleARN == "" {
+		role.roleARN = arn
+	}
+	return role
+}
+
+// lookupAmbientRole reads the role and credential expiry from whichever
+// ambient source is configured, without confirming it is in use.
+func lookupAmbientRole(ctx context.Context) (*ambientRole, error) {
+	now := time.Now()
+
+	if roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"); roleARN != "" && tokenFile != "" {
+		role := &ambientRole{
+			source:    SourceWebIdentity,
+			roleName:  roleARN[strings.LastIndex(roleARN, "/")+1:],
+			roleARN:   roleARN,
+			checkedAt: now,
+		}
+		// The aws CLI exchanges the token on every call, so the projected
+		// token's expiry is what bounds the credentials.
+		role.expiration, _ = tokenExpiry(tokenFile)
+		return role, nil
+	}
+
+	if uri := containerCredentialsURI(); uri != "" {
+		creds, err := fetchContainerCredentials(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		role := &ambientRole{
+			source:     SourceContainerRole,
+			roleARN:    creds.RoleARN,
+			expiration: creds.Expiration,
+			checkedAt:  now,
+		}
+		if creds.RoleARN != "" {
+			role.roleName = creds.RoleARN[strings.LastIndex(creds.RoleARN, "/")+1:]
+		}
+		return role, nil
+	}
+
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return nil, nil
+	}
+	name, expiration, err := fetchInstanceRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ambientRole{
+		source:     SourceInstanceRole,
+		roleName:   name,
+		expiration: expiration,
+		checkedAt:  now,
+	}, nil
+}
+
+// ambientStatus returns the system profile entry, looking the expiry up
+// again once it has passed (AWS rotates ambient credentials ahead of
+// expiry) or is unknown. It must not be called with m.mu held.
+func (m *Manager) ambientStatus() *PublicProfile {
+	m.mu.RLock()
+	role := m.ambient
+	m.mu.RUnlock()
+	if role == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if (role.expiration.IsZero() || now.After(role.expiration)) && now.Sub(role.checkedAt) > ambientRecheck {
+		updated := *role
+		updated.checkedAt = now
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*metadataTimeout)
+		defer cancel()
+		if fresh, err := lookupAmbientRole(ctx); err == nil && fresh != nil {
+			updated.expiration = fresh.expiration
+		}
+
+		m.mu.Lock()
+		m.ambient = &updated
+		m.mu.Unlock()
+		role = &updated
+	}
+	return role.public()
+}
+
+// containerCredentialsURI returns the ECS / EKS Pod Identity credentials
+// endpoint, if configured.
+func containerCredentialsURI() string {
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		return uri
+	}
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+		return ecsEndpoint + rel
+	}
+	return ""
+}
+
+type containerCredentials struct {
+	RoleARN    string    `json:"RoleArn"`
+	Expiration time.Time `json:"Expiration"`
+}
+
+// fetchContainerCredentials reads the credentials document served at uri,
+// authenticating with the token EKS Pod Identity provides.
+func fetchContainerCredentials(ctx context.Context, uri string) (containerCredentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return containerCredentials{}, err
+	}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return containerCredentials{}, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	body, err := metadataRequest(req)
+	if err != nil {
+		return containerCredentials{}, err
+	}
+	var creds containerCredentials
+	if err := json.Unmarshal(body, &creds); err != nil {
+		return containerCredentials{}, fmt.Errorf("failed to parse container credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// fetchInstanceRole returns the instance profile role and its credentials'
+// expiry from IMDSv2.
+func fetchInstanceRole(ctx context.Context) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, imdsEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := metadataRequest(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	get := func(path string) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, imdsEndpoint+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", string(token))
+		return metadataRequest(req)
+	}
+
+	roles, err := get("/latest/meta-data/iam/security-credentials/")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	name := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if name == "" {
+		return "", time.Time{}, fmt.Errorf("instance has no IAM role")
+	}
+
+	data, err := get("/latest/meta-data/iam/security-credentials/" + name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var creds struct {
+		Expiration time.Time `json:"Expiration"`
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to parse instance credentials: %w", err)
+	}
+	return name, creds.Expiration, nil
+}
+
+// metadataRequest performs a request against a link-local metadata
+// endpoint and returns the body of a 200 response.
+func metadataRequest(req *http.Request) ([]byte, error) {
+	client := &http.Client{Timeout: metadataTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	return body, nil
+}
+
+// tokenExpiry reads the exp claim of the JWT in file. The signature is not
+// checked; the value is only displayed.
+func tokenExpiry(file string) (time.Time, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	parts := strings.Split(strings.TrimSpace(string(data)), ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("web identity token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid web identity token: %w", err)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("invalid web identity token: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("web identity token has no expiry")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
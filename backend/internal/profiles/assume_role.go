@@ -0,0 +1,160 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// roleRefreshWindow is how long before expiry temporary credentials are
+// replaced, so a CLI call never starts with credentials about to lapse.
+const roleRefreshWindow = 5 * time.Minute
+
+// roleSessionName identifies the dashboard's sessions in CloudTrail.
+const roleSessionName = "aws-local-dashboard"
+
+// AddAssumeRoleProfile stores and activates a profile that assumes roleARN
+// with the credentials of baseID ("system" or a custom profile). The role is
+// assumed once up front to validate it.
+func (m *Manager) AddAssumeRoleProfile(ctx context.Context, name, baseID, roleARN, externalID, region string) (Profile, error) {
+	name = strings.TrimSpace(name)
+	roleARN = strings.TrimSpace(roleARN)
+	if name == "" {
+		return Profile{}, fmt.Errorf("profile name is required")
+	}
+	if !strings.HasPrefix(roleARN, "arn:") || !strings.Contains(roleARN, ":role/") {
+		return Profile{}, fmt.Errorf("role ARN must look like arn:aws:iam::<account>:role/<name>")
+	}
+	if baseID == "" {
+		baseID = "system"
+	}
+
+	p := Profile{
+		Name:          name,
+		Region:        region,
+		Source:        SourceAssumeRole,
+		RoleARN:       roleARN,
+		ExternalID:    strings.TrimSpace(externalID),
+		BaseProfileID: baseID,
+	}
+
+	baseEnv, err := m.baseEnv(p)
+	if err != nil {
+		return Profile{}, err
+	}
+	creds, err := assumeRole(ctx, baseEnv, p)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p.ID = strconv.FormatInt(m.nextID, 10)
+	m.nextID++
+
+	m.profiles[p.ID] = p
+	m.sessionCreds[p.ID] = creds
+	m.activeID = p.ID
+
+	m.saveLocked()
+
+	return p, nil
+}
+
+// roleEnv returns the environment for an assume-role profile, assuming the
+// role again when the cached credentials are missing or about to expire.
+func (m *Manager) roleEnv(ctx context.Context, p Profile) ([]string, error) {
+	m.mu.RLock()
+	creds, ok := m.sessionCreds[p.ID]
+	m.mu.RUnlock()
+
+	if !ok || !creds.validFor(p, time.Now(), roleRefreshWindow) {
+		m.refreshMu.Lock()
+		defer m.refreshMu.Unlock()
+
+		// Another request may have refreshed them while we waited.
+		m.mu.RLock()
+		creds, ok = m.sessionCreds[p.ID]
+		m.mu.RUnlock()
+
+		if !ok || !creds.validFor(p, time.Now(), roleRefreshWindow) {
+			baseEnv, err := m.baseEnv(p)
+			if err != nil {
+				return nil, err
+			}
+			creds, err = assumeRole(ctx, baseEnv, p)
+			if err != nil {
+				return nil, err
+			}
+
+			m.mu.Lock()
+			if _, exists := m.profiles[p.ID]; exists {
+				m.sessionCreds[p.ID] = creds
+				delete(m.expired, p.ID)
+			}
+			m.mu.Unlock()
+		}
+	}
+
+	region := defaultRegion(p)
+	if region == "" {
+		m.mu.RLock()
+		region = m.profiles[p.BaseProfileID].Region
+		m.mu.RUnlock()
+	}
+
+	return creds.env(region), nil
+}
+
+// baseEnv returns the environment used to call sts assume-role for p: nil
+// for the system profile, otherwise the base profile's keys (or MFA session
+// credentials). Role chaining is not supported.
+func (m *Manager) baseEnv(p Profile) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if p.BaseProfileID == "system" {
+		if !m.systemAvailable {
+			return nil, fmt.Errorf("system AWS credentials are not available")
+		}
+		return nil, nil
+	}
+
+	base, ok := m.profiles[p.BaseProfileID]
+	if !ok {
+		return nil, fmt.Errorf("base profile %q not found", p.BaseProfileID)
+	}
+	if base.RoleARN != "" {
+		return nil, fmt.Errorf("base profile %q is itself an assume-role profile", base.Name)
+	}
+	if base.VaultProfile != "" {
+		return nil, fmt.Errorf("base profile %q is an aws-vault profile; configure the role in aws-vault instead", base.Name)
+	}
+	if base.MFASerial != "" {
+		return m.mfaEnvLocked(base)
+	}
+	return staticEnv(base), nil
+}
+
+// assumeRole calls sts assume-role for p with the base credentials in
+// baseEnv (nil for the process environment).
+func assumeRole(ctx context.Context, baseEnv []string, p Profile) (sessionCredentials, error) {
+	args := []string{
+		"sts", "assume-role",
+		"--role-arn", p.RoleARN,
+		"--role-session-name", roleSessionName,
+	}
+	if p.ExternalID != "" {
+		args = append(args, "--external-id", p.ExternalID)
+	}
+
+	creds, err := runSTS(ctx, baseEnv, args...)
+	if err != nil {
+		return sessionCredentials{}, err
+	}
+	creds.issuedFor = credentialKey(p)
+	return creds, nil
+}
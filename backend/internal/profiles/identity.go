@@ -0,0 +1,154 @@
+package profiles
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Identity describes the AWS account and principal a profile's credentials
+// belong to.
+type Identity struct {
+	ProfileID string `json:"profileId"`
+	Account   string `json:"account"`
+	ARN       string `json:"arn"`
+	UserID    string `json:"userId"`
+	// AccountAlias is empty when the account has none or when AliasError is
+	// set; listing aliases needs iam:ListAccountAliases, which many roles lack.
+	AccountAlias string `json:"accountAlias,omitempty"`
+	AliasError   string `json:"aliasError,omitempty"`
+}
+
+// Identify runs sts get-caller-identity and iam list-account-aliases with
+// profile id's credentials ("system" for the host's), without switching to it.
+func (m *Manager) Identify(ctx context.Context, id string) (Identity, error) {
+	if err := m.checkExists(id); err != nil {
+		return Identity{}, err
+	}
+
+	env, err := m.Env(ctx, id)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	caller, err := callerIdentity(ctx, env)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	ident := Identity{
+		ProfileID: id,
+		Account:   caller.Account,
+		ARN:       caller.ARN,
+		UserID:    caller.UserID,
+	}
+
+	out, err := runCLI(ctx, env, "iam", "list-account-aliases")
+	if err != nil {
+		ident.AliasError = err.Error()
+		return ident, nil
+	}
+	var aliases struct {
+		AccountAliases []string `json:"AccountAliases"`
+	}
+	if err := json.Unmarshal(out, &aliases); err != nil {
+		ident.AliasError = fmt.Sprintf("failed to parse iam list-account-aliases response: %v", err)
+	} else if len(aliases.AccountAliases) > 0 {
+		ident.AccountAlias = aliases.AccountAliases[0]
+	}
+	return ident, nil
+}
+
+// checkExists returns an error unless id names a stored profile or is
+// "system" with system credentials available.
+func (m *Manager) checkExists(id string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id == "system" {
+		if !m.systemAvailable {
+			return fmt.Errorf("system AWS credentials are not available")
+		}
+		return nil
+	}
+	if _, ok := m.profiles[id]; !ok {
+		return fmt.Errorf("profile %q not found", id)
+	}
+	return nil
+}
+
+// callerIdentityResponse is the parsed response of sts get-caller-identity.
+type callerIdentityResponse struct {
+	UserID  string `json:"UserId"`
+	Account string `json:"Account"`
+	ARN     string `json:"Arn"`
+}
+
+// callerIdentity runs sts get-caller-identity with env overrides (nil for
+// the process environment).
+func callerIdentity(ctx context.Context, env []string) (callerIdentityResponse, error) {
+	out, err := runCLI(ctx, env, "sts", "get-caller-identity")
+	if err != nil {
+		return callerIdentityResponse{}, err
+	}
+	var caller callerIdentityResponse
+	if err := json.Unmarshal(out, &caller); err != nil {
+		return callerIdentityResponse{}, fmt.Errorf("failed to parse sts get-caller-identity response: %w", err)
+	}
+	return caller, nil
+}
+
+// caller returns the sts get-caller-identity response for profile id,
+// looked up once and then remembered.
+func (m *Manager) caller(ctx context.Context, id string) (callerIdentityResponse, error) {
+	m.mu.RLock()
+	caller, ok := m.callers[id]
+	m.mu.RUnlock()
+	if ok {
+		return caller, nil
+	}
+
+	env, err := m.Env(ctx, id)
+	if err != nil {
+		return callerIdentityResponse{}, err
+	}
+	caller, err = callerIdentity(ctx, env)
+	if err != nil {
+		return callerIdentityResponse{}, err
+	}
+
+	m.mu.Lock()
+	m.callers[id] = caller
+	m.mu.Unlock()
+	return caller, nil
+}
+
+// CacheScope returns what the data of AWS calls made with ctx depends on,
+// as "<account>:<fingerprint>", for caches shared with other dashboards,
+// where the same profile ID can stand for other credentials. The
+// fingerprint covers the principal the credentials belong to, ignoring
+// assumed-role session names, and the profile's default and allowed
+// regions, so dashboards share entries only when they would fetch the same
+// data.
+func (m *Manager) CacheScope(ctx context.Context) (string, error) {
+	id := m.ProfileID(ctx)
+	caller, err := m.caller(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	principal := caller.ARN
+	if prefix, rest, ok := strings.Cut(principal, ":assumed-role/"); ok {
+		role, _, _ := strings.Cut(rest, "/")
+		principal = prefix + ":assumed-role/" + role
+	}
+	m.mu.RLock()
+	allowed := m.profiles[id].AllowedRegions
+	m.mu.RUnlock()
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{principal, m.DefaultRegion(id), strings.Join(allowed, ",")}, "\n")))
+	return caller.Account + ":" + hex.EncodeToString(sum[:8]), nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Source indicates where a profile comes from.
@@ -19,6 +21,12 @@ type Source string
 const (
 	SourceSystem Source = "system"
 	SourceCustom Source = "custom"
+	// SourceAssumeRole profiles hold no keys of their own; they assume
+	// RoleARN using the credentials of BaseProfileID.
+	SourceAssumeRole Source = "assume-role"
+	// SourceAWSVault profiles hold no keys either; they get them from
+	// `aws-vault exec VaultProfile`.
+	SourceAWSVault Source = "aws-vault"
 )
 
 type Profile struct {
@@ -29,13 +37,38 @@ type Profile struct {
 	SessionToken    string `json:"sessionToken,omitempty"`
 	Region          string `json:"region,omitempty"`
 	Source          Source `json:"source"`
+	RoleARN         string `json:"roleArn,omitempty"`
+	ExternalID      string `json:"externalId,omitempty"`
+	BaseProfileID   string `json:"baseProfileId,omitempty"`
+	// MFASerial is the ARN of the MFA device required by the profile's
+	// policies. Such profiles work on get-session-token credentials, obtained
+	// with ActivateMFA.
+	MFASerial string `json:"mfaSerial,omitempty"`
+	// AllowedRegions, when set, restricts all-regions scans and explicit
+	// --region arguments to these regions.
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+	// VaultProfile is the aws-vault profile an aws-vault profile uses.
+	VaultProfile string `json:"vaultProfile,omitempty"`
 }
 
 // PublicProfile is a redacted view of a Profile sent to the frontend.
 type PublicProfile struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Source Source `json:"source"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Source        Source `json:"source"`
+	RoleARN       string `json:"roleArn,omitempty"`
+	BaseProfileID string `json:"baseProfileId,omitempty"`
+	MFASerial     string `json:"mfaSerial,omitempty"`
+	VaultProfile  string `json:"vaultProfile,omitempty"`
+	// Region is the profile's default region.
+	Region         string   `json:"region,omitempty"`
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+	// ExpiresAt is when the profile's session credentials expire, if known.
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	// IsExpired means the profile needs re-authentication: its session
+	// credentials have expired (or AWS said so), or an MFA profile has no
+	// session yet.
+	IsExpired bool `json:"isExpired"`
 }
 
 // Status summarizes the profile state for the frontend.
@@ -43,6 +76,11 @@ type Status struct {
 	SystemAvailable bool            `json:"systemAvailable"`
 	ActiveID        string          `json:"activeId"`
 	Profiles        []PublicProfile `json:"profiles"`
+	// System describes the system profile's ambient role when the server
+	// runs on EC2, ECS or EKS without keys of its own.
+	System *PublicProfile `json:"system,omitempty"`
+	// MultiAccountIDs are the profiles ?profile=all queries cover.
+	MultiAccountIDs []string `json:"multiAccountIds,omitempty"`
 }
 
 // Manager keeps track of profiles and the active selection.
@@ -53,11 +91,40 @@ type Manager struct {
 	systemAvailable bool
 	nextID          int64
 	storePath       string
+
+	// sessionCreds holds temporary credentials of assume-role and MFA
+	// profiles, by profile ID. They are never persisted.
+	sessionCreds map[string]sessionCredentials
+	// expired marks profiles whose credentials AWS reported as expired, by
+	// profile ID. Cleared when new session credentials are obtained.
+	expired map[string]bool
+	// multiIDs are the profiles selected for multi-account mode.
+	multiIDs []string
+	// ambient is the role behind the system profile, if it is an ambient
+	// instance, container or web identity role.
+	ambient *ambientRole
+	// callers caches the sts get-caller-identity response of each profile,
+	// by profile ID.
+	callers map[string]callerIdentityResponse
+	// readOnly backs CheckReadOnly.
+	readOnly readOnlyChecker
+	// preflight backs CheckPermission.
+	preflight permissionChecker
+	// refreshMu serializes STS refreshes so concurrent requests don't each
+	// assume the role. It is never acquired while holding mu.
+	refreshMu sync.Mutex
+}
+
+// Options tweak how a Manager is constructed.
+type Options struct {
+	// Offline skips probing system credentials and reports them as available.
+	// Used by fixture replay, where no real AWS calls are made.
+	Offline bool
 }
 
 // NewManager creates a Manager and probes whether system AWS credentials
 // are available (using aws sts get-caller-identity).
-func NewManager(ctx context.Context) *Manager {
+func NewManager(ctx context.Context, opts Options) *Manager {
 	storePath := os.Getenv("PROFILE_STORE_PATH")
 	if storePath == "" {
 		// Default to a project-local file so profiles persist across restarts
@@ -66,17 +133,23 @@ func NewManager(ctx context.Context) *Manager {
 	}
 
 	m := &Manager{
-		profiles:  make(map[string]Profile),
-		nextID:    1,
-		storePath: storePath,
+		profiles:     make(map[string]Profile),
+		sessionCreds: make(map[string]sessionCredentials),
+		expired:      make(map[string]bool),
+		callers:      make(map[string]callerIdentityResponse),
+		nextID:       1,
+		storePath:    storePath,
 	}
 
-	if ok := checkCredentialsWithEnv(ctx, nil); ok {
+	if opts.Offline || checkCredentialsWithEnv(ctx, nil) {
 		m.systemAvailable = true
 		if m.activeID == "" {
 			m.activeID = "system"
 		}
 	}
+	if !opts.Offline && m.systemAvailable {
+		m.ambient = detectAmbientRole(ctx)
+	}
 
 	// Best-effort load of any previously saved custom profiles.
 	_ = m.loadFromDisk()
@@ -86,16 +159,37 @@ func NewManager(ctx context.Context) *Manager {
 
 // Status returns a snapshot of profile state.
 func (m *Manager) Status() Status {
+	system := m.ambientStatus()
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	now := time.Now()
 	var pubs []PublicProfile
 	for _, p := range m.profiles {
-		pubs = append(pubs, PublicProfile{
-			ID:     p.ID,
-			Name:   p.Name,
-			Source: p.Source,
-		})
+		pub := PublicProfile{
+			ID:             p.ID,
+			Name:           p.Name,
+			Source:         p.Source,
+			RoleARN:        p.RoleARN,
+			BaseProfileID:  p.BaseProfileID,
+			MFASerial:      p.MFASerial,
+			VaultProfile:   p.VaultProfile,
+			Region:         p.Region,
+			AllowedRegions: p.AllowedRegions,
+			IsExpired:      m.expired[p.ID],
+		}
+		if creds, ok := m.sessionCreds[p.ID]; ok && creds.issuedFor == credentialKey(p) {
+			pub.ExpiresAt = creds.Expiration.UTC().Format(time.RFC3339)
+			// Assume-role credentials are refreshed on demand, so only MFA
+			// sessions need the user once they lapse.
+			if p.MFASerial != "" && !now.Before(creds.Expiration) {
+				pub.IsExpired = true
+			}
+		} else if p.MFASerial != "" {
+			pub.IsExpired = true
+		}
+		pubs = append(pubs, pub)
 	}
 
 	active := m.activeID
@@ -107,6 +201,8 @@ func (m *Manager) Status() Status {
 		SystemAvailable: m.systemAvailable,
 		ActiveID:        active,
 		Profiles:        pubs,
+		System:          system,
+		MultiAccountIDs: append([]string(nil), m.multiIDs...),
 	}
 }
 
@@ -123,20 +219,93 @@ func (m *Manager) ActiveID() string {
 }
 
 // ActiveEnv returns environment variable overrides for the active profile.
-// If the system profile is active (or none), it returns nil.
-func (m *Manager) ActiveEnv() []string {
+// If the system profile is active (or none), it returns nil. For assume-role
+// profiles it refreshes the temporary credentials when they are about to
+// expire, which is the only way it can fail.
+func (m *Manager) ActiveEnv(ctx context.Context) ([]string, error) {
+	return m.Env(ctx, m.ProfileID(ctx))
+}
+
+// Env returns environment variable overrides for profile id, as ActiveEnv
+// does for the active profile.
+func (m *Manager) Env(ctx context.Context, id string) ([]string, error) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	p, ok := m.profiles[id]
+	m.mu.RUnlock()
 
-	if m.activeID == "" || m.activeID == "system" {
-		return nil
+	if !ok {
+		return nil, nil
+	}
+	switch {
+	case p.RoleARN != "":
+		return m.roleEnv(ctx, p)
+	case p.VaultProfile != "":
+		return m.vaultEnv(ctx, p)
+	case p.MFASerial != "":
+		return m.mfaEnv(p)
 	}
+	return staticEnv(p), nil
+}
+
+// EnvNames returns the names of the variables Env sets for profile id,
+// without fetching temporary credentials.
+func (m *Manager) EnvNames(id string) []string {
+	m.mu.RLock()
+	p, ok := m.profiles[id]
+	m.mu.RUnlock()
 
-	p, ok := m.profiles[m.activeID]
 	if !ok {
 		return nil
 	}
+	if p.RoleARN != "" || p.VaultProfile != "" || p.MFASerial != "" {
+		// Temporary credentials always include a session token.
+		p = Profile{AccessKeyID: "-", SecretAccessKey: "-", SessionToken: "-", Region: m.DefaultRegion(id)}
+	}
 
+	var names []string
+	for _, kv := range staticEnv(p) {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+// DefaultRegion returns the region Env sets for profile id, or "" if calls
+// fall back to the CLI's own configuration.
+func (m *Manager) DefaultRegion(id string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.profiles[id]
+	if !ok {
+		return ""
+	}
+	region := defaultRegion(p)
+	if region == "" && p.RoleARN != "" {
+		region = m.profiles[p.BaseProfileID].Region
+	}
+	return region
+}
+
+// MarkExpired records that AWS rejected profile id's credentials as expired.
+// Cached assume-role and aws-vault credentials are dropped so the next call
+// fetches new ones.
+func (m *Manager) MarkExpired(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.profiles[id]
+	if !ok {
+		return
+	}
+	m.expired[id] = true
+	if p.RoleARN != "" || p.VaultProfile != "" {
+		delete(m.sessionCreds, id)
+	}
+}
+
+// staticEnv returns the environment for a profile with its own keys.
+func staticEnv(p Profile) []string {
 	var env []string
 	if p.AccessKeyID != "" {
 		env = append(env, "AWS_ACCESS_KEY_ID="+p.AccessKeyID)
@@ -147,17 +316,27 @@ func (m *Manager) ActiveEnv() []string {
 	if p.SessionToken != "" {
 		env = append(env, "AWS_SESSION_TOKEN="+p.SessionToken)
 	}
-	if p.Region != "" {
-		env = append(env, "AWS_DEFAULT_REGION="+p.Region)
+	if region := defaultRegion(p); region != "" {
+		env = append(env, "AWS_DEFAULT_REGION="+region)
 	}
 	// Disable IMDS to avoid slow lookups when using explicit keys.
 	env = append(env, "AWS_EC2_METADATA_DISABLED=true")
 	return env
 }
 
+// defaultRegion is p's region, falling back to its first allowed region so
+// calls without --region stay within the allowed list.
+func defaultRegion(p Profile) string {
+	if p.Region == "" && len(p.AllowedRegions) > 0 {
+		return p.AllowedRegions[0]
+	}
+	return p.Region
+}
+
 // AddAndActivateProfile validates credentials by calling sts get-caller-identity,
-// then stores and activates the profile if valid.
-func (m *Manager) AddAndActivateProfile(ctx context.Context, name, accessKey, secretKey, sessionToken, region string) (Profile, error) {
+// then stores and activates the profile if valid. mfaSerial is optional; when
+// set, the profile needs ActivateMFA before it can be used.
+func (m *Manager) AddAndActivateProfile(ctx context.Context, name, accessKey, secretKey, sessionToken, region, mfaSerial string) (Profile, error) {
 	if strings.TrimSpace(name) == "" {
 		return Profile{}, fmt.Errorf("profile name is required")
 	}
@@ -195,6 +374,7 @@ func (m *Manager) AddAndActivateProfile(ctx context.Context, name, accessKey, se
 		SessionToken:    sessionToken,
 		Region:          region,
 		Source:          SourceCustom,
+		MFASerial:       strings.TrimSpace(mfaSerial),
 	}
 
 	m.profiles[id] = p
@@ -227,32 +407,160 @@ func (m *Manager) SetActiveProfile(id string) error {
 	return nil
 }
 
-// loadFromDisk restores profiles and activeId from the store file, if present.
-func (m *Manager) loadFromDisk() error {
-	if m.storePath == "" {
-		return nil
+// DeleteProfile removes a custom profile. If it was active, the system
+// profile becomes active when available.
+func (m *Manager) DeleteProfile(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id == "system" {
+		return fmt.Errorf("the system profile cannot be deleted")
+	}
+	if _, ok := m.profiles[id]; !ok {
+		return fmt.Errorf("profile %q not found", id)
+	}
+	for _, other := range m.profiles {
+		if other.BaseProfileID == id {
+			return fmt.Errorf("profile %q is the base of assume-role profile %q", id, other.Name)
+		}
 	}
 
-	data, err := os.ReadFile(m.storePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
+	delete(m.profiles, id)
+	delete(m.sessionCreds, id)
+	delete(m.expired, id)
+	delete(m.callers, id)
+	for i, multiID := range m.multiIDs {
+		if multiID == id {
+			m.multiIDs = append(m.multiIDs[:i:i], m.multiIDs[i+1:]...)
+			break
+		}
+	}
+	if m.activeID == id {
+		m.activeID = ""
+		if m.systemAvailable {
+			m.activeID = "system"
 		}
-		return err
 	}
+	m.saveLocked()
+	return nil
+}
 
-	var state struct {
-		NextID   int64     `json:"nextId"`
-		ActiveID string    `json:"activeId"`
-		Profiles []Profile `json:"profiles"`
+// RenameProfile changes a custom profile's display name.
+func (m *Manager) RenameProfile(id, name string) (Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Profile{}, fmt.Errorf("profile name is required")
 	}
-	if err := json.Unmarshal(data, &state); err != nil {
-		return err
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id == "system" {
+		return Profile{}, fmt.Errorf("the system profile cannot be renamed")
+	}
+	p, ok := m.profiles[id]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", id)
+	}
+
+	p.Name = name
+	m.profiles[id] = p
+	m.saveLocked()
+	return p, nil
+}
+
+// SetRegions sets a custom profile's default region and allowed regions. An
+// empty allowed list removes the restriction; otherwise region, if set, must
+// be one of them.
+func (m *Manager) SetRegions(id, region string, allowed []string) (Profile, error) {
+	region = strings.TrimSpace(region)
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, r := range allowed {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r == "" || seen[r] {
+			continue
+		}
+		seen[r] = true
+		cleaned = append(cleaned, r)
+	}
+	if region != "" && len(cleaned) > 0 && !seen[strings.ToLower(region)] {
+		return Profile{}, fmt.Errorf("default region %s is not in the allowed regions", region)
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if id == "system" {
+		return Profile{}, fmt.Errorf("the system profile's regions come from the AWS CLI configuration")
+	}
+	p, ok := m.profiles[id]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", id)
+	}
+
+	p.Region = region
+	p.AllowedRegions = cleaned
+	m.profiles[id] = p
+	m.saveLocked()
+	return p, nil
+}
+
+// ErrRegionNotAllowed is returned (wrapped) for a region outside the active
+// profile's allowed regions.
+var ErrRegionNotAllowed = errors.New("region not allowed for this profile")
+
+// AllowedRegions returns the allowed regions of the profile ctx uses (see
+// ProfileID), or nil when it is unrestricted.
+func (m *Manager) AllowedRegions(ctx context.Context) []string {
+	id := m.ProfileID(ctx)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.profiles[id].AllowedRegions
+}
+
+// RegionAllowed reports whether the profile ctx uses may use region.
+func (m *Manager) RegionAllowed(ctx context.Context, region string) bool {
+	allowed := m.AllowedRegions(ctx)
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, r := range allowed {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// storeState is the persisted form of the Manager, used both for the on-disk
+// store and for backups.
+type storeState struct {
+	NextID          int64     `json:"nextId"`
+	ActiveID        string    `json:"activeId"`
+	Profiles        []Profile `json:"profiles"`
+	MultiAccountIDs []string  `json:"multiAccountIds,omitempty"`
+}
+
+// stateLocked snapshots the persisted state. Caller must hold m.mu.
+func (m *Manager) stateLocked() storeState {
+	var profiles []Profile
+	for _, p := range m.profiles {
+		profiles = append(profiles, p)
+	}
+	return storeState{
+		NextID:          m.nextID,
+		ActiveID:        m.activeID,
+		Profiles:        profiles,
+		MultiAccountIDs: m.multiIDs,
+	}
+}
+
+// applyStateLocked replaces the in-memory profiles with state. Caller must
+// hold m.mu.
+func (m *Manager) applyStateLocked(state storeState) {
 	if state.NextID > 0 {
 		m.nextID = state.NextID
 	}
@@ -260,38 +568,89 @@ func (m *Manager) loadFromDisk() error {
 		m.activeID = state.ActiveID
 	}
 	m.profiles = make(map[string]Profile, len(state.Profiles))
+	m.sessionCreds = make(map[string]sessionCredentials)
+	m.expired = make(map[string]bool)
+	m.callers = make(map[string]callerIdentityResponse)
 	for _, p := range state.Profiles {
 		// Skip any legacy entries that don't have credentials; they can't be used.
-		if p.AccessKeyID == "" || p.SecretAccessKey == "" {
+		if p.RoleARN == "" && p.VaultProfile == "" && (p.AccessKeyID == "" || p.SecretAccessKey == "") {
 			continue
 		}
 		m.profiles[p.ID] = p
 	}
+	if m.activeID != "system" && m.activeID != "" {
+		if _, ok := m.profiles[m.activeID]; !ok {
+			m.activeID = ""
+		}
+	}
+	m.multiIDs = nil
+	for _, id := range state.MultiAccountIDs {
+		if _, ok := m.profiles[id]; ok || id == "system" {
+			m.multiIDs = append(m.multiIDs, id)
+		}
+	}
+}
+
+// ExportState returns the custom profiles (including secrets) and the active
+// selection as JSON, for backups.
+func (m *Manager) ExportState() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return json.MarshalIndent(m.stateLocked(), "", "  ")
+}
+
+// ImportState replaces all custom profiles with those in data (as produced by
+// ExportState) and persists the result.
+func (m *Manager) ImportState(data []byte) error {
+	var state storeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid profile state: %w", err)
+	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.applyStateLocked(state)
+	m.saveLocked()
 	return nil
 }
 
-// saveLocked persists profiles and activeId to disk. Caller must hold m.mu.
-func (m *Manager) saveLocked() {
+// loadFromDisk restores profiles and activeId from the store file, if present.
+func (m *Manager) loadFromDisk() error {
 	if m.storePath == "" {
-		return
+		return nil
 	}
 
-	var profiles []Profile
-	for _, p := range m.profiles {
-		profiles = append(profiles, p)
+	data, err := os.ReadFile(m.storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var state storeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
 	}
 
-	state := struct {
-		NextID   int64     `json:"nextId"`
-		ActiveID string    `json:"activeId"`
-		Profiles []Profile `json:"profiles"`
-	}{
-		NextID:   m.nextID,
-		ActiveID: m.activeID,
-		Profiles: profiles,
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.applyStateLocked(state)
+
+	return nil
+}
+
+// saveLocked persists profiles and activeId to disk. Caller must hold m.mu.
+func (m *Manager) saveLocked() {
+	if m.storePath == "" {
+		return
 	}
 
+	state := m.stateLocked()
+
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return
@@ -0,0 +1,76 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrMFARequired is returned when an MFA profile has no valid session
+// credentials; a new code must be given to ActivateMFA.
+var ErrMFARequired = errors.New("MFA code required")
+
+var mfaCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+// ActivateMFA exchanges a TOTP code for session credentials of an MFA
+// profile using sts get-session-token, and makes the profile active.
+func (m *Manager) ActivateMFA(ctx context.Context, id, code string) (Profile, error) {
+	if !mfaCodePattern.MatchString(code) {
+		return Profile{}, fmt.Errorf("MFA code must be 6 digits")
+	}
+
+	m.mu.RLock()
+	p, ok := m.profiles[id]
+	m.mu.RUnlock()
+
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", id)
+	}
+	if p.MFASerial == "" {
+		return Profile{}, fmt.Errorf("profile %q has no MFA device configured", p.Name)
+	}
+
+	// Long-term keys only; get-session-token rejects session credentials.
+	keys := p
+	keys.SessionToken = ""
+	creds, err := runSTS(ctx, staticEnv(keys),
+		"sts", "get-session-token",
+		"--serial-number", p.MFASerial,
+		"--token-code", code,
+	)
+	if err != nil {
+		return Profile{}, err
+	}
+	creds.issuedFor = credentialKey(p)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.profiles[id]; !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", id)
+	}
+	m.sessionCreds[id] = creds
+	delete(m.expired, id)
+	m.activeID = id
+	m.saveLocked()
+	return p, nil
+}
+
+// mfaEnv returns the session credentials environment of an MFA profile.
+// Unlike assume-role credentials they cannot be refreshed without a new code.
+func (m *Manager) mfaEnv(p Profile) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mfaEnvLocked(p)
+}
+
+// mfaEnvLocked is mfaEnv for callers holding m.mu.
+func (m *Manager) mfaEnvLocked(p Profile) ([]string, error) {
+	creds, ok := m.sessionCreds[p.ID]
+	if !ok || !creds.validFor(p, time.Now(), 0) {
+		return nil, fmt.Errorf("%w for profile %q", ErrMFARequired, p.Name)
+	}
+	return creds.env(defaultRegion(p)), nil
+}
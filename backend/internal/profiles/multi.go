@@ -0,0 +1,119 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Multi-account mode
+
+type profileCtxKey struct{}
+
+// WithProfile returns a context whose AWS calls use profile id instead of the
+// active profile. Multi-account requests use it to query each profile.
+func WithProfile(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, profileCtxKey{}, id)
+}
+
+// ProfileID returns the profile AWS calls made with ctx use: the one set by
+// WithProfile, otherwise the active profile.
+func (m *Manager) ProfileID(ctx context.Context) string {
+	if id, ok := ctx.Value(profileCtxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return m.ActiveID()
+}
+
+type regionCtxKey struct{}
+
+// WithRegion returns a context whose AWS calls default to region when they
+// don't name one with --region.
+func WithRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionCtxKey{}, region)
+}
+
+// ContextRegion returns the region set by WithRegion, or "".
+func ContextRegion(ctx context.Context) string {
+	region, _ := ctx.Value(regionCtxKey{}).(string)
+	return region
+}
+
+// ResolveProfile returns the ID of the profile ref names: a profile ID,
+// "system", or a profile name (case-insensitive).
+func (m *Manager) ResolveProfile(ref string) (string, error) {
+	if err := m.checkExists(ref); err == nil {
+		return ref, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for id, p := range m.profiles {
+		if strings.EqualFold(p.Name, ref) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("profile %q not found", ref)
+}
+
+// AccountRef names a profile taking part in a multi-account query.
+type AccountRef struct {
+	ProfileID   string `json:"profileId"`
+	ProfileName string `json:"profileName"`
+}
+
+// SetMultiAccount selects the profiles that ?profile=all queries fan out
+// across. An empty list turns multi-account mode off.
+func (m *Manager) SetMultiAccount(ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if id == "system" {
+			if !m.systemAvailable {
+				return fmt.Errorf("system AWS credentials are not available")
+			}
+		} else if _, ok := m.profiles[id]; !ok {
+			return fmt.Errorf("profile %q not found", id)
+		}
+		cleaned = append(cleaned, id)
+	}
+
+	m.multiIDs = cleaned
+	m.saveLocked()
+	return nil
+}
+
+// MultiAccount returns the profiles selected for multi-account mode, in the
+// order they were selected.
+func (m *Manager) MultiAccount() []AccountRef {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	refs := make([]AccountRef, 0, len(m.multiIDs))
+	for _, id := range m.multiIDs {
+		name := "System default"
+		if p, ok := m.profiles[id]; ok {
+			name = p.Name
+		}
+		refs = append(refs, AccountRef{ProfileID: id, ProfileName: name})
+	}
+	return refs
+}
+
+// AccountID returns the AWS account ID of profile id, looked up once with
+// sts get-caller-identity and then remembered.
+func (m *Manager) AccountID(ctx context.Context, id string) (string, error) {
+	caller, err := m.caller(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return caller.Account, nil
+}
@@ -0,0 +1,183 @@
+package profiles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Feature access states reported by ProbePermissions.
+const (
+	AccessAllowed = "allowed"
+	AccessDenied  = "denied"
+	// AccessUnknown means the probe failed for a reason other than a
+	// permission error (throttling, network, unsupported region...).
+	AccessUnknown = "unknown"
+)
+
+// FeatureAccess reports whether a profile can serve one dashboard feature.
+type FeatureAccess struct {
+	Feature string `json:"feature"`
+	Action  string `json:"action"`
+	Access  string `json:"access"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Permissions is the result of probing a profile's dashboard features.
+type Permissions struct {
+	ProfileID string          `json:"profileId"`
+	Region    string          `json:"region"`
+	Features  []FeatureAccess `json:"features"`
+}
+
+// featureProbe is a cheap read-only call that succeeds only when the
+// profile may use a feature. A probe with no args is checked with
+// iam simulate-principal-policy instead, for APIs that bill per request.
+type featureProbe struct {
+	feature string
+	action  string
+	args    []string
+}
+
+// featureProbes lists the features the UI may hide, keyed by the service
+// names used in /api/services/{service}/resources ("cost" for Cost Explorer).
+var featureProbes = []featureProbe{
+	// Cost Explorer charges for every API request, so it is simulated.
+	{feature: "cost", action: "ce:GetCostAndUsage"},
+	{feature: "ec2", action: "ec2:DescribeInstances", args: []string{"ec2", "describe-instances", "--max-results", "5"}},
+	{feature: "vpc", action: "ec2:DescribeVpcs", args: []string{"ec2", "describe-vpcs", "--max-results", "5"}},
+	{feature: "s3", action: "s3:ListAllMyBuckets", args: []string{"s3api", "list-buckets", "--max-items", "1"}},
+	{feature: "rds", action: "rds:DescribeDBInstances", args: []string{"rds", "describe-db-instances", "--max-records", "20"}},
+	{feature: "lambda", action: "lambda:ListFunctions", args: []string{"lambda", "list-functions", "--max-items", "1"}},
+	{feature: "iam", action: "iam:ListUsers", args: []string{"iam", "list-users", "--max-items", "1"}},
+	{feature: "cloudwatch-alarms", action: "cloudwatch:DescribeAlarms", args: []string{"cloudwatch", "describe-alarms", "--max-records", "1"}},
+	{feature: "log-groups", action: "logs:DescribeLogGroups", args: []string{"logs", "describe-log-groups", "--limit", "1"}},
+	{feature: "cloudformation", action: "cloudformation:DescribeStacks", args: []string{"cloudformation", "describe-stacks", "--max-items", "1"}},
+}
+
+// probeFallbackRegion is used for regional probes when the profile has no
+// region configured.
+const probeFallbackRegion = "us-east-1"
+
+// ProbePermissions checks which dashboard features profile id ("system" for
+// the host's credentials) can use, without switching to it. Probes run in
+// parallel; a failed probe is reported per feature rather than as an error.
+func (m *Manager) ProbePermissions(ctx context.Context, id string) (Permissions, error) {
+	if err := m.checkExists(id); err != nil {
+		return Permissions{}, err
+	}
+
+	env, err := m.Env(ctx, id)
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	m.mu.RLock()
+	region := defaultRegion(m.profiles[id])
+	m.mu.RUnlock()
+	if region == "" {
+		region = probeFallbackRegion
+	}
+
+	// Simulation needs the caller's ARN; this also fails fast on bad
+	// credentials before launching every probe.
+	caller, err := callerIdentity(ctx, env)
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	features := make([]FeatureAccess, len(featureProbes))
+	var wg sync.WaitGroup
+	for i, probe := range featureProbes {
+		features[i] = FeatureAccess{Feature: probe.feature, Action: probe.action}
+
+		wg.Add(1)
+		go func(fa *FeatureAccess, probe featureProbe) {
+			defer wg.Done()
+			if probe.args == nil {
+				fa.Access, fa.Error = simulateAction(ctx, env, caller.ARN, probe.action)
+				return
+			}
+			args := append(append([]string(nil), probe.args...), "--region", region)
+			if _, err := runCLI(ctx, env, args...); err != nil {
+				fa.Access, fa.Error = classifyProbeError(err), err.Error()
+				return
+			}
+			fa.Access = AccessAllowed
+		}(&features[i], probe)
+	}
+	wg.Wait()
+
+	return Permissions{ProfileID: id, Region: region, Features: features}, nil
+}
+
+// simulateAction evaluates action for the principal behind callerARN with
+// iam simulate-principal-policy, passing any extra arguments through.
+// Simulation itself needs iam:SimulatePrincipalPolicy; without it the result
+// is unknown.
+func simulateAction(ctx context.Context, env []string, callerARN, action string, extra ...string) (string, string) {
+	source, ok := policySourceARN(callerARN)
+	if !ok {
+		// The account root user is allowed everything.
+		return AccessAllowed, ""
+	}
+
+	args := append([]string{"iam", "simulate-principal-policy",
+		"--policy-source-arn", source,
+		"--action-names", action}, extra...)
+	out, err := runCLI(ctx, env, args...)
+	if err != nil {
+		return AccessUnknown, err.Error()
+	}
+
+	var resp struct {
+		EvaluationResults []struct {
+			EvalDecision string `json:"EvalDecision"`
+		} `json:"EvaluationResults"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return AccessUnknown, fmt.Sprintf("failed to parse iam simulate-principal-policy response: %v", err)
+	}
+	if len(resp.EvaluationResults) == 0 {
+		return AccessUnknown, "iam simulate-principal-policy returned no results"
+	}
+	if resp.EvaluationResults[0].EvalDecision == "allowed" {
+		return AccessAllowed, ""
+	}
+	return AccessDenied, ""
+}
+
+// policySourceARN maps a caller ARN to the IAM user or role ARN that
+// simulate-principal-policy accepts; assumed-role session ARNs become the
+// role ARN (without its path, which the session ARN omits). It reports false
+// for the root user.
+func policySourceARN(callerARN string) (string, bool) {
+	parts := strings.SplitN(callerARN, ":", 6)
+	if len(parts) != 6 {
+		return callerARN, true
+	}
+	resource := parts[5]
+	switch {
+	case resource == "root":
+		return "", false
+	case parts[2] == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		role, _, _ := strings.Cut(strings.TrimPrefix(resource, "assumed-role/"), "/")
+		return fmt.Sprintf("%s:%s:iam::%s:role/%s", parts[0], parts[1], parts[4], role), true
+	}
+	return callerARN, true
+}
+
+// classifyProbeError reports whether a failed probe means the action is
+// denied, based on the error codes AWS services use for authorization
+// failures.
+func classifyProbeError(err error) string {
+	msg := err.Error()
+	for _, code := range []string{"AccessDenied", "UnauthorizedOperation", "AuthorizationError", "not authorized"} {
+		if strings.Contains(msg, code) {
+			return AccessDenied
+		}
+	}
+	return AccessUnknown
+}
@@ -0,0 +1,73 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrMissingPermission is returned by CheckPermission when the profile's
+// IAM policies don't allow the command's action.
+var ErrMissingPermission = errors.New("missing IAM permission")
+
+// permissionChecker caches recent CheckPermission simulation results.
+type permissionChecker struct {
+	mu        sync.Mutex
+	decisions map[string]permissionDecision
+}
+
+type permissionDecision struct {
+	access string
+	// reason says why an AccessUnknown simulation failed.
+	reason string
+	at     time.Time
+}
+
+// CheckPermission simulates the IAM action of the aws CLI command in args
+// (see IAMAction) against the context's profile's own policies with
+// iam simulate-principal-policy, so a command the profile can't run fails
+// with a clear error wrapping ErrMissingPermission instead of the service's
+// AccessDenied. Commands that don't map to a single action, and the account
+// root user, pass. Other errors mean the simulation couldn't tell, e.g.
+// because the profile may not call iam:SimulatePrincipalPolicy; such results
+// are remembered like the others, for readOnlyDecisionTTL.
+func (m *Manager) CheckPermission(ctx context.Context, args []string) error {
+	action, err := IAMAction(args)
+	if err != nil {
+		return nil
+	}
+
+	id := m.ProfileID(ctx)
+	key := id + "|" + action
+	m.preflight.mu.Lock()
+	d, ok := m.preflight.decisions[key]
+	m.preflight.mu.Unlock()
+	if !ok || time.Since(d.at) >= readOnlyDecisionTTL {
+		env, err := m.Env(ctx, id)
+		if err != nil {
+			return err
+		}
+		caller, err := callerIdentity(ctx, env)
+		if err != nil {
+			return err
+		}
+		access, msg := simulateAction(ctx, env, caller.ARN, action)
+		d = permissionDecision{access: access, reason: msg, at: time.Now()}
+		m.preflight.mu.Lock()
+		if m.preflight.decisions == nil {
+			m.preflight.decisions = make(map[string]permissionDecision)
+		}
+		m.preflight.decisions[key] = d
+		m.preflight.mu.Unlock()
+	}
+
+	switch d.access {
+	case AccessAllowed:
+		return nil
+	case AccessDenied:
+		return fmt.Errorf("%w: profile %q lacks %s", ErrMissingPermission, id, action)
+	}
+	return fmt.Errorf("failed to simulate %s: %s", action, d.reason)
+}
@@ -0,0 +1,202 @@
+package profiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotReadOnly is returned by CheckReadOnly when IAM does not allow the
+// operation as a read action for the profile.
+var ErrNotReadOnly = errors.New("operation is not a permitted read action for this profile")
+
+// readOnlyPolicyARN is the AWS managed policy whose actions count as reads.
+// AWS maintains it as services add operations, unlike a list of verbs.
+const readOnlyPolicyARN = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+
+// readOnlyDecisionTTL is how long a simulation result is reused for the same
+// profile and action.
+const readOnlyDecisionTTL = 10 * time.Minute
+
+// cliServiceActionPrefix maps aws CLI service names to IAM action prefixes
+// where they differ.
+var cliServiceActionPrefix = map[string]string{
+	"s3api":         "s3",
+	"elb":           "elasticloadbalancing",
+	"elbv2":         "elasticloadbalancing",
+	"configservice": "config",
+	"stepfunctions": "states",
+	"sesv2":         "ses",
+	"opensearch":    "es",
+}
+
+// readOnlyChecker caches the read-only boundary policy and recent
+// simulation results.
+type readOnlyChecker struct {
+	mu sync.Mutex
+	// boundaryFile holds the ReadOnlyAccess policy document, passed to
+	// simulate-principal-policy as file:// since it exceeds argument limits
+	// on some platforms.
+	boundaryFile string
+	decisions    map[string]readOnlyDecision
+}
+
+type readOnlyDecision struct {
+	allowed bool
+	at      time.Time
+}
+
+// IAMAction maps an aws CLI invocation such as "ec2 describe-instances ..."
+// to its IAM action, "ec2:DescribeInstances". IAM matches action names
+// case-insensitively, so "rds describe-db-instances" becoming
+// "rds:DescribeDbInstances" still evaluates correctly. High-level commands
+// with no single API operation (aws s3 ls, aws configure) are rejected.
+func IAMAction(args []string) (string, error) {
+	if len(args) < 2 || strings.HasPrefix(args[0], "-") || strings.HasPrefix(args[1], "-") {
+		return "", fmt.Errorf("expected <service> <operation>")
+	}
+	service, op := strings.ToLower(args[0]), strings.ToLower(args[1])
+	if service == "s3" || service == "configure" || service == "help" || op == "help" || op == "wait" {
+		return "", fmt.Errorf("%s %s does not map to a single API operation", service, op)
+	}
+	if prefix, ok := cliServiceActionPrefix[service]; ok {
+		service = prefix
+	}
+
+	var name strings.Builder
+	for _, part := range strings.Split(op, "-") {
+		if part == "" {
+			continue
+		}
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return service + ":" + name.String(), nil
+}
+
+// CheckReadOnly verifies with iam simulate-principal-policy that the aws CLI
+// command in args is allowed for the context's profile (see ProfileID) under
+// the ReadOnlyAccess policy as a permissions boundary, i.e. that it is a read
+// action the profile may perform. It returns an error wrapping
+// ErrNotReadOnly when it is not, and other errors when it cannot tell.
+func (m *Manager) CheckReadOnly(ctx context.Context, args []string) error {
+	action, err := IAMAction(args)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotReadOnly, err)
+	}
+
+	id := m.ProfileID(ctx)
+	key := id + "|" + action
+	m.readOnly.mu.Lock()
+	d, ok := m.readOnly.decisions[key]
+	m.readOnly.mu.Unlock()
+	if ok && time.Since(d.at) < readOnlyDecisionTTL {
+		if !d.allowed {
+			return fmt.Errorf("%w: %s", ErrNotReadOnly, action)
+		}
+		return nil
+	}
+
+	env, err := m.Env(ctx, id)
+	if err != nil {
+		return err
+	}
+	caller, err := callerIdentity(ctx, env)
+	if err != nil {
+		return err
+	}
+	if _, ok := policySourceARN(caller.ARN); !ok {
+		return fmt.Errorf("IAM policy simulation is not available for the account root user")
+	}
+	boundary, err := m.readOnlyBoundary(ctx, env)
+	if err != nil {
+		return err
+	}
+
+	access, msg := simulateAction(ctx, env, caller.ARN, action,
+		"--permissions-boundary-policy-input-list", "file://"+boundary)
+	if access == AccessUnknown {
+		return fmt.Errorf("failed to simulate %s: %s", action, msg)
+	}
+
+	m.readOnly.mu.Lock()
+	if m.readOnly.decisions == nil {
+		m.readOnly.decisions = make(map[string]readOnlyDecision)
+	}
+	m.readOnly.decisions[key] = readOnlyDecision{allowed: access == AccessAllowed, at: time.Now()}
+	m.readOnly.mu.Unlock()
+
+	if access != AccessAllowed {
+		return fmt.Errorf("%w: %s", ErrNotReadOnly, action)
+	}
+	return nil
+}
+
+// readOnlyBoundary returns the path of a file holding the default version of
+// the ReadOnlyAccess policy document, fetching it on first use.
+func (m *Manager) readOnlyBoundary(ctx context.Context, env []string) (string, error) {
+	m.readOnly.mu.Lock()
+	defer m.readOnly.mu.Unlock()
+
+	if m.readOnly.boundaryFile != "" {
+		return m.readOnly.boundaryFile, nil
+	}
+
+	out, err := runCLI(ctx, env, "iam", "get-policy", "--policy-arn", readOnlyPolicyARN)
+	if err != nil {
+		return "", err
+	}
+	var policy struct {
+		Policy struct {
+			DefaultVersionID string `json:"DefaultVersionId"`
+		} `json:"Policy"`
+	}
+	if err := json.Unmarshal(out, &policy); err != nil {
+		return "", fmt.Errorf("failed to parse iam get-policy response: %w", err)
+	}
+
+	out, err = runCLI(ctx, env, "iam", "get-policy-version",
+		"--policy-arn", readOnlyPolicyARN,
+		"--version-id", policy.Policy.DefaultVersionID)
+	if err != nil {
+		return "", err
+	}
+	var version struct {
+		PolicyVersion struct {
+			Document json.RawMessage `json:"Document"`
+		} `json:"PolicyVersion"`
+	}
+	if err := json.Unmarshal(out, &version); err != nil {
+		return "", fmt.Errorf("failed to parse iam get-policy-version response: %w", err)
+	}
+	doc := []byte(version.PolicyVersion.Document)
+	if len(doc) == 0 {
+		return "", fmt.Errorf("iam get-policy-version returned no document for %s", readOnlyPolicyARN)
+	}
+	// The API returns the document URL-encoded; the CLI usually decodes it.
+	var encoded string
+	if json.Unmarshal(doc, &encoded) == nil {
+		decoded, err := url.QueryUnescape(encoded)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode %s policy document: %w", readOnlyPolicyARN, err)
+		}
+		doc = []byte(decoded)
+	}
+
+	f, err := os.CreateTemp("", "aws-dashboard-readonly-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(doc); err != nil {
+		return "", err
+	}
+
+	m.readOnly.boundaryFile = f.Name()
+	return m.readOnly.boundaryFile, nil
+}
@@ -0,0 +1,173 @@
+package profiles
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BundleVersion is the format version of exported profile bundles.
+const BundleVersion = 1
+
+// Bundle is a set of custom profiles exported for sharing, e.g. a team's
+// profiles for a sandbox account. Unlike backups it carries no active
+// selection and is merged into, not swapped for, the importer's profiles.
+type Bundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	// Redacted bundles omit access keys; only aws-vault profiles and
+	// assume-role profiles based on the importer's system credentials remain
+	// usable from them.
+	Redacted bool      `json:"redacted"`
+	Profiles []Profile `json:"profiles"`
+}
+
+// ImportResult reports what ImportProfiles did with each bundled profile.
+type ImportResult struct {
+	Imported []PublicProfile `json:"imported"`
+	Skipped  []SkippedImport `json:"skipped,omitempty"`
+}
+
+// SkippedImport names a bundled profile that was not imported and why.
+type SkippedImport struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ExportProfiles bundles the custom profiles in ids (all of them when ids is
+// empty). Base profiles of selected assume-role profiles are included so the
+// bundle is self-contained. With redact, access keys are left out.
+func (m *Manager) ExportProfiles(ids []string, redact bool) (Bundle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	selected := make(map[string]bool)
+	if len(ids) == 0 {
+		for id := range m.profiles {
+			selected[id] = true
+		}
+	}
+	for _, id := range ids {
+		if _, ok := m.profiles[id]; !ok {
+			return Bundle{}, fmt.Errorf("profile %q not found", id)
+		}
+		selected[id] = true
+	}
+	for id := range selected {
+		if base := m.profiles[id].BaseProfileID; base != "" && base != "system" {
+			selected[base] = true
+		}
+	}
+
+	bundle := Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Redacted:   redact,
+	}
+	for id := range selected {
+		p := m.profiles[id]
+		if redact {
+			p.AccessKeyID, p.SecretAccessKey, p.SessionToken = "", "", ""
+		}
+		bundle.Profiles = append(bundle.Profiles, p)
+	}
+	sort.Slice(bundle.Profiles, func(i, j int) bool {
+		return bundle.Profiles[i].Name < bundle.Profiles[j].Name
+	})
+	return bundle, nil
+}
+
+// ImportProfiles adds the profiles in b under new IDs, keeping existing
+// profiles and the active selection. Profiles whose name is already taken,
+// that lack credentials, or whose base profile is missing are skipped.
+// Credentials are not checked; use Identify to validate them.
+func (m *Manager) ImportProfiles(b Bundle) (ImportResult, error) {
+	if b.Version != BundleVersion {
+		return ImportResult{}, fmt.Errorf("unsupported profile bundle version %d", b.Version)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	taken := make(map[string]bool)
+	for _, p := range m.profiles {
+		taken[strings.ToLower(p.Name)] = true
+	}
+
+	// Key-based profiles go first so assume-role profiles can be remapped to
+	// their bases' new IDs.
+	incoming := append([]Profile(nil), b.Profiles...)
+	sort.SliceStable(incoming, func(i, j int) bool {
+		return incoming[i].RoleARN == "" && incoming[j].RoleARN != ""
+	})
+
+	result := ImportResult{Imported: []PublicProfile{}}
+	newIDs := make(map[string]string)
+	for _, p := range incoming {
+		p.Name = strings.TrimSpace(p.Name)
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, SkippedImport{Name: p.Name, Reason: reason})
+		}
+
+		switch {
+		case p.Name == "":
+			skip("profile name is required")
+			continue
+		case taken[strings.ToLower(p.Name)]:
+			skip("a profile with this name already exists")
+			continue
+		}
+
+		if p.VaultProfile != "" {
+			p.Source = SourceAWSVault
+			p.AccessKeyID, p.SecretAccessKey, p.SessionToken = "", "", ""
+			p.RoleARN, p.BaseProfileID, p.MFASerial = "", "", ""
+		} else if p.RoleARN != "" {
+			p.Source = SourceAssumeRole
+			p.AccessKeyID, p.SecretAccessKey, p.SessionToken = "", "", ""
+			if p.BaseProfileID != "system" {
+				base, ok := newIDs[p.BaseProfileID]
+				if !ok {
+					skip(fmt.Sprintf("base profile %q is not in the bundle or was skipped", p.BaseProfileID))
+					continue
+				}
+				p.BaseProfileID = base
+			}
+		} else {
+			if p.AccessKeyID == "" || p.SecretAccessKey == "" {
+				skip("credentials were redacted")
+				continue
+			}
+			p.Source = SourceCustom
+			p.BaseProfileID = ""
+		}
+
+		oldID := p.ID
+		p.ID = strconv.FormatInt(m.nextID, 10)
+		m.nextID++
+		m.profiles[p.ID] = p
+		newIDs[oldID] = p.ID
+		taken[strings.ToLower(p.Name)] = true
+
+		result.Imported = append(result.Imported, PublicProfile{
+			ID:             p.ID,
+			Name:           p.Name,
+			Source:         p.Source,
+			RoleARN:        p.RoleARN,
+			BaseProfileID:  p.BaseProfileID,
+			MFASerial:      p.MFASerial,
+			VaultProfile:   p.VaultProfile,
+			Region:         p.Region,
+			AllowedRegions: p.AllowedRegions,
+			// MFA profiles need ActivateMFA before use.
+			IsExpired: p.MFASerial != "",
+		})
+	}
+
+	if len(result.Imported) > 0 {
+		m.saveLocked()
+	}
+	return result, nil
+}
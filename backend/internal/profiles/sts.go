@@ -0,0 +1,110 @@
+package profiles
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// sessionCredentials are temporary credentials from sts assume-role,
+// get-session-token or aws-vault.
+type sessionCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+	// issuedFor records what the credentials were issued for, so a profile
+	// replaced by an import under the same ID doesn't reuse them.
+	issuedFor string
+}
+
+// validFor reports whether c belongs to p and is still valid margin from now.
+func (c sessionCredentials) validFor(p Profile, now time.Time, margin time.Duration) bool {
+	return c.issuedFor == credentialKey(p) && now.Add(margin).Before(c.Expiration)
+}
+
+func (c sessionCredentials) env(region string) []string {
+	return staticEnv(Profile{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Region:          region,
+	})
+}
+
+// credentialKey identifies the settings temporary credentials depend on.
+func credentialKey(p Profile) string {
+	if p.RoleARN != "" {
+		return "role|" + p.BaseProfileID + "|" + p.RoleARN + "|" + p.ExternalID
+	}
+	if p.VaultProfile != "" {
+		return "vault|" + p.VaultProfile
+	}
+	return "mfa|" + p.MFASerial + "|" + p.AccessKeyID
+}
+
+// runCLI runs an aws command with env overrides (nil for the process
+// environment) and returns its JSON output. The error names the operation
+// and includes the CLI's message, which never contains credentials.
+func runCLI(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	args = append(args, "--output", "json")
+	cmd := exec.CommandContext(ctx, "aws", args...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s failed: %s", strings.Join(args[:2], " "), msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+// runSTS runs an sts command that returns Credentials, with env overrides
+// (nil for the process environment).
+func runSTS(ctx context.Context, env []string, args ...string) (sessionCredentials, error) {
+	op := strings.Join(args[:2], " ")
+	out, err := runCLI(ctx, env, args...)
+	if err != nil {
+		return sessionCredentials{}, err
+	}
+
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string `json:"AccessKeyId"`
+			SecretAccessKey string `json:"SecretAccessKey"`
+			SessionToken    string `json:"SessionToken"`
+			Expiration      string `json:"Expiration"`
+		} `json:"Credentials"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return sessionCredentials{}, fmt.Errorf("failed to parse %s response: %w", op, err)
+	}
+	c := resp.Credentials
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" || c.SessionToken == "" {
+		return sessionCredentials{}, fmt.Errorf("%s returned no credentials", op)
+	}
+	expiration, err := time.Parse(time.RFC3339, c.Expiration)
+	if err != nil {
+		return sessionCredentials{}, fmt.Errorf("invalid credential expiration %q: %w", c.Expiration, err)
+	}
+
+	return sessionCredentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Expiration:      expiration,
+	}, nil
+}
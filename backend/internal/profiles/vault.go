@@ -0,0 +1,160 @@
+package profiles
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vaultDefaultLifetime is assumed for aws-vault credentials that don't report
+// an expiration (long-lived keys exposed without a session).
+const vaultDefaultLifetime = 15 * time.Minute
+
+// AddVaultProfile stores and activates a profile whose credentials come from
+// `aws-vault exec vaultProfile`, so keys stay in aws-vault's encrypted store.
+// The credentials are fetched once up front to validate the profile.
+func (m *Manager) AddVaultProfile(ctx context.Context, name, vaultProfile, region string) (Profile, error) {
+	name = strings.TrimSpace(name)
+	vaultProfile = strings.TrimSpace(vaultProfile)
+	if name == "" {
+		return Profile{}, fmt.Errorf("profile name is required")
+	}
+	if vaultProfile == "" || strings.HasPrefix(vaultProfile, "-") {
+		return Profile{}, fmt.Errorf("aws-vault profile name is required")
+	}
+
+	p := Profile{
+		Name:         name,
+		Region:       region,
+		Source:       SourceAWSVault,
+		VaultProfile: vaultProfile,
+	}
+
+	creds, vaultRegion, err := vaultCredentials(ctx, vaultProfile)
+	if err != nil {
+		return Profile{}, err
+	}
+	creds.issuedFor = credentialKey(p)
+	if p.Region == "" {
+		p.Region = vaultRegion
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p.ID = strconv.FormatInt(m.nextID, 10)
+	m.nextID++
+
+	m.profiles[p.ID] = p
+	m.sessionCreds[p.ID] = creds
+	m.activeID = p.ID
+
+	m.saveLocked()
+
+	return p, nil
+}
+
+// vaultEnv returns the environment for an aws-vault profile, asking aws-vault
+// again when the cached credentials are missing or about to expire.
+func (m *Manager) vaultEnv(ctx context.Context, p Profile) ([]string, error) {
+	m.mu.RLock()
+	creds, ok := m.sessionCreds[p.ID]
+	m.mu.RUnlock()
+
+	if !ok || !creds.validFor(p, time.Now(), roleRefreshWindow) {
+		m.refreshMu.Lock()
+		defer m.refreshMu.Unlock()
+
+		// Another request may have refreshed them while we waited.
+		m.mu.RLock()
+		creds, ok = m.sessionCreds[p.ID]
+		m.mu.RUnlock()
+
+		if !ok || !creds.validFor(p, time.Now(), roleRefreshWindow) {
+			var err error
+			creds, _, err = vaultCredentials(ctx, p.VaultProfile)
+			if err != nil {
+				return nil, err
+			}
+			creds.issuedFor = credentialKey(p)
+
+			m.mu.Lock()
+			if _, exists := m.profiles[p.ID]; exists {
+				m.sessionCreds[p.ID] = creds
+				delete(m.expired, p.ID)
+			}
+			m.mu.Unlock()
+		}
+	}
+
+	return creds.env(defaultRegion(p)), nil
+}
+
+// vaultCredentials runs `aws-vault exec <profile> -- env` and reads the
+// credentials (and region, if configured) it exports. aws-vault must be able
+// to unlock its store without a prompt, e.g. an unlocked keychain or
+// AWS_VAULT_FILE_PASSPHRASE for the file backend; stdin is closed so a
+// prompt fails instead of hanging the request.
+func vaultCredentials(ctx context.Context, vaultProfile string) (sessionCredentials, string, error) {
+	cmd := exec.CommandContext(ctx, "aws-vault", "exec", vaultProfile, "--", "env")
+	// aws-vault refuses to nest inside another aws-vault shell.
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "AWS_VAULT=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return sessionCredentials{}, "", fmt.Errorf("aws-vault exec %s failed: %s", vaultProfile, msg)
+	}
+
+	vars := map[string]string{}
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		if key, value, ok := strings.Cut(sc.Text(), "="); ok && strings.HasPrefix(key, "AWS_") {
+			vars[key] = value
+		}
+	}
+
+	creds := sessionCredentials{
+		AccessKeyID:     vars["AWS_ACCESS_KEY_ID"],
+		SecretAccessKey: vars["AWS_SECRET_ACCESS_KEY"],
+		SessionToken:    vars["AWS_SESSION_TOKEN"],
+		Expiration:      time.Now().Add(vaultDefaultLifetime),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return sessionCredentials{}, "", fmt.Errorf("aws-vault exec %s returned no credentials", vaultProfile)
+	}
+	// Newer aws-vault versions set AWS_CREDENTIAL_EXPIRATION, older ones
+	// AWS_SESSION_EXPIRATION.
+	for _, key := range []string{"AWS_CREDENTIAL_EXPIRATION", "AWS_SESSION_EXPIRATION"} {
+		if v := vars[key]; v != "" {
+			expiration, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return sessionCredentials{}, "", fmt.Errorf("invalid credential expiration %q: %w", v, err)
+			}
+			creds.Expiration = expiration
+			break
+		}
+	}
+
+	region := vars["AWS_REGION"]
+	if region == "" {
+		region = vars["AWS_DEFAULT_REGION"]
+	}
+	return creds, region, nil
+}
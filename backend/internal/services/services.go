@@ -10,18 +10,69 @@ import (
 // ErrCostExplorerDisabled is returned when AWS Cost Explorer is not enabled for the account.
 var ErrCostExplorerDisabled = errors.New("aws cost explorer is not enabled for this account")
 
+// ErrCostExplorerAccessDenied is returned (wrapped) when the credentials lack
+// ce:* permissions.
+var ErrCostExplorerAccessDenied = errors.New("access to aws cost explorer was denied")
+
+// ErrCostDataUnavailable is returned (wrapped) when Cost Explorer has no data
+// for the requested period, e.g. before the account existed.
+var ErrCostDataUnavailable = errors.New("cost data is not available for this period")
+
+// ErrCostExplorerThrottled is returned (wrapped) when Cost Explorer rejects a
+// request for exceeding its rate limit.
+var ErrCostExplorerThrottled = errors.New("aws cost explorer rate limit exceeded")
+
+// ErrCredentialsExpired is returned (wrapped) when an AWS CLI call fails
+// because the active profile's session credentials have expired.
+var ErrCredentialsExpired = errors.New("aws credentials have expired")
+
+// ErrCLINotFound is returned (wrapped) when the aws CLI is not installed or
+// not on the server's PATH.
+var ErrCLINotFound = errors.New("aws cli not found")
+
+// ErrInvalidGranularity is returned for an unknown time-series granularity, or
+// HOURLY over a range longer than Cost Explorer allows.
+var ErrInvalidGranularity = errors.New("invalid cost granularity")
+
+// ErrInvalidResourceFilter is returned (wrapped) for a resource filter on a
+// field no resource list has.
+var ErrInvalidResourceFilter = errors.New("invalid resource filter")
+
 type CostService interface {
 	// GetCostOverview returns the overall cost for a period. If start/end are
-	// empty, the current month is used.
-	GetCostOverview(ctx context.Context, start, end string) (types.CostOverview, error)
-	GetServiceCosts(ctx context.Context, start, end string) ([]types.ServiceCost, error)
+	// empty, the current month is used. A zero filter includes all costs.
+	GetCostOverview(ctx context.Context, start, end string, filter types.CostFilter) (types.CostOverview, error)
+	GetServiceCosts(ctx context.Context, start, end string, filter types.CostFilter) ([]types.ServiceCost, error)
+	// GetServiceBreakdown splits one service's cost by usage type. service may
+	// be a Cost Explorer service name or a drilldown key such as "ec2".
+	GetServiceBreakdown(ctx context.Context, service, start, end string) (types.ServiceCostBreakdown, error)
+	// GetEC2OtherCosts groups the "EC2 - Other" line's usage types into
+	// readable buckets such as NAT gateway hours and EBS snapshots.
+	GetEC2OtherCosts(ctx context.Context, start, end string) (types.EC2OtherBreakdown, error)
+	// GetCostTimeSeries returns spend per period at the given granularity
+	// (DAILY, MONTHLY or HOURLY; empty means DAILY).
+	GetCostTimeSeries(ctx context.Context, start, end, granularity string, filter types.CostFilter) (types.CostTimeSeries, error)
+	// GetUntaggedCosts reports usage cost without a value for tagKey, per
+	// service.
+	GetUntaggedCosts(ctx context.Context, tagKey, start, end string) (types.UntaggedCostReport, error)
+	// GetPurchaseTypeCosts splits amortized cost by purchase option (On
+	// Demand, Spot, Reserved, Savings Plans).
+	GetPurchaseTypeCosts(ctx context.Context, start, end string, filter types.CostFilter) (types.PurchaseTypeReport, error)
+	// GetStatus reports whether Cost Explorer is usable and how many billed
+	// Cost Explorer requests this process has made. With probe set, a single
+	// small query is made if nothing is known yet.
+	GetStatus(ctx context.Context, probe bool) (types.CostExplorerStatus, error)
+	// GetServiceSparklines returns monthly cost per service for the trailing
+	// twelve months, including the current one.
+	GetServiceSparklines(ctx context.Context) (types.ServiceSparklines, error)
 }
 
 // ResourceService provides resource listings for services.
 type ResourceService interface {
 	// region can be a specific AWS region (e.g. "us-east-1") or "all" to
 	// aggregate across all regions. If empty, the AWS CLI default region is used.
-	GetResources(ctx context.Context, service, region string) (types.ServiceResources, error)
+	// Lists are narrowed to resources matching filter.
+	GetResources(ctx context.Context, service, region string, filter types.ResourceFilter) (types.ServiceResources, error)
 }
 
 
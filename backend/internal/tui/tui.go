@@ -0,0 +1,311 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/local/aws-local-dashboard/internal/profiles"
+	"github.com/local/aws-local-dashboard/internal/services"
+	"github.com/local/aws-local-dashboard/internal/types"
+)
+
+const (
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+	ansiReset = "\033[0m"
+	ansiClear = "\033[H\033[2J"
+
+	// maxCellWidth keeps wide values (ARNs, endpoints) from wrapping tables.
+	maxCellWidth = 48
+)
+
+// App is a terminal front end for the dashboard. It uses the same service
+// implementations as the HTTP server, so caching and profile selection
+// behave identically.
+type App struct {
+	costs          services.CostService
+	resources      services.ResourceService
+	profileManager *profiles.Manager
+
+	out io.Writer
+}
+
+// New creates a terminal App.
+func New(costs services.CostService, resources services.ResourceService, profileManager *profiles.Manager) *App {
+	return &App{
+		costs:          costs,
+		resources:      resources,
+		profileManager: profileManager,
+	}
+}
+
+// Run reads commands from in and renders results to out until the user quits,
+// in reaches EOF or ctx is cancelled.
+func (a *App) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	a.out = out
+	scanner := bufio.NewScanner(in)
+
+	fmt.Fprint(out, ansiClear)
+	a.printHeader()
+	a.showCosts(ctx, nil)
+
+	for {
+		fmt.Fprintf(out, "\n%s>%s ", ansiBold, ansiReset)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		cmd, args := fields[0], fields[1:]
+		switch cmd {
+		case "q", "quit", "exit":
+			return nil
+		case "h", "help", "?":
+			a.printHelp()
+		case "c", "costs":
+			fmt.Fprint(out, ansiClear)
+			a.printHeader()
+			a.showCosts(ctx, args)
+		case "r", "res", "resources":
+			fmt.Fprint(out, ansiClear)
+			a.printHeader()
+			a.showResources(ctx, args)
+		case "p", "profiles":
+			a.showProfiles()
+		case "use":
+			a.useProfile(args)
+		default:
+			fmt.Fprintf(out, "Unknown command %q. Type 'help' for a list of commands.\n", cmd)
+		}
+	}
+}
+
+func (a *App) printHeader() {
+	profile := "system"
+	if a.profileManager != nil {
+		if id := a.profileManager.ActiveID(); id != "" {
+			profile = id
+		}
+	}
+	fmt.Fprintf(a.out, "%sAWS Local Dashboard%s  %sprofile: %s  ·  type 'help' for commands%s\n\n",
+		ansiBold, ansiReset, ansiDim, profile, ansiReset)
+}
+
+func (a *App) printHelp() {
+	fmt.Fprintln(a.out, `Commands:
+  costs [start end]            cost overview and per-service breakdown (dates as YYYY-MM-DD)
+  res <service> [region|all]   resource table for a service, e.g. "res ec2 all"
+  profiles                     list profiles
+  use <id>                     switch the active profile ("system" for host credentials)
+  quit                         exit`)
+}
+
+func (a *App) showCosts(ctx context.Context, args []string) {
+	var start, end string
+	if len(args) >= 2 {
+		start, end = args[0], args[1]
+	}
+
+	overview, err := a.costs.GetCostOverview(ctx, start, end, types.CostFilter{})
+	if err != nil {
+		fmt.Fprintf(a.out, "Failed to fetch cost overview: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(a.out, "%sCost overview%s  %s → %s\n", ansiBold, ansiReset, overview.Start, overview.End)
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  Usage\t%s\n", money(overview.Total, overview.Currency))
+	fmt.Fprintf(tw, "  Credits\t-%s\n", money(overview.CreditsApplied, overview.Currency))
+	fmt.Fprintf(tw, "  Net\t%s\n", money(overview.NetTotal, overview.Currency))
+	tw.Flush()
+
+	svcCosts, err := a.costs.GetServiceCosts(ctx, start, end, types.CostFilter{})
+	if err != nil {
+		fmt.Fprintf(a.out, "\nFailed to fetch service costs: %v\n", err)
+		return
+	}
+
+	sort.Slice(svcCosts, func(i, j int) bool { return svcCosts[i].Cost > svcCosts[j].Cost })
+
+	fmt.Fprintf(a.out, "\n%sServices%s\n", ansiBold, ansiReset)
+	tw = tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  SERVICE\tCOST\tDRILLDOWN")
+	for _, sc := range svcCosts {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", truncate(sc.DisplayName), money(sc.Cost, sc.Currency), sc.DrilldownKey)
+	}
+	tw.Flush()
+}
+
+func (a *App) showResources(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(a.out, "Usage: res <service> [region|all]")
+		return
+	}
+	service := args[0]
+	region := ""
+	if len(args) > 1 {
+		region = args[1]
+	}
+
+	fmt.Fprintf(a.out, "Fetching %s resources...\n", service)
+	res, err := a.resources.GetResources(ctx, service, region, nil)
+	if err != nil {
+		fmt.Fprintf(a.out, "Failed to fetch resources: %v\n", err)
+		return
+	}
+
+	a.renderResources(res)
+	if res.Message != "" {
+		fmt.Fprintf(a.out, "\n%s%s%s\n", ansiDim, res.Message, ansiReset)
+	}
+}
+
+// renderResources prints one table per non-empty resource list in res, using
+// the JSON field names as column headers.
+func (a *App) renderResources(res types.ServiceResources) {
+	v := reflect.ValueOf(res)
+	t := v.Type()
+
+	printed := false
+	for i := 0; i < t.NumField(); i++ {
+		fv := v.Field(i)
+		if fv.Kind() != reflect.Slice || fv.Len() == 0 || fv.Type().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		printed = true
+
+		fmt.Fprintf(a.out, "\n%s%s%s (%d)\n", ansiBold, jsonName(t.Field(i)), ansiReset, fv.Len())
+		renderTable(a.out, fv)
+	}
+
+	if !printed {
+		fmt.Fprintln(a.out, "No resources found.")
+	}
+}
+
+func renderTable(out io.Writer, rows reflect.Value) {
+	elem := rows.Type().Elem()
+
+	var cols []int
+	var headers []string
+	for i := 0; i < elem.NumField(); i++ {
+		f := elem.Field(i)
+		if !f.IsExported() || jsonName(f) == "-" {
+			continue
+		}
+		cols = append(cols, i)
+		headers = append(headers, strings.ToUpper(jsonName(f)))
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  %s\n", strings.Join(headers, "\t"))
+	for r := 0; r < rows.Len(); r++ {
+		row := rows.Index(r)
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			cells[i] = truncate(formatCell(row.Field(c)))
+		}
+		fmt.Fprintf(tw, "  %s\n", strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
+
+func formatCell(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatCell(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return formatCell(v.Elem())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func (a *App) showProfiles() {
+	if a.profileManager == nil {
+		fmt.Fprintln(a.out, "Profile management is not configured.")
+		return
+	}
+
+	st := a.profileManager.Status()
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "   \tID\tNAME\tSOURCE")
+	if st.SystemAvailable {
+		fmt.Fprintf(tw, "  %s\tsystem\tSystem default\tsystem\n", marker(st.ActiveID == "system"))
+	}
+	for _, p := range st.Profiles {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", marker(st.ActiveID == p.ID), p.ID, p.Name, p.Source)
+	}
+	tw.Flush()
+}
+
+func (a *App) useProfile(args []string) {
+	if a.profileManager == nil {
+		fmt.Fprintln(a.out, "Profile management is not configured.")
+		return
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(a.out, "Usage: use <id>")
+		return
+	}
+	if err := a.profileManager.SetActiveProfile(args[0]); err != nil {
+		fmt.Fprintf(a.out, "Failed to select profile: %v\n", err)
+		return
+	}
+	fmt.Fprintf(a.out, "Active profile is now %s.\n", args[0])
+}
+
+func marker(active bool) string {
+	if active {
+		return "*"
+	}
+	return " "
+}
+
+func money(amount float64, currency string) string {
+	if currency == "" {
+		currency = "USD"
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxCellWidth {
+		return s
+	}
+	return string(r[:maxCellWidth-1]) + "…"
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
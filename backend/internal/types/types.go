@@ -3,22 +3,263 @@ package types
 type CostOverview struct {
 	// Total is the total usage cost before credits/discounts for the period.
 	Total float64 `json:"total"`
-	// NetTotal is the effective cost after credits/discounts for the period.
+	// NetTotal is the effective cost for the period: Total plus tax, support,
+	// fees and other charges, less credits and refunds.
 	NetTotal float64 `json:"netTotal"`
 	// CreditsApplied is the absolute value of credits applied in the period.
 	CreditsApplied float64 `json:"creditsApplied"`
-	Currency       string  `json:"currency"`
-	Start          string  `json:"start"`
-	End            string  `json:"end"`
+	// Tax, Support and Fees (reservation and Savings Plans fees) are charged
+	// on top of usage; Refunds is the absolute value of refunds received.
+	Tax     float64 `json:"tax"`
+	Refunds float64 `json:"refunds"`
+	Support float64 `json:"support"`
+	Fees    float64 `json:"fees"`
+	// OtherCharges sums the remaining record types, such as discounts, with
+	// their sign preserved.
+	OtherCharges float64 `json:"otherCharges"`
+	Currency     string  `json:"currency"`
+	Start        string  `json:"start"`
+	End          string  `json:"end"`
+	// PreviousPeriodTotal is Total for the equivalent period immediately
+	// before Start/End (the same days of the prior month for month-aligned
+	// ranges). PreviousStart/PreviousEnd are empty if it could not be fetched.
+	PreviousPeriodTotal float64 `json:"previousPeriodTotal"`
+	PreviousStart       string  `json:"previousStart,omitempty"`
+	PreviousEnd         string  `json:"previousEnd,omitempty"`
+	// PercentChange is the change in Total versus PreviousPeriodTotal, e.g.
+	// 34.0 for "up 34%". Nil when there was no prior spend to compare with.
+	PercentChange *float64 `json:"percentChange,omitempty"`
+	// Converted repeats the amounts in the configured display currency; nil
+	// when no display currency is set or no rate is available.
+	Converted *ConvertedCostOverview `json:"converted,omitempty"`
+	// Formatted holds locale-formatted display strings for the values above.
+	Formatted *FormattedCostOverview `json:"formatted,omitempty"`
+}
+
+// ConvertedCostOverview holds CostOverview amounts converted at Rate units of
+// Currency per unit of the original currency.
+type ConvertedCostOverview struct {
+	Currency            string  `json:"currency"`
+	Rate                float64 `json:"rate"`
+	Total               float64 `json:"total"`
+	NetTotal            float64 `json:"netTotal"`
+	CreditsApplied      float64 `json:"creditsApplied"`
+	Tax                 float64 `json:"tax"`
+	Refunds             float64 `json:"refunds"`
+	Support             float64 `json:"support"`
+	Fees                float64 `json:"fees"`
+	OtherCharges        float64 `json:"otherCharges"`
+	PreviousPeriodTotal float64 `json:"previousPeriodTotal"`
+}
+
+// FormattedCostOverview carries display strings for a CostOverview, formatted
+// for the locale negotiated from the request.
+type FormattedCostOverview struct {
+	Locale         string `json:"locale"`
+	Total          string `json:"total"`
+	NetTotal       string `json:"netTotal"`
+	CreditsApplied string `json:"creditsApplied"`
+	Tax            string `json:"tax"`
+	Refunds        string `json:"refunds"`
+	Support        string `json:"support"`
+	Fees           string `json:"fees"`
+	OtherCharges   string `json:"otherCharges"`
+	Start          string `json:"start"`
+	End            string `json:"end"`
+	// PreviousPeriodTotal is empty when no comparison period was fetched.
+	PreviousPeriodTotal string `json:"previousPeriodTotal,omitempty"`
 }
 
 // ServiceCost represents the cost of a single AWS service.
 type ServiceCost struct {
-	Service       string  `json:"service"`
-	DisplayName   string  `json:"displayName"`
-	DrilldownKey  string  `json:"drilldownKey,omitempty"`
-	Cost          float64 `json:"cost"`
-	Currency      string  `json:"currency"`
+	Service      string  `json:"service"`
+	DisplayName  string  `json:"displayName"`
+	DrilldownKey string  `json:"drilldownKey,omitempty"`
+	Cost         float64 `json:"cost"`
+	Currency     string  `json:"currency"`
+	// ConvertedCost is Cost in DisplayCurrency, when one is configured.
+	ConvertedCost   *float64 `json:"convertedCost,omitempty"`
+	DisplayCurrency string   `json:"displayCurrency,omitempty"`
+	// FormattedCost is Cost formatted for the request locale.
+	FormattedCost string `json:"formattedCost,omitempty"`
+	// AccountID and ProfileName identify the account in multi-account
+	// (?profile=all) responses.
+	AccountID   string `json:"accountId,omitempty"`
+	ProfileName string `json:"profileName,omitempty"`
+}
+
+// UsageTypeCost is the cost of a single Cost Explorer usage type, such as
+// "USE1-BoxUsage:t3.micro", within a service breakdown.
+type UsageTypeCost struct {
+	UsageType string `json:"usageType"`
+	// Category groups usage types into components like "Compute", "EBS" or
+	// "Data Transfer".
+	Category  string  `json:"category"`
+	Cost      float64 `json:"cost"`
+	Currency  string  `json:"currency"`
+	Usage     float64 `json:"usage"`
+	UsageUnit string  `json:"usageUnit,omitempty"`
+}
+
+// ServiceCostBreakdown is returned from /api/cost/service/{service}/breakdown.
+type ServiceCostBreakdown struct {
+	Service string `json:"service"`
+	// Services lists the Cost Explorer service names that were included, as a
+	// drilldown key like "ec2" can cover several.
+	Services   []string        `json:"services"`
+	Total      float64         `json:"total"`
+	Currency   string          `json:"currency"`
+	Start      string          `json:"start"`
+	End        string          `json:"end"`
+	UsageTypes []UsageTypeCost `json:"usageTypes"`
+}
+
+// EC2OtherBucket is a readable component of the "EC2 - Other" cost line.
+type EC2OtherBucket struct {
+	Name       string          `json:"name"`
+	Cost       float64         `json:"cost"`
+	Percent    float64         `json:"percent"`
+	UsageTypes []UsageTypeCost `json:"usageTypes"`
+}
+
+// EC2OtherBreakdown is returned from /api/cost/ec2-other.
+type EC2OtherBreakdown struct {
+	Total    float64          `json:"total"`
+	Currency string           `json:"currency"`
+	Start    string           `json:"start"`
+	End      string           `json:"end"`
+	Buckets  []EC2OtherBucket `json:"buckets"`
+}
+
+// UntaggedServiceCost is one service's usage cost split by whether the
+// report's tag key is set.
+type UntaggedServiceCost struct {
+	Service         string  `json:"service"`
+	DisplayName     string  `json:"displayName"`
+	DrilldownKey    string  `json:"drilldownKey,omitempty"`
+	UntaggedCost    float64 `json:"untaggedCost"`
+	TotalCost       float64 `json:"totalCost"`
+	UntaggedPercent float64 `json:"untaggedPercent"`
+}
+
+// TagValueCost is the usage cost attributed to one value of a tag key.
+type TagValueCost struct {
+	Value string  `json:"value"`
+	Cost  float64 `json:"cost"`
+}
+
+// UntaggedCostReport is returned from /api/cost/untagged. Only usage is
+// counted, since tax, credits and fees never carry resource tags.
+type UntaggedCostReport struct {
+	TagKey          string                `json:"tagKey"`
+	Start           string                `json:"start"`
+	End             string                `json:"end"`
+	Currency        string                `json:"currency"`
+	Total           float64               `json:"total"`
+	Untagged        float64               `json:"untagged"`
+	UntaggedPercent float64               `json:"untaggedPercent"`
+	Services        []UntaggedServiceCost `json:"services"`
+	TagValues       []TagValueCost        `json:"tagValues"`
+}
+
+// PurchaseTypeCost is the amortized cost of one purchase option, such as
+// "On Demand Instances" or "Savings Plans".
+type PurchaseTypeCost struct {
+	PurchaseType string  `json:"purchaseType"`
+	Cost         float64 `json:"cost"`
+	Percent      float64 `json:"percent"`
+	// Discounted is true for Spot, Reserved Instances and Savings Plans.
+	Discounted bool `json:"discounted"`
+}
+
+// PurchaseTypeReport is returned from /api/cost/purchase-types.
+type PurchaseTypeReport struct {
+	Start             string             `json:"start"`
+	End               string             `json:"end"`
+	Currency          string             `json:"currency"`
+	Total             float64            `json:"total"`
+	Discounted        float64            `json:"discounted"`
+	DiscountedPercent float64            `json:"discountedPercent"`
+	PurchaseTypes     []PurchaseTypeCost `json:"purchaseTypes"`
+}
+
+// CostExplorerStatus is returned from /api/cost/status.
+type CostExplorerStatus struct {
+	// State is "enabled", "disabled", "access_denied", "error", or "unknown"
+	// before any Cost Explorer request has been made.
+	State string `json:"state"`
+	// LastSuccessAt is when Cost Explorer last answered (RFC 3339).
+	LastSuccessAt string `json:"lastSuccessAt,omitempty"`
+	// DataEstimated is true when the latest results include periods AWS has
+	// not finalised yet.
+	DataEstimated bool   `json:"dataEstimated"`
+	LastErrorAt   string `json:"lastErrorAt,omitempty"`
+	LastErrorCode string `json:"lastErrorCode,omitempty"`
+	LastError     string `json:"lastError,omitempty"`
+	// APICalls counts Cost Explorer requests since SessionStart, and
+	// EstimatedAPICost prices them at USD 0.01 each.
+	APICalls         int     `json:"apiCalls"`
+	EstimatedAPICost float64 `json:"estimatedApiCost"`
+	SessionStart     string  `json:"sessionStart"`
+}
+
+// ServiceSparkline is one service's monthly cost, aligned with
+// ServiceSparklines.Months.
+type ServiceSparkline struct {
+	Service      string    `json:"service"`
+	DisplayName  string    `json:"displayName"`
+	DrilldownKey string    `json:"drilldownKey,omitempty"`
+	Monthly      []float64 `json:"monthly"`
+	Total        float64   `json:"total"`
+}
+
+// ServiceSparklines is returned from /api/cost/sparklines.
+type ServiceSparklines struct {
+	// Months are YYYY-MM, oldest first; the last one is month-to-date.
+	Months   []string           `json:"months"`
+	Currency string             `json:"currency"`
+	Services []ServiceSparkline `json:"services"`
+}
+
+// CostFilter narrows cost queries to matching Cost Explorer dimension values.
+// Values within a field are ORed; fields are ANDed. Services may be Cost
+// Explorer service names or drilldown keys such as "ec2".
+type CostFilter struct {
+	Services   []string `json:"services,omitempty"`
+	Regions    []string `json:"regions,omitempty"`
+	UsageTypes []string `json:"usageTypes,omitempty"`
+}
+
+// ResourceFilter narrows resource listings; every condition must match. A
+// nil filter includes all resources.
+type ResourceFilter []ResourceCondition
+
+// ResourceCondition matches resources on one field.
+type ResourceCondition struct {
+	// Field is a resource field's JSON name, or "tag:<key>" for a tag.
+	Field string `json:"field"`
+	// Op is "=", "!=" or "~=" (case-insensitive substring).
+	Op string `json:"op"`
+	// Values are alternatives: the condition holds if any of them matches,
+	// or for "!=" if none does.
+	Values []string `json:"values"`
+}
+
+// CostPoint is the spend for one period of a CostTimeSeries. Start and End
+// are dates, or RFC 3339 timestamps for HOURLY; End is exclusive.
+type CostPoint struct {
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Cost  float64 `json:"cost"`
+}
+
+// CostTimeSeries is returned from /api/cost/timeseries.
+type CostTimeSeries struct {
+	Granularity string      `json:"granularity"`
+	Currency    string      `json:"currency"`
+	Start       string      `json:"start"`
+	End         string      `json:"end"`
+	Points      []CostPoint `json:"points"`
 }
 
 // CostResponse is returned from /api/cost.
@@ -28,30 +269,61 @@ type CostResponse struct {
 
 // ServicesResponse is returned from /api/services.
 type ServicesResponse struct {
-	Overview CostOverview `json:"overview"`
+	Overview CostOverview  `json:"overview"`
 	Services []ServiceCost `json:"services"`
 }
 
+// AccountInfo identifies one profile's part of a multi-account response.
+// Error is set when that profile could not be queried.
+type AccountInfo struct {
+	ProfileID   string `json:"profileId"`
+	ProfileName string `json:"profileName"`
+	AccountID   string `json:"accountId,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// AccountCostOverview is one profile's cost overview.
+type AccountCostOverview struct {
+	AccountInfo
+	Overview *CostOverview `json:"overview,omitempty"`
+}
+
+// MultiAccountCostResponse is returned from /api/cost and /api/services with
+// ?profile=all. Total, NetTotal and Currency are only set when every account
+// reports the same currency.
+type MultiAccountCostResponse struct {
+	Accounts []AccountCostOverview `json:"accounts"`
+	Total    float64               `json:"total"`
+	NetTotal float64               `json:"netTotal"`
+	Currency string                `json:"currency,omitempty"`
+	// Services lists each account's service costs (for /api/services).
+	Services []ServiceCost `json:"services,omitempty"`
+}
+
 // EC2Instance represents a simplified EC2 instance description.
 type EC2Instance struct {
-	InstanceID       string `json:"instanceId"`
-	Name             string `json:"name"`
-	State            string `json:"state"`
-	InstanceType     string `json:"instanceType"`
-	AvailabilityZone string `json:"availabilityZone"`
-	PrivateIP        string `json:"privateIp"`
-	PublicIP         string `json:"publicIp"`
-	Region           string `json:"region"`
+	InstanceID       string            `json:"instanceId"`
+	Name             string            `json:"name"`
+	State            string            `json:"state"`
+	InstanceType     string            `json:"instanceType"`
+	AvailabilityZone string            `json:"availabilityZone"`
+	PrivateIP        string            `json:"privateIp"`
+	PublicIP         string            `json:"publicIp"`
+	LaunchTime       string            `json:"launchTime,omitempty"`
+	Region           string            `json:"region"`
+	Tags             map[string]string `json:"tags,omitempty"`
 }
 
 // VPC represents a simplified VPC description.
 type VPC struct {
-	VpcID     string `json:"vpcId"`
-	Name      string `json:"name"`
-	CIDRBlock string `json:"cidrBlock"`
-	State     string `json:"state"`
-	IsDefault bool   `json:"isDefault"`
-	Region    string `json:"region"`
+	VpcID     string            `json:"vpcId"`
+	Name      string            `json:"name"`
+	CIDRBlock string            `json:"cidrBlock"`
+	State     string            `json:"state"`
+	IsDefault bool              `json:"isDefault"`
+	Region    string            `json:"region"`
+	Tags      map[string]string `json:"tags,omitempty"`
 }
 
 // ElasticIP represents a simplified Elastic IP description.
@@ -74,7 +346,74 @@ type ServiceResources struct {
 	S3Buckets              []S3Bucket              `json:"s3Buckets,omitempty"`
 	RekognitionCollections []RekognitionCollection `json:"rekognitionCollections,omitempty"`
 	RDSInstances           []RDSInstance           `json:"rdsInstances,omitempty"`
+	RDSClusters            []RDSCluster            `json:"rdsClusters,omitempty"`
+	RDSSnapshots           []RDSSnapshot           `json:"rdsSnapshots,omitempty"`
+	LambdaFunctions        []LambdaFunction        `json:"lambdaFunctions,omitempty"`
+	EBSVolumes             []EBSVolume             `json:"ebsVolumes,omitempty"`
+	EBSSnapshots           []EBSSnapshot           `json:"ebsSnapshots,omitempty"`
+	AMIs                   []AMI                   `json:"amis,omitempty"`
+	SecurityGroups         []SecurityGroup         `json:"securityGroups,omitempty"`
+	Subnets                []Subnet                `json:"subnets,omitempty"`
+	InternetGateways       []InternetGateway       `json:"internetGateways,omitempty"`
+	RouteTables            []RouteTable            `json:"routeTables,omitempty"`
+	VPCPeeringConnections  []VPCPeeringConnection  `json:"vpcPeeringConnections,omitempty"`
+	LoadBalancers          []LoadBalancer          `json:"loadBalancers,omitempty"`
+	TargetGroups           []TargetGroup           `json:"targetGroups,omitempty"`
+	LaunchTemplates        []LaunchTemplate        `json:"launchTemplates,omitempty"`
+	HostedZones            []HostedZone            `json:"hostedZones,omitempty"`
+	Certificates           []Certificate           `json:"certificates,omitempty"`
+	IAMUsers               []IAMUser               `json:"iamUsers,omitempty"`
+	IAMRoles               []IAMRole               `json:"iamRoles,omitempty"`
+	IAMGroups              []IAMGroup              `json:"iamGroups,omitempty"`
+	IAMPolicies            []IAMPolicy             `json:"iamPolicies,omitempty"`
+	AccessKeys             []AccessKey             `json:"accessKeys,omitempty"`
+	KMSKeys                []KMSKey                `json:"kmsKeys,omitempty"`
+	SSMParameters          []SSMParameter          `json:"ssmParameters,omitempty"`
+	SQSQueues              []SQSQueue              `json:"sqsQueues,omitempty"`
+	CloudWatchAlarms       []CloudWatchAlarm       `json:"cloudWatchAlarms,omitempty"`
+	LogGroups              []LogGroup              `json:"logGroups,omitempty"`
+	BeanstalkApplications  []BeanstalkApplication  `json:"beanstalkApplications,omitempty"`
+	BeanstalkEnvironments  []BeanstalkEnvironment  `json:"beanstalkEnvironments,omitempty"`
+	APIs                   []APIGatewayAPI         `json:"apis,omitempty"`
+	GlueDatabases          []GlueDatabase          `json:"glueDatabases,omitempty"`
+	GlueCrawlers           []GlueCrawler           `json:"glueCrawlers,omitempty"`
+	GlueJobs               []GlueJob               `json:"glueJobs,omitempty"`
+	AthenaWorkGroups       []AthenaWorkGroup       `json:"athenaWorkGroups,omitempty"`
+	AthenaNamedQueries     []AthenaNamedQuery      `json:"athenaNamedQueries,omitempty"`
+	CloudFormationStacks   []CloudFormationStack   `json:"cloudFormationStacks,omitempty"`
+	LightsailInstances     []LightsailInstance     `json:"lightsailInstances,omitempty"`
+	LightsailStaticIPs     []LightsailStaticIP     `json:"lightsailStaticIps,omitempty"`
+	LightsailDatabases     []LightsailDatabase     `json:"lightsailDatabases,omitempty"`
+	BackupVaults           []BackupVault           `json:"backupVaults,omitempty"`
+	BackupPlans            []BackupPlan            `json:"backupPlans,omitempty"`
+	DMSInstances           []DMSInstance           `json:"dmsInstances,omitempty"`
+	DMSTasks               []DMSTask               `json:"dmsTasks,omitempty"`
+	MQBrokers              []MQBroker              `json:"mqBrokers,omitempty"`
+	CognitoUserPools       []CognitoUserPool       `json:"cognitoUserPools,omitempty"`
+	CognitoIdentityPools   []CognitoIdentityPool   `json:"cognitoIdentityPools,omitempty"`
+	EventBridgeRules       []EventBridgeRule       `json:"eventBridgeRules,omitempty"`
+	EventBridgeSchedules   []EventBridgeSchedule   `json:"eventBridgeSchedules,omitempty"`
+	WorkSpaces             []WorkSpace             `json:"workSpaces,omitempty"`
 	Message                string                  `json:"message,omitempty"`
+	// Pagination is set when the request asked for a page of each list.
+	Pagination *Pagination `json:"pagination,omitempty"`
+	// CachedAt is set when AWS couldn't be reached and a saved listing
+	// was returned instead; it is when that listing was fetched (RFC 3339).
+	CachedAt string `json:"cachedAt,omitempty"`
+}
+
+// Pagination describes a page of resource lists. The same limit and offset
+// apply to every list in the response.
+type Pagination struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset"`
+	// Total is the unpaged number of resources across all lists; Totals
+	// breaks it down by list key.
+	Total  int            `json:"total"`
+	Totals map[string]int `json:"totals"`
+	// NextOffset is the offset of the next page, or 0 when every list ends
+	// on this page.
+	NextOffset int `json:"nextOffset,omitempty"`
 }
 
 // S3Bucket represents a simplified S3 bucket description.
@@ -82,6 +421,10 @@ type S3Bucket struct {
 	Name         string `json:"name"`
 	CreationDate string `json:"creationDate"`
 	Region       string `json:"region"`
+	// SizeBytes (summed over all storage classes) and ObjectCount come from
+	// the daily CloudWatch storage metrics and lag by up to a day.
+	SizeBytes   int64 `json:"sizeBytes"`
+	ObjectCount int64 `json:"objectCount"`
 }
 
 // RekognitionCollection represents a simplified Rekognition collection.
@@ -102,6 +445,702 @@ type RDSInstance struct {
 	Region               string `json:"region"`
 }
 
+// RDSCluster represents a simplified Aurora or Multi-AZ DB cluster.
+type RDSCluster struct {
+	DBClusterIdentifier string `json:"dbClusterIdentifier"`
+	Engine              string `json:"engine"`
+	EngineVersion       string `json:"engineVersion"`
+	EngineMode          string `json:"engineMode"` // provisioned or serverless (v1)
+	Status              string `json:"status"`
+	Endpoint            string `json:"endpoint"`
+	MultiAZ             bool   `json:"multiAZ"`
+	MemberCount         int    `json:"memberCount"`
+	// Serverless v2 capacity range in Aurora capacity units.
+	ServerlessV2   bool    `json:"serverlessV2"`
+	MinCapacityACU float64 `json:"minCapacityAcu,omitempty"`
+	MaxCapacityACU float64 `json:"maxCapacityAcu,omitempty"`
+	Region         string  `json:"region"`
+}
+
+// RDSSnapshot represents a manual DB instance or cluster snapshot.
+type RDSSnapshot struct {
+	SnapshotIdentifier string `json:"snapshotIdentifier"`
+	Source             string `json:"source"`
+	SourceType         string `json:"sourceType"` // instance or cluster
+	Engine             string `json:"engine"`
+	Status             string `json:"status"`
+	AllocatedStorageGB int    `json:"allocatedStorageGB"`
+	CreateTime         string `json:"createTime"`
+	Region             string `json:"region"`
+}
+
+// LambdaFunction represents a simplified Lambda function description.
+type LambdaFunction struct {
+	FunctionName string `json:"functionName"`
+	FunctionArn  string `json:"functionArn"`
+	Runtime      string `json:"runtime"`
+	Handler      string `json:"handler,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	// MemorySize is in MB.
+	MemorySize int `json:"memorySize"`
+	// Timeout is in seconds.
+	Timeout int `json:"timeout"`
+	// CodeSize is the deployment package size in bytes.
+	CodeSize     int64  `json:"codeSize"`
+	LastModified string `json:"lastModified"`
+	Region       string `json:"region"`
+}
+
+// EBSVolume represents a simplified EBS volume description.
+type EBSVolume struct {
+	VolumeID   string `json:"volumeId"`
+	Name       string `json:"name"`
+	SizeGiB    int    `json:"sizeGiB"`
+	VolumeType string `json:"volumeType"`
+	Iops       int    `json:"iops,omitempty"`
+	Throughput int    `json:"throughput,omitempty"`
+	Encrypted  bool   `json:"encrypted"`
+	State      string `json:"state"`
+	// AttachedTo lists the instance IDs the volume is attached to.
+	AttachedTo []string `json:"attachedTo,omitempty"`
+	// Unattached is true for volumes not attached to any instance, which
+	// still incur storage charges.
+	Unattached       bool              `json:"unattached"`
+	SnapshotID       string            `json:"snapshotId,omitempty"`
+	AvailabilityZone string            `json:"availabilityZone"`
+	CreateTime       string            `json:"createTime"`
+	Region           string            `json:"region"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// EBSSnapshot represents a simplified EBS snapshot owned by the account.
+type EBSSnapshot struct {
+	SnapshotID  string `json:"snapshotId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	VolumeID    string `json:"volumeId"`
+	SizeGiB     int    `json:"sizeGiB"`
+	State       string `json:"state"`
+	Encrypted   bool   `json:"encrypted"`
+	StorageTier string `json:"storageTier,omitempty"`
+	StartTime   string `json:"startTime"`
+	AgeDays     int    `json:"ageDays"`
+	// VolumeExists reports whether the source volume still exists.
+	VolumeExists bool `json:"volumeExists"`
+	// Orphaned is true when the source volume has been deleted, so the
+	// snapshot is likely only kept around by accident.
+	Orphaned bool              `json:"orphaned"`
+	Region   string            `json:"region"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// AMI represents a simplified machine image owned by the account.
+type AMI struct {
+	ImageID      string `json:"imageId"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	CreationDate string `json:"creationDate"`
+	State        string `json:"state"`
+	Architecture string `json:"architecture"`
+	Public       bool   `json:"public"`
+	// SnapshotIDs are the EBS snapshots backing the image; they keep
+	// incurring storage cost until the image is deregistered.
+	SnapshotIDs  []string `json:"snapshotIds,omitempty"`
+	TotalSizeGiB int      `json:"totalSizeGiB"`
+	Region       string   `json:"region"`
+}
+
+// SecurityGroup represents a simplified security group with rule counts.
+type SecurityGroup struct {
+	GroupID          string `json:"groupId"`
+	GroupName        string `json:"groupName"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	VpcID            string `json:"vpcId"`
+	IngressRuleCount int    `json:"ingressRuleCount"`
+	EgressRuleCount  int    `json:"egressRuleCount"`
+	// OpenToWorld is true when any ingress rule allows 0.0.0.0/0 or ::/0.
+	OpenToWorld bool `json:"openToWorld"`
+	// OpenIngressPorts lists the world-open rules, e.g. "tcp/22" or "all".
+	OpenIngressPorts []string          `json:"openIngressPorts,omitempty"`
+	Region           string            `json:"region"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// Subnet represents a simplified subnet with IP capacity information.
+type Subnet struct {
+	SubnetID                string `json:"subnetId"`
+	Name                    string `json:"name"`
+	VpcID                   string `json:"vpcId"`
+	CIDRBlock               string `json:"cidrBlock"`
+	AvailabilityZone        string `json:"availabilityZone"`
+	AvailableIPAddressCount int    `json:"availableIpAddressCount"`
+	// TotalIPAddressCount is the usable size of the CIDR after the five
+	// addresses AWS reserves in every subnet.
+	TotalIPAddressCount int               `json:"totalIpAddressCount"`
+	UtilizationPercent  float64           `json:"utilizationPercent"`
+	MapPublicIPOnLaunch bool              `json:"mapPublicIpOnLaunch"`
+	DefaultForAz        bool              `json:"defaultForAz"`
+	State               string            `json:"state"`
+	Region              string            `json:"region"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+// InternetGateway represents a simplified internet gateway and its VPC attachment.
+type InternetGateway struct {
+	InternetGatewayID string `json:"internetGatewayId"`
+	Name              string `json:"name"`
+	OwnerID           string `json:"ownerId"`
+	VpcID             string `json:"vpcId,omitempty"`
+	// State is the attachment state, or "detached" when not attached to a VPC.
+	State  string `json:"state"`
+	Region string `json:"region"`
+}
+
+// RouteTable represents a simplified route table with its associations.
+type RouteTable struct {
+	RouteTableID      string   `json:"routeTableId"`
+	Name              string   `json:"name"`
+	VpcID             string   `json:"vpcId"`
+	Main              bool     `json:"main"`
+	AssociatedSubnets []string `json:"associatedSubnets,omitempty"`
+	Routes            []Route  `json:"routes,omitempty"`
+	Region            string   `json:"region"`
+}
+
+// Route is a single entry in a route table.
+type Route struct {
+	Destination string `json:"destination"`
+	Target      string `json:"target"`
+	State       string `json:"state"`
+}
+
+// VPCPeeringConnection represents a simplified VPC peering connection.
+type VPCPeeringConnection struct {
+	PeeringConnectionID string `json:"peeringConnectionId"`
+	Name                string `json:"name"`
+	Status              string `json:"status"`
+	StatusMessage       string `json:"statusMessage,omitempty"`
+	RequesterVpcID      string `json:"requesterVpcId"`
+	RequesterOwnerID    string `json:"requesterOwnerId"`
+	RequesterCIDR       string `json:"requesterCidr"`
+	RequesterRegion     string `json:"requesterRegion"`
+	AccepterVpcID       string `json:"accepterVpcId"`
+	AccepterOwnerID     string `json:"accepterOwnerId"`
+	AccepterCIDR        string `json:"accepterCidr"`
+	AccepterRegion      string `json:"accepterRegion"`
+	Region              string `json:"region"`
+}
+
+// LoadBalancer represents a simplified application, network, gateway or
+// classic load balancer.
+type LoadBalancer struct {
+	Name string `json:"name"`
+	ARN  string `json:"arn,omitempty"`
+	// Type is "application", "network", "gateway" or "classic".
+	Type              string   `json:"type"`
+	Scheme            string   `json:"scheme"`
+	DNSName           string   `json:"dnsName"`
+	State             string   `json:"state"`
+	VpcID             string   `json:"vpcId"`
+	AvailabilityZones []string `json:"availabilityZones"`
+	CreatedTime       string   `json:"createdTime"`
+	Region            string   `json:"region"`
+}
+
+// TargetGroup represents a simplified elbv2 target group with health counts.
+type TargetGroup struct {
+	Name             string   `json:"name"`
+	ARN              string   `json:"arn"`
+	Protocol         string   `json:"protocol"`
+	Port             int      `json:"port"`
+	TargetType       string   `json:"targetType"`
+	VpcID            string   `json:"vpcId"`
+	HealthCheckPath  string   `json:"healthCheckPath,omitempty"`
+	LoadBalancerArns []string `json:"loadBalancerArns,omitempty"`
+	TotalTargets     int      `json:"totalTargets"`
+	HealthyTargets   int      `json:"healthyTargets"`
+	// UnhealthyTargets counts only the "unhealthy" state; initial, draining
+	// and unused targets are included in TotalTargets alone.
+	UnhealthyTargets int    `json:"unhealthyTargets"`
+	Region           string `json:"region"`
+}
+
+// LaunchTemplate represents an EC2 launch template or a legacy Auto Scaling
+// launch configuration.
+type LaunchTemplate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Kind is "launch-template" or "launch-configuration".
+	Kind string `json:"kind"`
+	// DefaultVersion and LatestVersion are zero for launch configurations,
+	// which are not versioned.
+	DefaultVersion int64  `json:"defaultVersion,omitempty"`
+	LatestVersion  int64  `json:"latestVersion,omitempty"`
+	ImageID        string `json:"imageId"`
+	InstanceType   string `json:"instanceType"`
+	CreatedTime    string `json:"createdTime"`
+	Region         string `json:"region"`
+}
+
+// HostedZone represents a simplified Route 53 hosted zone.
+type HostedZone struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Visibility     string `json:"visibility"` // public or private
+	RecordSetCount int    `json:"recordSetCount"`
+	Comment        string `json:"comment,omitempty"`
+	// CleanupCandidate is true when the zone holds only the default SOA and
+	// NS records.
+	CleanupCandidate bool `json:"cleanupCandidate"`
+}
+
+// Certificate represents a simplified ACM certificate.
+type Certificate struct {
+	ARN          string `json:"arn"`
+	DomainName   string `json:"domainName"`
+	Status       string `json:"status"`
+	Type         string `json:"type"`
+	KeyAlgorithm string `json:"keyAlgorithm"`
+	InUse        bool   `json:"inUse"`
+	NotAfter     string `json:"notAfter,omitempty"`
+	// DaysUntilExpiry is negative for expired certificates and nil when the
+	// certificate has not been issued yet.
+	DaysUntilExpiry *int   `json:"daysUntilExpiry,omitempty"`
+	ExpiringSoon    bool   `json:"expiringSoon"`
+	Region          string `json:"region"`
+}
+
+// IAMUser represents a simplified IAM user.
+type IAMUser struct {
+	UserName   string `json:"userName"`
+	UserID     string `json:"userId"`
+	ARN        string `json:"arn"`
+	CreateDate string `json:"createDate"`
+	// PasswordLastUsed is empty for users without console access or who
+	// have never signed in.
+	PasswordLastUsed string `json:"passwordLastUsed,omitempty"`
+}
+
+// IAMRole represents a simplified IAM role with last-used information.
+type IAMRole struct {
+	RoleName       string `json:"roleName"`
+	RoleID         string `json:"roleId"`
+	ARN            string `json:"arn"`
+	Path           string `json:"path"`
+	CreateDate     string `json:"createDate"`
+	LastUsedDate   string `json:"lastUsedDate,omitempty"`
+	LastUsedRegion string `json:"lastUsedRegion,omitempty"`
+}
+
+// IAMGroup represents a simplified IAM group.
+type IAMGroup struct {
+	GroupName  string `json:"groupName"`
+	GroupID    string `json:"groupId"`
+	ARN        string `json:"arn"`
+	CreateDate string `json:"createDate"`
+}
+
+// IAMPolicy represents a customer-managed IAM policy.
+type IAMPolicy struct {
+	PolicyName      string `json:"policyName"`
+	PolicyID        string `json:"policyId"`
+	ARN             string `json:"arn"`
+	AttachmentCount int    `json:"attachmentCount"`
+	CreateDate      string `json:"createDate"`
+	UpdateDate      string `json:"updateDate"`
+}
+
+// AccessKey represents an IAM user's access key with age and last-used data.
+type AccessKey struct {
+	UserName    string `json:"userName"`
+	AccessKeyID string `json:"accessKeyId"`
+	Status      string `json:"status"`
+	CreateDate  string `json:"createDate"`
+	AgeDays     int    `json:"ageDays"`
+	// LastUsed fields are empty for keys that have never been used.
+	LastUsedDate    string `json:"lastUsedDate,omitempty"`
+	LastUsedService string `json:"lastUsedService,omitempty"`
+	LastUsedRegion  string `json:"lastUsedRegion,omitempty"`
+	// Stale is true when the key is older than 90 days.
+	Stale bool `json:"stale"`
+}
+
+// KMSKey represents a customer-managed KMS key.
+type KMSKey struct {
+	KeyID        string `json:"keyId"`
+	ARN          string `json:"arn"`
+	Alias        string `json:"alias,omitempty"`
+	Description  string `json:"description"`
+	KeyState     string `json:"keyState"`
+	KeySpec      string `json:"keySpec"`
+	KeyUsage     string `json:"keyUsage"`
+	Origin       string `json:"origin"`
+	MultiRegion  bool   `json:"multiRegion"`
+	CreationDate string `json:"creationDate"`
+	// RotationEnabled is always false for keys that do not support
+	// automatic rotation (asymmetric, HMAC or imported key material).
+	RotationEnabled bool   `json:"rotationEnabled"`
+	Region          string `json:"region"`
+}
+
+// SSMParameter represents a Systems Manager Parameter Store entry. Values
+// are never fetched.
+type SSMParameter struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"` // String, StringList or SecureString
+	Tier             string `json:"tier"` // Standard, Advanced or Intelligent-Tiering
+	DataType         string `json:"dataType"`
+	Description      string `json:"description,omitempty"`
+	Version          int64  `json:"version"`
+	LastModifiedDate string `json:"lastModifiedDate"`
+	LastModifiedUser string `json:"lastModifiedUser,omitempty"`
+	Region           string `json:"region"`
+}
+
+// SQSQueue represents an SQS queue with its approximate depth.
+type SQSQueue struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Fifo bool   `json:"fifo"`
+	// MessagesAvailable is ApproximateNumberOfMessages, the backlog waiting
+	// to be received.
+	MessagesAvailable int64 `json:"messagesAvailable"`
+	// MessagesInFlight is ApproximateNumberOfMessagesNotVisible, received
+	// but not yet deleted.
+	MessagesInFlight    int64  `json:"messagesInFlight"`
+	MessagesDelayed     int64  `json:"messagesDelayed"`
+	HasDeadLetterTarget bool   `json:"hasDeadLetterTarget"`
+	Region              string `json:"region"`
+}
+
+// CloudWatchAlarm represents a metric or composite CloudWatch alarm.
+type CloudWatchAlarm struct {
+	Name         string `json:"name"`
+	ARN          string `json:"arn"`
+	Type         string `json:"type"`  // metric or composite
+	State        string `json:"state"` // OK, ALARM or INSUFFICIENT_DATA
+	StateUpdated string `json:"stateUpdated"`
+	// Metric fields are empty for composite alarms and for metric-math alarms.
+	Namespace          string   `json:"namespace,omitempty"`
+	MetricName         string   `json:"metricName,omitempty"`
+	Statistic          string   `json:"statistic,omitempty"`
+	ComparisonOperator string   `json:"comparisonOperator,omitempty"`
+	Threshold          *float64 `json:"threshold,omitempty"`
+	ActionsEnabled     bool     `json:"actionsEnabled"`
+	Region             string   `json:"region"`
+}
+
+// LogGroup represents a CloudWatch Logs log group.
+type LogGroup struct {
+	Name         string `json:"name"`
+	ARN          string `json:"arn"`
+	Class        string `json:"class,omitempty"`
+	CreationTime string `json:"creationTime"`
+	// RetentionInDays is zero when NeverExpire is set.
+	RetentionInDays int    `json:"retentionInDays"`
+	NeverExpire     bool   `json:"neverExpire"`
+	StoredBytes     int64  `json:"storedBytes"`
+	Region          string `json:"region"`
+}
+
+// BeanstalkApplication represents an Elastic Beanstalk application.
+type BeanstalkApplication struct {
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	DateCreated      string `json:"dateCreated"`
+	VersionCount     int    `json:"versionCount"`
+	EnvironmentCount int    `json:"environmentCount"`
+	Region           string `json:"region"`
+}
+
+// BeanstalkEnvironment represents an Elastic Beanstalk environment.
+type BeanstalkEnvironment struct {
+	Name            string `json:"name"`
+	EnvironmentID   string `json:"environmentId"`
+	ApplicationName string `json:"applicationName"`
+	VersionLabel    string `json:"versionLabel"`
+	Platform        string `json:"platform"`
+	Tier            string `json:"tier"` // WebServer or Worker
+	CNAME           string `json:"cname,omitempty"`
+	Status          string `json:"status"`
+	// Health is the color (Green, Yellow, Red, Grey); HealthStatus is the
+	// enhanced health status when enabled.
+	Health       string `json:"health"`
+	HealthStatus string `json:"healthStatus,omitempty"`
+	DateUpdated  string `json:"dateUpdated"`
+	Region       string `json:"region"`
+}
+
+// APIGatewayAPI represents a REST, HTTP or WebSocket API Gateway API.
+type APIGatewayAPI struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Protocol    string `json:"protocol"` // REST, HTTP or WEBSOCKET
+	// EndpointType is EDGE, REGIONAL or PRIVATE for REST APIs; HTTP and
+	// WebSocket APIs are always regional.
+	EndpointType string `json:"endpointType"`
+	Endpoint     string `json:"endpoint"`
+	StageCount   int    `json:"stageCount"`
+	CreatedDate  string `json:"createdDate"`
+	Region       string `json:"region"`
+}
+
+// GlueDatabase represents a Glue Data Catalog database.
+type GlueDatabase struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	LocationURI string `json:"locationUri,omitempty"`
+	CreateTime  string `json:"createTime"`
+	Region      string `json:"region"`
+}
+
+// GlueCrawler represents a Glue crawler with its schedule and last run.
+type GlueCrawler struct {
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	DatabaseName  string `json:"databaseName"`
+	Schedule      string `json:"schedule,omitempty"`
+	ScheduleState string `json:"scheduleState,omitempty"`
+	LastRunStatus string `json:"lastRunStatus,omitempty"`
+	LastRunTime   string `json:"lastRunTime,omitempty"`
+	LastRunError  string `json:"lastRunError,omitempty"`
+	Region        string `json:"region"`
+}
+
+// GlueJob represents a Glue ETL job and its configured capacity.
+type GlueJob struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"` // glueetl, gluestreaming or pythonshell
+	GlueVersion     string `json:"glueVersion"`
+	WorkerType      string `json:"workerType,omitempty"`
+	NumberOfWorkers int    `json:"numberOfWorkers,omitempty"`
+	// DPU is the capacity allocated per run, derived from the worker type
+	// and count or taken from MaxCapacity for older jobs.
+	DPU            float64 `json:"dpu"`
+	Role           string  `json:"role"`
+	LastModifiedOn string  `json:"lastModifiedOn"`
+	Region         string  `json:"region"`
+}
+
+// AthenaWorkGroup represents an Athena workgroup and its governance settings.
+type AthenaWorkGroup struct {
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	Description   string `json:"description,omitempty"`
+	EngineVersion string `json:"engineVersion"`
+	// BytesScannedCutoffPerQuery is zero when queries are not limited.
+	BytesScannedCutoffPerQuery int64  `json:"bytesScannedCutoffPerQuery"`
+	EnforceConfiguration       bool   `json:"enforceConfiguration"`
+	CloudWatchMetricsEnabled   bool   `json:"cloudWatchMetricsEnabled"`
+	OutputLocation             string `json:"outputLocation,omitempty"`
+	NamedQueryCount            int    `json:"namedQueryCount"`
+	CreationTime               string `json:"creationTime"`
+	Region                     string `json:"region"`
+}
+
+// AthenaNamedQuery represents a saved Athena query. The query text is not
+// included.
+type AthenaNamedQuery struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Database    string `json:"database"`
+	WorkGroup   string `json:"workGroup"`
+	Region      string `json:"region"`
+}
+
+// CloudFormationStack represents a CloudFormation stack with drift status.
+type CloudFormationStack struct {
+	Name         string `json:"name"`
+	StackID      string `json:"stackId"`
+	Status       string `json:"status"`
+	StatusReason string `json:"statusReason,omitempty"`
+	Description  string `json:"description,omitempty"`
+	CreationTime string `json:"creationTime"`
+	LastUpdated  string `json:"lastUpdated,omitempty"`
+	Nested       bool   `json:"nested"`
+	// DriftStatus is DRIFTED, IN_SYNC, UNKNOWN or NOT_CHECKED as of the last
+	// drift detection run.
+	DriftStatus    string `json:"driftStatus"`
+	DriftCheckedAt string `json:"driftCheckedAt,omitempty"`
+	Region         string `json:"region"`
+}
+
+// LightsailInstance represents a Lightsail instance with its bundle details.
+type LightsailInstance struct {
+	Name      string `json:"name"`
+	ARN       string `json:"arn"`
+	State     string `json:"state"`
+	Blueprint string `json:"blueprint"`
+	BundleID  string `json:"bundleId"`
+	// Bundle fields are looked up from get-bundles; MonthlyPrice is in USD.
+	BundleName       string  `json:"bundleName,omitempty"`
+	MonthlyPrice     float64 `json:"monthlyPrice"`
+	CPUCount         int     `json:"cpuCount"`
+	RAMGiB           float64 `json:"ramGiB"`
+	DiskGiB          int     `json:"diskGiB"`
+	PublicIP         string  `json:"publicIp,omitempty"`
+	StaticIP         bool    `json:"staticIp"`
+	AvailabilityZone string  `json:"availabilityZone"`
+	CreatedAt        string  `json:"createdAt"`
+	Region           string  `json:"region"`
+}
+
+// LightsailStaticIP represents a Lightsail static IP.
+type LightsailStaticIP struct {
+	Name       string `json:"name"`
+	IPAddress  string `json:"ipAddress"`
+	AttachedTo string `json:"attachedTo,omitempty"`
+	Attached   bool   `json:"attached"`
+	Region     string `json:"region"`
+}
+
+// LightsailDatabase represents a Lightsail managed database.
+type LightsailDatabase struct {
+	Name          string `json:"name"`
+	BundleID      string `json:"bundleId"`
+	Engine        string `json:"engine"`
+	EngineVersion string `json:"engineVersion"`
+	State         string `json:"state"`
+	CreatedAt     string `json:"createdAt"`
+	Region        string `json:"region"`
+}
+
+// BackupVault represents an AWS Backup vault.
+type BackupVault struct {
+	Name           string `json:"name"`
+	ARN            string `json:"arn"`
+	RecoveryPoints int64  `json:"recoveryPoints"`
+	EncryptionKey  string `json:"encryptionKey,omitempty"`
+	Locked         bool   `json:"locked"`
+	CreationDate   string `json:"creationDate"`
+	Region         string `json:"region"`
+}
+
+// BackupPlan represents an AWS Backup plan and its scheduled rules.
+type BackupPlan struct {
+	ID                string       `json:"id"`
+	Name              string       `json:"name"`
+	Rules             []BackupRule `json:"rules,omitempty"`
+	CreationDate      string       `json:"creationDate"`
+	LastExecutionDate string       `json:"lastExecutionDate,omitempty"`
+	Region            string       `json:"region"`
+}
+
+// BackupRule is a single schedule within a backup plan.
+type BackupRule struct {
+	Name        string `json:"name"`
+	Schedule    string `json:"schedule"`
+	TargetVault string `json:"targetVault"`
+	// DeleteAfterDays is zero when recovery points are kept indefinitely.
+	DeleteAfterDays int `json:"deleteAfterDays"`
+}
+
+// DMSInstance represents a Database Migration Service replication instance.
+type DMSInstance struct {
+	Identifier         string `json:"identifier"`
+	ARN                string `json:"arn"`
+	Class              string `json:"class"`
+	AllocatedStorageGB int    `json:"allocatedStorageGB"`
+	Status             string `json:"status"`
+	EngineVersion      string `json:"engineVersion"`
+	MultiAZ            bool   `json:"multiAZ"`
+	PubliclyAccessible bool   `json:"publiclyAccessible"`
+	CreateTime         string `json:"createTime"`
+	Region             string `json:"region"`
+}
+
+// DMSTask represents a Database Migration Service replication task.
+type DMSTask struct {
+	Identifier          string `json:"identifier"`
+	ARN                 string `json:"arn"`
+	ReplicationInstance string `json:"replicationInstance"`
+	MigrationType       string `json:"migrationType"` // full-load, cdc or full-load-and-cdc
+	Status              string `json:"status"`
+	StatusReason        string `json:"statusReason,omitempty"`
+	FullLoadPercent     int    `json:"fullLoadPercent"`
+	Region              string `json:"region"`
+}
+
+// MQBroker represents an Amazon MQ broker.
+type MQBroker struct {
+	BrokerID       string `json:"brokerId"`
+	Name           string `json:"name"`
+	ARN            string `json:"arn"`
+	EngineType     string `json:"engineType"` // ACTIVEMQ or RABBITMQ
+	InstanceType   string `json:"instanceType"`
+	DeploymentMode string `json:"deploymentMode"`
+	State          string `json:"state"`
+	Created        string `json:"created"`
+	Region         string `json:"region"`
+}
+
+// CognitoUserPool represents a Cognito user pool with its user estimate.
+type CognitoUserPool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+	Tier   string `json:"tier,omitempty"`
+	// EstimatedUsers is Cognito's own approximation of the pool size, the
+	// closest available proxy for monthly active users.
+	EstimatedUsers int64  `json:"estimatedUsers"`
+	MFA            string `json:"mfa"`
+	CreationDate   string `json:"creationDate"`
+	LastModified   string `json:"lastModified"`
+	Region         string `json:"region"`
+}
+
+// CognitoIdentityPool represents a Cognito identity pool.
+type CognitoIdentityPool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+// EventBridgeRule represents an EventBridge rule on a specific event bus.
+type EventBridgeRule struct {
+	Name        string `json:"name"`
+	ARN         string `json:"arn"`
+	EventBus    string `json:"eventBus"`
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+	// A rule has either a schedule expression or an event pattern.
+	ScheduleExpression string `json:"scheduleExpression,omitempty"`
+	EventPattern       string `json:"eventPattern,omitempty"`
+	TargetCount        int    `json:"targetCount"`
+	// ManagedBy is set for rules created by other AWS services.
+	ManagedBy string `json:"managedBy,omitempty"`
+	Region    string `json:"region"`
+}
+
+// EventBridgeSchedule represents an EventBridge Scheduler schedule.
+type EventBridgeSchedule struct {
+	Name         string `json:"name"`
+	ARN          string `json:"arn"`
+	Group        string `json:"group"`
+	State        string `json:"state"`
+	TargetARN    string `json:"targetArn"`
+	CreationDate string `json:"creationDate"`
+	Region       string `json:"region"`
+}
+
+// WorkSpace represents an Amazon WorkSpaces virtual desktop.
+type WorkSpace struct {
+	WorkspaceID  string `json:"workspaceId"`
+	UserName     string `json:"userName"`
+	ComputerName string `json:"computerName,omitempty"`
+	DirectoryID  string `json:"directoryId"`
+	BundleID     string `json:"bundleId"`
+	ComputeType  string `json:"computeType"`
+	// RunningMode is ALWAYS_ON (billed monthly) or AUTO_STOP (billed hourly).
+	RunningMode string `json:"runningMode"`
+	State       string `json:"state"`
+	IPAddress   string `json:"ipAddress,omitempty"`
+	Region      string `json:"region"`
+}
+
 // ResourceSummary represents a high-level summary of resources for a service.
 type ResourceSummary struct {
 	Service      string `json:"service"`
@@ -114,4 +1153,3 @@ type ResourceSummary struct {
 type ResourcesSummaryResponse struct {
 	Summaries []ResourceSummary `json:"summaries"`
 }
-
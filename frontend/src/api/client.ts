@@ -32,7 +32,9 @@ export interface EC2Instance {
   availabilityZone: string;
   privateIp: string;
   publicIp: string;
+  launchTime?: string;
   region: string;
+  tags?: Record<string, string>;
 }
 
 export interface VPC {
@@ -42,6 +44,7 @@ export interface VPC {
   state: string;
   isDefault: boolean;
   region: string;
+  tags?: Record<string, string>;
 }
 
 export interface ElasticIP {
@@ -85,23 +88,45 @@ export interface ServiceResources {
   rekognitionCollections?: RekognitionCollection[];
    rdsInstances?: RDSInstance[];
   message?: string;
+  // Set when AWS couldn't be reached and a saved listing was returned; when it was fetched.
+  cachedAt?: string;
 }
 
 export interface ApiError {
   error: string;
   details?: string;
+  // Stable identifier such as "credentials_expired", "throttled" or
+  // "cli_not_found"; branch on this rather than on the message.
+  code?: string;
+}
+
+// ApiRequestError is thrown for error responses, keeping the API's code.
+export class ApiRequestError extends Error {
+  constructor(
+    message: string,
+    readonly status: number,
+    readonly code?: string,
+  ) {
+    super(message);
+    this.name = 'ApiRequestError';
+  }
 }
 
 export interface PublicProfile {
   id: string;
   name: string;
-  source: 'system' | 'custom';
+  source: string;
+  roleArn?: string;
+  expiresAt?: string;
+  isExpired?: boolean;
 }
 
 export interface ProfileStatus {
   systemAvailable: boolean;
   activeId: string;
   profiles: PublicProfile[];
+  // Set when the system credentials are an EC2, ECS or EKS ambient role.
+  system?: PublicProfile;
 }
 
 export interface ResourceSummary {
@@ -121,11 +146,109 @@ export interface PublicCommand {
   description: string;
   service: string;
   supportsRegion: boolean;
+  category: string;
+  tags: string[];
+  // Number of steps, for pipeline commands.
+  steps?: number;
+  favorite: boolean;
+}
+
+export interface PipelineStepResult {
+  command: string;
+  output?: unknown;
+  skipped?: string;
+}
+
+export interface CommandGroup {
+  category: string;
+  commands: PublicCommand[];
+}
+
+export interface SavedCommand {
+  id: string;
+  label: string;
+  args: string;
+  createdAt: string;
 }
 
 export interface CommandExecutionResult {
   command: string;
   output: any;
+  // Downloads the output again with downloadCommandExecution.
+  executionId?: string;
+}
+
+export interface CommandExecution {
+  id: string;
+  command: string;
+  commandId?: string;
+  profile: string;
+  region?: string;
+  format: string;
+  executedAt: string;
+  size: number;
+}
+
+export interface BatchResult {
+  service: string;
+  region?: string;
+  status: number;
+  resources?: ServiceResources;
+  error?: ApiError;
+}
+
+export interface ServerConfig {
+  listen: string;
+  tls: boolean;
+  // maxStaleSeconds is set when the cache outlives the server (persisted), on disk or in Redis.
+  cache: { ttlSeconds: number; backend: 'memory' | 'redis'; persisted: boolean; maxStaleSeconds?: number };
+  timeouts: { requestSeconds: number; scanSeconds: number; jobSeconds: number };
+  regions: { profile: string; default?: string; allowed: string[] };
+  commands: {
+    safetyFilter: string;
+    configured: number;
+    permissionCheck: boolean;
+    rawMaxPages: number;
+    mutating: string[];
+  };
+  features: {
+    auth: boolean;
+    audit: boolean;
+    alerts: boolean;
+    costHistory: boolean;
+    backups: boolean;
+    favorites: boolean;
+    jobs: boolean;
+    eventSinks: string[];
+    demoMode?: string;
+    displayCurrency?: string;
+    defaultLocale: string;
+    trustedOrigins: string[];
+  };
+  awsCli: { version?: string; error?: string };
+}
+
+const TOKEN_STORAGE_KEY = 'apiToken';
+
+// The server prints a link with #token=... when API auth is enabled; keep the
+// token for later visits and drop it from the address bar.
+function apiToken(): string | null {
+  const match = window.location.hash.match(/token=([^&]+)/);
+  if (match) {
+    localStorage.setItem(TOKEN_STORAGE_KEY, decodeURIComponent(match[1]));
+    history.replaceState(null, '', window.location.pathname + window.location.search);
+  }
+  return localStorage.getItem(TOKEN_STORAGE_KEY);
+}
+
+function apiFetch(input: string, init: RequestInit = {}): Promise<Response> {
+  const token = apiToken();
+  if (!token) {
+    return fetch(input, init);
+  }
+  const headers = new Headers(init.headers);
+  headers.set('Authorization', `Bearer ${token}`);
+  return fetch(input, { ...init, headers });
 }
 
 async function handleResponse<T>(resp: Response): Promise<T> {
@@ -136,9 +259,9 @@ async function handleResponse<T>(resp: Response): Promise<T> {
     if (isJSON) {
       const data = (await resp.json()) as ApiError;
       const errorMessage = data.error || resp.statusText;
-      throw new Error(errorMessage + (data.details ? `: ${data.details}` : ''));
+      throw new ApiRequestError(errorMessage + (data.details ? `: ${data.details}` : ''), resp.status, data.code);
     }
-    throw new Error(resp.statusText);
+    throw new ApiRequestError(resp.statusText, resp.status);
   }
 
   return (await resp.json()) as T;
@@ -149,7 +272,7 @@ export async function fetchCost(params?: { start?: string; end?: string }): Prom
   if (params?.start) qs.set('start', params.start);
   if (params?.end) qs.set('end', params.end);
   const url = qs.toString() ? `/api/cost?${qs.toString()}` : '/api/cost';
-  const resp = await fetch(url);
+  const resp = await apiFetch(url);
   return handleResponse<CostResponse>(resp);
 }
 
@@ -158,7 +281,7 @@ export async function fetchServices(params?: { start?: string; end?: string }):
   if (params?.start) qs.set('start', params.start);
   if (params?.end) qs.set('end', params.end);
   const url = qs.toString() ? `/api/services?${qs.toString()}` : '/api/services';
-  const resp = await fetch(url);
+  const resp = await apiFetch(url);
   return handleResponse<ServicesResponse>(resp);
 }
 
@@ -169,12 +292,12 @@ export async function fetchServiceResources(serviceKey: string, region?: string)
   }
   const qs = params.toString();
   const url = `/api/services/${encodeURIComponent(serviceKey)}/resources${qs ? `?${qs}` : ''}`;
-  const resp = await fetch(url);
+  const resp = await apiFetch(url);
   return handleResponse<ServiceResources>(resp);
 }
 
 export async function fetchProfileStatus(): Promise<ProfileStatus> {
-  const resp = await fetch('/api/profiles');
+  const resp = await apiFetch('/api/profiles');
   return handleResponse<ProfileStatus>(resp);
 }
 
@@ -185,7 +308,7 @@ export async function createProfile(input: {
   sessionToken?: string;
   region?: string;
 }): Promise<ProfileStatus> {
-  const resp = await fetch('/api/profiles', {
+  const resp = await apiFetch('/api/profiles', {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(input),
@@ -194,7 +317,7 @@ export async function createProfile(input: {
 }
 
 export async function selectProfile(id: string): Promise<ProfileStatus> {
-  const resp = await fetch('/api/profiles/select', {
+  const resp = await apiFetch('/api/profiles/select', {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({ id }),
@@ -202,25 +325,44 @@ export async function selectProfile(id: string): Promise<ProfileStatus> {
   return handleResponse<ProfileStatus>(resp);
 }
 
+// fetchResourcesBatch loads several services' resources in one request; each
+// result carries its own status, so one failing service doesn't fail the rest.
+export async function fetchResourcesBatch(
+  requests: { service: string; region?: string }[],
+): Promise<BatchResult[]> {
+  const resp = await apiFetch('/api/resources/batch', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ requests }),
+  });
+  const data = await handleResponse<{ results: BatchResult[] }>(resp);
+  return data.results;
+}
+
 export async function fetchResourcesSummary(): Promise<ResourcesSummaryResponse> {
-  const resp = await fetch('/api/resources/summary');
+  const resp = await apiFetch('/api/resources/summary');
   return handleResponse<ResourcesSummaryResponse>(resp);
 }
 
 export async function clearBackendCache(): Promise<void> {
-  const resp = await fetch('/api/cache/clear', { method: 'POST' });
+  const resp = await apiFetch('/api/cache/clear', { method: 'POST' });
   if (!resp.ok && resp.status !== 204) {
     await handleResponse<void>(resp);
   }
 }
 
 export async function fetchCommands(): Promise<PublicCommand[]> {
-  const resp = await fetch('/api/commands');
+  const resp = await apiFetch('/api/commands');
   return handleResponse<PublicCommand[]>(resp);
 }
 
+export async function fetchCommandGroups(): Promise<CommandGroup[]> {
+  const resp = await apiFetch('/api/commands?group=category');
+  return handleResponse<CommandGroup[]>(resp);
+}
+
 export async function executeCommand(id: string, region?: string): Promise<CommandExecutionResult> {
-  const resp = await fetch('/api/commands/execute', {
+  const resp = await apiFetch('/api/commands/execute', {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({ id, region }),
@@ -229,7 +371,7 @@ export async function executeCommand(id: string, region?: string): Promise<Comma
 }
 
 export async function executeRawCommand(args: string): Promise<CommandExecutionResult> {
-  const resp = await fetch('/api/commands/execute-raw', {
+  const resp = await apiFetch('/api/commands/execute-raw', {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({ args }),
@@ -237,4 +379,158 @@ export async function executeRawCommand(args: string): Promise<CommandExecutionR
   return handleResponse<CommandExecutionResult>(resp);
 }
 
+export async function setCommandFavorite(id: string, favorite: boolean): Promise<PublicCommand[]> {
+  const resp = await apiFetch(`/api/commands/${encodeURIComponent(id)}/favorite`, {
+    method: favorite ? 'POST' : 'DELETE',
+  });
+  return handleResponse<PublicCommand[]>(resp);
+}
+
+export async function fetchSavedCommands(): Promise<SavedCommand[]> {
+  const resp = await apiFetch('/api/commands/saved');
+  return handleResponse<SavedCommand[]>(resp);
+}
+
+export async function saveCommand(args: string, label?: string): Promise<SavedCommand> {
+  const resp = await apiFetch('/api/commands/saved', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ args, label }),
+  });
+  return handleResponse<SavedCommand>(resp);
+}
 
+export async function deleteSavedCommand(id: string): Promise<SavedCommand[]> {
+  const resp = await apiFetch(`/api/commands/saved/${encodeURIComponent(id)}`, { method: 'DELETE' });
+  return handleResponse<SavedCommand[]>(resp);
+}
+
+export async function executeSavedCommand(savedId: string): Promise<CommandExecutionResult> {
+  const resp = await apiFetch('/api/commands/execute-raw', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ savedId }),
+  });
+  return handleResponse<CommandExecutionResult>(resp);
+}
+
+export type CommandOutputFormat = 'table' | 'text' | 'yaml';
+
+// Runs a command and returns the aws CLI's own rendering of its output.
+export async function executeCommandAs(
+  body: { id: string; region?: string } | { args: string },
+  format: CommandOutputFormat,
+): Promise<string> {
+  const path = 'args' in body ? '/api/commands/execute-raw' : '/api/commands/execute';
+  const resp = await apiFetch(path, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ ...body, format }),
+  });
+  if (!resp.ok) {
+    return handleResponse<string>(resp);
+  }
+  return resp.text();
+}
+
+export interface CommandDryRun {
+  dryRun: true;
+  argv: string[];
+  env: string[];
+  profile?: string;
+  region?: string;
+}
+
+export async function dryRunCommand(
+  body: { id: string; region?: string } | { args: string },
+  format?: CommandOutputFormat,
+): Promise<CommandDryRun> {
+  const path = 'args' in body ? '/api/commands/execute-raw' : '/api/commands/execute';
+  const resp = await apiFetch(path, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ ...body, format, dryRun: true }),
+  });
+  return handleResponse<CommandDryRun>(resp);
+}
+
+export interface PendingMutation {
+  token: string;
+  command: string;
+  summary: string;
+  profile?: string;
+  region?: string;
+  expiresAt: string;
+}
+
+export async function fetchCommandExecutions(): Promise<CommandExecution[]> {
+  const resp = await apiFetch('/api/commands/executions');
+  return handleResponse<CommandExecution[]>(resp);
+}
+
+// Fetches a command execution's output as a file; format is json, csv or
+// table, and query a JMESPath expression selecting the rows.
+export async function downloadCommandExecution(
+  id: string,
+  format?: 'json' | 'csv' | 'table',
+  query?: string,
+): Promise<Blob> {
+  const params = new URLSearchParams();
+  if (format) params.set('format', format);
+  if (query) params.set('query', query);
+  const qs = params.toString();
+  const resp = await apiFetch(`/api/commands/executions/${encodeURIComponent(id)}/download${qs ? `?${qs}` : ''}`);
+  if (!resp.ok) {
+    return handleResponse<Blob>(resp);
+  }
+  return resp.blob();
+}
+
+export async function requestMutation(args: string): Promise<PendingMutation> {
+  const resp = await apiFetch('/api/commands/mutations', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ args }),
+  });
+  return handleResponse<PendingMutation>(resp);
+}
+
+export async function confirmMutation(token: string): Promise<CommandExecutionResult> {
+  const resp = await apiFetch('/api/commands/mutations/confirm', {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ token }),
+  });
+  return handleResponse<CommandExecutionResult>(resp);
+}
+
+export interface Job {
+  id: string;
+  request: string;
+  status: 'queued' | 'running' | 'succeeded' | 'failed' | 'canceled';
+  createdAt: string;
+  startedAt?: string;
+  finishedAt?: string;
+  statusCode?: number;
+  result?: unknown;
+}
+
+export async function fetchJobs(): Promise<Job[]> {
+  const resp = await apiFetch('/api/jobs');
+  return handleResponse<Job[]>(resp);
+}
+
+export async function fetchJob(id: string): Promise<Job> {
+  const resp = await apiFetch(`/api/jobs/${encodeURIComponent(id)}`);
+  return handleResponse<Job>(resp);
+}
+
+export async function cancelJob(id: string): Promise<Job> {
+  const resp = await apiFetch(`/api/jobs/${encodeURIComponent(id)}`, { method: 'DELETE' });
+  return handleResponse<Job>(resp);
+}
+
+export async function fetchServerConfig(): Promise<ServerConfig> {
+  const resp = await apiFetch('/api/config');
+  return handleResponse<ServerConfig>(resp);
+}
@@ -78,10 +78,14 @@ function ProfileBar() {
   const profiles = status?.profiles ?? [];
   const hasAnyCreds = !!status?.systemAvailable || profiles.length > 0;
 
+  const systemLabel = status?.system
+    ? `${status.system.name} (${status.system.source})`
+    : 'System default';
+
   const activeLabel = status?.activeId
     ? profiles.find((p) => p.id === status.activeId)?.name || status.activeId
     : status?.systemAvailable
-    ? 'System default'
+    ? systemLabel
     : 'No credentials';
 
   return (
@@ -100,7 +104,7 @@ function ProfileBar() {
           className="form-select form-input-sm"
           style={{ minWidth: 160 }}
         >
-          {status.systemAvailable && <option value="system">System default</option>}
+          {status.systemAvailable && <option value="system" title={status.system?.roleArn}>{systemLabel}</option>}
           {profiles.map((p) => (
             <option key={p.id} value={p.id}>
               {p.name} ({p.source})
@@ -236,7 +236,7 @@ function CommandRunnerPage() {
       {/* Help Card */}
       <div className="alert alert-info">
         <strong>Security Note:</strong> This CLI runner is configured to only allow read-only AWS operations.
-        Commands that modify, create, or delete resources are blocked for safety.
+        Only describe, list, get, lookup, search and head operations (and s3 ls) are allowed; anything else is blocked for safety.
       </div>
     </div>
   );
@@ -73,6 +73,6 @@ echo ""
 echo -e "Image URL: ${BLUE}https://hub.docker.com/r/${FULL_IMAGE_NAME}${NC}"
 echo ""
 echo -e "Users can now run:"
-echo -e "${YELLOW}docker run -d -p 8080:8080 -v ~/.aws:/root/.aws:ro ${FULL_IMAGE_NAME}${NC}"
+echo -e "${YELLOW}docker run -d -p 127.0.0.1:8080:8080 -v ~/.aws:/root/.aws:ro ${FULL_IMAGE_NAME}${NC}"
 echo ""
 